@@ -0,0 +1,224 @@
+// Command rajaongkir-stub serves the RajaOngkir province, city, cost and
+// waybill endpoints with deterministic answers so the app can run locally
+// without a RajaOngkir key. Point RO_BASE_URL at it, e.g.
+// RO_BASE_URL=http://localhost:8081.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	stubStatus struct {
+		Code        int    `json:"code"`
+		Description string `json:"description"`
+	}
+	stubCost struct {
+		Value int    `json:"value"`
+		ETD   string `json:"etd"`
+		Note  string `json:"note"`
+	}
+	stubService struct {
+		Service     string     `json:"service"`
+		Description string     `json:"description"`
+		Cost        []stubCost `json:"cost"`
+	}
+	stubProvince struct {
+		ProvinceID string `json:"province_id"`
+		Province   string `json:"province"`
+	}
+	stubCity struct {
+		CityID     string `json:"city_id"`
+		ProvinceID string `json:"province_id"`
+		Province   string `json:"province"`
+		Type       string `json:"type"`
+		CityName   string `json:"city_name"`
+		PostalCode string `json:"postal_code"`
+	}
+	stubServiceRate struct {
+		name       string
+		perKg      int
+		etd        string
+		sameCityOK bool
+	}
+)
+
+// provinces and cities are a small part of the RajaOngkir starter regions
+// with their real ids.
+var (
+	provinces = []stubProvince{
+		{ProvinceID: "6", Province: "DKI Jakarta"},
+		{ProvinceID: "9", Province: "Jawa Barat"},
+	}
+	cities = []stubCity{
+		{CityID: "151", ProvinceID: "6", Province: "DKI Jakarta", Type: "Kota", CityName: "Jakarta Barat", PostalCode: "11220"},
+		{CityID: "152", ProvinceID: "6", Province: "DKI Jakarta", Type: "Kota", CityName: "Jakarta Pusat", PostalCode: "10540"},
+		{CityID: "22", ProvinceID: "9", Province: "Jawa Barat", Type: "Kabupaten", CityName: "Bandung", PostalCode: "40311"},
+		{CityID: "23", ProvinceID: "9", Province: "Jawa Barat", Type: "Kota", CityName: "Bandung", PostalCode: "40111"},
+	}
+)
+
+// rates lists the services of every courier the app offers. CTC is the JNE
+// service for deliveries within one city.
+var rates = map[string][]stubServiceRate{
+	"jne": {
+		{name: "CTC", perKg: 8000, etd: "1-2", sameCityOK: true},
+		{name: "OKE", perKg: 9000, etd: "2-3"},
+		{name: "REG", perKg: 11000, etd: "1-2"},
+		{name: "YES", perKg: 18000, etd: "1-1"},
+	},
+	"pos": {
+		{name: "Paket Kilat Khusus", perKg: 10000, etd: "2-4", sameCityOK: true},
+	},
+	"tiki": {
+		{name: "ECO", perKg: 8500, etd: "4", sameCityOK: true},
+		{name: "REG", perKg: 10500, etd: "2", sameCityOK: true},
+		{name: "ONS", perKg: 19000, etd: "1", sameCityOK: true},
+	},
+}
+
+func main() {
+	addr := flag.String("addr", ":8081", "address to listen on")
+	flag.Parse()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/province", province)
+	mux.HandleFunc("/city", city)
+	mux.HandleFunc("/cost", cost)
+	mux.HandleFunc("/waybill", waybill)
+
+	log.Printf("rajaongkir stub listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, mux))
+}
+
+func province(w http.ResponseWriter, r *http.Request) {
+	if !authorized(w, r, http.MethodGet) {
+		return
+	}
+
+	respond(w, http.StatusOK, "OK", map[string]interface{}{"results": provinces})
+}
+
+func city(w http.ResponseWriter, r *http.Request) {
+	if !authorized(w, r, http.MethodGet) {
+		return
+	}
+
+	respond(w, http.StatusOK, "OK", map[string]interface{}{"results": cities})
+}
+
+// cost prices a delivery per started kilogram with a surcharge growing with
+// the distance between the city ids.
+func cost(w http.ResponseWriter, r *http.Request) {
+	if !authorized(w, r, http.MethodPost) {
+		return
+	}
+
+	origin, err1 := strconv.Atoi(r.FormValue("origin"))
+	destination, err2 := strconv.Atoi(r.FormValue("destination"))
+	weight, err3 := strconv.Atoi(r.FormValue("weight"))
+	courier := strings.ToLower(r.FormValue("courier"))
+	serviceRates, ok := rates[courier]
+	if err1 != nil || err2 != nil || err3 != nil || weight <= 0 || !ok {
+		respond(w, http.StatusBadRequest, "Bad request", nil)
+		return
+	}
+
+	kg := (weight + 999) / 1000
+	distance := origin - destination
+	if distance < 0 {
+		distance = -distance
+	}
+	surcharge := distance % 10 * 1000
+
+	services := make([]stubService, 0, len(serviceRates))
+	for _, rate := range serviceRates {
+		if rate.name == "CTC" && origin != destination {
+			continue
+		}
+		if origin == destination && !rate.sameCityOK {
+			continue
+		}
+		services = append(services, stubService{
+			Service:     rate.name,
+			Description: rate.name,
+			Cost:        []stubCost{{Value: kg*rate.perKg + surcharge, ETD: rate.etd}},
+		})
+	}
+
+	results := []map[string]interface{}{{
+		"code":  courier,
+		"name":  strings.ToUpper(courier),
+		"costs": services,
+	}}
+	respond(w, http.StatusOK, "OK", map[string]interface{}{"results": results})
+}
+
+// waybill reports every waybill as picked up and delivered two days later,
+// except waybills ending in 0 which are still on their way.
+func waybill(w http.ResponseWriter, r *http.Request) {
+	if !authorized(w, r, http.MethodPost) {
+		return
+	}
+
+	number := r.FormValue("waybill")
+	if number == "" || r.FormValue("courier") == "" {
+		respond(w, http.StatusBadRequest, "Invalid waybill", nil)
+		return
+	}
+
+	delivered := !strings.HasSuffix(number, "0")
+	pickedUp := time.Date(2023, time.January, 2, 9, 0, 0, 0, time.UTC)
+	manifest := []map[string]string{{
+		"manifest_description": "SHIPMENT RECEIVED BY COURIER",
+		"manifest_date":        pickedUp.Format("2006-01-02"),
+		"manifest_time":        pickedUp.Format("15:04"),
+		"city_name":            "JAKARTA",
+	}}
+	if delivered {
+		deliveredAt := pickedUp.AddDate(0, 0, 2)
+		manifest = append(manifest, map[string]string{
+			"manifest_description": "DELIVERED",
+			"manifest_date":        deliveredAt.Format("2006-01-02"),
+			"manifest_time":        deliveredAt.Format("15:04"),
+			"city_name":            "BANDUNG",
+		})
+	}
+
+	respond(w, http.StatusOK, "OK", map[string]interface{}{
+		"result": map[string]interface{}{
+			"delivered": delivered,
+			"manifest":  manifest,
+		},
+	})
+}
+
+func authorized(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method != method {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return false
+	}
+	if r.Header.Get("key") == "" {
+		respond(w, http.StatusBadRequest, "Invalid key", nil)
+		return false
+	}
+
+	return true
+}
+
+func respond(w http.ResponseWriter, code int, description string, body map[string]interface{}) {
+	if body == nil {
+		body = map[string]interface{}{}
+	}
+	body["status"] = stubStatus{Code: code, Description: description}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"rajaongkir": body})
+}
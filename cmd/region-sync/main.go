@@ -0,0 +1,58 @@
+// Command region-sync copies the provinces and cities of the shipping
+// provider into the provinces and districts tables and prints what changed.
+// Regions the provider no longer lists are marked inactive, never deleted,
+// because addresses keep pointing at them. Use -dry-run to only print the
+// changes.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/repository"
+	"github.com/lil-oren/rest/internal/usecase"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "report the changes without writing them")
+	flag.Parse()
+
+	if err := run(*dryRun); err != nil {
+		os.Exit(1)
+	}
+}
+
+func run(dryRun bool) error {
+	logger := dependency.NewLogger()
+
+	config, err := dependency.NewConfig(logger)
+	if err != nil {
+		return err
+	}
+
+	db, err := dependency.NewPGDB(*config, logger)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	rsu := usecase.NewRegionSyncUsecase(
+		repository.NewRajaOngkirRegionRepository(dependency.NewRajaOngkirClient(*config)),
+		repository.NewProvinceRepository(db),
+		repository.NewDistrictRepository(db),
+	)
+
+	report, err := rsu.Sync(context.Background(), dryRun)
+	if err != nil {
+		logger.Errorf("sync regions: %s", err.Error())
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(report)
+}
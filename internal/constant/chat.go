@@ -0,0 +1,41 @@
+package constant
+
+import "time"
+
+type ChatMessageType string
+
+const (
+	TextChatMessage  ChatMessageType = "TEXT"
+	ImageChatMessage ChatMessageType = "IMAGE"
+)
+
+// ChatRole is the side of a conversation an account is on. A seller can be
+// the buyer in conversations with other shops.
+type ChatRole string
+
+const (
+	BuyerChatRole  ChatRole = "buyer"
+	SellerChatRole ChatRole = "seller"
+)
+
+// Types of the frames sent over a chat WebSocket.
+const (
+	ChatMessageFrame = "message"
+	ChatReadFrame    = "read"
+	ChatErrorFrame   = "error"
+	ChatPingFrame    = "ping"
+)
+
+const (
+	ChatDefaultConversations = 20
+	ChatDefaultMessages      = 30
+	ChatMaxQuickReplies      = 50
+
+	ChatStreamBuffer = 32
+	// ChatPingInterval is how often a ping frame is sent on an idle
+	// WebSocket, so proxies keep the connection open.
+	ChatPingInterval = 25 * time.Second
+	ChatWriteTimeout = 10 * time.Second
+	ChatMaxFrameSize = 64 << 10
+	ChatListenRetry  = 5 * time.Second
+)
@@ -0,0 +1,12 @@
+package constant
+
+const (
+	DashboardBucketDaily  = "daily"
+	DashboardBucketWeekly = "weekly"
+)
+
+const (
+	DashboardDefaultDays = 30
+	DashboardMaxDays     = 366
+	DashboardTopItems    = 5
+)
@@ -29,4 +29,19 @@ const (
 	<p>Have a nice day,<br>OrenLite Team</p>
 	</div>
 	`
+
+	LowStockSubject         = "Low Stock Alert OrenLite"
+	LowStockContentTemplate = `
+	<h3>Hi, %s</h3>
+	<p>The following products are running low on stock:</p>
+	<table cellpadding="6">
+	<tr><th align="left">Product</th><th align="left">Variant</th><th align="right">Stock</th><th align="right">Threshold</th></tr>
+	%s
+	</table>
+	<br>
+	<p>Restock them soon so buyers can keep ordering.</p>
+	<br>
+	<p>Have a nice day,<br>OrenLite Team</p>
+	`
+	LowStockRowTemplate = `<tr><td>%s</td><td>%s</td><td align="right">%d</td><td align="right">%d</td></tr>`
 )
@@ -0,0 +1,21 @@
+package constant
+
+import "time"
+
+type EventType string
+
+const (
+	OrderCreatedEvent       EventType = "ORDER_CREATED"
+	OrderStatusChangedEvent EventType = "ORDER_STATUS_CHANGED"
+	WalletCreditedEvent     EventType = "WALLET_CREDITED"
+	ProductUpdatedEvent     EventType = "PRODUCT_UPDATED"
+	ReviewPostedEvent       EventType = "REVIEW_POSTED"
+	FlashSaleStartedEvent   EventType = "FLASH_SALE_STARTED"
+)
+
+const (
+	// OutboxRetryBackoff is how long a failed event waits before its first
+	// retry. The wait doubles with every attempt up to OutboxMaxRetryBackoff.
+	OutboxRetryBackoff    = 30 * time.Second
+	OutboxMaxRetryBackoff = time.Hour
+)
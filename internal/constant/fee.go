@@ -0,0 +1,20 @@
+package constant
+
+type FeeKind string
+
+const (
+	ServiceFeeKind    FeeKind = "SERVICE_FEE"
+	CommissionFeeKind FeeKind = "COMMISSION"
+)
+
+type ShopTier string
+
+const (
+	RegularShopTier  ShopTier = "REGULAR"
+	PowerShopTier    ShopTier = "POWER"
+	OfficialShopTier ShopTier = "OFFICIAL"
+)
+
+const (
+	FeeRuleDefaultItems = 20
+)
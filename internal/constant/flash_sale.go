@@ -0,0 +1,12 @@
+package constant
+
+const (
+	FlashSaleStatusScheduled = "SCHEDULED"
+	FlashSaleStatusActive    = "ACTIVE"
+	FlashSaleStatusEnded     = "ENDED"
+	FlashSaleStatusCancelled = "CANCELLED"
+)
+
+const (
+	FlashSaleDefaultItems = 10
+)
@@ -0,0 +1,56 @@
+package constant
+
+import "time"
+
+type (
+	MediaStorageDriver string
+	MediaContentType   struct {
+		MediaType string
+		Extension string
+	}
+	MediaVariant struct {
+		Name         string
+		MaxDimension int
+	}
+)
+
+const (
+	LocalMediaStorageDriver MediaStorageDriver = "local"
+	S3MediaStorageDriver    MediaStorageDriver = "s3"
+
+	MediaUploadFormField  = "file"
+	MediaFilesPath        = "/media/files/"
+	MediaKeyTemplate      = "%d/%s%s"
+	MediaSniffLength      = 512
+	MediaSignatureExpires = "expires"
+	MediaSignatureQuery   = "signature"
+
+	ThumbnailMediaVariant    = "thumbnail"
+	MediumMediaVariant       = "medium"
+	LargeMediaVariant        = "large"
+	MediaVariantKeyTemplate  = "%s_%s%s"
+	MediaVariantContentType  = "image/jpeg"
+	MediaVariantJPEGQuality  = 85
+	MediaOriginalJPEGQuality = 92
+	MediaProcessingBatchSize = 20
+	MediaProcessingAttempts  = 3
+	MediaMaxImagePixels      = 40000000
+	// MediaFetchTimeout is how long downloading media hosted elsewhere may
+	// take.
+	MediaFetchTimeout = 30 * time.Second
+)
+
+var (
+	AllowedMediaContentTypes = map[string]MediaContentType{
+		"image/jpeg": {MediaType: ImageTypeDefault, Extension: JPGImageType},
+		"image/png":  {MediaType: ImageTypeDefault, Extension: PNGImageType},
+		"image/webp": {MediaType: ImageTypeDefault, Extension: WEBPImageType},
+		"video/mp4":  {MediaType: VideoTypeDefault, Extension: MP4VideoType},
+		"video/webm": {MediaType: VideoTypeDefault, Extension: WEBMVideoType},
+	}
+	MediaVariants = []MediaVariant{
+		{Name: ThumbnailMediaVariant, MaxDimension: 200},
+		{Name: MediumMediaVariant, MaxDimension: 600},
+		{Name: LargeMediaVariant, MaxDimension: 1200},
+	}
+)
@@ -0,0 +1,22 @@
+package constant
+
+import "time"
+
+type NotificationType string
+
+const (
+	OrderStatusNotification  NotificationType = "ORDER_STATUS"
+	WalletCreditNotification NotificationType = "WALLET_CREDIT"
+	PromotionNotification    NotificationType = "PROMOTION"
+)
+
+const (
+	NotificationDefaultItems = 20
+
+	// NotificationStreamEvent is the name of the server-sent event a
+	// notification is pushed as.
+	NotificationStreamEvent     = "notification"
+	NotificationStreamBuffer    = 16
+	NotificationStreamHeartbeat = 25 * time.Second
+	NotificationListenRetry     = 5 * time.Second
+)
@@ -0,0 +1,20 @@
+package constant
+
+const (
+	OrderDocumentFormatPDF       = "pdf"
+	OrderDocumentFormatHTML      = "html"
+	OrderDocumentPDFContentType  = "application/pdf"
+	OrderDocumentHTMLContentType = "text/html; charset=utf-8"
+
+	InvoiceFileTemplate      = "invoice-%d.%s"
+	ReceiptFileTemplate      = "receipt-%d.%s"
+	PackingSlipFileTemplate  = "packing-slip-%d.%s"
+	PackingSlipsFileTemplate = "packing-slips-%s.%s"
+
+	// InvoiceNumberTemplate takes the order date, the shop id and the
+	// sequence of the invoice within the shop.
+	InvoiceNumberTemplate   = "INV/%s/%d/%06d"
+	InvoiceNumberDateLayout = "20060102"
+	OrderDocumentDateLayout = "02 Jan 2006 15:04"
+	OrderDocumentLineWidth  = 78
+)
@@ -0,0 +1,16 @@
+package constant
+
+type OrderReturnStatusType string
+
+const (
+	RequestedReturnStatus OrderReturnStatusType = "REQUESTED"
+	RejectedReturnStatus  OrderReturnStatusType = "REJECTED"
+	EscalatedReturnStatus OrderReturnStatusType = "ESCALATED"
+	RefundedReturnStatus  OrderReturnStatusType = "REFUNDED"
+	ClosedReturnStatus    OrderReturnStatusType = "CLOSED"
+)
+
+const (
+	OrderReturnDefaultItems = 10
+	OrderReturnMaxImages    = 5
+)
@@ -1,5 +0,0 @@
-package constant
-
-const (
-	ServicePrice float64 = 0
-)
@@ -0,0 +1,54 @@
+package constant
+
+const (
+	ProductImportFormField     = "file"
+	ProductImportMaxFileSize   = 10 << 20
+	ProductImportMaxRows       = 1000
+	ProductImportListSeparator = "|"
+	ProductImportSheetName     = "Products"
+
+	ProductImportStatusPending    = "PENDING"
+	ProductImportStatusProcessing = "PROCESSING"
+	ProductImportStatusDone       = "DONE"
+	ProductImportStatusFailed     = "FAILED"
+
+	ProductExportFormatCSV      = "csv"
+	ProductExportFormatXLSX     = "xlsx"
+	ProductExportFileTemplate   = "products-%s.%s"
+	ProductExportCSVContentType = "text/csv"
+	ProductExportXLSXType       = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+const (
+	ProductColumnName = iota
+	ProductColumnDescription
+	ProductColumnWeight
+	ProductColumnCategoryLevel1
+	ProductColumnCategoryLevel2
+	ProductColumnCategoryLevel3
+	ProductColumnImageURLs
+	ProductColumnVariantGroup1
+	ProductColumnVariantType1
+	ProductColumnVariantGroup2
+	ProductColumnVariantType2
+	ProductColumnPrice
+	ProductColumnStock
+)
+
+var (
+	ProductImportColumns = []string{
+		"product_name",
+		"description",
+		"weight",
+		"category_level_1",
+		"category_level_2",
+		"category_level_3",
+		"image_urls",
+		"variant_group_1",
+		"variant_type_1",
+		"variant_group_2",
+		"variant_type_2",
+		"price",
+		"stock",
+	}
+)
@@ -0,0 +1,17 @@
+package constant
+
+const (
+	ProductStatusDraft    = "DRAFT"
+	ProductStatusActive   = "ACTIVE"
+	ProductStatusInactive = "INACTIVE"
+	ProductStatusArchived = "ARCHIVED"
+)
+
+var (
+	ProductStatuses = map[string]bool{
+		ProductStatusDraft:    true,
+		ProductStatusActive:   true,
+		ProductStatusInactive: true,
+		ProductStatusArchived: true,
+	}
+)
@@ -4,6 +4,7 @@ import "fmt"
 
 const (
 	ListProductDistrictQueryRegexPattern = `^(([1-9][0-9]*)(,([1-9][0-9]*))*)?$`
+	WaybillNumberRegexPattern            = `^[A-Za-z0-9-]{1,50}$`
 )
 
 var (
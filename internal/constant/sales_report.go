@@ -0,0 +1,51 @@
+package constant
+
+const (
+	SalesReportStatusPending    = "PENDING"
+	SalesReportStatusProcessing = "PROCESSING"
+	SalesReportStatusDone       = "DONE"
+	SalesReportStatusFailed     = "FAILED"
+
+	SalesReportRowOrder = "ORDER"
+	SalesReportRowItem  = "ITEM"
+
+	SalesReportMaxDays       = 366
+	SalesReportSyncMaxOrders = 500
+	SalesReportSheetName     = "Sales"
+	SalesReportFileTemplate  = "sales-%s-%s.%s"
+	SalesReportKeyTemplate   = "reports/%d/%s.%s"
+)
+
+const (
+	SalesReportColumnRowType = iota
+	SalesReportColumnOrderID
+	SalesReportColumnOrderDate
+	SalesReportColumnStatus
+	SalesReportColumnBuyerName
+	SalesReportColumnProductName
+	SalesReportColumnVariantName
+	SalesReportColumnQuantity
+	SalesReportColumnSubtotal
+	SalesReportColumnPromotionAmount
+	SalesReportColumnVoucherAmount
+	SalesReportColumnDeliveryCost
+	SalesReportColumnNetReceived
+)
+
+var (
+	SalesReportColumns = []string{
+		"row_type",
+		"order_id",
+		"order_date",
+		"status",
+		"buyer_name",
+		"product_name",
+		"variant_name",
+		"quantity",
+		"subtotal",
+		"promotion_amount",
+		"voucher_amount",
+		"delivery_cost",
+		"net_received",
+	}
+)
@@ -0,0 +1,22 @@
+package constant
+
+// SearchMaxRating is the highest rating a product can have.
+const SearchMaxRating = 5
+
+type SearchPriceRange struct {
+	Min float64
+	Max float64
+}
+
+var (
+	// SearchPriceRangeFacets is the list of price buckets shown on search
+	// results, a zero Max means the bucket has no upper bound.
+	SearchPriceRangeFacets = []SearchPriceRange{
+		{Min: 0, Max: 50000},
+		{Min: 50000, Max: 100000},
+		{Min: 100000, Max: 500000},
+		{Min: 500000, Max: 1000000},
+		{Min: 1000000},
+	}
+	SearchRatingFacets = []int{4, 3, 2, 1}
+)
@@ -0,0 +1,26 @@
+package constant
+
+type (
+	ShipmentTrackerDriver string
+	ShipmentStatusType    string
+)
+
+const (
+	LocalShipmentTracker      ShipmentTrackerDriver = "local"
+	RajaOngkirShipmentTracker ShipmentTrackerDriver = "rajaongkir"
+)
+
+const (
+	InTransitShipmentStatus ShipmentStatusType = "IN_TRANSIT"
+	DeliveredShipmentStatus ShipmentStatusType = "DELIVERED"
+	UntrackedShipmentStatus ShipmentStatusType = "UNTRACKED"
+)
+
+const (
+	RajaOngkirWaybillPath       = "/waybill"
+	RajaOngkirManifestLayout    = "2006-01-02 15:04"
+	RajaOngkirManifestTimezone  = "Asia/Jakarta"
+	LocalShipmentPickedUpEvent  = "Package picked up by courier"
+	LocalShipmentInTransitEvent = "Package in transit to destination city"
+	LocalShipmentDeliveredEvent = "Package delivered to receiver"
+)
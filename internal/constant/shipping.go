@@ -0,0 +1,16 @@
+package constant
+
+const (
+	RajaOngkirShippingProvider = "rajaongkir"
+	TableRateShippingProvider  = "table"
+)
+
+const (
+	ShippingTableRateDefaultItems = 20
+)
+
+// SameCityCourierServices maps couriers to the service they deliver with
+// when the shop and the buyer are in the same city.
+var SameCityCourierServices = map[string]string{
+	"jne": "CTC",
+}
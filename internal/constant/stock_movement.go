@@ -0,0 +1,13 @@
+package constant
+
+const (
+	StockReasonInitial          = "INITIAL"
+	StockReasonSellerAdjustment = "SELLER_ADJUSTMENT"
+	StockReasonBulkUpdate       = "BULK_UPDATE"
+	StockReasonBulkImport       = "BULK_IMPORT"
+	StockReasonOrderReserve     = "ORDER_RESERVE"
+)
+
+const (
+	StockHistoryDefaultItems = 10
+)
@@ -11,6 +11,12 @@ const (
 	RedisWrongPinTemplate           = "wrong_pin:%d"
 	RedisLockedWalletTemplate       = "locked_wallet:%d"
 	RedisRecommendedProductTemplate = "recommended_product"
+	RedisSellerDashboardTemplate    = "seller_dashboard:%d:%s:%s:%s"
+	RedisShippingRatesTemplate      = "shipping_rates:%s:%d:%d:%d"
+	RedisCheckoutSessionTemplate    = "checkout_session:%s"
+	RedisCheckoutClaimTemplate      = "checkout_session_claim:%s"
+	RedisNotificationChannel        = "notifications"
+	RedisChatChannel                = "chats"
 
 	VerifCodeAlphaNum = `ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789`
 )
@@ -1,7 +1,8 @@
 package constant
 
 const (
-	RajaOngkirProvince     = "https://api.rajaongkir.com/starter/province"
-	RajaOngkirCity         = "https://api.rajaongkir.com/starter/city"
+	RajaOngkirProvincePath = "/province"
+	RajaOngkirCityPath     = "/city"
 	RajaOngkirAPIKeyHeader = "key"
+	RajaOngkirCostPath     = "/cost"
 )
@@ -3,9 +3,17 @@ package constant
 type TransactionTitle string
 
 const (
-	PaymentOrderTitle TransactionTitle = "PAYMENT-ORDER"
-	TopUpTitle        TransactionTitle = "TOPUP"
-	WithdrawTitle     TransactionTitle = "WITHDRAW"
-	TransferTitle     TransactionTitle = "TRANSFER-ORDER"
-	RefundTitle       TransactionTitle = "REFUND-ORDER"
+	PaymentOrderTitle   TransactionTitle = "PAYMENT-ORDER"
+	TopUpTitle          TransactionTitle = "TOPUP"
+	WithdrawTitle       TransactionTitle = "WITHDRAW"
+	TransferTitle       TransactionTitle = "TRANSFER-ORDER"
+	RefundTitle         TransactionTitle = "REFUND-ORDER"
+	VoucherSubsidyTitle TransactionTitle = "VOUCHER-SUBSIDY"
+	ReturnHoldTitle     TransactionTitle = "RETURN-HOLD"
+	ReturnReleaseTitle  TransactionTitle = "RETURN-RELEASE"
+	ReturnRefundTitle   TransactionTitle = "RETURN-REFUND"
+	ServiceFeeTitle     TransactionTitle = "SERVICE-FEE"
+	CommissionTitle     TransactionTitle = "COMMISSION"
+	CommissionBackTitle TransactionTitle = "COMMISSION-REFUND"
+	SubsidyBackTitle    TransactionTitle = "VOUCHER-SUBSIDY-REFUND"
 )
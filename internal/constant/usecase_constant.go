@@ -9,6 +9,8 @@ const (
 	JPGImageType = ".jpg"
 	JPEGImageType = ".jpeg"
 	PNGImageType = ".png"
+	WEBPImageType = ".webp"
+	WEBMVideoType = ".webm"
 	PriceDefault = 99
 	StockDefault = 0
 )
@@ -0,0 +1,16 @@
+package constant
+
+const (
+	VoucherIssuerShop     = "SHOP"
+	VoucherIssuerPlatform = "PLATFORM"
+)
+
+const (
+	VoucherTypePercentage   = "PERCENTAGE"
+	VoucherTypeFixed        = "FIXED"
+	VoucherTypeFreeShipping = "FREE_SHIPPING"
+)
+
+const (
+	VoucherDefaultItems = 10
+)
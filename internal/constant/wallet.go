@@ -6,4 +6,8 @@ const (
 	TempWalletType WalletType = "TEMP"
 	UserWalletType WalletType = "USER"
 	ShopWalletType WalletType = "SHOP"
+
+	// PlatformWalletType holds the service fees and commissions the
+	// platform earns.
+	PlatformWalletType WalletType = "PLATFORM"
 )
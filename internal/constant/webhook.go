@@ -0,0 +1,46 @@
+package constant
+
+import "time"
+
+type WebhookEventType string
+
+const (
+	OrderCreatedWebhook   WebhookEventType = "order.created"
+	OrderCancelledWebhook WebhookEventType = "order.cancelled"
+	OrderReceivedWebhook  WebhookEventType = "order.received"
+	ReviewPostedWebhook   WebhookEventType = "review.posted"
+	PingWebhook           WebhookEventType = "ping"
+)
+
+const (
+	WebhookDeliveryPending   = "PENDING"
+	WebhookDeliverySucceeded = "SUCCEEDED"
+	// WebhookDeliveryDead is a delivery that ran out of attempts. It stays in
+	// the dead-letter list until the seller redelivers it.
+	WebhookDeliveryDead = "DEAD"
+)
+
+const (
+	WebhookDefaultItems    = 20
+	WebhookSecretPrefix    = "whsec_"
+	WebhookSecretBytes     = 24
+	WebhookSignatureHeader = "X-Lil-Oren-Signature"
+	WebhookEventHeader     = "X-Lil-Oren-Event"
+	WebhookDeliveryHeader  = "X-Lil-Oren-Delivery"
+	WebhookUserAgent       = "lil-oren-webhook/1.0"
+	// WebhookSignatureTemplate is the value of WebhookSignatureHeader, the
+	// unix time the request was signed at and the hex HMAC-SHA256 of
+	// "<time>.<body>" with the secret of the endpoint.
+	WebhookSignatureTemplate = "t=%d,v1=%s"
+	// WebhookResponseLimit is how much of the response of an endpoint is
+	// kept in the delivery log.
+	WebhookResponseLimit = 1024
+)
+
+const (
+	// WebhookRetryBackoff is how long a failed delivery waits before its
+	// first retry. The wait doubles with every attempt up to
+	// WebhookMaxRetryBackoff.
+	WebhookRetryBackoff    = time.Minute
+	WebhookMaxRetryBackoff = 6 * time.Hour
+)
@@ -1,20 +1,40 @@
 package dependency
 
-import "github.com/ilyakaznacheev/cleanenv"
+import (
+	"errors"
+
+	"github.com/ilyakaznacheev/cleanenv"
+)
 
 type (
 	Config struct {
-		App          app
-		Rest         rest
-		PostgreDB    postgreDB
-		RedisCache   redisconfig
-		Jwt          jwt
-		ThirdParty   thirdParty
-		EmailSender  emailSender
-		ResetPW      resetPW
-		ChangePW     changePW
-		LockedWallet lockedWallet
-		GOauth       gOauth
+		App             app
+		Rest            rest
+		PostgreDB       postgreDB
+		RedisCache      redisconfig
+		Jwt             jwt
+		ThirdParty      thirdParty
+		EmailSender     emailSender
+		ResetPW         resetPW
+		ChangePW        changePW
+		LockedWallet    lockedWallet
+		GOauth          gOauth
+		MediaStorage    mediaStorage
+		ProductImport   productImport
+		ProductSchedule productSchedule
+		Inventory       inventory
+		FlashSale       flashSale
+		Dashboard       dashboard
+		SalesReport     salesReport
+		Admin           admin
+		OrderReturn     orderReturn
+		Shipment        shipment
+		ShippingRate    shippingRate
+		RegionSync      regionSync
+		CheckoutSession checkoutSession
+		Fee             fee
+		Outbox          outbox
+		Webhook         webhook
 	}
 
 	app struct {
@@ -51,8 +71,15 @@ type (
 	}
 
 	thirdParty struct {
-		RajaOngkirBaseURL string `env:"RO_BASE_URL"`
-		RajaOngkirAPIKey  string `env:"RO_API_KEY"`
+		RajaOngkirBaseURL          string `env:"RO_BASE_URL"`
+		RajaOngkirAPIKey           string `env:"RO_API_KEY"`
+		RajaOngkirTimeout          uint   `env:"RO_TIMEOUT" env-default:"5"`
+		RajaOngkirMaxRetries       uint   `env:"RO_MAX_RETRIES" env-default:"2"`
+		RajaOngkirRetryWait        uint   `env:"RO_RETRY_WAIT" env-default:"200"`
+		RajaOngkirBreakerThreshold uint   `env:"RO_BREAKER_THRESHOLD" env-default:"5"`
+		RajaOngkirBreakerCooldown  uint   `env:"RO_BREAKER_COOLDOWN" env-default:"30"`
+		RajaOngkirCacheExpiration  uint   `env:"RO_CACHE_EXPIRATION" env-default:"60"`
+		RajaOngkirWeightBucket     uint   `env:"RO_WEIGHT_BUCKET" env-default:"100"`
 	}
 
 	emailSender struct {
@@ -79,6 +106,100 @@ type (
 		RedirectURL  string `env:"GOOGLE_OAUTH_REDIRECT_URL"`
 		RedirectFE   string `env:"GOOGLE_REDIRECT_FRONTEND"`
 	}
+
+	mediaStorage struct {
+		Driver              string `env:"MEDIA_STORAGE_DRIVER" env-default:"local"`
+		PublicBaseURL       string `env:"MEDIA_PUBLIC_BASE_URL"`
+		LocalDir            string `env:"MEDIA_LOCAL_DIR" env-default:"./uploads"`
+		S3Endpoint          string `env:"MEDIA_S3_ENDPOINT"`
+		S3Region            string `env:"MEDIA_S3_REGION" env-default:"us-east-1"`
+		S3Bucket            string `env:"MEDIA_S3_BUCKET"`
+		S3AccessKey         string `env:"MEDIA_S3_ACCESS_KEY"`
+		S3SecretKey         string `env:"MEDIA_S3_SECRET_KEY"`
+		SigningSecret       string `env:"MEDIA_SIGNING_SECRET"`
+		SignedURLExpiration uint   `env:"MEDIA_SIGNED_URL_EXPIRATION" env-default:"15"`
+		MaxImageSize        int64  `env:"MEDIA_MAX_IMAGE_SIZE" env-default:"5242880"`
+		MaxVideoSize        int64  `env:"MEDIA_MAX_VIDEO_SIZE" env-default:"52428800"`
+		ProcessingInterval  uint   `env:"MEDIA_PROCESSING_INTERVAL" env-default:"30"`
+	}
+
+	productImport struct {
+		ProcessingInterval uint `env:"PRODUCT_IMPORT_INTERVAL" env-default:"10"`
+		LeaseTime          uint `env:"PRODUCT_IMPORT_LEASE_TIME" env-default:"600"`
+	}
+
+	productSchedule struct {
+		ProcessingInterval uint `env:"PRODUCT_SCHEDULE_INTERVAL" env-default:"60"`
+	}
+
+	inventory struct {
+		LowStockThreshold int64 `env:"LOW_STOCK_THRESHOLD" env-default:"5"`
+		AlertInterval     uint  `env:"LOW_STOCK_ALERT_INTERVAL" env-default:"300"`
+	}
+
+	flashSale struct {
+		ProcessingInterval uint `env:"FLASH_SALE_INTERVAL" env-default:"10"`
+	}
+
+	dashboard struct {
+		CacheExpiration uint `env:"DASHBOARD_CACHE_EXPIRATION" env-default:"5"`
+	}
+
+	salesReport struct {
+		ProcessingInterval uint `env:"SALES_REPORT_INTERVAL" env-default:"10"`
+		LeaseTime          uint `env:"SALES_REPORT_LEASE_TIME" env-default:"600"`
+	}
+
+	admin struct {
+		AccountIDs []int64 `env:"ADMIN_ACCOUNT_IDS" env-separator:","`
+	}
+
+	orderReturn struct {
+		RequestWindow uint `env:"ORDER_RETURN_WINDOW" env-default:"7"`
+	}
+
+	shipment struct {
+		Tracker            string `env:"SHIPMENT_TRACKER" env-default:"local"`
+		ProcessingInterval uint   `env:"SHIPMENT_TRACKING_INTERVAL" env-default:"60"`
+		CheckInterval      uint   `env:"SHIPMENT_CHECK_INTERVAL" env-default:"30"`
+		LocalDeliveryTime  uint   `env:"SHIPMENT_LOCAL_DELIVERY_TIME" env-default:"60"`
+		MaxFailedChecks    uint   `env:"SHIPMENT_MAX_FAILED_CHECKS" env-default:"48"`
+	}
+
+	shippingRate struct {
+		DefaultProvider  string            `env:"SHIPPING_DEFAULT_PROVIDER" env-default:"rajaongkir"`
+		CourierProviders map[string]string `env:"SHIPPING_COURIER_PROVIDERS" env-separator:","`
+	}
+
+	regionSync struct {
+		Interval uint `env:"REGION_SYNC_INTERVAL" env-default:"0"`
+	}
+
+	checkoutSession struct {
+		Expiration uint `env:"CHECKOUT_SESSION_EXPIRATION" env-default:"15"`
+	}
+
+	fee struct {
+		PlatformAccountID int64 `env:"PLATFORM_ACCOUNT_ID"`
+	}
+
+	outbox struct {
+		ProcessingInterval uint `env:"OUTBOX_INTERVAL" env-default:"5"`
+		BatchSize          uint `env:"OUTBOX_BATCH_SIZE" env-default:"100"`
+		MaxAttempts        uint `env:"OUTBOX_MAX_ATTEMPTS" env-default:"10"`
+		LeaseTime          uint `env:"OUTBOX_LEASE_TIME" env-default:"60"`
+	}
+
+	webhook struct {
+		ProcessingInterval uint `env:"WEBHOOK_INTERVAL" env-default:"10"`
+		BatchSize          uint `env:"WEBHOOK_BATCH_SIZE" env-default:"50"`
+		MaxAttempts        uint `env:"WEBHOOK_MAX_ATTEMPTS" env-default:"8"`
+		Timeout            uint `env:"WEBHOOK_TIMEOUT" env-default:"10"`
+		LeaseTime          uint `env:"WEBHOOK_LEASE_TIME" env-default:"60"`
+		MaxEndpoints       uint `env:"WEBHOOK_MAX_ENDPOINTS" env-default:"10"`
+		Workers            uint `env:"WEBHOOK_WORKERS" env-default:"10"`
+		PerEndpoint        uint `env:"WEBHOOK_PER_ENDPOINT" env-default:"2"`
+	}
 )
 
 func NewConfig(logger Logger) (*Config, error) {
@@ -90,7 +211,26 @@ func NewConfig(logger Logger) (*Config, error) {
 		return nil, err
 	}
 
+	if err := config.check(); err != nil {
+		logger.Fatalf("Invalid config: %s", err.Error())
+		return nil, err
+	}
+
 	logger.Infof("Successfully load config", nil)
 
 	return config, err
 }
+
+// check fails on settings that have no safe default, so the server refuses
+// to start instead of failing on the first request that needs them.
+func (c *Config) check() error {
+	if c.Fee.PlatformAccountID <= 0 {
+		return errors.New("PLATFORM_ACCOUNT_ID is required")
+	}
+
+	if c.MediaStorage.SigningSecret == "" {
+		return errors.New("MEDIA_SIGNING_SECRET is required")
+	}
+
+	return nil
+}
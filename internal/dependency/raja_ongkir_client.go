@@ -1,7 +1,29 @@
 package dependency
 
-import "github.com/go-resty/resty/v2"
+import (
+	"net/http"
+	"time"
 
-func NewRajaOngkirClient() *resty.Client {
-	return resty.New()
+	"github.com/go-resty/resty/v2"
+	"github.com/lil-oren/rest/internal/constant"
+)
+
+// NewRajaOngkirClient returns a client for the RajaOngkir API. Every attempt
+// times out after RO_TIMEOUT seconds and attempts failing on the network or
+// on the RajaOngkir side are retried with an exponential backoff.
+func NewRajaOngkirClient(config Config) *resty.Client {
+	retryWait := time.Duration(config.ThirdParty.RajaOngkirRetryWait) * time.Millisecond
+
+	return resty.New().
+		SetBaseURL(config.ThirdParty.RajaOngkirBaseURL).
+		SetHeader(constant.RajaOngkirAPIKeyHeader, config.ThirdParty.RajaOngkirAPIKey).
+		SetTimeout(time.Duration(config.ThirdParty.RajaOngkirTimeout) * time.Second).
+		SetRetryCount(int(config.ThirdParty.RajaOngkirMaxRetries)).
+		SetRetryWaitTime(retryWait).
+		SetRetryMaxWaitTime(retryWait << config.ThirdParty.RajaOngkirMaxRetries).
+		AddRetryCondition(func(res *resty.Response, err error) bool {
+			return err != nil ||
+				res.StatusCode() == http.StatusTooManyRequests ||
+				res.StatusCode() >= http.StatusInternalServerError
+		})
 }
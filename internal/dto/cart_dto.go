@@ -59,6 +59,7 @@ type CartOrderModel struct {
 	CartID           int64           `db:"cart_id"`
 	ProductName      string          `db:"product_name"`
 	ProductID        int64           `db:"product_id"`
+	ProductStatus    string          `db:"product_status"`
 	ImageUrl         string          `db:"image_url"`
 	ProductVariantID int64           `db:"product_variant_id"`
 	BasePrice        decimal.Decimal `db:"base_price"`
@@ -102,9 +103,10 @@ type (
 		IsChecked     bool    `json:"is_checked"`
 	}
 	ProductVariantForCartModel struct {
-		ID       int64 `db:"id"`
-		Stock    int   `db:"stock"`
-		SellerID int64 `db:"seller_id"`
+		ID       int64  `db:"id"`
+		Stock    int    `db:"stock"`
+		SellerID int64  `db:"seller_id"`
+		Status   string `db:"status"`
 	}
 	UpdateQuantityRequestBody struct {
 		Quantity int64 `json:"quantity" validate:"required,gte=1"`
@@ -0,0 +1,167 @@
+package dto
+
+import "time"
+
+type (
+	StartConversationRequestBody struct {
+		ShopID int64 `json:"shop_id" validate:"required,gt=0"`
+	}
+	SendChatMessageRequestBody struct {
+		Type      string `json:"type" validate:"required,oneof=TEXT IMAGE"`
+		Text      string `json:"text" validate:"max=2000"`
+		ImageURL  string `json:"image_url" validate:"omitempty,url"`
+		ProductID int64  `json:"product_id" validate:"omitempty,gt=0"`
+		OrderID   int64  `json:"order_id" validate:"omitempty,gt=0"`
+	}
+	ConversationParams struct {
+		Role string `form:"role" validate:"omitempty,oneof=buyer seller"`
+		Page int    `form:"page" validate:"omitempty,gt=0"`
+	}
+	// ChatMessageParams pages through the history of a conversation from
+	// the newest message back. Cursor is the next_cursor of the previous
+	// page.
+	ChatMessageParams struct {
+		Cursor int64 `form:"cursor" validate:"omitempty,gt=0"`
+		Limit  int   `form:"limit" validate:"omitempty,gt=0,max=100"`
+	}
+)
+
+type (
+	// ConversationModel is a conversation with its participants and last
+	// message.
+	ConversationModel struct {
+		ID                   int64      `db:"id"`
+		BuyerID              int64      `db:"buyer_id"`
+		BuyerName            string     `db:"buyer_name"`
+		BuyerImageURL        *string    `db:"buyer_image_url"`
+		ShopID               int64      `db:"shop_id"`
+		SellerID             int64      `db:"seller_id"`
+		ShopName             string     `db:"shop_name"`
+		BuyerUnreadCount     int        `db:"buyer_unread_count"`
+		SellerUnreadCount    int        `db:"seller_unread_count"`
+		LastMessageID        *int64     `db:"last_message_id"`
+		LastMessageSenderID  *int64     `db:"last_message_sender_id"`
+		LastMessageType      *string    `db:"last_message_type"`
+		LastMessageText      *string    `db:"last_message_text"`
+		LastMessageCreatedAt *time.Time `db:"last_message_created_at"`
+		CreatedAt            time.Time  `db:"created_at"`
+	}
+	// ChatMessageModel is a message with the cards of what it refers to.
+	ChatMessageModel struct {
+		ID                 int64     `db:"id"`
+		ConversationID     int64     `db:"conversation_id"`
+		SenderID           int64     `db:"sender_id"`
+		Type               string    `db:"type"`
+		Text               string    `db:"text"`
+		ImageURL           *string   `db:"image_url"`
+		ProductID          *int64    `db:"product_id"`
+		ProductCode        *string   `db:"product_code"`
+		ProductName        *string   `db:"product_name"`
+		ProductImageURL    *string   `db:"product_image_url"`
+		OrderID            *int64    `db:"order_id"`
+		OrderInvoiceNumber *string   `db:"order_invoice_number"`
+		OrderStatus        *string   `db:"order_status"`
+		CreatedAt          time.Time `db:"created_at"`
+	}
+)
+
+type (
+	ChatParticipantResponse struct {
+		AccountID int64   `json:"account_id"`
+		Name      string  `json:"name"`
+		ImageURL  *string `json:"image_url"`
+	}
+	ChatShopResponse struct {
+		ShopID int64  `json:"shop_id"`
+		Name   string `json:"name"`
+	}
+	ConversationResponse struct {
+		ID          int64                   `json:"id"`
+		Role        string                  `json:"role"`
+		Buyer       ChatParticipantResponse `json:"buyer"`
+		Shop        ChatShopResponse        `json:"shop"`
+		LastMessage *ChatLastMessage        `json:"last_message"`
+		UnreadCount int                     `json:"unread_count"`
+		CreatedAt   time.Time               `json:"created_at"`
+	}
+	ChatLastMessage struct {
+		ID        int64     `json:"id"`
+		SenderID  int64     `json:"sender_id"`
+		Type      string    `json:"type"`
+		Text      string    `json:"text"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+	ConversationListResponse struct {
+		Items       []ConversationResponse `json:"items"`
+		TotalData   int                    `json:"total_data"`
+		TotalPage   int                    `json:"total_page"`
+		CurrentPage int                    `json:"current_page"`
+	}
+	ChatProductCard struct {
+		ProductID   int64  `json:"product_id"`
+		ProductCode string `json:"product_code"`
+		Name        string `json:"name"`
+		ImageURL    string `json:"image_url"`
+	}
+	ChatOrderCard struct {
+		OrderID       int64   `json:"order_id"`
+		InvoiceNumber *string `json:"invoice_number"`
+		Status        string  `json:"status"`
+	}
+	ChatMessageResponse struct {
+		ID             int64            `json:"id"`
+		ConversationID int64            `json:"conversation_id"`
+		SenderID       int64            `json:"sender_id"`
+		Type           string           `json:"type"`
+		Text           string           `json:"text"`
+		ImageURL       *string          `json:"image_url"`
+		Product        *ChatProductCard `json:"product"`
+		Order          *ChatOrderCard   `json:"order"`
+		IsRead         bool             `json:"is_read"`
+		CreatedAt      time.Time        `json:"created_at"`
+	}
+	ChatMessageListResponse struct {
+		Items      []ChatMessageResponse `json:"items"`
+		NextCursor *int64                `json:"next_cursor"`
+	}
+	ChatUnreadResponse struct {
+		Buyer  int `json:"buyer"`
+		Seller int `json:"seller"`
+		Total  int `json:"total"`
+	}
+)
+
+type (
+	// ChatFrame is a frame of a chat WebSocket. The server sends new
+	// messages, read receipts and errors, the client sends messages and
+	// read receipts.
+	ChatFrame struct {
+		Type           string                      `json:"type"`
+		ConversationID int64                       `json:"conversation_id,omitempty"`
+		Message        *ChatMessageResponse        `json:"message,omitempty"`
+		ReaderID       int64                       `json:"reader_id,omitempty"`
+		LastReadID     int64                       `json:"last_read_id,omitempty"`
+		Send           *SendChatMessageRequestBody `json:"send,omitempty"`
+		Error          string                      `json:"error,omitempty"`
+	}
+	// ChatEvent is a frame published to every replica for the streams of
+	// the participants of a conversation.
+	ChatEvent struct {
+		AccountIDs []int64   `json:"account_ids"`
+		Frame      ChatFrame `json:"frame"`
+	}
+)
+
+type (
+	ChatQuickReplyRequestBody struct {
+		Title string `json:"title" validate:"required,max=50"`
+		Body  string `json:"body" validate:"required,max=2000"`
+	}
+	ChatQuickReplyResponse struct {
+		ID        int64     `json:"id"`
+		Title     string    `json:"title"`
+		Body      string    `json:"body"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+)
@@ -1,38 +1,59 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
 type (
 	OrderDelivery struct {
-		ShopID        int64  `json:"shop_id"`
-		ShopCourierID *int64 `json:"shop_courier_id,omitempty"`
-		PromotionID   int64  `json:"promotion_id,omitempty"`
+		ShopID         int64  `json:"shop_id"`
+		ShopCourierID  *int64 `json:"shop_courier_id,omitempty"`
+		CourierService string `json:"courier_service,omitempty"`
+		PromotionID    int64  `json:"promotion_id,omitempty"`
+		VoucherCode    string `json:"voucher_code,omitempty"`
 	}
 	CalculateCheckoutSummaryBodyPayload struct {
-		OrderDeliveries []OrderDelivery `json:"order_deliveries"`
-		BuyerAddressID  int64           `json:"buyer_address_id"`
+		OrderDeliveries     []OrderDelivery `json:"order_deliveries"`
+		BuyerAddressID      int64           `json:"buyer_address_id"`
+		PlatformVoucherCode string          `json:"platform_voucher_code,omitempty"`
 	}
 	CalculateCheckoutSummaryPayload struct {
-		BuyerID         int64
-		OrderDeliveries []OrderDelivery
-		BuyerAddressID  int64
+		BuyerID             int64
+		OrderDeliveries     []OrderDelivery
+		BuyerAddressID      int64
+		PlatformVoucherCode string
 	}
 
 	CalculateCheckoutSummaryOrder struct {
-		ShopID            int64   `json:"shop_id"`
-		SubTotalProduct   float64 `json:"sub_total_product"`
-		SubTotalPromotion float64 `json:"sub_total_promotion"`
-		DeliveryCost      float64 `json:"delivery_cost"`
-		Subtotal          float64 `json:"subtotal"`
+		ShopID            int64                     `json:"shop_id"`
+		SubTotalProduct   float64                   `json:"sub_total_product"`
+		SubTotalPromotion float64                   `json:"sub_total_promotion"`
+		DeliveryCost      float64                   `json:"delivery_cost"`
+		ShopVoucher       float64                   `json:"shop_voucher_discount"`
+		PlatformVoucher   float64                   `json:"platform_voucher_discount"`
+		Subtotal          float64                   `json:"subtotal"`
+		ServiceFee        float64                   `json:"service_fee"`
+		CourierService    string                    `json:"courier_service,omitempty"`
+		DeliveryETD       string                    `json:"delivery_etd,omitempty"`
+		ShippingServices  []ShippingServiceResponse `json:"shipping_services"`
 	}
 	CalculateCheckoutSummaryResponse struct {
 		Orders            []CalculateCheckoutSummaryOrder `json:"orders"`
 		TotalShopPrice    float64                         `json:"total_shop_price"`
 		TotalProduct      int                             `json:"total_product"`
 		TotalDeliveryCost float64                         `json:"total_delivery_cost"`
+		TotalVoucher      float64                         `json:"total_voucher_discount"`
 		ServicePrice      float64                         `json:"service_price"`
 		SummaryPrice      float64                         `json:"summary_price"`
 	}
+	ListCheckoutItemParams struct {
+		BuyerAddressID int64 `form:"buyer_address_id" validate:"omitempty,gt=0"`
+	}
 	ListCheckoutItemPayload struct {
-		UserID int64
+		UserID         int64
+		BuyerAddressID int64
 	}
 	ListCheckoutItem struct {
 		Name        string  `json:"name"`
@@ -48,13 +69,44 @@ type (
 		MinimumSpend float64 `json:"minimum_spend"`
 		IsApplicable bool    `json:"is_applicable"`
 	}
+	CourierDropdownValue struct {
+		Label    string                    `json:"label"`
+		Value    any                       `json:"value"`
+		Services []ShippingServiceResponse `json:"services"`
+	}
 	ListCheckout struct {
-		ShopID            int64               `json:"shop_id"`
-		ShopName          string              `json:"shop_name"`
-		ShopCity          string              `json:"shop_city"`
-		Items             []ListCheckoutItem  `json:"items"`
-		CourierDropdown   []DropdownValue     `json:"courier_dropdown"`
-		PromotionDropdown []PromotionDropdown `json:"promotion_dropdown"`
+		ShopID            int64                  `json:"shop_id"`
+		ShopName          string                 `json:"shop_name"`
+		ShopCity          string                 `json:"shop_city"`
+		Items             []ListCheckoutItem     `json:"items"`
+		CourierDropdown   []CourierDropdownValue `json:"courier_dropdown"`
+		PromotionDropdown []PromotionDropdown    `json:"promotion_dropdown"`
+	}
+	CheckoutSessionItem struct {
+		ProductVariantID int64           `json:"product_variant_id"`
+		Quantity         int             `json:"quantity"`
+		Price            decimal.Decimal `json:"price"`
+	}
+	CheckoutSessionOrder struct {
+		ShopID     int64                 `json:"shop_id"`
+		Items      []CheckoutSessionItem `json:"items"`
+		Subtotal   decimal.Decimal       `json:"subtotal"`
+		ServiceFee decimal.Decimal       `json:"service_fee"`
+	}
+	CheckoutSession struct {
+		ID                  string                           `json:"id"`
+		BuyerID             int64                            `json:"buyer_id"`
+		BuyerAddressID      int64                            `json:"buyer_address_id"`
+		PlatformVoucherCode string                           `json:"platform_voucher_code"`
+		OrderDeliveries     []OrderDelivery                  `json:"order_deliveries"`
+		Orders              []CheckoutSessionOrder           `json:"orders"`
+		Summary             CalculateCheckoutSummaryResponse `json:"summary"`
+		ExpiresAt           time.Time                        `json:"expires_at"`
+	}
+	CheckoutSessionResponse struct {
+		ID        string                           `json:"id"`
+		ExpiresAt time.Time                        `json:"expires_at"`
+		Summary   CalculateCheckoutSummaryResponse `json:"summary"`
 	}
 	ListCheckoutItemResponse struct {
 		Checkouts         []ListCheckout `json:"checkouts"`
@@ -0,0 +1,88 @@
+package dto
+
+type (
+	DashboardParams struct {
+		StartDate string `form:"start_date" validate:"omitempty,datetime=2006-01-02"`
+		EndDate   string `form:"end_date" validate:"omitempty,datetime=2006-01-02"`
+		Bucket    string `form:"bucket" validate:"omitempty,oneof=daily weekly"`
+	}
+	DashboardPayload struct {
+		SellerID  int64
+		StartDate string
+		EndDate   string
+		Bucket    string
+	}
+)
+
+type (
+	DashboardSummaryModel struct {
+		Orders          int     `db:"orders"`
+		CancelledOrders int     `db:"cancelled_orders"`
+		Revenue         float64 `db:"revenue"`
+		UnitsSold       int     `db:"units_sold"`
+	}
+	DashboardEngagementModel struct {
+		ProductViews int `db:"product_views"`
+		AddToCarts   int `db:"add_to_carts"`
+	}
+	DashboardRatingModel struct {
+		AverageRating float64 `db:"average_rating"`
+		ReviewCount   int     `db:"review_count"`
+	}
+	DashboardBucketModel struct {
+		BucketStart string  `db:"bucket_start"`
+		Orders      int     `db:"orders"`
+		Revenue     float64 `db:"revenue"`
+		UnitsSold   int     `db:"units_sold"`
+	}
+	DashboardTopProductModel struct {
+		ProductCode string  `db:"product_code"`
+		ProductName string  `db:"product_name"`
+		UnitsSold   int     `db:"units_sold"`
+		Revenue     float64 `db:"revenue"`
+	}
+	DashboardTopCategoryModel struct {
+		CategoryID   int64   `db:"category_id"`
+		CategoryName string  `db:"category_name"`
+		UnitsSold    int     `db:"units_sold"`
+		Revenue      float64 `db:"revenue"`
+	}
+)
+
+type (
+	DashboardBucketResponse struct {
+		BucketStart string  `json:"bucket_start"`
+		Orders      int     `json:"orders"`
+		Revenue     float64 `json:"revenue"`
+		UnitsSold   int     `json:"units_sold"`
+	}
+	DashboardTopProductResponse struct {
+		ProductCode string  `json:"product_code"`
+		ProductName string  `json:"product_name"`
+		UnitsSold   int     `json:"units_sold"`
+		Revenue     float64 `json:"revenue"`
+	}
+	DashboardTopCategoryResponse struct {
+		CategoryID   int64   `json:"category_id"`
+		CategoryName string  `json:"category_name"`
+		UnitsSold    int     `json:"units_sold"`
+		Revenue      float64 `json:"revenue"`
+	}
+	SellerDashboardResponse struct {
+		StartDate        string                         `json:"start_date"`
+		EndDate          string                         `json:"end_date"`
+		Bucket           string                         `json:"bucket"`
+		Revenue          float64                        `json:"revenue"`
+		Orders           int                            `json:"orders"`
+		UnitsSold        int                            `json:"units_sold"`
+		ProductViews     int                            `json:"product_views"`
+		AddToCarts       int                            `json:"add_to_carts"`
+		ConversionRate   float64                        `json:"conversion_rate"`
+		AverageRating    float64                        `json:"average_rating"`
+		ReviewCount      int                            `json:"review_count"`
+		CancellationRate float64                        `json:"cancellation_rate"`
+		Series           []DashboardBucketResponse      `json:"series"`
+		TopProducts      []DashboardTopProductResponse  `json:"top_products"`
+		TopCategories    []DashboardTopCategoryResponse `json:"top_categories"`
+	}
+)
@@ -10,6 +10,8 @@ type (
 		CategoryID  int64  `validate:"omitempty,numeric"`
 		MinPrice    float64
 		MaxPrice    float64
+		MinRating   float64 `validate:"omitempty,gte=0,lte=5"`
+		InStock     bool
 	}
 	CountProductBySearchTermPayload struct {
 		SearchTerm  string `validate:"omitempty"`
@@ -17,18 +19,23 @@ type (
 		CategoryID  int64  `validate:"omitempty,numeric"`
 		MinPrice    float64
 		MaxPrice    float64
+		MinRating   float64 `validate:"omitempty,gte=0,lte=5"`
+		InStock     bool
 	}
 	SearchProductResponseItem struct {
-		ProductCode   string  `json:"product_code" db:"product_code"`
-		ProductName   string  `json:"name" db:"product_name"`
-		ThumbnailURL  string  `json:"image_url" db:"thumbnail_url"`
-		BasePrice     float64 `json:"price" db:"base_price"`
-		DiscountPrice float64 `json:"discounted_price" db:"discount_price"`
-		Discount      float32 `json:"discount" db:"discount"`
-		ShopName      string  `json:"shop_name" db:"shop_name"`
-		DistrictName  string  `json:"shop_location" db:"district_name"`
-		TotalSold     int64   `json:"total_sold" db:"total_sold"`
-		Rating        float64 `json:"rating"`
+		ProductCode       string  `json:"product_code" db:"product_code"`
+		ProductName       string  `json:"name" db:"product_name"`
+		ThumbnailURL      string  `json:"image_url" db:"thumbnail_url"`
+		ImageThumbnailURL string  `json:"image_thumbnail_url,omitempty" db:"image_thumbnail_url"`
+		ImageMediumURL    string  `json:"image_medium_url,omitempty" db:"image_medium_url"`
+		ImageLargeURL     string  `json:"image_large_url,omitempty" db:"image_large_url"`
+		BasePrice         float64 `json:"price" db:"base_price"`
+		DiscountPrice     float64 `json:"discounted_price" db:"discount_price"`
+		Discount          float32 `json:"discount" db:"discount"`
+		ShopName          string  `json:"shop_name" db:"shop_name"`
+		DistrictName      string  `json:"shop_location" db:"district_name"`
+		TotalSold         int64   `json:"total_sold" db:"total_sold"`
+		Rating            float64 `json:"rating"`
 	}
 	SearchProductResponse struct {
 		Products     []SearchProductResponseItem `json:"products"`
@@ -36,5 +43,28 @@ type (
 		TotalPage    int                         `json:"total_page"`
 		TotalProduct int                         `json:"total_product"`
 		SearchTerm   string                      `json:"search"`
+		Facets       *SearchProductFacets        `json:"facets"`
+	}
+	SearchFacetItem struct {
+		ID    int64  `json:"id" db:"id"`
+		Name  string `json:"name" db:"name"`
+		Count int    `json:"count" db:"count"`
+	}
+	SearchPriceRangeFacet struct {
+		MinPrice float64 `json:"min_price"`
+		MaxPrice float64 `json:"max_price,omitempty"`
+		Count    int     `json:"count"`
+	}
+	SearchRatingFacet struct {
+		MinRating int `json:"min_rating"`
+		Count     int `json:"count"`
+	}
+	SearchProductFacets struct {
+		Categories   []SearchFacetItem       `json:"categories"`
+		Districts    []SearchFacetItem       `json:"districts"`
+		PriceRanges  []SearchPriceRangeFacet `json:"price_ranges"`
+		Ratings      []SearchRatingFacet     `json:"ratings"`
+		Discounted   int                     `json:"discounted"`
+		FreeShipping int                     `json:"free_shipping"`
 	}
 )
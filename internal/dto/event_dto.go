@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"encoding/json"
+
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/shopspring/decimal"
+)
+
+type (
+	// DomainEvent is an event taken from the outbox to be handed to its
+	// subscribers. Payload holds one of the event payloads below.
+	DomainEvent struct {
+		ID          int64
+		Type        constant.EventType
+		AggregateID int64
+		Attempt     int
+		Payload     json.RawMessage
+	}
+
+	// OrderEventPayload is the payload of ORDER_CREATED and
+	// ORDER_STATUS_CHANGED events, with the status the order is now in.
+	OrderEventPayload struct {
+		OrderID  int64  `json:"order_id"`
+		BuyerID  int64  `json:"buyer_id"`
+		SellerID int64  `json:"seller_id"`
+		Status   string `json:"status"`
+	}
+	WalletCreditedPayload struct {
+		TransactionID int64           `json:"transaction_id"`
+		WalletID      int64           `json:"wallet_id"`
+		WalletType    string          `json:"wallet_type"`
+		AccountID     int64           `json:"account_id"`
+		Amount        decimal.Decimal `json:"amount"`
+		Title         string          `json:"title"`
+	}
+	ProductUpdatedPayload struct {
+		ProductID int64 `json:"product_id" db:"id"`
+		SellerID  int64 `json:"seller_id" db:"seller_id"`
+	}
+	ReviewPostedPayload struct {
+		ReviewID    int64  `json:"review_id"`
+		ProductID   int64  `json:"product_id" db:"product_id"`
+		ProductCode string `json:"product_code"`
+		SellerID    int64  `json:"seller_id" db:"seller_id"`
+		AccountID   int64  `json:"account_id"`
+		Rating      int    `json:"rating"`
+		Comment     string `json:"comment"`
+	}
+	// FlashSaleStartedPayload is the payload of FLASH_SALE_STARTED events.
+	// WisherIDs are the buyers with a product of the flash sale in their
+	// wishlist when it started.
+	FlashSaleStartedPayload struct {
+		FlashSaleID int64   `json:"flash_sale_id" db:"id"`
+		SellerID    int64   `json:"seller_id" db:"seller_id"`
+		Name        string  `json:"name" db:"name"`
+		WisherIDs   []int64 `json:"wisher_ids"`
+	}
+)
@@ -0,0 +1,53 @@
+package dto
+
+import "github.com/shopspring/decimal"
+
+type (
+	FeeRuleRequestBody struct {
+		Kind       string  `json:"kind" validate:"required,oneof=SERVICE_FEE COMMISSION"`
+		CategoryID *int64  `json:"category_id" validate:"omitempty,gt=0"`
+		ShopTier   string  `json:"shop_tier" validate:"omitempty,oneof=REGULAR POWER OFFICIAL"`
+		Rate       float64 `json:"rate" validate:"gte=0,lte=100"`
+		FlatAmount float64 `json:"flat_amount" validate:"gte=0"`
+	}
+	FeeRuleParams struct {
+		Kind string `form:"kind" validate:"omitempty,oneof=SERVICE_FEE COMMISSION"`
+		Page int    `form:"page" validate:"omitempty,gt=0"`
+	}
+	FeeRuleResponse struct {
+		ID         int64   `json:"id"`
+		Kind       string  `json:"kind"`
+		CategoryID *int64  `json:"category_id"`
+		ShopTier   string  `json:"shop_tier,omitempty"`
+		Rate       float64 `json:"rate"`
+		FlatAmount float64 `json:"flat_amount"`
+	}
+	FeeRuleListResponse struct {
+		Items       []FeeRuleResponse `json:"items"`
+		TotalData   int               `json:"total_data"`
+		TotalPage   int               `json:"total_page"`
+		CurrentPage int               `json:"current_page"`
+	}
+
+	ShopTierRequestBody struct {
+		Tier string `json:"tier" validate:"required,oneof=REGULAR POWER OFFICIAL"`
+	}
+	PlatformWalletResponse struct {
+		Balance float64 `json:"balance"`
+	}
+
+	// ProductCategoryLevel is a category of a product, used to find the fee
+	// rules of the product.
+	ProductCategoryLevel struct {
+		ProductID  int64 `db:"product_id"`
+		CategoryID int64 `db:"category_id"`
+		Level      uint8 `db:"level"`
+	}
+
+	// OrderFee is what the platform takes from an order: the service fee the
+	// buyer pays on top of it and the commission kept from the seller.
+	OrderFee struct {
+		ServiceFee decimal.Decimal
+		Commission decimal.Decimal
+	}
+)
@@ -0,0 +1,84 @@
+package dto
+
+import "time"
+
+type (
+	CreateFlashSaleRequestBody struct {
+		Name     string                    `json:"name" validate:"required"`
+		StartAt  time.Time                 `json:"start_at" validate:"required"`
+		EndAt    time.Time                 `json:"end_at" validate:"required"`
+		Variants []FlashSaleVariantRequest `json:"variants" validate:"required,min=1,max=100,dive"`
+	}
+	FlashSaleVariantRequest struct {
+		ProductVariantID int64   `json:"product_variant_id" validate:"required"`
+		Discount         float64 `json:"discount" validate:"omitempty,gt=0,lt=100"`
+		DiscountedPrice  float64 `json:"discounted_price" validate:"omitempty,gt=0"`
+		PurchaseLimit    int     `json:"purchase_limit" validate:"omitempty,gt=0"`
+		Quota            int     `json:"quota" validate:"omitempty,gt=0"`
+	}
+	CreateFlashSalePayload struct {
+		SellerID int64
+		Name     string
+		StartAt  time.Time
+		EndAt    time.Time
+		Variants []FlashSaleVariantRequest
+	}
+)
+
+type (
+	FlashSaleParams struct {
+		Page int `form:"page" validate:"omitempty,gt=0"`
+	}
+	FlashSaleVariantModel struct {
+		FlashSaleID      int64    `db:"flash_sale_id"`
+		ProductVariantID int64    `db:"product_variant_id"`
+		ProductCode      string   `db:"product_code"`
+		ProductName      string   `db:"product_name"`
+		VariantType1     string   `db:"variant_type1_name"`
+		VariantType2     string   `db:"variant_type2_name"`
+		Price            float64  `db:"price"`
+		Discount         *float64 `db:"discount"`
+		DiscountedPrice  *float64 `db:"discounted_price"`
+		PurchaseLimit    int      `db:"purchase_limit"`
+		Quota            int      `db:"quota"`
+		Sold             int      `db:"sold"`
+		Status           string   `db:"status"`
+	}
+	FlashSaleVariantResponse struct {
+		ProductVariantID int64    `json:"product_variant_id"`
+		ProductCode      string   `json:"product_code"`
+		ProductName      string   `json:"product_name"`
+		VariantType1     string   `json:"variant_type1"`
+		VariantType2     string   `json:"variant_type2"`
+		Price            float64  `json:"price"`
+		Discount         *float64 `json:"discount,omitempty"`
+		DiscountedPrice  *float64 `json:"discounted_price,omitempty"`
+		PurchaseLimit    int      `json:"purchase_limit"`
+		Quota            int      `json:"quota"`
+		Sold             int      `json:"sold"`
+		Status           string   `json:"status"`
+	}
+	FlashSaleResponse struct {
+		ID       int64                      `json:"id"`
+		Name     string                     `json:"name"`
+		StartAt  time.Time                  `json:"start_at"`
+		EndAt    time.Time                  `json:"end_at"`
+		Variants []FlashSaleVariantResponse `json:"variants"`
+	}
+	FlashSaleListResponse struct {
+		Items       []FlashSaleResponse `json:"items"`
+		TotalData   int                 `json:"total_data"`
+		TotalPage   int                 `json:"total_page"`
+		CurrentPage int                 `json:"current_page"`
+	}
+)
+
+type (
+	ActiveFlashSaleVariant struct {
+		ProductVariantID int64     `db:"product_variant_id"`
+		EndAt            time.Time `db:"end_at"`
+		PurchaseLimit    int       `db:"purchase_limit"`
+		Quota            int       `db:"quota"`
+		Sold             int       `db:"sold"`
+	}
+)
@@ -0,0 +1,83 @@
+package dto
+
+type (
+	StockHistoryPayload struct {
+		ProductCode string
+		SellerID    int64
+		Page        int
+	}
+	StockMovementModel struct {
+		ID           int64  `db:"id"`
+		VariantType1 string `db:"variant_type1_name"`
+		VariantType2 string `db:"variant_type2_name"`
+		Quantity     int64  `db:"quantity"`
+		StockAfter   int64  `db:"stock_after"`
+		Reason       string `db:"reason"`
+		ActorName    string `db:"actor_name"`
+		OrderID      *int64 `db:"order_id"`
+		CreatedAt    string `db:"created_at"`
+	}
+	StockMovementResponse struct {
+		ID           int64  `json:"id"`
+		VariantType1 string `json:"variant_type1"`
+		VariantType2 string `json:"variant_type2"`
+		Quantity     int64  `json:"quantity"`
+		StockAfter   int64  `json:"stock_after"`
+		Reason       string `json:"reason"`
+		ActorName    string `json:"actor_name,omitempty"`
+		OrderID      *int64 `json:"order_id,omitempty"`
+		CreatedAt    string `json:"created_at"`
+	}
+	StockHistoryResponseBody struct {
+		Movements  []StockMovementResponse `json:"movements"`
+		Pagination StockHistoryPagination  `json:"pagination"`
+	}
+	StockHistoryPagination struct {
+		Page       int `json:"page"`
+		TotalPage  int `json:"total_page"`
+		TotalItems int `json:"total_items"`
+	}
+)
+
+type (
+	BulkUpdateStockRequestBody struct {
+		Items []StockUpdateItem `json:"items" validate:"required,min=1,max=100,dive"`
+	}
+	StockUpdateItem struct {
+		ProductVariantID int64 `json:"product_variant_id" validate:"required"`
+		Stock            int64 `json:"stock" validate:"min=0"`
+	}
+	BulkUpdateStockPayload struct {
+		SellerID int64
+		Items    []StockUpdateItem
+	}
+	BulkUpdateStockResponse struct {
+		UpdatedItems int `json:"updated_items"`
+	}
+)
+
+type (
+	UpdateLowStockThresholdRequestBody struct {
+		Threshold *int64 `json:"threshold" validate:"omitempty,min=0"`
+	}
+	UpdateLowStockThresholdPayload struct {
+		ProductCode string
+		SellerID    int64
+		Threshold   *int64
+	}
+)
+
+type (
+	LowStockVariant struct {
+		ProductVariantID int64  `db:"product_variant_id"`
+		ProductCode      string `db:"product_code"`
+		ProductName      string `db:"product_name"`
+		VariantType1     string `db:"variant_type1_name"`
+		VariantType2     string `db:"variant_type2_name"`
+		Stock            int64  `db:"stock"`
+		Threshold        int64  `db:"threshold"`
+		SellerID         int64  `db:"seller_id"`
+		SellerName       string `db:"seller_name"`
+		SellerEmail      string `db:"seller_email"`
+	}
+)
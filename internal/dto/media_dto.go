@@ -0,0 +1,34 @@
+package dto
+
+import "io"
+
+type (
+	UploadMediaPayload struct {
+		AccountID int64
+		FileName  string
+		Size      int64
+		File      io.Reader
+	}
+	UploadMediaResponse struct {
+		ID          int64  `json:"id"`
+		MediaURL    string `json:"media_url"`
+		SignedURL   string `json:"signed_url"`
+		MediaType   string `json:"media_type"`
+		ContentType string `json:"content_type"`
+		Size        int64  `json:"size"`
+	}
+	MediaSignedURLResponse struct {
+		SignedURL string `json:"signed_url"`
+		ExpiredAt string `json:"expired_at"`
+	}
+	OpenMediaPayload struct {
+		StorageKey string
+		Expires    string
+		Signature  string
+	}
+	OpenMediaResponse struct {
+		ContentType string
+		Size        int64
+		Body        io.ReadCloser
+	}
+)
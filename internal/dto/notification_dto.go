@@ -0,0 +1,44 @@
+package dto
+
+import "github.com/lil-oren/rest/internal/constant"
+
+type (
+	NotificationParams struct {
+		Page   int  `form:"page" validate:"omitempty,gt=0"`
+		Unread bool `form:"unread"`
+	}
+	NotificationResponse struct {
+		ID          int64  `json:"id"`
+		Type        string `json:"type"`
+		Title       string `json:"title"`
+		Message     string `json:"message"`
+		ReferenceID *int64 `json:"reference_id"`
+		IsRead      bool   `json:"is_read"`
+		CreatedAt   string `json:"created_at"`
+	}
+	NotificationListResponse struct {
+		Items       []NotificationResponse `json:"items"`
+		UnreadCount int                    `json:"unread_count"`
+		TotalData   int                    `json:"total_data"`
+		TotalPage   int                    `json:"total_page"`
+		CurrentPage int                    `json:"current_page"`
+	}
+	UnreadNotificationResponse struct {
+		UnreadCount int `json:"unread_count"`
+	}
+
+	// NotificationEvent is a notification as it is published to every
+	// replica, which pushes it to the streams of the account it is for.
+	NotificationEvent struct {
+		AccountID    int64                `json:"account_id"`
+		Notification NotificationResponse `json:"notification"`
+	}
+	NotifyPayload struct {
+		EventID     int64
+		AccountID   int64
+		Type        constant.NotificationType
+		Title       string
+		Message     string
+		ReferenceID int64
+	}
+)
@@ -0,0 +1,93 @@
+package dto
+
+import (
+	"bytes"
+	"database/sql"
+
+	"github.com/shopspring/decimal"
+)
+
+type (
+	OrderDocumentParams struct {
+		Format string `form:"format" validate:"omitempty,oneof=pdf html"`
+	}
+	PackingSlipsRequestBody struct {
+		OrderIDs []int64 `json:"order_ids" validate:"required,min=1,max=50,unique,dive,gt=0"`
+	}
+	OrderDocumentResponse struct {
+		FileName    string
+		ContentType string
+		Body        *bytes.Buffer
+	}
+)
+
+type (
+	OrderDocumentModel struct {
+		ID                  int64           `db:"id"`
+		Status              string          `db:"status"`
+		InvoiceNumber       sql.NullString  `db:"invoice_number"`
+		CreatedAt           sql.NullTime    `db:"created_at"`
+		SellerID            int64           `db:"seller_id"`
+		BuyerID             int64           `db:"buyer_id"`
+		DeliveryCost        decimal.Decimal `db:"delivery_cost"`
+		PromotionName       sql.NullString  `db:"promotion_name"`
+		PromotionAmount     sql.NullFloat64 `db:"promotion_amount"`
+		VoucherAmount       sql.NullFloat64 `db:"voucher_amount"`
+		ServiceFee          decimal.Decimal `db:"service_fee"`
+		PaymentAmount       decimal.Decimal `db:"payment_amount"`
+		ShopName            string          `db:"shop_name"`
+		BuyerUsername       string          `db:"buyer_username"`
+		BuyerEmail          string          `db:"buyer_email"`
+		CourierName         string          `db:"courier_name"`
+		CourierService      sql.NullString  `db:"courier_service"`
+		WaybillNumber       sql.NullString  `db:"waybill_number"`
+		ReceiverName        sql.NullString  `db:"receiver_name"`
+		ReceiverPhoneNumber sql.NullString  `db:"receiver_phone_number"`
+		ReceiverAddress     sql.NullString  `db:"receiver_address"`
+		ReceiverDistrict    sql.NullString  `db:"receiver_district"`
+		ReceiverProvince    sql.NullString  `db:"receiver_province"`
+		ReceiverPostalCode  sql.NullString  `db:"receiver_postal_code"`
+		SenderName          sql.NullString  `db:"sender_name"`
+		SenderPhoneNumber   sql.NullString  `db:"sender_phone_number"`
+		SenderAddress       sql.NullString  `db:"sender_address"`
+		SenderDistrict      sql.NullString  `db:"sender_district"`
+		SenderProvince      sql.NullString  `db:"sender_province"`
+		SenderPostalCode    sql.NullString  `db:"sender_postal_code"`
+	}
+	OrderDocumentItemModel struct {
+		OrderID       int64           `db:"order_id"`
+		ProductCode   string          `db:"product_code"`
+		ProductName   string          `db:"product_name"`
+		VariantName   string          `db:"variant_name"`
+		SubTotalPrice decimal.Decimal `db:"sub_total_price"`
+		Quantity      int             `db:"quantity"`
+		Weight        sql.NullInt64   `db:"weight"`
+	}
+)
+
+// OrderDocument is what an invoice, a receipt or a packing slip shows,
+// independent of the format it is rendered in.
+type (
+	OrderDocument struct {
+		Title     string
+		Fields    []OrderDocumentField
+		Parties   []OrderDocumentParty
+		Items     []OrderDocumentItem
+		Totals    []OrderDocumentField
+		ShowPrice bool
+	}
+	OrderDocumentField struct {
+		Label string
+		Value string
+	}
+	OrderDocumentParty struct {
+		Heading string
+		Lines   []string
+	}
+	OrderDocumentItem struct {
+		Name     string
+		Variant  string
+		Quantity int
+		Amount   string
+	}
+)
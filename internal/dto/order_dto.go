@@ -9,17 +9,19 @@ import (
 
 type (
 	CreateOrderRequestBody struct {
-		Orders         []OrderPayload `json:"order_deliveries"`
-		BuyerAddressId int            `json:"buyer_address_id"`
+		CheckoutSessionID string `json:"checkout_session_id" validate:"required,uuid"`
 	}
 	CreateOrderRequestPayload struct {
-		Orders         []OrderPayload
-		BuyerAddressId int
+		Orders              []OrderPayload
+		BuyerAddressId      int
+		PlatformVoucherCode string
 	}
 	OrderPayload struct {
-		ShopId      int `json:"shop_id"`
-		CourierId   int `json:"shop_courier_id"`
-		PromotionId int `json:"promotion_id"`
+		ShopId         int    `json:"shop_id"`
+		CourierId      int    `json:"shop_courier_id"`
+		CourierService string `json:"courier_service,omitempty"`
+		PromotionId    int    `json:"promotion_id"`
+		VoucherCode    string `json:"voucher_code,omitempty"`
 	}
 )
 
@@ -41,6 +43,7 @@ type (
 		ETA                 sql.NullTime    `db:"estimated_time_arrival"`
 		ShopName            string          `db:"shop_name"`
 		PromotionAmount     sql.NullFloat64 `db:"promotion_amount"`
+		VoucherAmount       sql.NullFloat64 `db:"voucher_amount"`
 	}
 	OrderBuyerResponse struct {
 		Orders     []OrderList `json:"order"`
@@ -87,6 +90,7 @@ type CreateOrderModel struct {
 	ProductCode      string          `db:"product_code"`
 	ProductName      string          `db:"product_name"`
 	ProductID        int64           `db:"product_id"`
+	ProductStatus    string          `db:"product_status"`
 	ImageUrl         string          `db:"image_url"`
 	ProductVariantID int64           `db:"product_variant_id"`
 	BasePrice        decimal.Decimal `db:"base_price"`
@@ -0,0 +1,88 @@
+package dto
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+type (
+	OrderReturnItemRequest struct {
+		OrderDetailID int64 `json:"order_detail_id" validate:"required,gt=0"`
+		Quantity      int   `json:"quantity" validate:"required,gt=0"`
+	}
+	CreateOrderReturnRequestBody struct {
+		Reason    string                   `json:"reason" validate:"required,max=500"`
+		ImageUrls []string                 `json:"image_urls" validate:"omitempty,max=5,dive,url"`
+		Items     []OrderReturnItemRequest `json:"items" validate:"omitempty,max=100,dive"`
+	}
+	CreateOrderReturnPayload struct {
+		OrderID   int64
+		BuyerID   int64
+		Reason    string
+		ImageUrls []string
+		Items     []OrderReturnItemRequest
+	}
+	OrderReturnNoteRequestBody struct {
+		Note string `json:"note" validate:"omitempty,max=500"`
+	}
+	RejectOrderReturnRequestBody struct {
+		Note string `json:"note" validate:"required,max=500"`
+	}
+	EscalateOrderReturnRequestBody struct {
+		Reason string `json:"reason" validate:"required,max=500"`
+	}
+	OrderReturnParams struct {
+		Status string `form:"status" validate:"omitempty,oneof=REQUESTED REJECTED ESCALATED REFUNDED CLOSED"`
+		Page   int    `form:"page" validate:"omitempty,gt=0"`
+	}
+	OrderReturnFilter struct {
+		BuyerID  int64
+		SellerID int64
+		Status   string
+		Page     int
+	}
+)
+
+type (
+	OrderReturnItemModel struct {
+		OrderReturnID int64           `db:"order_return_id"`
+		OrderDetailID int64           `db:"order_detail_id"`
+		ProductName   string          `db:"product_name"`
+		VariantName   string          `db:"variant_name"`
+		Quantity      int             `db:"quantity"`
+		Amount        decimal.Decimal `db:"amount"`
+	}
+	OrderReturnImageModel struct {
+		OrderReturnID int64  `db:"order_return_id"`
+		ImageUrl      string `db:"image_url"`
+	}
+)
+
+type (
+	OrderReturnItemResponse struct {
+		OrderDetailID int64   `json:"order_detail_id"`
+		ProductName   string  `json:"product_name"`
+		VariantName   string  `json:"variant_name"`
+		Quantity      int     `json:"quantity"`
+		Amount        float64 `json:"amount"`
+	}
+	OrderReturnResponse struct {
+		ID               int64                     `json:"id"`
+		OrderID          int64                     `json:"order_id"`
+		Reason           string                    `json:"reason"`
+		Status           string                    `json:"status"`
+		Amount           float64                   `json:"amount"`
+		ImageUrls        []string                  `json:"image_urls"`
+		Items            []OrderReturnItemResponse `json:"items"`
+		SellerNote       string                    `json:"seller_note,omitempty"`
+		EscalationReason string                    `json:"escalation_reason,omitempty"`
+		AdminNote        string                    `json:"admin_note,omitempty"`
+		CreatedAt        string                    `json:"created_at"`
+		ResolvedAt       string                    `json:"resolved_at,omitempty"`
+	}
+	OrderReturnListResponse struct {
+		Items       []OrderReturnResponse `json:"items"`
+		TotalData   int                   `json:"total_data"`
+		TotalPage   int                   `json:"total_page"`
+		CurrentPage int                   `json:"current_page"`
+	}
+)
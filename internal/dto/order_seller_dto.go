@@ -9,8 +9,9 @@ import (
 
 type (
 	OrderSellerStatusRequest struct {
-		NewStatus constant.OrderStatusType
-		EstDays   int `json:"est_days" validate:"required,gte=1,lte=3"`
+		NewStatus     constant.OrderStatusType
+		EstDays       int    `json:"est_days" validate:"required,gte=1,lte=3"`
+		WaybillNumber string `json:"waybill_number" validate:"required,max=50"`
 	}
 	OrderSellerModel struct {
 		ID                  int64           `db:"id"`
@@ -26,7 +27,9 @@ type (
 		Address             string          `db:"address_detail"`
 		CourierName         string          `db:"courier_name"`
 		ETA                 sql.NullTime    `db:"estimated_time_arrival"`
+		QuotedETA           sql.NullTime    `db:"quoted_time_arrival"`
 		PromotionAmount     sql.NullFloat64 `db:"promotion_amount"`
+		Commission          decimal.Decimal `db:"commission_amount"`
 	}
 	OrderSellerResponse struct {
 		OrdersData []OrderSellerData `json:"order_data"`
@@ -42,9 +45,12 @@ type (
 		Address              string                `json:"address_detail"`
 		CourierName          string                `json:"courier_name"`
 		ETA                  string                `json:"eta"`
+		IsLate               bool                  `json:"is_late"`
 		TotalBeforePromotion float64               `json:"total_before_promotion"`
 		PromotionAmount      float64               `json:"promotion_amount"`
 		TotalPrice           float64               `json:"total_price"`
+		Commission           float64               `json:"commission"`
+		Earnings             float64               `json:"earnings"`
 	}
 )
 
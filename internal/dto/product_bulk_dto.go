@@ -0,0 +1,62 @@
+package dto
+
+import (
+	"bytes"
+	"database/sql"
+	"io"
+
+	"github.com/shopspring/decimal"
+)
+
+type (
+	ImportProductPayload struct {
+		AccountID int64
+		FileName  string
+		File      io.Reader
+	}
+	ProductImportItem struct {
+		Rows    []int             `json:"rows"`
+		Payload AddProductPayload `json:"payload"`
+	}
+	ProductImportError struct {
+		Row     int    `json:"row"`
+		Column  string `json:"column,omitempty"`
+		Message string `json:"message"`
+	}
+	ProductImportJobResponse struct {
+		ID          int64                `json:"id"`
+		FileName    string               `json:"file_name"`
+		Status      string               `json:"status"`
+		TotalRows   int                  `json:"total_rows"`
+		SuccessRows int                  `json:"success_rows"`
+		FailedRows  int                  `json:"failed_rows"`
+		Errors      []ProductImportError `json:"errors"`
+		CreatedAt   string               `json:"created_at"`
+		FinishedAt  string               `json:"finished_at,omitempty"`
+	}
+	ExportProductPayload struct {
+		AccountID int64
+		Format    string
+	}
+	ExportProductResponse struct {
+		FileName    string
+		ContentType string
+		Body        *bytes.Buffer
+	}
+	ProductExportRow struct {
+		ProductID      int64           `db:"product_id"`
+		ProductName    string          `db:"product_name"`
+		Description    string          `db:"description"`
+		Weight         int             `db:"weight"`
+		CategoryLevel1 sql.NullInt64   `db:"category_level1"`
+		CategoryLevel2 sql.NullInt64   `db:"category_level2"`
+		CategoryLevel3 sql.NullInt64   `db:"category_level3"`
+		ImageURLs      string          `db:"image_urls"`
+		VariantGroup1  string          `db:"variant_group1_name"`
+		VariantType1   string          `db:"variant_type1_name"`
+		VariantGroup2  string          `db:"variant_group2_name"`
+		VariantType2   string          `db:"variant_type2_name"`
+		Price          decimal.Decimal `db:"price"`
+		Stock          int64           `db:"stock"`
+	}
+)
@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 type (
 	ProductPageResponse struct {
 		Product         *ProductPageProductDetail   `json:"product"`
@@ -32,17 +34,26 @@ type (
 		Location          string `db:"location" json:"location"`
 	}
 	ProductPageProductVariant struct {
-		ID              int64   `json:"id"`
-		Price           float64 `json:"price"`
-		DiscountedPrice float64 `json:"discounted_price"`
-		Stock           uint32  `json:"stock"`
-		Discount        float32 `json:"discount"`
-		VariantType1ID  int64   `json:"variant_type1_id"`
-		VariantType2ID  int64   `json:"variant_type2_id"`
+		ID              int64                 `json:"id"`
+		Price           float64               `json:"price"`
+		DiscountedPrice float64               `json:"discounted_price"`
+		Stock           uint32                `json:"stock"`
+		Discount        float32               `json:"discount"`
+		VariantType1ID  int64                 `json:"variant_type1_id"`
+		VariantType2ID  int64                 `json:"variant_type2_id"`
+		FlashSale       *ProductPageFlashSale `json:"flash_sale,omitempty"`
+	}
+	ProductPageFlashSale struct {
+		EndAt          time.Time `json:"end_at"`
+		PurchaseLimit  int       `json:"purchase_limit,omitempty"`
+		RemainingQuota *int      `json:"remaining_quota,omitempty"`
 	}
 	ProductPageProductMedia struct {
-		MediaUrl  string `json:"media_url"`
-		MediaType string `json:"mediat_type"`
+		MediaUrl     string `json:"media_url"`
+		MediaType    string `json:"mediat_type"`
+		ThumbnailUrl string `json:"thumbnail_url,omitempty"`
+		MediumUrl    string `json:"medium_url,omitempty"`
+		LargeUrl     string `json:"large_url,omitempty"`
 	}
 	ProductPageVariants struct {
 		GroupName string `db:"group_name"`
@@ -18,6 +18,7 @@ type (
 		Percentage   float64 `json:"Percentage"`
 		MinimumSpend float64 `json:"minimum_spend"`
 		Quota        int     `json:"quota"`
+		UsageLimit   int     `json:"usage_limit_per_user,omitempty"`
 		StartedAt    string  `json:"started_at"`
 		ExpiredAt    string  `json:"expired_at"`
 	}
@@ -36,6 +37,7 @@ type (
 		Percentage   float64 `json:"percentage" validate:"omitempty,gt=0,lte=100"`
 		MinimumSpend float64 `json:"minimum_spend" validate:"required,gte=0"`
 		Quota        int     `json:"quota" validate:"required,gt=0"`
+		UsageLimit   int     `json:"usage_limit_per_user" validate:"omitempty,gt=0"`
 		StartedAt    string  `json:"started_at" validate:"required"`
 		ExpiredAt    string  `json:"expired_at" validate:"required"`
 	}
@@ -46,6 +48,7 @@ type (
 		Percentage   float64
 		MinimumSpend float64
 		Quota        int
+		UsageLimit   int
 		StartedAt    string
 		ExpiredAt    string
 	}
@@ -59,3 +62,37 @@ type (
 		MinimumSpend  float64         `db:"minimum_spend"`
 	}
 )
+
+type (
+	PromotionAnalyticsModel struct {
+		TotalOrders   int     `db:"total_orders"`
+		UniqueBuyers  int     `db:"unique_buyers"`
+		GMV           float64 `db:"gmv"`
+		TotalDiscount float64 `db:"total_discount"`
+	}
+	PromotionDailyModel struct {
+		Date     string  `db:"date"`
+		Orders   int     `db:"orders"`
+		GMV      float64 `db:"gmv"`
+		Discount float64 `db:"discount"`
+	}
+	PromotionDailyResponse struct {
+		Date     string  `json:"date"`
+		Orders   int     `json:"orders"`
+		GMV      float64 `json:"gmv"`
+		Discount float64 `json:"discount"`
+	}
+	PromotionAnalyticsResponse struct {
+		PromotionID               int64                    `json:"promotion_id"`
+		Name                      string                   `json:"name"`
+		StartedAt                 string                   `json:"started_at"`
+		ExpiredAt                 string                   `json:"expired_at"`
+		TotalOrders               int                      `json:"total_orders"`
+		UniqueBuyers              int                      `json:"unique_buyers"`
+		GMV                       float64                  `json:"gmv"`
+		TotalDiscount             float64                  `json:"total_discount"`
+		AverageOrderValue         float64                  `json:"average_order_value"`
+		NonPromoAverageOrderValue float64                  `json:"non_promo_average_order_value"`
+		Daily                     []PromotionDailyResponse `json:"daily"`
+	}
+)
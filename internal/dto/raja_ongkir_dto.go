@@ -1,23 +1,49 @@
 package dto
 
 type (
-	RajaOngkirGetCostHTTPQueries struct {
-		OriginCityID      int
-		DestinationCityID int
-		Weight            int
-		CourierCode       string
-		CourierService    string
-	}
 	RajaOngkirGetCostHTTPResponse struct {
 		RajaOngkir struct {
+			Status struct {
+				Code        int    `json:"code"`
+				Description string `json:"description"`
+			} `json:"status"`
 			Results []struct {
 				Costs []struct {
-					Service string `json:"service"`
-					Cost    []struct {
-						Value int `json:"value"`
+					Service     string `json:"service"`
+					Description string `json:"description"`
+					Cost        []struct {
+						Value int    `json:"value"`
+						ETD   string `json:"etd"`
 					} `json:"cost"`
 				} `json:"costs"`
 			} `json:"results"`
 		} `json:"rajaongkir"`
 	}
+	RajaOngkirProvinceHTTPResponse struct {
+		RajaOngkir struct {
+			Status struct {
+				Code        int    `json:"code"`
+				Description string `json:"description"`
+			} `json:"status"`
+			Results []struct {
+				ProvinceID string `json:"province_id"`
+				Province   string `json:"province"`
+			} `json:"results"`
+		} `json:"rajaongkir"`
+	}
+	RajaOngkirCityHTTPResponse struct {
+		RajaOngkir struct {
+			Status struct {
+				Code        int    `json:"code"`
+				Description string `json:"description"`
+			} `json:"status"`
+			Results []struct {
+				CityID     string `json:"city_id"`
+				ProvinceID string `json:"province_id"`
+				Type       string `json:"type"`
+				CityName   string `json:"city_name"`
+				PostalCode string `json:"postal_code"`
+			} `json:"results"`
+		} `json:"rajaongkir"`
+	}
 )
@@ -0,0 +1,32 @@
+package dto
+
+type (
+	RegionProvince struct {
+		ProviderID string
+		Name       string
+	}
+	RegionCity struct {
+		ProviderID         string
+		ProvinceProviderID string
+		Name               string
+		PostalCode         string
+	}
+	RegionChange struct {
+		ID           int64  `json:"id,omitempty"`
+		ProviderID   string `json:"provider_id,omitempty"`
+		Name         string `json:"name"`
+		PreviousName string `json:"previous_name,omitempty"`
+	}
+	RegionSyncDiff struct {
+		Added       []RegionChange `json:"added"`
+		Updated     []RegionChange `json:"updated"`
+		Deactivated []RegionChange `json:"deactivated"`
+		Mapped      int            `json:"mapped"`
+		Unchanged   int            `json:"unchanged"`
+	}
+	RegionSyncReport struct {
+		DryRun    bool           `json:"dry_run"`
+		Provinces RegionSyncDiff `json:"provinces"`
+		Districts RegionSyncDiff `json:"districts"`
+	}
+)
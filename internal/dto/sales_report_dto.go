@@ -0,0 +1,60 @@
+package dto
+
+import (
+	"database/sql"
+	"io"
+	"time"
+
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/shopspring/decimal"
+)
+
+type (
+	SalesReportParams struct {
+		StartDate string                   `form:"start_date" validate:"required,datetime=2006-01-02"`
+		EndDate   string                   `form:"end_date" validate:"required,datetime=2006-01-02"`
+		Status    constant.OrderStatusType `form:"status" validate:"omitempty,oneof=NEW PROCESS DELIVER ARRIVE RECEIVE CANCEL"`
+		Format    string                   `form:"format" validate:"omitempty,oneof=csv xlsx"`
+	}
+	SalesReportPayload struct {
+		SellerID  int64
+		StartDate string
+		EndDate   string
+		Status    string
+		Format    string
+	}
+	SalesReportRowModel struct {
+		OrderID         int64           `db:"order_id"`
+		OrderDate       time.Time       `db:"order_date"`
+		Status          string          `db:"status"`
+		BuyerName       string          `db:"buyer_name"`
+		ProductName     string          `db:"product_name"`
+		VariantName     string          `db:"variant_name"`
+		Quantity        int             `db:"quantity"`
+		SubTotalPrice   decimal.Decimal `db:"sub_total_price"`
+		PromotionAmount sql.NullFloat64 `db:"promotion_amount"`
+		VoucherAmount   decimal.Decimal `db:"voucher_amount"`
+		DeliveryCost    decimal.Decimal `db:"delivery_cost"`
+	}
+	SalesReportJobResponse struct {
+		ID          int64  `json:"id"`
+		StartDate   string `json:"start_date"`
+		EndDate     string `json:"end_date"`
+		OrderStatus string `json:"order_status,omitempty"`
+		Format      string `json:"format"`
+		Status      string `json:"status"`
+		FileName    string `json:"file_name"`
+		CreatedAt   string `json:"created_at"`
+		FinishedAt  string `json:"finished_at,omitempty"`
+	}
+	SalesReportExportResponse struct {
+		Job         *SalesReportJobResponse
+		FileName    string
+		ContentType string
+	}
+	SalesReportFileResponse struct {
+		FileName    string
+		ContentType string
+		Body        io.ReadCloser
+	}
+)
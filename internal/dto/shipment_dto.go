@@ -0,0 +1,50 @@
+package dto
+
+import "time"
+
+type (
+	ShipmentTrackingResult struct {
+		Delivered bool
+		Events    []ShipmentTrackingEvent
+	}
+	ShipmentTrackingEvent struct {
+		Description string
+		Location    string
+		OccurredAt  time.Time
+	}
+	RajaOngkirWaybillHTTPResponse struct {
+		RajaOngkir struct {
+			Status struct {
+				Code        int    `json:"code"`
+				Description string `json:"description"`
+			} `json:"status"`
+			Result struct {
+				Delivered bool `json:"delivered"`
+				Manifest  []struct {
+					Description string `json:"manifest_description"`
+					Date        string `json:"manifest_date"`
+					Time        string `json:"manifest_time"`
+					CityName    string `json:"city_name"`
+				} `json:"manifest"`
+			} `json:"result"`
+		} `json:"rajaongkir"`
+	}
+)
+
+type (
+	OrderTrackingEventResponse struct {
+		Description string `json:"description"`
+		Location    string `json:"location"`
+		OccurredAt  string `json:"occurred_at"`
+	}
+	OrderTrackingResponse struct {
+		OrderID        int64                        `json:"order_id"`
+		OrderStatus    string                       `json:"order_status"`
+		CourierCode    string                       `json:"courier_code"`
+		WaybillNumber  string                       `json:"waybill_number"`
+		ShipmentStatus string                       `json:"shipment_status"`
+		ShippedAt      string                       `json:"shipped_at"`
+		DeliveredAt    string                       `json:"delivered_at,omitempty"`
+		Events         []OrderTrackingEventResponse `json:"events"`
+	}
+)
@@ -0,0 +1,61 @@
+package dto
+
+type (
+	ShippingRateQuery struct {
+		CourierCode           string
+		OriginDistrictID      int64
+		OriginProvinceID      int64
+		DestinationDistrictID int64
+		DestinationProvinceID int64
+		Weight                int
+	}
+	ShippingRate struct {
+		CourierCode string  `json:"courier_code"`
+		Service     string  `json:"service"`
+		Description string  `json:"description"`
+		Cost        float64 `json:"cost"`
+		ETD         string  `json:"etd"`
+	}
+	ShippingServiceResponse struct {
+		Service     string  `json:"service"`
+		Description string  `json:"description"`
+		Cost        float64 `json:"cost"`
+		ETD         string  `json:"etd"`
+	}
+)
+
+type (
+	ShippingTableRateRequestBody struct {
+		CourierCode           string  `json:"courier_code" validate:"required,max=20"`
+		Service               string  `json:"service" validate:"required,max=50"`
+		Description           string  `json:"description" validate:"max=100"`
+		OriginProvinceID      int64   `json:"origin_province_id" validate:"required,gt=0"`
+		DestinationProvinceID int64   `json:"destination_province_id" validate:"required,gt=0"`
+		MinWeight             int     `json:"min_weight" validate:"gte=0"`
+		MaxWeight             int     `json:"max_weight" validate:"required,gtfield=MinWeight"`
+		Price                 float64 `json:"price" validate:"required,gt=0"`
+		ETD                   string  `json:"etd" validate:"required,max=20"`
+	}
+	ShippingTableRateParams struct {
+		CourierCode string `form:"courier_code"`
+		Page        int    `form:"page" validate:"omitempty,gt=0"`
+	}
+	ShippingTableRateResponse struct {
+		ID                    int64   `json:"id"`
+		CourierCode           string  `json:"courier_code"`
+		Service               string  `json:"service"`
+		Description           string  `json:"description"`
+		OriginProvinceID      int64   `json:"origin_province_id"`
+		DestinationProvinceID int64   `json:"destination_province_id"`
+		MinWeight             int     `json:"min_weight"`
+		MaxWeight             int     `json:"max_weight"`
+		Price                 float64 `json:"price"`
+		ETD                   string  `json:"etd"`
+	}
+	ShippingTableRateListResponse struct {
+		Items       []ShippingTableRateResponse `json:"items"`
+		TotalData   int                         `json:"total_data"`
+		TotalPage   int                         `json:"total_page"`
+		CurrentPage int                         `json:"current_page"`
+	}
+)
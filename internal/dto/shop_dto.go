@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 type (
 	CreateShopRequestBody struct {
 		ShopName  string `json:"shop_name" validate:"required"`
@@ -46,6 +48,10 @@ type (
 		ProductCategoryID  ProductCategory
 		VariantDefinitions VariantDefinitionReq
 		Variants           []VariantReq
+		Status             string
+		PublishAt          *time.Time
+		UnpublishAt        *time.Time
+		StockReason        string
 	}
 	AddProductRequestBody struct {
 		ProductName        string            `json:"product_name" validate:"required"`
@@ -56,6 +62,10 @@ type (
 		ProductCategoryID  ProductCategory   `json:"product_category_id" validate:"required"`
 		VariantDefinitions VariantDefinition `json:"variant_definition"`
 		Variants           []Variant         `json:"variants"`
+		Status             string            `json:"status" validate:"omitempty,oneof=DRAFT ACTIVE INACTIVE"`
+		PublishAt          *time.Time        `json:"publish_at"`
+		UnpublishAt        *time.Time        `json:"unpublish_at"`
+		StockReason        string            `json:"-"`
 	}
 	AddProductPayload struct {
 		ProductName        string
@@ -66,6 +76,10 @@ type (
 		ProductCategoryID  ProductCategory
 		VariantDefinitions VariantDefinition
 		Variants           []Variant
+		Status             string
+		PublishAt          *time.Time
+		UnpublishAt        *time.Time
+		StockReason        string
 	}
 	ProductCategory struct {
 		Level1 int  `json:"level_1" validate:"required"`
@@ -148,6 +162,24 @@ type (
 		ProductCode  string `db:"product_code"`
 		ProductName  string `db:"product_name"`
 		ThumbnailURL string `db:"thumbnail_url"`
+		Status       string `db:"status"`
+	}
+	GetAllProductPayload struct {
+		SellerID int
+		Page     int
+		Status   string
+	}
+	UpdateProductStatusRequestBody struct {
+		Status      string     `json:"status" validate:"required,oneof=DRAFT ACTIVE INACTIVE ARCHIVED"`
+		PublishAt   *time.Time `json:"publish_at"`
+		UnpublishAt *time.Time `json:"unpublish_at"`
+	}
+	UpdateProductStatusPayload struct {
+		ProductCode string
+		SellerID    int64
+		Status      string
+		PublishAt   *time.Time
+		UnpublishAt *time.Time
 	}
 	GetAllProductResponseBody struct {
 		Products   []GetAllProduct  `json:"products"`
@@ -0,0 +1,100 @@
+package dto
+
+import "github.com/shopspring/decimal"
+
+type (
+	VoucherRequestBody struct {
+		Code              string  `json:"code" validate:"required,alphanum,min=4,max=20"`
+		Name              string  `json:"name" validate:"required"`
+		Type              string  `json:"type" validate:"required,oneof=PERCENTAGE FIXED FREE_SHIPPING"`
+		Value             float64 `json:"value" validate:"omitempty,gt=0"`
+		MaxDiscount       float64 `json:"max_discount" validate:"omitempty,gt=0"`
+		MinimumSpend      float64 `json:"minimum_spend" validate:"gte=0"`
+		Quota             int     `json:"quota" validate:"required,gt=0"`
+		UsageLimitPerUser int     `json:"usage_limit_per_user" validate:"required,gt=0"`
+		CategoryIDs       []int64 `json:"category_ids" validate:"omitempty,max=50,dive,gt=0"`
+		StartedAt         string  `json:"started_at" validate:"required"`
+		ExpiredAt         string  `json:"expired_at" validate:"required"`
+	}
+	// VoucherPayload creates or updates a voucher. SellerID is only set for
+	// shop vouchers, ID only for updates.
+	VoucherPayload struct {
+		ID                int64
+		SellerID          int64
+		Code              string
+		Name              string
+		Type              string
+		Value             float64
+		MaxDiscount       float64
+		MinimumSpend      float64
+		Quota             int
+		UsageLimitPerUser int
+		CategoryIDs       []int64
+		StartedAt         string
+		ExpiredAt         string
+	}
+)
+
+type (
+	VoucherParams struct {
+		Page int `form:"page" validate:"omitempty,gt=0"`
+	}
+	VoucherCategoryModel struct {
+		VoucherID  int64 `db:"voucher_id"`
+		CategoryID int64 `db:"category_id"`
+	}
+	VoucherResponse struct {
+		ID                int64    `json:"id"`
+		Code              string   `json:"code"`
+		Name              string   `json:"name"`
+		Issuer            string   `json:"issuer"`
+		Type              string   `json:"type"`
+		Value             float64  `json:"value,omitempty"`
+		MaxDiscount       *float64 `json:"max_discount,omitempty"`
+		MinimumSpend      float64  `json:"minimum_spend"`
+		Quota             int      `json:"quota"`
+		Used              int      `json:"used"`
+		UsageLimitPerUser int      `json:"usage_limit_per_user"`
+		CategoryIDs       []int64  `json:"category_ids"`
+		StartedAt         string   `json:"started_at"`
+		ExpiredAt         string   `json:"expired_at"`
+	}
+	VoucherListResponse struct {
+		Items       []VoucherResponse `json:"items"`
+		TotalData   int               `json:"total_data"`
+		TotalPage   int               `json:"total_page"`
+		CurrentPage int               `json:"current_page"`
+	}
+)
+
+type (
+	ValidateVoucherRequestBody struct {
+		VoucherCode string `json:"voucher_code" validate:"required"`
+		ShopID      int64  `json:"shop_id" validate:"omitempty,gt=0"`
+	}
+	ValidateVoucherPayload struct {
+		BuyerID     int64
+		VoucherCode string
+		ShopID      int64
+	}
+	ValidateVoucherResponse struct {
+		Code             string   `json:"code"`
+		Name             string   `json:"name"`
+		Issuer           string   `json:"issuer"`
+		Type             string   `json:"type"`
+		MinimumSpend     float64  `json:"minimum_spend"`
+		MaxDiscount      *float64 `json:"max_discount,omitempty"`
+		EligibleSubtotal float64  `json:"eligible_subtotal"`
+		Discount         float64  `json:"discount"`
+		ExpiredAt        string   `json:"expired_at"`
+	}
+)
+
+type (
+	OrderVoucher struct {
+		VoucherID int64
+		Issuer    string
+		Type      string
+		Amount    decimal.Decimal
+	}
+)
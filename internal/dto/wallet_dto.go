@@ -31,20 +31,22 @@ type (
 		TransactionType constant.ListWalletHistoryQueryValue
 	}
 	ListWalletHistoryItemFromDB struct {
-		Title    string         `db:"title"`
-		Amount   float64        `db:"amount"`
-		Date     string         `db:"date"`
-		IsDebit  bool           `db:"is_debit"`
-		ShopName sql.NullString `db:"shop_name"`
-		OrderID  sql.NullInt64  `db:"order_id"`
+		Title      string          `db:"title"`
+		Amount     float64         `db:"amount"`
+		Date       string          `db:"date"`
+		IsDebit    bool            `db:"is_debit"`
+		ShopName   sql.NullString  `db:"shop_name"`
+		OrderID    sql.NullInt64   `db:"order_id"`
+		ServiceFee sql.NullFloat64 `db:"service_fee"`
 	}
 	ListWalletHistoryItem struct {
-		Title    string  `json:"title"`
-		Amount   float64 `json:"amount"`
-		Date     string  `json:"date"`
-		IsDebit  bool    `json:"is_debit"`
-		ShopName string  `json:"shop_name,omitempty"`
-		OrderID  int64   `json:"order_id,omitempty"`
+		Title      string  `json:"title"`
+		Amount     float64 `json:"amount"`
+		Date       string  `json:"date"`
+		IsDebit    bool    `json:"is_debit"`
+		ShopName   string  `json:"shop_name,omitempty"`
+		OrderID    int64   `json:"order_id,omitempty"`
+		ServiceFee float64 `json:"service_fee,omitempty"`
 	}
 
 	ListWalletHistoryResponse struct {
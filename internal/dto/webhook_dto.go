@@ -0,0 +1,83 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type (
+	WebhookRequestBody struct {
+		URL        string   `json:"url" validate:"required,http_url,max=2048"`
+		EventTypes []string `json:"event_types" validate:"required,min=1,unique,dive,oneof=order.created order.cancelled order.received review.posted"`
+		IsActive   *bool    `json:"is_active"`
+	}
+	WebhookResponse struct {
+		ID         int64     `json:"id"`
+		URL        string    `json:"url"`
+		Secret     string    `json:"secret"`
+		EventTypes []string  `json:"event_types"`
+		IsActive   bool      `json:"is_active"`
+		CreatedAt  time.Time `json:"created_at"`
+		UpdatedAt  time.Time `json:"updated_at"`
+	}
+)
+
+type (
+	WebhookDeliveryParams struct {
+		Page       int    `form:"page" validate:"omitempty,gt=0"`
+		EndpointID int64  `form:"webhook_id" validate:"omitempty,gt=0"`
+		Status     string `form:"status" validate:"omitempty,oneof=PENDING SUCCEEDED DEAD"`
+	}
+	WebhookDeliveryResponse struct {
+		ID             int64           `json:"id"`
+		WebhookID      int64           `json:"webhook_id"`
+		EventType      string          `json:"event_type"`
+		Payload        json.RawMessage `json:"payload"`
+		Status         string          `json:"status"`
+		Attempts       int             `json:"attempts"`
+		ResponseStatus *int64          `json:"response_status"`
+		ResponseBody   *string         `json:"response_body"`
+		LastError      *string         `json:"last_error"`
+		NextAttemptAt  *time.Time      `json:"next_attempt_at"`
+		DeliveredAt    *time.Time      `json:"delivered_at"`
+		CreatedAt      time.Time       `json:"created_at"`
+	}
+	WebhookDeliveryListResponse struct {
+		Items       []WebhookDeliveryResponse `json:"items"`
+		TotalData   int                       `json:"total_data"`
+		TotalPage   int                       `json:"total_page"`
+		CurrentPage int                       `json:"current_page"`
+	}
+)
+
+type (
+	// WebhookDeliveryJob is a delivery taken to be sent, with the endpoint
+	// it goes to.
+	WebhookDeliveryJob struct {
+		ID         int64     `db:"id"`
+		EndpointID int64     `db:"endpoint_id"`
+		EventType  string    `db:"event_type"`
+		Payload    []byte    `db:"payload"`
+		Attempts   int       `db:"attempts"`
+		CreatedAt  time.Time `db:"created_at"`
+		URL        string    `db:"url"`
+		Secret     string    `db:"secret"`
+	}
+	// WebhookBody is what is posted to an endpoint. The id stays the same
+	// when a delivery is retried, so endpoints can tell a delivery they
+	// already handled.
+	WebhookBody struct {
+		ID        int64           `json:"id"`
+		Event     string          `json:"event"`
+		CreatedAt time.Time       `json:"created_at"`
+		Data      json.RawMessage `json:"data"`
+	}
+	WebhookResult struct {
+		StatusCode int
+		Body       string
+	}
+	WebhookPingPayload struct {
+		WebhookID int64 `json:"webhook_id"`
+		SellerID  int64 `json:"seller_id"`
+	}
+)
@@ -0,0 +1,39 @@
+package jobhandler
+
+import (
+	"context"
+	"time"
+
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/usecase"
+)
+
+type ChatJobHandler struct {
+	cu     usecase.ChatUsecase
+	logger dependency.Logger
+}
+
+// Run listens for chat frames published by any replica and pushes them to
+// the WebSockets open on this one until ctx is cancelled. Listening starts
+// over when the connection to Redis fails.
+func (h ChatJobHandler) Run(ctx context.Context) {
+	for {
+		if err := h.cu.ListenChats(ctx); err != nil && ctx.Err() == nil {
+			h.logger.Errorf("listen chats: %s", err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(constant.ChatListenRetry):
+		}
+	}
+}
+
+func NewChatJobHandler(cu usecase.ChatUsecase, logger dependency.Logger) *ChatJobHandler {
+	return &ChatJobHandler{
+		cu:     cu,
+		logger: logger,
+	}
+}
@@ -0,0 +1,41 @@
+package jobhandler
+
+import (
+	"context"
+	"time"
+
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/usecase"
+)
+
+type FlashSaleJobHandler struct {
+	fsu    usecase.FlashSaleUsecase
+	cfg    dependency.Config
+	logger dependency.Logger
+}
+
+// Run starts and ends flash sales every FLASH_SALE_INTERVAL seconds until ctx
+// is cancelled.
+func (h FlashSaleJobHandler) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(h.cfg.FlashSale.ProcessingInterval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := h.fsu.ApplyFlashSales(ctx); err != nil && ctx.Err() == nil {
+				h.logger.Errorf("apply flash sales: %s", err.Error())
+			}
+		}
+	}
+}
+
+func NewFlashSaleJobHandler(fsu usecase.FlashSaleUsecase, cfg dependency.Config, logger dependency.Logger) *FlashSaleJobHandler {
+	return &FlashSaleJobHandler{
+		fsu:    fsu,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
@@ -0,0 +1,41 @@
+package jobhandler
+
+import (
+	"context"
+	"time"
+
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/usecase"
+)
+
+type InventoryJobHandler struct {
+	iu     usecase.InventoryUsecase
+	cfg    dependency.Config
+	logger dependency.Logger
+}
+
+// Run sends low-stock alerts every LOW_STOCK_ALERT_INTERVAL seconds until ctx
+// is cancelled.
+func (h InventoryJobHandler) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(h.cfg.Inventory.AlertInterval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := h.iu.SendLowStockAlerts(ctx); err != nil && ctx.Err() == nil {
+				h.logger.Errorf("send low stock alerts: %s", err.Error())
+			}
+		}
+	}
+}
+
+func NewInventoryJobHandler(iu usecase.InventoryUsecase, cfg dependency.Config, logger dependency.Logger) *InventoryJobHandler {
+	return &InventoryJobHandler{
+		iu:     iu,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
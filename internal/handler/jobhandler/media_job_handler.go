@@ -0,0 +1,41 @@
+package jobhandler
+
+import (
+	"context"
+	"time"
+
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/usecase"
+)
+
+type MediaJobHandler struct {
+	mu     usecase.MediaUsecase
+	cfg    dependency.Config
+	logger dependency.Logger
+}
+
+// Run processes pending uploaded images every MEDIA_PROCESSING_INTERVAL
+// seconds until ctx is cancelled.
+func (h MediaJobHandler) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(h.cfg.MediaStorage.ProcessingInterval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := h.mu.ProcessPendingMedias(ctx); err != nil && ctx.Err() == nil {
+				h.logger.Errorf("process pending medias: %s", err.Error())
+			}
+		}
+	}
+}
+
+func NewMediaJobHandler(mu usecase.MediaUsecase, cfg dependency.Config, logger dependency.Logger) *MediaJobHandler {
+	return &MediaJobHandler{
+		mu:     mu,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
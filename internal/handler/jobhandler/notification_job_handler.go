@@ -0,0 +1,39 @@
+package jobhandler
+
+import (
+	"context"
+	"time"
+
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/usecase"
+)
+
+type NotificationJobHandler struct {
+	nu     usecase.NotificationUsecase
+	logger dependency.Logger
+}
+
+// Run listens for notifications published by any replica and pushes them to
+// the streams open on this one until ctx is cancelled. Listening starts
+// over when the connection to Redis fails.
+func (h NotificationJobHandler) Run(ctx context.Context) {
+	for {
+		if err := h.nu.ListenNotifications(ctx); err != nil && ctx.Err() == nil {
+			h.logger.Errorf("listen notifications: %s", err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(constant.NotificationListenRetry):
+		}
+	}
+}
+
+func NewNotificationJobHandler(nu usecase.NotificationUsecase, logger dependency.Logger) *NotificationJobHandler {
+	return &NotificationJobHandler{
+		nu:     nu,
+		logger: logger,
+	}
+}
@@ -0,0 +1,41 @@
+package jobhandler
+
+import (
+	"context"
+	"time"
+
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/usecase"
+)
+
+type OutboxJobHandler struct {
+	eu     usecase.EventUsecase
+	cfg    dependency.Config
+	logger dependency.Logger
+}
+
+// Run hands the events in the outbox to their subscribers every
+// OUTBOX_INTERVAL seconds until ctx is cancelled.
+func (h OutboxJobHandler) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(h.cfg.Outbox.ProcessingInterval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := h.eu.DispatchEvents(ctx); err != nil && ctx.Err() == nil {
+				h.logger.Errorf("dispatch events: %s", err.Error())
+			}
+		}
+	}
+}
+
+func NewOutboxJobHandler(eu usecase.EventUsecase, cfg dependency.Config, logger dependency.Logger) *OutboxJobHandler {
+	return &OutboxJobHandler{
+		eu:     eu,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
@@ -0,0 +1,41 @@
+package jobhandler
+
+import (
+	"context"
+	"time"
+
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/usecase"
+)
+
+type ProductImportJobHandler struct {
+	pbu    usecase.ProductBulkUsecase
+	cfg    dependency.Config
+	logger dependency.Logger
+}
+
+// Run imports queued product files every PRODUCT_IMPORT_INTERVAL seconds
+// until ctx is cancelled.
+func (h ProductImportJobHandler) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(h.cfg.ProductImport.ProcessingInterval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := h.pbu.ProcessPendingImports(ctx); err != nil && ctx.Err() == nil {
+				h.logger.Errorf("process pending product imports: %s", err.Error())
+			}
+		}
+	}
+}
+
+func NewProductImportJobHandler(pbu usecase.ProductBulkUsecase, cfg dependency.Config, logger dependency.Logger) *ProductImportJobHandler {
+	return &ProductImportJobHandler{
+		pbu:    pbu,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
@@ -0,0 +1,41 @@
+package jobhandler
+
+import (
+	"context"
+	"time"
+
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/usecase"
+)
+
+type ProductScheduleJobHandler struct {
+	su     usecase.ShopUsecase
+	cfg    dependency.Config
+	logger dependency.Logger
+}
+
+// Run publishes and unpublishes scheduled products every
+// PRODUCT_SCHEDULE_INTERVAL seconds until ctx is cancelled.
+func (h ProductScheduleJobHandler) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(h.cfg.ProductSchedule.ProcessingInterval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := h.su.ApplyProductSchedules(ctx); err != nil && ctx.Err() == nil {
+				h.logger.Errorf("apply product schedules: %s", err.Error())
+			}
+		}
+	}
+}
+
+func NewProductScheduleJobHandler(su usecase.ShopUsecase, cfg dependency.Config, logger dependency.Logger) *ProductScheduleJobHandler {
+	return &ProductScheduleJobHandler{
+		su:     su,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
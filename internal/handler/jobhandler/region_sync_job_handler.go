@@ -0,0 +1,60 @@
+package jobhandler
+
+import (
+	"context"
+	"time"
+
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/usecase"
+	"github.com/sirupsen/logrus"
+)
+
+type RegionSyncJobHandler struct {
+	rsu    usecase.RegionSyncUsecase
+	cfg    dependency.Config
+	logger dependency.Logger
+}
+
+// Run syncs provinces and districts from the shipping provider every
+// REGION_SYNC_INTERVAL hours until ctx is cancelled. The job is off when the
+// interval is 0, syncs are then run with the region-sync command.
+func (h RegionSyncJobHandler) Run(ctx context.Context) {
+	if h.cfg.RegionSync.Interval == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(h.cfg.RegionSync.Interval) * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := h.rsu.Sync(ctx, false)
+			if err != nil {
+				if ctx.Err() == nil {
+					h.logger.Errorf("sync regions: %s", err.Error())
+				}
+				continue
+			}
+
+			h.logger.Infof("Synced regions", logrus.Fields{
+				"provinces_added":       len(report.Provinces.Added),
+				"provinces_updated":     len(report.Provinces.Updated),
+				"provinces_deactivated": len(report.Provinces.Deactivated),
+				"districts_added":       len(report.Districts.Added),
+				"districts_updated":     len(report.Districts.Updated),
+				"districts_deactivated": len(report.Districts.Deactivated),
+			})
+		}
+	}
+}
+
+func NewRegionSyncJobHandler(rsu usecase.RegionSyncUsecase, cfg dependency.Config, logger dependency.Logger) *RegionSyncJobHandler {
+	return &RegionSyncJobHandler{
+		rsu:    rsu,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
@@ -0,0 +1,41 @@
+package jobhandler
+
+import (
+	"context"
+	"time"
+
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/usecase"
+)
+
+type SalesReportJobHandler struct {
+	sru    usecase.SalesReportUsecase
+	cfg    dependency.Config
+	logger dependency.Logger
+}
+
+// Run writes queued sales reports every SALES_REPORT_INTERVAL seconds until
+// ctx is cancelled.
+func (h SalesReportJobHandler) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(h.cfg.SalesReport.ProcessingInterval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := h.sru.ProcessPendingSalesReports(ctx); err != nil && ctx.Err() == nil {
+				h.logger.Errorf("process pending sales reports: %s", err.Error())
+			}
+		}
+	}
+}
+
+func NewSalesReportJobHandler(sru usecase.SalesReportUsecase, cfg dependency.Config, logger dependency.Logger) *SalesReportJobHandler {
+	return &SalesReportJobHandler{
+		sru:    sru,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
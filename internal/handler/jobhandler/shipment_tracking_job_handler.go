@@ -0,0 +1,41 @@
+package jobhandler
+
+import (
+	"context"
+	"time"
+
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/usecase"
+)
+
+type ShipmentTrackingJobHandler struct {
+	su     usecase.ShipmentUsecase
+	cfg    dependency.Config
+	logger dependency.Logger
+}
+
+// Run polls the courier for shipped orders every SHIPMENT_TRACKING_INTERVAL
+// seconds until ctx is cancelled.
+func (h ShipmentTrackingJobHandler) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(h.cfg.Shipment.ProcessingInterval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := h.su.ProcessShipmentTracking(ctx); err != nil && ctx.Err() == nil {
+				h.logger.Errorf("process shipment tracking: %s", err.Error())
+			}
+		}
+	}
+}
+
+func NewShipmentTrackingJobHandler(su usecase.ShipmentUsecase, cfg dependency.Config, logger dependency.Logger) *ShipmentTrackingJobHandler {
+	return &ShipmentTrackingJobHandler{
+		su:     su,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
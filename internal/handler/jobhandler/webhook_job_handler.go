@@ -0,0 +1,41 @@
+package jobhandler
+
+import (
+	"context"
+	"time"
+
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/usecase"
+)
+
+type WebhookJobHandler struct {
+	wu     usecase.WebhookUsecase
+	cfg    dependency.Config
+	logger dependency.Logger
+}
+
+// Run sends the due webhook deliveries every WEBHOOK_INTERVAL seconds until
+// ctx is cancelled.
+func (h WebhookJobHandler) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(h.cfg.Webhook.ProcessingInterval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := h.wu.DeliverWebhooks(ctx); err != nil && ctx.Err() == nil {
+				h.logger.Errorf("deliver webhooks: %s", err.Error())
+			}
+		}
+	}
+}
+
+func NewWebhookJobHandler(wu usecase.WebhookUsecase, cfg dependency.Config, logger dependency.Logger) *WebhookJobHandler {
+	return &WebhookJobHandler{
+		wu:     wu,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
@@ -0,0 +1,384 @@
+package resthandler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/middleware"
+	"github.com/lil-oren/rest/internal/shared"
+	"github.com/lil-oren/rest/internal/usecase"
+	"golang.org/x/net/websocket"
+)
+
+type ChatHandler struct {
+	cu       usecase.ChatUsecase
+	cfg      dependency.Config
+	validate *validator.Validate
+}
+
+func (h ChatHandler) startConversation(c *gin.Context) {
+	body := dto.StartConversationRequestBody{}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		_ = c.Error(shared.ErrInvalidBodySchema)
+		return
+	}
+
+	if err := h.validate.Struct(body); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	ctx := c.Request.Context()
+	res, err := h.cu.StartConversation(ctx, c.GetInt64(constant.CtxUserId), body)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.JSONResponse{Data: res})
+}
+
+func (h ChatHandler) getConversations(c *gin.Context) {
+	params := dto.ConversationParams{}
+	if err := c.ShouldBindQuery(&params); err != nil {
+		_ = c.Error(shared.GenerateErrQueryParamInvalid("page"))
+		return
+	}
+
+	if err := h.validate.Struct(params); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	ctx := c.Request.Context()
+	res, err := h.cu.GetConversations(ctx, c.GetInt64(constant.CtxUserId), params)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.JSONResponse{Data: res})
+}
+
+func (h ChatHandler) getMessages(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(shared.GenerateErrPathParamInvalid("id"))
+		return
+	}
+
+	params := dto.ChatMessageParams{}
+	if err := c.ShouldBindQuery(&params); err != nil {
+		_ = c.Error(shared.GenerateErrQueryParamInvalid("cursor"))
+		return
+	}
+
+	if err := h.validate.Struct(params); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	ctx := c.Request.Context()
+	res, err := h.cu.GetMessages(ctx, int64(id), c.GetInt64(constant.CtxUserId), params)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.JSONResponse{Data: res})
+}
+
+func (h ChatHandler) sendMessage(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(shared.GenerateErrPathParamInvalid("id"))
+		return
+	}
+
+	body := dto.SendChatMessageRequestBody{}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		_ = c.Error(shared.ErrInvalidBodySchema)
+		return
+	}
+
+	if err := h.validate.Struct(body); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	ctx := c.Request.Context()
+	res, err := h.cu.SendMessage(ctx, int64(id), c.GetInt64(constant.CtxUserId), body)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.JSONResponse{Data: res})
+}
+
+func (h ChatHandler) readConversation(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(shared.GenerateErrPathParamInvalid("id"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.cu.ReadConversation(ctx, int64(id), c.GetInt64(constant.CtxUserId)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (h ChatHandler) getUnreadCount(c *gin.Context) {
+	ctx := c.Request.Context()
+	res, err := h.cu.GetUnreadCount(ctx, c.GetInt64(constant.CtxUserId))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.JSONResponse{Data: res})
+}
+
+// serveChat upgrades the request to a WebSocket for the account signed in.
+// Browsers send the auth cookie with the upgrade, so only the web client
+// origin may open one.
+func (h ChatHandler) serveChat(c *gin.Context) {
+	accountID := c.GetInt64(constant.CtxUserId)
+	origin := middleware.AllowedOrigin(h.cfg)
+
+	websocket.Server{
+		Handshake: func(config *websocket.Config, r *http.Request) error {
+			if config.Origin != nil && config.Origin.String() != origin {
+				return fmt.Errorf("origin %s is not allowed", config.Origin)
+			}
+			return nil
+		},
+		Handler: func(ws *websocket.Conn) {
+			h.chat(ws, accountID)
+		},
+	}.ServeHTTP(c.Writer, c.Request)
+}
+
+// chat reads the frames sent by the client until the socket is closed.
+// Message and read frames work like their REST counterparts, a frame that
+// fails is answered with an error frame.
+func (h ChatHandler) chat(ws *websocket.Conn, accountID int64) {
+	ws.MaxPayloadBytes = constant.ChatMaxFrameSize
+
+	ctx, cancel := context.WithCancel(ws.Request().Context())
+	defer cancel()
+
+	frames, unsubscribe := h.cu.SubscribeChats(accountID)
+	defer unsubscribe()
+
+	replies := make(chan dto.ChatFrame, constant.ChatStreamBuffer)
+	go h.writeChat(ctx, cancel, ws, frames, replies)
+
+	for {
+		var data string
+		err := websocket.Message.Receive(ws, &data)
+		if err != nil && !errors.Is(err, websocket.ErrFrameTooLarge) {
+			return
+		}
+
+		frame := dto.ChatFrame{}
+		if err == nil {
+			err = json.Unmarshal([]byte(data), &frame)
+		}
+		if err != nil {
+			err = shared.ErrInvalidBodySchema
+		} else {
+			err = h.handleFrame(ctx, accountID, frame)
+		}
+		if err == nil {
+			continue
+		}
+
+		select {
+		case replies <- chatErrorFrame(frame.ConversationID, err):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (h ChatHandler) handleFrame(ctx context.Context, accountID int64, frame dto.ChatFrame) error {
+	switch frame.Type {
+	case constant.ChatMessageFrame:
+		if frame.Send == nil {
+			return shared.ErrInvalidBodySchema
+		}
+		if err := h.validate.Struct(frame.Send); err != nil {
+			return err
+		}
+		_, err := h.cu.SendMessage(ctx, frame.ConversationID, accountID, *frame.Send)
+		return err
+	case constant.ChatReadFrame:
+		return h.cu.ReadConversation(ctx, frame.ConversationID, accountID)
+	default:
+		return shared.ErrInvalidBodySchema
+	}
+}
+
+// writeChat is the only writer of ws. It closes ws when a write fails so
+// the reader stops as well.
+func (h ChatHandler) writeChat(ctx context.Context, cancel context.CancelFunc, ws *websocket.Conn, frames, replies <-chan dto.ChatFrame) {
+	defer cancel()
+
+	ping := time.NewTicker(constant.ChatPingInterval)
+	defer ping.Stop()
+
+	for {
+		var frame dto.ChatFrame
+		select {
+		case <-ctx.Done():
+			return
+		case frame = <-frames:
+		case frame = <-replies:
+		case <-ping.C:
+			frame = dto.ChatFrame{Type: constant.ChatPingFrame}
+		}
+
+		_ = ws.SetWriteDeadline(time.Now().Add(constant.ChatWriteTimeout))
+		if err := websocket.JSON.Send(ws, frame); err != nil {
+			_ = ws.Close()
+			return
+		}
+	}
+}
+
+func chatErrorFrame(conversationID int64, err error) dto.ChatFrame {
+	frame := dto.ChatFrame{
+		Type:           constant.ChatErrorFrame,
+		ConversationID: conversationID,
+		Error:          shared.ErrInternalServer.Error(),
+	}
+
+	var ve validator.ValidationErrors
+	var ce *shared.CustomError
+	if errors.As(err, &ve) {
+		frame.Error = shared.ValidationErrResponse(err)
+	} else if errors.As(err, &ce) {
+		frame.Error = ce.Error()
+	}
+
+	return frame
+}
+
+func (h ChatHandler) getQuickReplies(c *gin.Context) {
+	ctx := c.Request.Context()
+	res, err := h.cu.GetQuickReplies(ctx, c.GetInt64(constant.CtxUserId))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.JSONResponse{Data: res})
+}
+
+func (h ChatHandler) createQuickReply(c *gin.Context) {
+	body := dto.ChatQuickReplyRequestBody{}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		_ = c.Error(shared.ErrInvalidBodySchema)
+		return
+	}
+
+	if err := h.validate.Struct(body); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	ctx := c.Request.Context()
+	res, err := h.cu.CreateQuickReply(ctx, c.GetInt64(constant.CtxUserId), body)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.JSONResponse{Data: res})
+}
+
+func (h ChatHandler) updateQuickReply(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(shared.GenerateErrPathParamInvalid("id"))
+		return
+	}
+
+	body := dto.ChatQuickReplyRequestBody{}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		_ = c.Error(shared.ErrInvalidBodySchema)
+		return
+	}
+
+	if err := h.validate.Struct(body); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	ctx := c.Request.Context()
+	res, err := h.cu.UpdateQuickReply(ctx, int64(id), c.GetInt64(constant.CtxUserId), body)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.JSONResponse{Data: res})
+}
+
+func (h ChatHandler) deleteQuickReply(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(shared.GenerateErrPathParamInvalid("id"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.cu.DeleteQuickReply(ctx, int64(id), c.GetInt64(constant.CtxUserId)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (h ChatHandler) Route(r *gin.Engine) {
+	r.
+		Group("/chats", middleware.AllowAuthenticated(h.cfg)).
+		POST("/conversations", h.startConversation).
+		GET("/conversations", h.getConversations).
+		GET("/conversations/:id/messages", h.getMessages).
+		POST("/conversations/:id/messages", h.sendMessage).
+		PUT("/conversations/:id/read", h.readConversation).
+		GET("/unread-count", h.getUnreadCount).
+		GET("/ws", h.serveChat)
+
+	r.
+		Group("/merchant/chat-templates", middleware.AllowAuthenticated(h.cfg), middleware.IsSeller()).
+		GET("", h.getQuickReplies).
+		POST("", h.createQuickReply).
+		PUT("/:id", h.updateQuickReply).
+		DELETE("/:id", h.deleteQuickReply)
+}
+
+func NewChatHandler(cu usecase.ChatUsecase, cfg dependency.Config, v *validator.Validate) *ChatHandler {
+	return &ChatHandler{
+		cu:       cu,
+		cfg:      cfg,
+		validate: v,
+	}
+}
@@ -15,6 +15,7 @@ import (
 
 type CheckoutHandler struct {
 	cu     usecase.CheckoutUsecase
+	vu     usecase.VoucherUsecase
 	v      *validator.Validate
 	config dependency.Config
 }
@@ -28,9 +29,10 @@ func (h CheckoutHandler) summary(c *gin.Context) {
 
 	userID := c.GetInt64(constant.CtxUserId)
 	payload := dto.CalculateCheckoutSummaryPayload{
-		BuyerID:         userID,
-		BuyerAddressID:  body.BuyerAddressID,
-		OrderDeliveries: body.OrderDeliveries,
+		BuyerID:             userID,
+		BuyerAddressID:      body.BuyerAddressID,
+		OrderDeliveries:     body.OrderDeliveries,
+		PlatformVoucherCode: body.PlatformVoucherCode,
 	}
 	if err := h.v.Struct(payload); err != nil {
 		_ = c.Error(err.(validator.ValidationErrors))
@@ -49,11 +51,62 @@ func (h CheckoutHandler) summary(c *gin.Context) {
 	})
 }
 
+func (h CheckoutHandler) createSession(c *gin.Context) {
+	body := new(dto.CalculateCheckoutSummaryBodyPayload)
+	if err := c.ShouldBindJSON(body); err != nil {
+		_ = c.Error(shared.ErrInvalidBodySchema)
+		return
+	}
+
+	payload := dto.CalculateCheckoutSummaryPayload{
+		BuyerID:             c.GetInt64(constant.CtxUserId),
+		BuyerAddressID:      body.BuyerAddressID,
+		OrderDeliveries:     body.OrderDeliveries,
+		PlatformVoucherCode: body.PlatformVoucherCode,
+	}
+
+	ctx := c.Request.Context()
+	res, err := h.cu.CreateCheckoutSession(ctx, payload)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.JSONResponse{
+		Data: res,
+	})
+}
+
+func (h CheckoutHandler) getSession(c *gin.Context) {
+	ctx := c.Request.Context()
+	res, err := h.cu.GetCheckoutSession(ctx, c.Param("id"), c.GetInt64(constant.CtxUserId))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.JSONResponse{
+		Data: res,
+	})
+}
+
 func (h CheckoutHandler) listCheckoutItem(c *gin.Context) {
+	params := dto.ListCheckoutItemParams{}
+	if err := c.ShouldBindQuery(&params); err != nil {
+		_ = c.Error(shared.GenerateErrQueryParamInvalid("buyer_address_id"))
+		return
+	}
+
+	if err := h.v.Struct(params); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
 	userID := c.GetInt64(constant.CtxUserId)
 	ctx := c.Request.Context()
 	payload := dto.ListCheckoutItemPayload{
-		UserID: userID,
+		UserID:         userID,
+		BuyerAddressID: params.BuyerAddressID,
 	}
 
 	res, err := h.cu.ListCheckoutItem(ctx, payload)
@@ -67,17 +120,51 @@ func (h CheckoutHandler) listCheckoutItem(c *gin.Context) {
 	})
 }
 
+func (h CheckoutHandler) validateVoucher(c *gin.Context) {
+	body := new(dto.ValidateVoucherRequestBody)
+	if err := c.ShouldBindJSON(body); err != nil {
+		_ = c.Error(shared.ErrInvalidBodySchema)
+		return
+	}
+
+	if err := h.v.Struct(body); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	ctx := c.Request.Context()
+	payload := dto.ValidateVoucherPayload{
+		BuyerID:     c.GetInt64(constant.CtxUserId),
+		VoucherCode: body.VoucherCode,
+		ShopID:      body.ShopID,
+	}
+
+	res, err := h.vu.ValidateVoucher(ctx, payload)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.JSONResponse{
+		Data: res,
+	})
+}
+
 func (h CheckoutHandler) Route(r *gin.Engine) {
 	r.
 		Group("/checkouts", middleware.AllowAuthenticated(h.config)).
 		GET("", h.listCheckoutItem).
-		POST("/summary", h.summary)
+		POST("/summary", h.summary).
+		POST("/sessions", h.createSession).
+		GET("/sessions/:id", h.getSession).
+		POST("/vouchers/validate", h.validateVoucher)
 }
 
-func NewCheckoutHandler(v *validator.Validate, cu usecase.CheckoutUsecase, config dependency.Config) CheckoutHandler {
+func NewCheckoutHandler(v *validator.Validate, cu usecase.CheckoutUsecase, vu usecase.VoucherUsecase, config dependency.Config) CheckoutHandler {
 	return CheckoutHandler{
 		v:      v,
 		cu:     cu,
+		vu:     vu,
 		config: config,
 	}
 }
@@ -0,0 +1,56 @@
+package resthandler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/middleware"
+	"github.com/lil-oren/rest/internal/shared"
+	"github.com/lil-oren/rest/internal/usecase"
+)
+
+type DashboardHandler struct {
+	du  usecase.DashboardUsecase
+	cfg dependency.Config
+	v   *validator.Validate
+}
+
+func (h DashboardHandler) getSellerDashboard(c *gin.Context) {
+	params := dto.DashboardParams{}
+	if err := c.ShouldBindQuery(&params); err != nil {
+		_ = c.Error(shared.GenerateErrQueryParamInvalid("start_date"))
+		return
+	}
+
+	if err := h.v.Struct(params); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	ctx := c.Request.Context()
+	res, err := h.du.GetSellerDashboard(ctx, c.GetInt64(constant.CtxUserId), params)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.JSONResponse{Data: res})
+}
+
+func (h DashboardHandler) Route(r *gin.Engine) {
+	r.
+		Group("/merchant/dashboard", middleware.AllowAuthenticated(h.cfg), middleware.IsSeller()).
+		GET("", h.getSellerDashboard)
+}
+
+func NewDashboardHandler(du usecase.DashboardUsecase, cfg dependency.Config, v *validator.Validate) DashboardHandler {
+	return DashboardHandler{
+		du:  du,
+		cfg: cfg,
+		v:   v,
+	}
+}
@@ -0,0 +1,166 @@
+package resthandler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/middleware"
+	"github.com/lil-oren/rest/internal/shared"
+	"github.com/lil-oren/rest/internal/usecase"
+)
+
+type FeeHandler struct {
+	fu       usecase.FeeUsecase
+	cfg      dependency.Config
+	validate *validator.Validate
+}
+
+func (h FeeHandler) getFeeRules(c *gin.Context) {
+	params := dto.FeeRuleParams{}
+	if err := c.ShouldBindQuery(&params); err != nil {
+		_ = c.Error(shared.GenerateErrQueryParamInvalid("page"))
+		return
+	}
+
+	if err := h.validate.Struct(params); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	ctx := c.Request.Context()
+	res, err := h.fu.GetFeeRules(ctx, params)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.JSONResponse{Data: res})
+}
+
+func (h FeeHandler) createFeeRule(c *gin.Context) {
+	body := dto.FeeRuleRequestBody{}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		_ = c.Error(shared.ErrInvalidBodySchema)
+		return
+	}
+
+	if err := h.validate.Struct(body); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	ctx := c.Request.Context()
+	res, err := h.fu.CreateFeeRule(ctx, body)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.JSONResponse{Data: res})
+}
+
+func (h FeeHandler) updateFeeRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(shared.GenerateErrPathParamInvalid("id"))
+		return
+	}
+
+	body := dto.FeeRuleRequestBody{}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		_ = c.Error(shared.ErrInvalidBodySchema)
+		return
+	}
+
+	if err := h.validate.Struct(body); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	ctx := c.Request.Context()
+	res, err := h.fu.UpdateFeeRule(ctx, int64(id), body)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.JSONResponse{Data: res})
+}
+
+func (h FeeHandler) deleteFeeRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(shared.GenerateErrPathParamInvalid("id"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.fu.DeleteFeeRule(ctx, int64(id)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (h FeeHandler) updateShopTier(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(shared.GenerateErrPathParamInvalid("id"))
+		return
+	}
+
+	body := dto.ShopTierRequestBody{}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		_ = c.Error(shared.ErrInvalidBodySchema)
+		return
+	}
+
+	if err := h.validate.Struct(body); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.fu.UpdateShopTier(ctx, int64(id), body.Tier); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (h FeeHandler) getPlatformWallet(c *gin.Context) {
+	ctx := c.Request.Context()
+	res, err := h.fu.GetPlatformWallet(ctx)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.JSONResponse{Data: res})
+}
+
+func (h FeeHandler) Route(r *gin.Engine) {
+	admin := r.Group("/admin", middleware.AllowAuthenticated(h.cfg), middleware.IsAdmin(h.cfg))
+	admin.
+		Group("/fee-rules").
+		GET("", h.getFeeRules).
+		POST("", h.createFeeRule).
+		PUT("/:id", h.updateFeeRule).
+		DELETE("/:id", h.deleteFeeRule)
+	admin.PUT("/shops/:id/tier", h.updateShopTier)
+	admin.GET("/platform-wallet", h.getPlatformWallet)
+}
+
+func NewFeeHandler(fu usecase.FeeUsecase, cfg dependency.Config, v *validator.Validate) *FeeHandler {
+	return &FeeHandler{
+		fu:       fu,
+		cfg:      cfg,
+		validate: v,
+	}
+}
@@ -0,0 +1,123 @@
+package resthandler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/middleware"
+	"github.com/lil-oren/rest/internal/shared"
+	"github.com/lil-oren/rest/internal/usecase"
+)
+
+type FlashSaleHandler struct {
+	fsu      usecase.FlashSaleUsecase
+	cfg      dependency.Config
+	validate *validator.Validate
+}
+
+func (h FlashSaleHandler) createFlashSale(c *gin.Context) {
+	body := new(dto.CreateFlashSaleRequestBody)
+	if err := c.ShouldBindJSON(body); err != nil {
+		_ = c.Error(shared.ErrInvalidBodySchema)
+		return
+	}
+
+	if err := h.validate.Struct(*body); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	ctx := c.Request.Context()
+	payload := dto.CreateFlashSalePayload{
+		SellerID: c.GetInt64(constant.CtxUserId),
+		Name:     body.Name,
+		StartAt:  body.StartAt,
+		EndAt:    body.EndAt,
+		Variants: body.Variants,
+	}
+
+	res, err := h.fsu.CreateFlashSale(ctx, payload)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.JSONResponse{Data: res})
+}
+
+func (h FlashSaleHandler) getFlashSales(c *gin.Context) {
+	params := dto.FlashSaleParams{}
+	if err := c.ShouldBindQuery(&params); err != nil {
+		_ = c.Error(shared.GenerateErrQueryParamInvalid("page"))
+		return
+	}
+
+	if err := h.validate.Struct(params); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	ctx := c.Request.Context()
+	res, err := h.fsu.GetFlashSales(ctx, c.GetInt64(constant.CtxUserId), params)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.JSONResponse{Data: res})
+}
+
+func (h FlashSaleHandler) getFlashSale(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(shared.GenerateErrPathParamInvalid("id"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	res, err := h.fsu.GetFlashSale(ctx, int64(id), c.GetInt64(constant.CtxUserId))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.JSONResponse{Data: res})
+}
+
+func (h FlashSaleHandler) cancelFlashSale(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(shared.GenerateErrPathParamInvalid("id"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.fsu.CancelFlashSale(ctx, int64(id), c.GetInt64(constant.CtxUserId)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (h FlashSaleHandler) Route(r *gin.Engine) {
+	r.
+		Group("/merchant/flash-sales", middleware.AllowAuthenticated(h.cfg), middleware.IsSeller()).
+		POST("", h.createFlashSale).
+		GET("", h.getFlashSales).
+		GET("/:id", h.getFlashSale).
+		DELETE("/:id", h.cancelFlashSale)
+}
+
+func NewFlashSaleHandler(fsu usecase.FlashSaleUsecase, cfg dependency.Config, v *validator.Validate) *FlashSaleHandler {
+	return &FlashSaleHandler{
+		fsu:      fsu,
+		cfg:      cfg,
+		validate: v,
+	}
+}
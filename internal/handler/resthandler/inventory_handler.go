@@ -0,0 +1,114 @@
+package resthandler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/middleware"
+	"github.com/lil-oren/rest/internal/shared"
+	"github.com/lil-oren/rest/internal/usecase"
+)
+
+type InventoryHandler struct {
+	iu       usecase.InventoryUsecase
+	cfg      dependency.Config
+	validate *validator.Validate
+}
+
+func (h InventoryHandler) getStockHistory(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", strconv.Itoa(constant.DefaultPage)))
+	if err != nil {
+		_ = c.Error(shared.GenerateErrQueryParamInvalid("page"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	payload := dto.StockHistoryPayload{
+		ProductCode: c.Param("code"),
+		SellerID:    c.GetInt64(constant.CtxUserId),
+		Page:        page,
+	}
+
+	res, err := h.iu.GetStockHistory(ctx, payload)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.JSONResponse{Data: res})
+}
+
+func (h InventoryHandler) bulkUpdateStock(c *gin.Context) {
+	body := new(dto.BulkUpdateStockRequestBody)
+	if err := c.ShouldBindJSON(body); err != nil {
+		_ = c.Error(shared.ErrInvalidBodySchema)
+		return
+	}
+
+	if err := h.validate.Struct(*body); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	ctx := c.Request.Context()
+	payload := dto.BulkUpdateStockPayload{
+		SellerID: c.GetInt64(constant.CtxUserId),
+		Items:    body.Items,
+	}
+
+	res, err := h.iu.BulkUpdateStock(ctx, payload)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.JSONResponse{Data: res})
+}
+
+func (h InventoryHandler) updateLowStockThreshold(c *gin.Context) {
+	body := new(dto.UpdateLowStockThresholdRequestBody)
+	if err := c.ShouldBindJSON(body); err != nil {
+		_ = c.Error(shared.ErrInvalidBodySchema)
+		return
+	}
+
+	if err := h.validate.Struct(*body); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	ctx := c.Request.Context()
+	payload := dto.UpdateLowStockThresholdPayload{
+		ProductCode: c.Param("code"),
+		SellerID:    c.GetInt64(constant.CtxUserId),
+		Threshold:   body.Threshold,
+	}
+
+	if err := h.iu.UpdateLowStockThreshold(ctx, payload); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (h InventoryHandler) Route(r *gin.Engine) {
+	r.
+		Group("/merchant/product", middleware.AllowAuthenticated(h.cfg), middleware.IsSeller()).
+		GET("/:code/stock-history", h.getStockHistory).
+		PUT("/stock", h.bulkUpdateStock).
+		PUT("/:code/low-stock-threshold", h.updateLowStockThreshold)
+}
+
+func NewInventoryHandler(iu usecase.InventoryUsecase, cfg dependency.Config, v *validator.Validate) *InventoryHandler {
+	return &InventoryHandler{
+		iu:       iu,
+		cfg:      cfg,
+		validate: v,
+	}
+}
@@ -0,0 +1,113 @@
+package resthandler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/middleware"
+	"github.com/lil-oren/rest/internal/shared"
+	"github.com/lil-oren/rest/internal/usecase"
+)
+
+type MediaHandler struct {
+	mu       usecase.MediaUsecase
+	cfg      dependency.Config
+	validate *validator.Validate
+}
+
+func (h MediaHandler) uploadMedia(c *gin.Context) {
+	maxBody := h.cfg.MediaStorage.MaxVideoSize
+	if h.cfg.MediaStorage.MaxImageSize > maxBody {
+		maxBody = h.cfg.MediaStorage.MaxImageSize
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBody+(1<<20))
+
+	fileHeader, err := c.FormFile(constant.MediaUploadFormField)
+	if err != nil {
+		_ = c.Error(shared.ErrInvalidBodySchema)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		_ = c.Error(shared.ErrInvalidBodySchema)
+		return
+	}
+	defer file.Close()
+
+	ctx := c.Request.Context()
+	accountID := c.GetInt64(constant.CtxUserId)
+	payload := dto.UploadMediaPayload{
+		AccountID: accountID,
+		FileName:  fileHeader.Filename,
+		Size:      fileHeader.Size,
+		File:      file,
+	}
+
+	res, err := h.mu.UploadMedia(ctx, payload)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.JSONResponse{Data: res})
+}
+
+func (h MediaHandler) getSignedURL(c *gin.Context) {
+	mediaID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(shared.GenerateErrPathParamInvalid("id"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	accountID := c.GetInt64(constant.CtxUserId)
+	res, err := h.mu.GetSignedURL(ctx, int64(mediaID), accountID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.JSONResponse{Data: res})
+}
+
+func (h MediaHandler) serveMedia(c *gin.Context) {
+	payload := dto.OpenMediaPayload{
+		StorageKey: strings.TrimPrefix(c.Param("key"), "/"),
+		Expires:    c.Query(constant.MediaSignatureExpires),
+		Signature:  c.Query(constant.MediaSignatureQuery),
+	}
+
+	ctx := c.Request.Context()
+	res, err := h.mu.OpenMedia(ctx, payload)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	defer res.Body.Close()
+
+	c.DataFromReader(http.StatusOK, res.Size, res.ContentType, res.Body, nil)
+}
+
+func (h MediaHandler) Route(r *gin.Engine) {
+	r.GET(constant.MediaFilesPath+"*key", h.serveMedia)
+
+	r.
+		Group("/media", middleware.AllowAuthenticated(h.cfg)).
+		POST("", h.uploadMedia).
+		GET("/:id/signed-url", h.getSignedURL)
+}
+
+func NewMediaHandler(mu usecase.MediaUsecase, cfg dependency.Config, v *validator.Validate) *MediaHandler {
+	return &MediaHandler{
+		mu:       mu,
+		cfg:      cfg,
+		validate: v,
+	}
+}
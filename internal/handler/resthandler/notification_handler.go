@@ -0,0 +1,133 @@
+package resthandler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/middleware"
+	"github.com/lil-oren/rest/internal/shared"
+	"github.com/lil-oren/rest/internal/usecase"
+)
+
+type NotificationHandler struct {
+	nu       usecase.NotificationUsecase
+	cfg      dependency.Config
+	validate *validator.Validate
+}
+
+func (h NotificationHandler) getNotifications(c *gin.Context) {
+	params := dto.NotificationParams{}
+	if err := c.ShouldBindQuery(&params); err != nil {
+		_ = c.Error(shared.GenerateErrQueryParamInvalid("page"))
+		return
+	}
+
+	if err := h.validate.Struct(params); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	ctx := c.Request.Context()
+	res, err := h.nu.GetNotifications(ctx, c.GetInt64(constant.CtxUserId), params)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.JSONResponse{Data: res})
+}
+
+func (h NotificationHandler) getUnreadCount(c *gin.Context) {
+	ctx := c.Request.Context()
+	res, err := h.nu.GetUnreadCount(ctx, c.GetInt64(constant.CtxUserId))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.JSONResponse{Data: res})
+}
+
+func (h NotificationHandler) readNotification(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(shared.GenerateErrPathParamInvalid("id"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.nu.ReadNotification(ctx, c.GetInt64(constant.CtxUserId), int64(id)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (h NotificationHandler) readAllNotifications(c *gin.Context) {
+	ctx := c.Request.Context()
+	if err := h.nu.ReadAllNotifications(ctx, c.GetInt64(constant.CtxUserId)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// streamNotifications pushes new notifications of the account signed in as
+// server-sent events until the client goes away. A comment is sent now and
+// then so proxies keep the connection open.
+func (h NotificationHandler) streamNotifications(c *gin.Context) {
+	notifications, unsubscribe := h.nu.SubscribeNotifications(c.GetInt64(constant.CtxUserId))
+	defer unsubscribe()
+
+	heartbeat := time.NewTicker(constant.NotificationStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case notification := <-notifications:
+			c.SSEvent(constant.NotificationStreamEvent, notification)
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func (h NotificationHandler) Route(r *gin.Engine) {
+	r.
+		Group("/notifications", middleware.AllowAuthenticated(h.cfg)).
+		GET("", h.getNotifications).
+		GET("/unread-count", h.getUnreadCount).
+		GET("/stream", h.streamNotifications).
+		PUT("/read", h.readAllNotifications).
+		PUT("/:id/read", h.readNotification)
+}
+
+func NewNotificationHandler(nu usecase.NotificationUsecase, cfg dependency.Config, v *validator.Validate) *NotificationHandler {
+	return &NotificationHandler{
+		nu:       nu,
+		cfg:      cfg,
+		validate: v,
+	}
+}
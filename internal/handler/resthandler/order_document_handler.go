@@ -0,0 +1,129 @@
+package resthandler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/middleware"
+	"github.com/lil-oren/rest/internal/shared"
+	"github.com/lil-oren/rest/internal/usecase"
+)
+
+type OrderDocumentHandler struct {
+	odu      usecase.OrderDocumentUsecase
+	cfg      dependency.Config
+	validate *validator.Validate
+}
+
+func (h OrderDocumentHandler) getInvoice(c *gin.Context) {
+	h.serveOrderDocument(c, h.odu.GetInvoice)
+}
+
+func (h OrderDocumentHandler) getReceipt(c *gin.Context) {
+	h.serveOrderDocument(c, h.odu.GetReceipt)
+}
+
+func (h OrderDocumentHandler) getPackingSlip(c *gin.Context) {
+	h.serveOrderDocument(c, h.odu.GetPackingSlip)
+}
+
+func (h OrderDocumentHandler) getPackingSlips(c *gin.Context) {
+	format, ok := h.bindFormat(c)
+	if !ok {
+		return
+	}
+
+	body := dto.PackingSlipsRequestBody{}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		_ = c.Error(shared.ErrInvalidBodySchema)
+		return
+	}
+
+	if err := h.validate.Struct(body); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	ctx := c.Request.Context()
+	res, err := h.odu.GetPackingSlips(ctx, body.OrderIDs, c.GetInt64(constant.CtxUserId), format)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	writeOrderDocument(c, res)
+}
+
+// serveOrderDocument answers with the document get makes of the order in the
+// path for the account signed in.
+func (h OrderDocumentHandler) serveOrderDocument(c *gin.Context, get func(ctx context.Context, orderID, accountID int64, format string) (*dto.OrderDocumentResponse, error)) {
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(shared.GenerateErrPathParamInvalid("id"))
+		return
+	}
+
+	format, ok := h.bindFormat(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	res, err := get(ctx, int64(orderID), c.GetInt64(constant.CtxUserId), format)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	writeOrderDocument(c, res)
+}
+
+func (h OrderDocumentHandler) bindFormat(c *gin.Context) (string, bool) {
+	params := dto.OrderDocumentParams{}
+	if err := c.ShouldBindQuery(&params); err != nil {
+		_ = c.Error(shared.GenerateErrQueryParamInvalid("format"))
+		return "", false
+	}
+
+	if err := h.validate.Struct(params); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return "", false
+	}
+
+	if params.Format == "" {
+		params.Format = constant.OrderDocumentFormatPDF
+	}
+
+	return params.Format, true
+}
+
+func writeOrderDocument(c *gin.Context, res *dto.OrderDocumentResponse) {
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="%s"`, res.FileName))
+	c.Data(http.StatusOK, res.ContentType, res.Body.Bytes())
+}
+
+func (h OrderDocumentHandler) Route(r *gin.Engine) {
+	r.
+		Group("/orders", middleware.AllowAuthenticated(h.cfg)).
+		GET("/:id/invoice", h.getInvoice).
+		GET("/:id/receipt", h.getReceipt)
+	r.
+		Group("/orders/seller", middleware.AllowAuthenticated(h.cfg), middleware.IsSeller()).
+		GET("/:id/packing-slip", h.getPackingSlip).
+		POST("/packing-slips", h.getPackingSlips)
+}
+
+func NewOrderDocumentHandler(odu usecase.OrderDocumentUsecase, cfg dependency.Config, v *validator.Validate) *OrderDocumentHandler {
+	return &OrderDocumentHandler{
+		odu:      odu,
+		cfg:      cfg,
+		validate: v,
+	}
+}
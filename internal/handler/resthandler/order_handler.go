@@ -27,10 +27,14 @@ func (h OrderHandler) createOrder(c *gin.Context) {
 		return
 	}
 
+	if err := h.validate.Struct(body); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
 	ctx := c.Request.Context()
 	accountId := c.GetInt64(constant.CtxUserId)
-	payload := dto.CreateOrderRequestPayload(*body)
-	err := h.ou.CreateOrder(ctx, int(accountId), payload)
+	err := h.ou.CreateOrder(ctx, int(accountId), body.CheckoutSessionID)
 	if err != nil {
 		_ = c.Error(err)
 		return
@@ -0,0 +1,256 @@
+package resthandler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/middleware"
+	"github.com/lil-oren/rest/internal/shared"
+	"github.com/lil-oren/rest/internal/usecase"
+)
+
+type OrderReturnHandler struct {
+	oru      usecase.OrderReturnUsecase
+	cfg      dependency.Config
+	validate *validator.Validate
+}
+
+func (h OrderReturnHandler) requestReturn(c *gin.Context) {
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(shared.GenerateErrPathParamInvalid("id"))
+		return
+	}
+
+	body := dto.CreateOrderReturnRequestBody{}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		_ = c.Error(shared.ErrInvalidBodySchema)
+		return
+	}
+
+	if err := h.validate.Struct(body); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	ctx := c.Request.Context()
+	payload := dto.CreateOrderReturnPayload{
+		OrderID:   int64(orderID),
+		BuyerID:   c.GetInt64(constant.CtxUserId),
+		Reason:    body.Reason,
+		ImageUrls: body.ImageUrls,
+		Items:     body.Items,
+	}
+	res, err := h.oru.RequestReturn(ctx, payload)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.JSONResponse{Data: res})
+}
+
+func (h OrderReturnHandler) getBuyerReturns(c *gin.Context) {
+	h.getReturns(c, dto.OrderReturnFilter{BuyerID: c.GetInt64(constant.CtxUserId)})
+}
+
+func (h OrderReturnHandler) getSellerReturns(c *gin.Context) {
+	h.getReturns(c, dto.OrderReturnFilter{SellerID: c.GetInt64(constant.CtxUserId)})
+}
+
+func (h OrderReturnHandler) getArbitrationReturns(c *gin.Context) {
+	h.getReturns(c, dto.OrderReturnFilter{})
+}
+
+func (h OrderReturnHandler) getReturns(c *gin.Context, filter dto.OrderReturnFilter) {
+	params := dto.OrderReturnParams{}
+	if err := c.ShouldBindQuery(&params); err != nil {
+		_ = c.Error(shared.GenerateErrQueryParamInvalid("page"))
+		return
+	}
+
+	if err := h.validate.Struct(params); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	ctx := c.Request.Context()
+	filter.Status = params.Status
+	filter.Page = params.Page
+	res, err := h.oru.GetReturns(ctx, filter)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.JSONResponse{Data: res})
+}
+
+func (h OrderReturnHandler) getReturn(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(shared.GenerateErrPathParamInvalid("id"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	res, err := h.oru.GetReturn(ctx, int64(id), c.GetInt64(constant.CtxUserId))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.JSONResponse{Data: res})
+}
+
+func (h OrderReturnHandler) escalateReturn(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(shared.GenerateErrPathParamInvalid("id"))
+		return
+	}
+
+	body := dto.EscalateOrderReturnRequestBody{}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		_ = c.Error(shared.ErrInvalidBodySchema)
+		return
+	}
+
+	if err := h.validate.Struct(body); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.oru.EscalateReturn(ctx, int64(id), c.GetInt64(constant.CtxUserId), body.Reason); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (h OrderReturnHandler) acceptReturn(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(shared.GenerateErrPathParamInvalid("id"))
+		return
+	}
+
+	body := dto.OrderReturnNoteRequestBody{}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		_ = c.Error(shared.ErrInvalidBodySchema)
+		return
+	}
+
+	if err := h.validate.Struct(body); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.oru.AcceptReturn(ctx, int64(id), c.GetInt64(constant.CtxUserId), body.Note); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (h OrderReturnHandler) rejectReturn(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(shared.GenerateErrPathParamInvalid("id"))
+		return
+	}
+
+	body := dto.RejectOrderReturnRequestBody{}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		_ = c.Error(shared.ErrInvalidBodySchema)
+		return
+	}
+
+	if err := h.validate.Struct(body); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.oru.RejectReturn(ctx, int64(id), c.GetInt64(constant.CtxUserId), body.Note); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (h OrderReturnHandler) arbitrateRefund(c *gin.Context) {
+	h.arbitrate(c, h.oru.ArbitrateRefund)
+}
+
+func (h OrderReturnHandler) arbitrateClose(c *gin.Context) {
+	h.arbitrate(c, h.oru.ArbitrateClose)
+}
+
+func (h OrderReturnHandler) arbitrate(c *gin.Context, decide func(ctx context.Context, id int64, note string) error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(shared.GenerateErrPathParamInvalid("id"))
+		return
+	}
+
+	body := dto.OrderReturnNoteRequestBody{}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		_ = c.Error(shared.ErrInvalidBodySchema)
+		return
+	}
+
+	if err := h.validate.Struct(body); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := decide(ctx, int64(id), body.Note); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (h OrderReturnHandler) Route(r *gin.Engine) {
+	r.
+		Group("/orders", middleware.AllowAuthenticated(h.cfg)).
+		POST("/:id/returns", h.requestReturn).
+		GET("/returns", h.getBuyerReturns).
+		GET("/returns/:id", h.getReturn).
+		PUT("/returns/:id/escalate", h.escalateReturn)
+
+	r.
+		Group("/orders/seller/returns", middleware.AllowAuthenticated(h.cfg), middleware.IsSeller()).
+		GET("", h.getSellerReturns).
+		GET("/:id", h.getReturn).
+		PUT("/:id/accept", h.acceptReturn).
+		PUT("/:id/reject", h.rejectReturn)
+
+	r.
+		Group("/admin/returns", middleware.AllowAuthenticated(h.cfg), middleware.IsAdmin(h.cfg)).
+		GET("", h.getArbitrationReturns).
+		PUT("/:id/refund", h.arbitrateRefund).
+		PUT("/:id/close", h.arbitrateClose)
+}
+
+func NewOrderReturnHandler(oru usecase.OrderReturnUsecase, cfg dependency.Config, v *validator.Validate) *OrderReturnHandler {
+	return &OrderReturnHandler{
+		oru:      oru,
+		cfg:      cfg,
+		validate: v,
+	}
+}
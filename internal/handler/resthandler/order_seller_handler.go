@@ -93,6 +93,15 @@ func (h OrderSellerHandler) orderStatusDeliver(c *gin.Context) {
 		_ = c.Error(err.(validator.ValidationErrors))
 		return
 	}
+	match, err := shared.WaybillNumberRegex.MatchString(req.WaybillNumber)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if !match {
+		_ = c.Error(shared.ErrInvalidWaybillNumber)
+		return
+	}
 
 	if err := h.ouc.UpdateOrderStatus(ctx, int64(orderId), req, sellerId); err != nil {
 		_ = c.Error(err)
@@ -0,0 +1,109 @@
+package resthandler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/middleware"
+	"github.com/lil-oren/rest/internal/shared"
+	"github.com/lil-oren/rest/internal/usecase"
+)
+
+type PlatformVoucherHandler struct {
+	vu  usecase.VoucherUsecase
+	cfg dependency.Config
+	v   *validator.Validate
+}
+
+func (h PlatformVoucherHandler) getPlatformVouchers(c *gin.Context) {
+	params := dto.VoucherParams{}
+	if err := c.ShouldBindQuery(&params); err != nil {
+		_ = c.Error(shared.GenerateErrQueryParamInvalid("page"))
+		return
+	}
+
+	if err := h.v.Struct(params); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	ctx := c.Request.Context()
+	res, err := h.vu.GetPlatformVouchers(ctx, params)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.JSONResponse{Data: res})
+}
+
+func (h PlatformVoucherHandler) createPlatformVoucher(c *gin.Context) {
+	body := new(dto.VoucherRequestBody)
+	if err := c.ShouldBindJSON(body); err != nil {
+		_ = c.Error(shared.ErrInvalidBodySchema)
+		return
+	}
+
+	if err := h.v.Struct(*body); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	ctx := c.Request.Context()
+	res, err := h.vu.CreatePlatformVoucher(ctx, toVoucherPayload(*body))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.JSONResponse{Data: res})
+}
+
+func (h PlatformVoucherHandler) updatePlatformVoucher(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(shared.GenerateErrPathParamInvalid("id"))
+		return
+	}
+
+	body := new(dto.VoucherRequestBody)
+	if err := c.ShouldBindJSON(body); err != nil {
+		_ = c.Error(shared.ErrInvalidBodySchema)
+		return
+	}
+
+	if err := h.v.Struct(*body); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	ctx := c.Request.Context()
+	payload := toVoucherPayload(*body)
+	payload.ID = int64(id)
+	res, err := h.vu.UpdatePlatformVoucher(ctx, payload)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.JSONResponse{Data: res})
+}
+
+func (h PlatformVoucherHandler) Route(r *gin.Engine) {
+	r.Group("/admin/vouchers", middleware.AllowAuthenticated(h.cfg), middleware.IsAdmin(h.cfg)).
+		GET("", h.getPlatformVouchers).
+		POST("", h.createPlatformVoucher).
+		PUT("/:id", h.updatePlatformVoucher)
+}
+
+func NewPlatformVoucherHandler(vu usecase.VoucherUsecase, cfg dependency.Config, v *validator.Validate) PlatformVoucherHandler {
+	return PlatformVoucherHandler{
+		vu:  vu,
+		cfg: cfg,
+		v:   v,
+	}
+}
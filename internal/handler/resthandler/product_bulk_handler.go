@@ -0,0 +1,106 @@
+package resthandler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/middleware"
+	"github.com/lil-oren/rest/internal/shared"
+	"github.com/lil-oren/rest/internal/usecase"
+)
+
+type ProductBulkHandler struct {
+	pbu      usecase.ProductBulkUsecase
+	cfg      dependency.Config
+	validate *validator.Validate
+}
+
+func (h ProductBulkHandler) importProducts(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, constant.ProductImportMaxFileSize)
+
+	fileHeader, err := c.FormFile(constant.ProductImportFormField)
+	if err != nil {
+		_ = c.Error(shared.ErrInvalidBodySchema)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		_ = c.Error(shared.ErrInvalidBodySchema)
+		return
+	}
+	defer file.Close()
+
+	ctx := c.Request.Context()
+	accountID := c.GetInt64(constant.CtxUserId)
+	payload := dto.ImportProductPayload{
+		AccountID: accountID,
+		FileName:  fileHeader.Filename,
+		File:      file,
+	}
+
+	res, err := h.pbu.ImportProducts(ctx, payload)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.JSONResponse{Data: res})
+}
+
+func (h ProductBulkHandler) getImportJob(c *gin.Context) {
+	jobID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(shared.GenerateErrPathParamInvalid("id"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	accountID := c.GetInt64(constant.CtxUserId)
+	res, err := h.pbu.GetImportJob(ctx, int64(jobID), accountID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.JSONResponse{Data: res})
+}
+
+func (h ProductBulkHandler) exportProducts(c *gin.Context) {
+	ctx := c.Request.Context()
+	payload := dto.ExportProductPayload{
+		AccountID: c.GetInt64(constant.CtxUserId),
+		Format:    c.DefaultQuery("format", constant.ProductExportFormatCSV),
+	}
+
+	res, err := h.pbu.ExportProducts(ctx, payload)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, res.FileName))
+	c.Data(http.StatusOK, res.ContentType, res.Body.Bytes())
+}
+
+func (h ProductBulkHandler) Route(r *gin.Engine) {
+	r.
+		Group("/merchant/product", middleware.AllowAuthenticated(h.cfg), middleware.IsSeller()).
+		POST("/bulk", h.importProducts).
+		GET("/bulk/:id", h.getImportJob).
+		GET("/export", h.exportProducts)
+}
+
+func NewProductBulkHandler(pbu usecase.ProductBulkUsecase, cfg dependency.Config, v *validator.Validate) *ProductBulkHandler {
+	return &ProductBulkHandler{
+		pbu:      pbu,
+		cfg:      cfg,
+		validate: v,
+	}
+}
@@ -45,6 +45,8 @@ func (h ProductPageHandler) listProduct(c *gin.Context) {
 		categoryIdQuery = c.DefaultQuery("category", "")
 		maxPriceQuery   = c.DefaultQuery("max_price", "0")
 		minPriceQuery   = c.DefaultQuery("min_price", "0")
+		minRatingQuery  = c.DefaultQuery("min_rating", "0")
+		inStockQuery    = c.DefaultQuery("in_stock", "false")
 	)
 
 	payload := dto.SearchProductPayload{
@@ -107,6 +109,21 @@ func (h ProductPageHandler) listProduct(c *gin.Context) {
 	payload.MaxPrice = maxPrice
 	payload.MinPrice = minPrice
 
+	minRating, err := strconv.ParseFloat(minRatingQuery, 64)
+	if err != nil {
+		_ = c.Error(shared.GenerateErrQueryParamInvalid("min_rating"))
+		return
+	}
+
+	inStock, err := strconv.ParseBool(inStockQuery)
+	if err != nil {
+		_ = c.Error(shared.GenerateErrQueryParamInvalid("in_stock"))
+		return
+	}
+
+	payload.MinRating = minRating
+	payload.InStock = inStock
+
 	if err := h.validate.Struct(payload); err != nil {
 		_ = c.Error(err.(validator.ValidationErrors))
 		return
@@ -0,0 +1,119 @@
+package resthandler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/middleware"
+	"github.com/lil-oren/rest/internal/shared"
+	"github.com/lil-oren/rest/internal/usecase"
+)
+
+type SalesReportHandler struct {
+	sru      usecase.SalesReportUsecase
+	cfg      dependency.Config
+	validate *validator.Validate
+}
+
+func (h SalesReportHandler) exportSalesReport(c *gin.Context) {
+	params := dto.SalesReportParams{}
+	if err := c.ShouldBindQuery(&params); err != nil {
+		_ = c.Error(shared.GenerateErrQueryParamInvalid("start_date"))
+		return
+	}
+
+	if err := h.validate.Struct(params); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	ctx := c.Request.Context()
+	payload := dto.SalesReportPayload{
+		SellerID:  c.GetInt64(constant.CtxUserId),
+		StartDate: params.StartDate,
+		EndDate:   params.EndDate,
+		Status:    string(params.Status),
+		Format:    params.Format,
+	}
+	if payload.Format == "" {
+		payload.Format = constant.ProductExportFormatCSV
+	}
+
+	res, err := h.sru.ExportSalesReport(ctx, payload)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if res.Job != nil {
+		c.JSON(http.StatusAccepted, dto.JSONResponse{Data: res.Job})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, res.FileName))
+	c.Header("Content-Type", res.ContentType)
+	if err := h.sru.WriteSalesReport(ctx, payload, c.Writer); err != nil {
+		_ = c.Error(err)
+		return
+	}
+}
+
+func (h SalesReportHandler) getSalesReportJob(c *gin.Context) {
+	jobID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(shared.GenerateErrPathParamInvalid("id"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	res, err := h.sru.GetSalesReportJob(ctx, int64(jobID), c.GetInt64(constant.CtxUserId))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.JSONResponse{Data: res})
+}
+
+func (h SalesReportHandler) downloadSalesReport(c *gin.Context) {
+	jobID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(shared.GenerateErrPathParamInvalid("id"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	res, err := h.sru.DownloadSalesReport(ctx, int64(jobID), c.GetInt64(constant.CtxUserId))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	defer res.Body.Close()
+
+	extraHeaders := map[string]string{
+		"Content-Disposition": fmt.Sprintf(`attachment; filename="%s"`, res.FileName),
+	}
+	c.DataFromReader(http.StatusOK, -1, res.ContentType, res.Body, extraHeaders)
+}
+
+func (h SalesReportHandler) Route(r *gin.Engine) {
+	r.
+		Group("/orders/seller/export", middleware.AllowAuthenticated(h.cfg), middleware.IsSeller()).
+		GET("", h.exportSalesReport).
+		GET("/:id", h.getSalesReportJob).
+		GET("/:id/download", h.downloadSalesReport)
+}
+
+func NewSalesReportHandler(sru usecase.SalesReportUsecase, cfg dependency.Config, v *validator.Validate) *SalesReportHandler {
+	return &SalesReportHandler{
+		sru:      sru,
+		cfg:      cfg,
+		validate: v,
+	}
+}
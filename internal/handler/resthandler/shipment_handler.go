@@ -0,0 +1,49 @@
+package resthandler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/middleware"
+	"github.com/lil-oren/rest/internal/shared"
+	"github.com/lil-oren/rest/internal/usecase"
+)
+
+type ShipmentHandler struct {
+	su  usecase.ShipmentUsecase
+	cfg dependency.Config
+}
+
+func (h ShipmentHandler) getOrderTracking(c *gin.Context) {
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(shared.GenerateErrPathParamInvalid("id"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	res, err := h.su.GetOrderTracking(ctx, int64(orderID), c.GetInt64(constant.CtxUserId))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.JSONResponse{Data: res})
+}
+
+func (h ShipmentHandler) Route(r *gin.Engine) {
+	r.
+		Group("/orders", middleware.AllowAuthenticated(h.cfg)).
+		GET("/:id/tracking", h.getOrderTracking)
+}
+
+func NewShipmentHandler(su usecase.ShipmentUsecase, cfg dependency.Config) *ShipmentHandler {
+	return &ShipmentHandler{
+		su:  su,
+		cfg: cfg,
+	}
+}
@@ -0,0 +1,125 @@
+package resthandler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/middleware"
+	"github.com/lil-oren/rest/internal/shared"
+	"github.com/lil-oren/rest/internal/usecase"
+)
+
+type ShippingRateHandler struct {
+	sru      usecase.ShippingRateUsecase
+	cfg      dependency.Config
+	validate *validator.Validate
+}
+
+func (h ShippingRateHandler) getTableRates(c *gin.Context) {
+	params := dto.ShippingTableRateParams{}
+	if err := c.ShouldBindQuery(&params); err != nil {
+		_ = c.Error(shared.GenerateErrQueryParamInvalid("page"))
+		return
+	}
+
+	if err := h.validate.Struct(params); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	ctx := c.Request.Context()
+	res, err := h.sru.GetTableRates(ctx, params)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.JSONResponse{Data: res})
+}
+
+func (h ShippingRateHandler) createTableRate(c *gin.Context) {
+	body := dto.ShippingTableRateRequestBody{}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		_ = c.Error(shared.ErrInvalidBodySchema)
+		return
+	}
+
+	if err := h.validate.Struct(body); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	ctx := c.Request.Context()
+	res, err := h.sru.CreateTableRate(ctx, body)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.JSONResponse{Data: res})
+}
+
+func (h ShippingRateHandler) updateTableRate(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(shared.GenerateErrPathParamInvalid("id"))
+		return
+	}
+
+	body := dto.ShippingTableRateRequestBody{}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		_ = c.Error(shared.ErrInvalidBodySchema)
+		return
+	}
+
+	if err := h.validate.Struct(body); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	ctx := c.Request.Context()
+	res, err := h.sru.UpdateTableRate(ctx, int64(id), body)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.JSONResponse{Data: res})
+}
+
+func (h ShippingRateHandler) deleteTableRate(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(shared.GenerateErrPathParamInvalid("id"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.sru.DeleteTableRate(ctx, int64(id)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (h ShippingRateHandler) Route(r *gin.Engine) {
+	r.
+		Group("/admin/shipping-rates", middleware.AllowAuthenticated(h.cfg), middleware.IsAdmin(h.cfg)).
+		GET("", h.getTableRates).
+		POST("", h.createTableRate).
+		PUT("/:id", h.updateTableRate).
+		DELETE("/:id", h.deleteTableRate)
+}
+
+func NewShippingRateHandler(sru usecase.ShippingRateUsecase, cfg dependency.Config, v *validator.Validate) *ShippingRateHandler {
+	return &ShippingRateHandler{
+		sru:      sru,
+		cfg:      cfg,
+		validate: v,
+	}
+}
@@ -167,9 +167,20 @@ func (h ShopHandler) getProduct(c *gin.Context) {
 		return
 	}
 
+	status := c.Query("status")
+	if status != "" && !constant.ProductStatuses[status] {
+		_ = c.Error(shared.GenerateErrQueryParamInvalid("status"))
+		return
+	}
+
 	ctx := c.Request.Context()
 	accountId := c.GetInt64(constant.CtxUserId)
-	products, err := h.su.GetAllProduct(ctx, int(accountId), page)
+	payload := dto.GetAllProductPayload{
+		SellerID: int(accountId),
+		Page:     page,
+		Status:   status,
+	}
+	products, err := h.su.GetAllProduct(ctx, payload)
 	if err != nil {
 		_ = c.Error(err)
 		return
@@ -248,6 +259,36 @@ func (h ShopHandler) deleteProduct(c *gin.Context) {
 	c.Status(http.StatusOK)
 }
 
+func (h ShopHandler) updateProductStatus(c *gin.Context) {
+	body := new(dto.UpdateProductStatusRequestBody)
+	if err := c.ShouldBindJSON(body); err != nil {
+		_ = c.Error(shared.ErrInvalidBodySchema)
+		return
+	}
+
+	if err := h.validate.Struct(*body); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	ctx := c.Request.Context()
+	accountId := c.GetInt64(constant.CtxUserId)
+	payload := dto.UpdateProductStatusPayload{
+		ProductCode: c.Param("code"),
+		SellerID:    accountId,
+		Status:      body.Status,
+		PublishAt:   body.PublishAt,
+		UnpublishAt: body.UnpublishAt,
+	}
+	err := h.su.UpdateProductStatus(ctx, payload)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
 func (h ShopHandler) Route(r *gin.Engine) {
 	r.
 		Group("/merchant", middleware.AllowAuthenticated(h.config)).
@@ -263,6 +304,7 @@ func (h ShopHandler) Route(r *gin.Engine) {
 		GET("/product/discount/:code", h.getProductDiscount).
 		GET("/product-detail/:code", h.getMerchantProductDetail).
 		PUT("/product/discount/:code", h.updateProductDiscount).
+		PUT("/product/status/:code", h.updateProductStatus).
 		DELETE("/product/:code", h.deleteProduct)
 }
 
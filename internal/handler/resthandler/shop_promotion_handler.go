@@ -62,6 +62,7 @@ func (h ShopPromotionHandler) addShopPromotion(c *gin.Context) {
 		Percentage:   req.Percentage,
 		MinimumSpend: req.MinimumSpend,
 		Quota:        req.Quota,
+		UsageLimit:   req.UsageLimit,
 		StartedAt:    req.StartedAt,
 		ExpiredAt:    req.ExpiredAt,
 	}
@@ -101,6 +102,7 @@ func (h ShopPromotionHandler) updateShopPromotion(c *gin.Context) {
 		Percentage:   req.Percentage,
 		MinimumSpend: req.MinimumSpend,
 		Quota:        req.Quota,
+		UsageLimit:   req.UsageLimit,
 		StartedAt:    req.StartedAt,
 		ExpiredAt:    req.ExpiredAt,
 	}
@@ -146,11 +148,29 @@ func (h ShopPromotionHandler) deleteShopPromotion(c *gin.Context) {
 	c.Status(http.StatusOK)
 }
 
+func (h ShopPromotionHandler) getPromotionAnalytics(c *gin.Context) {
+	promoID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(shared.GenerateErrPathParamInvalid("id"))
+		return
+	}
+
+	sellerID := c.GetInt64(constant.CtxUserId)
+	ctx := c.Request.Context()
+	res, err := h.puc.GetPromotionAnalytics(ctx, int64(promoID), sellerID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.JSONResponse{Data: res})
+}
+
 func (h ShopPromotionHandler) Route(r *gin.Engine) {
 	r.Group("/shop-promotions", middleware.AllowAuthenticated(h.cfg), middleware.IsSeller()).
 		GET("", h.getAllPromoFromShop).
 		POST("", h.addShopPromotion).
 		PUT("/:id", h.updateShopPromotion).
+		GET("/:id/analytics", h.getPromotionAnalytics).
 		POST("/:id/duplicate", h.duplicateShopPromotion).
 		DELETE("/:id", h.deleteShopPromotion)
 }
@@ -0,0 +1,115 @@
+package resthandler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/middleware"
+	"github.com/lil-oren/rest/internal/shared"
+	"github.com/lil-oren/rest/internal/usecase"
+)
+
+type ShopVoucherHandler struct {
+	vu  usecase.VoucherUsecase
+	cfg dependency.Config
+	v   *validator.Validate
+}
+
+func (h ShopVoucherHandler) createShopVoucher(c *gin.Context) {
+	body := new(dto.VoucherRequestBody)
+	if err := c.ShouldBindJSON(body); err != nil {
+		_ = c.Error(shared.ErrInvalidBodySchema)
+		return
+	}
+
+	if err := h.v.Struct(*body); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	ctx := c.Request.Context()
+	payload := toVoucherPayload(*body)
+	payload.SellerID = c.GetInt64(constant.CtxUserId)
+
+	res, err := h.vu.CreateShopVoucher(ctx, payload)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.JSONResponse{Data: res})
+}
+
+func toVoucherPayload(body dto.VoucherRequestBody) dto.VoucherPayload {
+	return dto.VoucherPayload{
+		Code:              body.Code,
+		Name:              body.Name,
+		Type:              body.Type,
+		Value:             body.Value,
+		MaxDiscount:       body.MaxDiscount,
+		MinimumSpend:      body.MinimumSpend,
+		Quota:             body.Quota,
+		UsageLimitPerUser: body.UsageLimitPerUser,
+		CategoryIDs:       body.CategoryIDs,
+		StartedAt:         body.StartedAt,
+		ExpiredAt:         body.ExpiredAt,
+	}
+}
+
+func (h ShopVoucherHandler) getShopVouchers(c *gin.Context) {
+	params := dto.VoucherParams{}
+	if err := c.ShouldBindQuery(&params); err != nil {
+		_ = c.Error(shared.GenerateErrQueryParamInvalid("page"))
+		return
+	}
+
+	if err := h.v.Struct(params); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	ctx := c.Request.Context()
+	res, err := h.vu.GetShopVouchers(ctx, c.GetInt64(constant.CtxUserId), params)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.JSONResponse{Data: res})
+}
+
+func (h ShopVoucherHandler) deleteShopVoucher(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(shared.GenerateErrPathParamInvalid("id"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.vu.DeleteShopVoucher(ctx, int64(id), c.GetInt64(constant.CtxUserId)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (h ShopVoucherHandler) Route(r *gin.Engine) {
+	r.Group("/shop-vouchers", middleware.AllowAuthenticated(h.cfg), middleware.IsSeller()).
+		GET("", h.getShopVouchers).
+		POST("", h.createShopVoucher).
+		DELETE("/:id", h.deleteShopVoucher)
+}
+
+func NewShopVoucherHandler(vu usecase.VoucherUsecase, cfg dependency.Config, v *validator.Validate) ShopVoucherHandler {
+	return ShopVoucherHandler{
+		vu:  vu,
+		cfg: cfg,
+		v:   v,
+	}
+}
@@ -0,0 +1,194 @@
+package resthandler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/middleware"
+	"github.com/lil-oren/rest/internal/shared"
+	"github.com/lil-oren/rest/internal/usecase"
+)
+
+type WebhookHandler struct {
+	wu       usecase.WebhookUsecase
+	cfg      dependency.Config
+	validate *validator.Validate
+}
+
+func (h WebhookHandler) createWebhook(c *gin.Context) {
+	body := dto.WebhookRequestBody{}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		_ = c.Error(shared.ErrInvalidBodySchema)
+		return
+	}
+
+	if err := h.validate.Struct(body); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	ctx := c.Request.Context()
+	res, err := h.wu.CreateWebhook(ctx, c.GetInt64(constant.CtxUserId), body)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.JSONResponse{Data: res})
+}
+
+func (h WebhookHandler) getWebhooks(c *gin.Context) {
+	ctx := c.Request.Context()
+	res, err := h.wu.GetWebhooks(ctx, c.GetInt64(constant.CtxUserId))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.JSONResponse{Data: res})
+}
+
+func (h WebhookHandler) updateWebhook(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(shared.GenerateErrPathParamInvalid("id"))
+		return
+	}
+
+	body := dto.WebhookRequestBody{}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		_ = c.Error(shared.ErrInvalidBodySchema)
+		return
+	}
+
+	if err := h.validate.Struct(body); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	ctx := c.Request.Context()
+	res, err := h.wu.UpdateWebhook(ctx, int64(id), c.GetInt64(constant.CtxUserId), body)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.JSONResponse{Data: res})
+}
+
+func (h WebhookHandler) deleteWebhook(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(shared.GenerateErrPathParamInvalid("id"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.wu.DeleteWebhook(ctx, int64(id), c.GetInt64(constant.CtxUserId)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (h WebhookHandler) pingWebhook(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(shared.GenerateErrPathParamInvalid("id"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	res, err := h.wu.PingWebhook(ctx, int64(id), c.GetInt64(constant.CtxUserId))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.JSONResponse{Data: res})
+}
+
+func (h WebhookHandler) getDeliveries(c *gin.Context) {
+	params := dto.WebhookDeliveryParams{}
+	if err := c.ShouldBindQuery(&params); err != nil {
+		_ = c.Error(shared.GenerateErrQueryParamInvalid("page"))
+		return
+	}
+
+	if err := h.validate.Struct(params); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	h.listDeliveries(c, params)
+}
+
+func (h WebhookHandler) getDeadLetters(c *gin.Context) {
+	params := dto.WebhookDeliveryParams{}
+	if err := c.ShouldBindQuery(&params); err != nil {
+		_ = c.Error(shared.GenerateErrQueryParamInvalid("page"))
+		return
+	}
+
+	if err := h.validate.Struct(params); err != nil {
+		_ = c.Error(err.(validator.ValidationErrors))
+		return
+	}
+
+	params.Status = constant.WebhookDeliveryDead
+	h.listDeliveries(c, params)
+}
+
+func (h WebhookHandler) listDeliveries(c *gin.Context, params dto.WebhookDeliveryParams) {
+	ctx := c.Request.Context()
+	res, err := h.wu.GetDeliveries(ctx, c.GetInt64(constant.CtxUserId), params)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.JSONResponse{Data: res})
+}
+
+func (h WebhookHandler) redeliver(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(shared.GenerateErrPathParamInvalid("id"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.wu.RedeliverDelivery(ctx, int64(id), c.GetInt64(constant.CtxUserId)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+func (h WebhookHandler) Route(r *gin.Engine) {
+	r.
+		Group("/merchant/webhooks", middleware.AllowAuthenticated(h.cfg), middleware.IsSeller()).
+		POST("", h.createWebhook).
+		GET("", h.getWebhooks).
+		GET("/deliveries", h.getDeliveries).
+		GET("/dead-letters", h.getDeadLetters).
+		POST("/deliveries/:id/redeliver", h.redeliver).
+		PUT("/:id", h.updateWebhook).
+		DELETE("/:id", h.deleteWebhook).
+		POST("/:id/ping", h.pingWebhook)
+}
+
+func NewWebhookHandler(wu usecase.WebhookUsecase, cfg dependency.Config, v *validator.Validate) *WebhookHandler {
+	return &WebhookHandler{
+		wu:       wu,
+		cfg:      cfg,
+		validate: v,
+	}
+}
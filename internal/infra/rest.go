@@ -14,7 +14,9 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/go-redis/redis/v8"
 	"github.com/jmoiron/sqlx"
+	"github.com/lil-oren/rest/internal/constant"
 	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/handler/jobhandler"
 	"github.com/lil-oren/rest/internal/handler/resthandler"
 	"github.com/lil-oren/rest/internal/middleware"
 	"github.com/lil-oren/rest/internal/repository"
@@ -32,32 +34,58 @@ type (
 	}
 
 	repositories struct {
-		exampleRepository        repository.ExampleRepository
-		accountRepository        repository.AccountRepository
-		accountAddressRepository repository.AccountAddressRepository
-		productRepository        repository.ProductRepository
-		productVariantRepository repository.ProductVariantRepository
-		productMediaRepository   repository.ProductMediaRepository
-		shopRepository           repository.ShopRepository
-		variantTypeRepository    repository.VariantTypeRepository
-		variantGroupRepository   repository.VariantGroupRepository
-		provinceRepository       repository.ProvinceRepository
-		districtRepository       repository.DistrictRepository
-		shopCourierRepository    repository.ShopCourierRepository
-		cacheRepository          repository.CacheRepository
-		cartRepository           repository.CartRepository
-		walletRepository         repository.WalletRepository
-		orderRepository          repository.OrderRepository
-		courierRepository        repository.CourierRepository
-		rajaOngkirRepository     repository.RajaOngkirRepository
-		changedEmailRepository   repository.ChangedEmailRepository
-		transactionRepository    repository.TransactionRepository
-		wishlistRepository       repository.WishlistRepository
-		sellerPageRepository     repository.SellerPageRepository
-		categoryRepository       repository.CategoryRepository
-		reviewRepository         repository.ReviewRepository
-		promotionRepository      repository.PromotionRepository
-		orderDetailRepository    repository.OrderDetailRepository
+		exampleRepository          repository.ExampleRepository
+		accountRepository          repository.AccountRepository
+		accountAddressRepository   repository.AccountAddressRepository
+		productRepository          repository.ProductRepository
+		productVariantRepository   repository.ProductVariantRepository
+		productMediaRepository     repository.ProductMediaRepository
+		shopRepository             repository.ShopRepository
+		variantTypeRepository      repository.VariantTypeRepository
+		variantGroupRepository     repository.VariantGroupRepository
+		provinceRepository         repository.ProvinceRepository
+		districtRepository         repository.DistrictRepository
+		shopCourierRepository      repository.ShopCourierRepository
+		cacheRepository            repository.CacheRepository
+		cartRepository             repository.CartRepository
+		walletRepository           repository.WalletRepository
+		orderRepository            repository.OrderRepository
+		courierRepository          repository.CourierRepository
+		rajaOngkirRepository       repository.RajaOngkirRepository
+		changedEmailRepository     repository.ChangedEmailRepository
+		transactionRepository      repository.TransactionRepository
+		wishlistRepository         repository.WishlistRepository
+		sellerPageRepository       repository.SellerPageRepository
+		categoryRepository         repository.CategoryRepository
+		reviewRepository           repository.ReviewRepository
+		promotionRepository        repository.PromotionRepository
+		orderDetailRepository      repository.OrderDetailRepository
+		mediaRepository            repository.MediaRepository
+		mediaFetcher               repository.MediaFetcher
+		mediaStorage               repository.MediaStorage
+		productImportJobRepository repository.ProductImportJobRepository
+		inventoryRepository        repository.InventoryRepository
+		flashSaleRepository        repository.FlashSaleRepository
+		voucherRepository          repository.VoucherRepository
+		productStatRepository      repository.ProductStatRepository
+		dashboardRepository        repository.DashboardRepository
+		salesReportJobRepository   repository.SalesReportJobRepository
+		orderReturnRepository      repository.OrderReturnRepository
+		orderShipmentRepository    repository.OrderShipmentRepository
+		shipmentTracker            repository.ShipmentTracker
+		shippingRateRepository     repository.ShippingTableRateRepository
+		shippingProviders          repository.ShippingProviderRegistry
+		regionProvider             repository.RegionProvider
+		feeRuleRepository          repository.FeeRuleRepository
+		orderDocumentRepository    repository.OrderDocumentRepository
+		notificationRepository     repository.NotificationRepository
+		notificationBroker         repository.NotificationBroker
+		outboxRepository           repository.OutboxRepository
+		webhookRepository          repository.WebhookRepository
+		webhookSender              repository.WebhookSender
+		chatRepository             repository.ChatRepository
+		chatQuickReplyRepository   repository.ChatQuickReplyRepository
+		chatBroker                 repository.ChatBroker
 	}
 
 	usecases struct {
@@ -78,10 +106,28 @@ type (
 		wishlistUseCase       usecase.WishlistUseCase
 		reviewUsecase         usecase.ReviewUsecase
 		promotionUsecase      usecase.PromotionUsecase
+		mediaUsecase          usecase.MediaUsecase
+		productBulkUsecase    usecase.ProductBulkUsecase
+		inventoryUsecase      usecase.InventoryUsecase
+		flashSaleUsecase      usecase.FlashSaleUsecase
+		voucherUsecase        usecase.VoucherUsecase
+		dashboardUsecase      usecase.DashboardUsecase
+		salesReportUsecase    usecase.SalesReportUsecase
+		orderReturnUsecase    usecase.OrderReturnUsecase
+		shipmentUsecase       usecase.ShipmentUsecase
+		shippingRateUsecase   usecase.ShippingRateUsecase
+		regionSyncUsecase     usecase.RegionSyncUsecase
+		feeUsecase            usecase.FeeUsecase
+		orderDocumentUsecase  usecase.OrderDocumentUsecase
+		notificationUsecase   usecase.NotificationUsecase
+		eventUsecase          usecase.EventUsecase
+		webhookUsecase        usecase.WebhookUsecase
+		chatUsecase           usecase.ChatUsecase
 	}
 )
 
 func (s *server) initRepository(db *sqlx.DB, rd *redis.Client, cfg dependency.Config) {
+	roClient := dependency.NewRajaOngkirClient(cfg)
 
 	s.repositories.exampleRepository = repository.NewExampleRepository()
 	s.repositories.categoryRepository = repository.NewCategoryRepository(db)
@@ -104,17 +150,54 @@ func (s *server) initRepository(db *sqlx.DB, rd *redis.Client, cfg dependency.Co
 	s.repositories.cartRepository = repository.NewCartRepository(db)
 	s.repositories.orderRepository = repository.NewOrderRepository(db, s.repositories.transactionRepository)
 	s.repositories.courierRepository = repository.NewCourierRepository(db)
-	s.repositories.rajaOngkirRepository = repository.NewRajaOngkirRepository(cfg)
+	s.repositories.rajaOngkirRepository = repository.NewRajaOngkirRepository(roClient, s.repositories.cacheRepository, s.repositories.districtRepository, cfg)
 	s.repositories.changedEmailRepository = repository.NewChangedEmailRepository(db)
 	s.repositories.sellerPageRepository = repository.NewSellerPageRepository(db)
 	s.repositories.wishlistRepository = repository.NewWishlistRepository(db)
 	s.repositories.reviewRepository = repository.NewReviewRepository(db)
 	s.repositories.promotionRepository = repository.NewPromotionRepository(db)
 	s.repositories.orderDetailRepository = repository.NewOrderDetailRepository(db)
+	s.repositories.mediaRepository = repository.NewMediaRepository(db)
+	s.repositories.mediaFetcher = repository.NewMediaFetcher()
+	s.repositories.mediaStorage = repository.NewMediaStorage(cfg)
+	s.repositories.productImportJobRepository = repository.NewProductImportJobRepository(db)
+	s.repositories.inventoryRepository = repository.NewInventoryRepository(db)
+	s.repositories.flashSaleRepository = repository.NewFlashSaleRepository(db)
+	s.repositories.voucherRepository = repository.NewVoucherRepository(db)
+	s.repositories.productStatRepository = repository.NewProductStatRepository(db)
+	s.repositories.dashboardRepository = repository.NewDashboardRepository(db)
+	s.repositories.salesReportJobRepository = repository.NewSalesReportJobRepository(db)
+	s.repositories.orderReturnRepository = repository.NewOrderReturnRepository(db, s.repositories.transactionRepository)
+	s.repositories.orderShipmentRepository = repository.NewOrderShipmentRepository(db)
+	s.repositories.shipmentTracker = repository.NewShipmentTracker(cfg, roClient)
+	s.repositories.shippingRateRepository = repository.NewShippingTableRateRepository(db)
+	s.repositories.shippingProviders = repository.NewShippingProviderRegistry(cfg)
+	s.repositories.regionProvider = repository.NewRajaOngkirRegionRepository(roClient)
+	s.repositories.feeRuleRepository = repository.NewFeeRuleRepository(db)
+	s.repositories.orderDocumentRepository = repository.NewOrderDocumentRepository(db)
+	s.repositories.notificationRepository = repository.NewNotificationRepository(db)
+	s.repositories.notificationBroker = repository.NewNotificationBroker(rd)
+	s.repositories.outboxRepository = repository.NewOutboxRepository(db)
+	s.repositories.webhookRepository = repository.NewWebhookRepository(db)
+	s.repositories.webhookSender = repository.NewWebhookSender(cfg)
+	s.repositories.chatRepository = repository.NewChatRepository(db)
+	s.repositories.chatQuickReplyRepository = repository.NewChatQuickReplyRepository(db)
+	s.repositories.chatBroker = repository.NewChatBroker(rd)
+	s.repositories.shippingProviders.Register(constant.RajaOngkirShippingProvider, s.repositories.rajaOngkirRepository)
+	s.repositories.shippingProviders.Register(constant.TableRateShippingProvider, s.repositories.shippingRateRepository)
 }
 
 func (s *server) initUsecase(rd *redis.Client) {
 	s.usecases.exampleUsecase = usecase.NewExampleRepository()
+	s.usecases.notificationUsecase = usecase.NewNotificationUsecase(s.repositories.notificationRepository, s.repositories.notificationBroker)
+	s.usecases.eventUsecase = usecase.NewEventUsecase(s.repositories.outboxRepository, s.cfg)
+	s.usecases.webhookUsecase = usecase.NewWebhookUsecase(s.repositories.webhookRepository, s.repositories.webhookSender, s.cfg)
+	s.usecases.chatUsecase = usecase.NewChatUsecase(
+		s.repositories.chatRepository,
+		s.repositories.chatQuickReplyRepository,
+		s.repositories.chatBroker,
+		s.repositories.mediaRepository,
+	)
 	s.usecases.authUsecase = usecase.NewAuthUsecase(
 		s.repositories.accountRepository,
 		s.repositories.cacheRepository,
@@ -130,12 +213,14 @@ func (s *server) initUsecase(rd *redis.Client) {
 		s.repositories.reviewRepository,
 		s.repositories.categoryRepository,
 		s.repositories.cacheRepository,
+		s.repositories.mediaRepository,
 	)
 	s.usecases.accountAddressUsecase = usecase.NewProfileUsecase(
 		s.repositories.accountAddressRepository,
 		s.repositories.accountRepository,
 		s.repositories.districtRepository,
 		s.repositories.provinceRepository,
+		s.repositories.mediaRepository,
 	)
 	s.usecases.shopUsecase = usecase.NewShopUsecase(
 		s.repositories.shopRepository,
@@ -143,6 +228,7 @@ func (s *server) initUsecase(rd *redis.Client) {
 		s.repositories.shopCourierRepository,
 		s.repositories.walletRepository,
 		s.repositories.productRepository,
+		s.repositories.mediaRepository,
 	)
 	s.usecases.productPageUsecase = usecase.NewProductPageUsecase(
 		s.repositories.productRepository,
@@ -154,8 +240,15 @@ func (s *server) initUsecase(rd *redis.Client) {
 		s.repositories.wishlistRepository,
 		s.repositories.reviewRepository,
 		s.repositories.orderDetailRepository,
+		s.repositories.mediaRepository,
+		s.repositories.flashSaleRepository,
+		s.repositories.productStatRepository,
+	)
+	s.usecases.cartUsecase = usecase.NewCartUsecase(
+		s.repositories.cartRepository,
+		s.repositories.productVariantRepository,
+		s.repositories.productStatRepository,
 	)
-	s.usecases.cartUsecase = usecase.NewCartUsecase(s.repositories.cartRepository, s.repositories.productVariantRepository)
 	s.usecases.dropdownUsecase = usecase.NewDropdownUsecase(
 		s.repositories.provinceRepository,
 		s.repositories.districtRepository,
@@ -172,12 +265,16 @@ func (s *server) initUsecase(rd *redis.Client) {
 		s.repositories.orderRepository,
 		s.repositories.cartRepository,
 		s.repositories.walletRepository,
-		s.repositories.rajaOngkirRepository,
+		s.repositories.shippingProviders,
 		s.repositories.accountAddressRepository,
 		s.repositories.courierRepository,
 		s.repositories.productRepository,
 		s.repositories.transactionRepository,
 		s.repositories.promotionRepository,
+		s.repositories.voucherRepository,
+		s.repositories.orderReturnRepository,
+		s.repositories.cacheRepository,
+		s.repositories.feeRuleRepository,
 	)
 	s.usecases.orderSellerUsecase = usecase.NewOrderSellerUsecase(
 		s.repositories.orderRepository,
@@ -189,11 +286,15 @@ func (s *server) initUsecase(rd *redis.Client) {
 		s.repositories.accountAddressRepository,
 		s.repositories.courierRepository,
 		s.repositories.productRepository,
-		s.repositories.rajaOngkirRepository,
+		s.repositories.shippingProviders,
 		s.repositories.districtRepository,
 		s.repositories.shopCourierRepository,
 		s.repositories.walletRepository,
 		s.repositories.promotionRepository,
+		s.repositories.voucherRepository,
+		s.repositories.cacheRepository,
+		s.repositories.feeRuleRepository,
+		s.cfg,
 	)
 	s.usecases.discoveryUsecase = usecase.NewDiscoveryUsecase(s.repositories.productRepository, s.repositories.reviewRepository)
 	s.usecases.sellerPageUsecase = usecase.NewSellerPageUsecase(
@@ -201,8 +302,63 @@ func (s *server) initUsecase(rd *redis.Client) {
 		s.repositories.reviewRepository,
 	)
 	s.usecases.wishlistUseCase = usecase.NewWishlistUsecase(s.repositories.wishlistRepository, s.repositories.productRepository, s.repositories.reviewRepository)
-	s.usecases.reviewUsecase = usecase.NewReviewUsecase(s.repositories.reviewRepository, s.repositories.productRepository)
+	s.usecases.reviewUsecase = usecase.NewReviewUsecase(s.repositories.reviewRepository, s.repositories.productRepository, s.repositories.mediaRepository)
 	s.usecases.promotionUsecase = usecase.NewPromotionRepository(s.repositories.promotionRepository, s.repositories.shopRepository)
+	s.usecases.mediaUsecase = usecase.NewMediaUsecase(
+		s.repositories.mediaRepository,
+		s.repositories.productMediaRepository,
+		s.repositories.mediaStorage,
+		s.cfg,
+	)
+	s.usecases.productBulkUsecase = usecase.NewProductBulkUsecase(
+		s.usecases.shopUsecase,
+		s.repositories.productRepository,
+		s.repositories.productImportJobRepository,
+		s.repositories.mediaRepository,
+		s.repositories.mediaFetcher,
+		s.usecases.mediaUsecase,
+		s.cfg,
+	)
+	s.usecases.inventoryUsecase = usecase.NewInventoryUsecase(
+		s.repositories.inventoryRepository,
+		s.repositories.productRepository,
+		s.cfg,
+	)
+	s.usecases.flashSaleUsecase = usecase.NewFlashSaleUsecase(s.repositories.flashSaleRepository)
+	s.usecases.voucherUsecase = usecase.NewVoucherUsecase(
+		s.repositories.voucherRepository,
+		s.repositories.shopRepository,
+		s.repositories.cartRepository,
+	)
+	s.usecases.dashboardUsecase = usecase.NewDashboardUsecase(s.repositories.dashboardRepository, s.repositories.cacheRepository)
+	s.usecases.salesReportUsecase = usecase.NewSalesReportUsecase(
+		s.repositories.orderRepository,
+		s.repositories.salesReportJobRepository,
+		s.repositories.mediaStorage,
+		s.cfg,
+	)
+	s.usecases.orderReturnUsecase = usecase.NewOrderReturnUsecase(
+		s.repositories.orderReturnRepository,
+		s.repositories.orderRepository,
+		s.repositories.orderDetailRepository,
+		s.repositories.transactionRepository,
+		s.repositories.mediaRepository,
+		s.cfg,
+	)
+	s.usecases.shipmentUsecase = usecase.NewShipmentUsecase(
+		s.repositories.orderRepository,
+		s.repositories.orderShipmentRepository,
+		s.repositories.shipmentTracker,
+		s.cfg,
+	)
+	s.usecases.shippingRateUsecase = usecase.NewShippingRateUsecase(s.repositories.shippingRateRepository)
+	s.usecases.feeUsecase = usecase.NewFeeUsecase(s.repositories.feeRuleRepository, s.repositories.shopRepository, s.repositories.walletRepository)
+	s.usecases.orderDocumentUsecase = usecase.NewOrderDocumentUsecase(s.repositories.orderDocumentRepository)
+	s.usecases.regionSyncUsecase = usecase.NewRegionSyncUsecase(
+		s.repositories.regionProvider,
+		s.repositories.provinceRepository,
+		s.repositories.districtRepository,
+	)
 }
 
 func (s *server) initRESTHandler(logger dependency.Logger, config dependency.Config) {
@@ -225,17 +381,69 @@ func (s *server) initRESTHandler(logger dependency.Logger, config dependency.Con
 	resthandler.NewWalletHandler(s.v, s.usecases.walletUsecase, config).Route(s.r)
 	resthandler.NewOrderSellerHandler(s.v, s.usecases.orderSellerUsecase, config, s.usecases.orderUsecase).Route(s.r)
 	resthandler.NewOrderHandler(s.usecases.orderUsecase, s.cfg, s.v).Route(s.r)
-	resthandler.NewCheckoutHandler(s.v, s.usecases.checkoutUsecase, s.cfg).Route(s.r)
+	resthandler.NewCheckoutHandler(s.v, s.usecases.checkoutUsecase, s.usecases.voucherUsecase, s.cfg).Route(s.r)
 	resthandler.NewSellerPageHandler(s.usecases.sellerPageUsecase, s.cfg, s.v).Route(s.r)
 	resthandler.NewWishlistHandler(s.usecases.wishlistUseCase, s.cfg, s.v).Route(s.r)
 	resthandler.NewReviewHandler(s.usecases.reviewUsecase, s.cfg, s.v).Route(s.r)
 	resthandler.NewShopPromotionHandler(s.usecases.promotionUsecase, s.cfg, s.v).Route(s.r)
+	resthandler.NewShopVoucherHandler(s.usecases.voucherUsecase, s.cfg, s.v).Route(s.r)
+	resthandler.NewPlatformVoucherHandler(s.usecases.voucherUsecase, s.cfg, s.v).Route(s.r)
+	resthandler.NewMediaHandler(s.usecases.mediaUsecase, s.cfg, s.v).Route(s.r)
+	resthandler.NewProductBulkHandler(s.usecases.productBulkUsecase, s.cfg, s.v).Route(s.r)
+	resthandler.NewInventoryHandler(s.usecases.inventoryUsecase, s.cfg, s.v).Route(s.r)
+	resthandler.NewFlashSaleHandler(s.usecases.flashSaleUsecase, s.cfg, s.v).Route(s.r)
+	resthandler.NewDashboardHandler(s.usecases.dashboardUsecase, s.cfg, s.v).Route(s.r)
+	resthandler.NewSalesReportHandler(s.usecases.salesReportUsecase, s.cfg, s.v).Route(s.r)
+	resthandler.NewOrderReturnHandler(s.usecases.orderReturnUsecase, s.cfg, s.v).Route(s.r)
+	resthandler.NewShipmentHandler(s.usecases.shipmentUsecase, s.cfg).Route(s.r)
+	resthandler.NewShippingRateHandler(s.usecases.shippingRateUsecase, s.cfg, s.v).Route(s.r)
+	resthandler.NewFeeHandler(s.usecases.feeUsecase, s.cfg, s.v).Route(s.r)
+	resthandler.NewOrderDocumentHandler(s.usecases.orderDocumentUsecase, s.cfg, s.v).Route(s.r)
+	resthandler.NewNotificationHandler(s.usecases.notificationUsecase, s.cfg, s.v).Route(s.r)
+	resthandler.NewWebhookHandler(s.usecases.webhookUsecase, s.cfg, s.v).Route(s.r)
+	resthandler.NewChatHandler(s.usecases.chatUsecase, s.cfg, s.v).Route(s.r)
 
 	s.r.NoRoute(func(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"message": "page not found"})
 	})
 }
 
+// initPlatformWallet makes the wallet that service fees and commissions are
+// paid into for the account in PLATFORM_ACCOUNT_ID.
+func (s *server) initPlatformWallet(logger dependency.Logger) {
+	err := s.repositories.walletRepository.CreatePlatformWallet(context.Background(), s.cfg.Fee.PlatformAccountID)
+	if err != nil {
+		logger.Fatalf("failed to create platform wallet: %v", err)
+	}
+}
+
+// registerEventSubscribers tells the outbox dispatcher who reacts to which
+// domain event.
+func (s *server) registerEventSubscribers() {
+	s.usecases.eventUsecase.Subscribe(constant.OrderCreatedEvent, "order notification", s.usecases.notificationUsecase.NotifyOrderEvent)
+	s.usecases.eventUsecase.Subscribe(constant.OrderStatusChangedEvent, "order notification", s.usecases.notificationUsecase.NotifyOrderEvent)
+	s.usecases.eventUsecase.Subscribe(constant.WalletCreditedEvent, "wallet notification", s.usecases.notificationUsecase.NotifyWalletCredited)
+	s.usecases.eventUsecase.Subscribe(constant.FlashSaleStartedEvent, "flash sale notification", s.usecases.notificationUsecase.NotifyFlashSaleStarted)
+	s.usecases.eventUsecase.Subscribe(constant.OrderCreatedEvent, "order webhook", s.usecases.webhookUsecase.EnqueueOrderEvent)
+	s.usecases.eventUsecase.Subscribe(constant.OrderStatusChangedEvent, "order webhook", s.usecases.webhookUsecase.EnqueueOrderEvent)
+	s.usecases.eventUsecase.Subscribe(constant.ReviewPostedEvent, "review webhook", s.usecases.webhookUsecase.EnqueueReviewPosted)
+}
+
+func (s *server) startJobHandler(ctx context.Context, logger dependency.Logger) {
+	go jobhandler.NewMediaJobHandler(s.usecases.mediaUsecase, s.cfg, logger).Run(ctx)
+	go jobhandler.NewProductImportJobHandler(s.usecases.productBulkUsecase, s.cfg, logger).Run(ctx)
+	go jobhandler.NewProductScheduleJobHandler(s.usecases.shopUsecase, s.cfg, logger).Run(ctx)
+	go jobhandler.NewInventoryJobHandler(s.usecases.inventoryUsecase, s.cfg, logger).Run(ctx)
+	go jobhandler.NewFlashSaleJobHandler(s.usecases.flashSaleUsecase, s.cfg, logger).Run(ctx)
+	go jobhandler.NewSalesReportJobHandler(s.usecases.salesReportUsecase, s.cfg, logger).Run(ctx)
+	go jobhandler.NewShipmentTrackingJobHandler(s.usecases.shipmentUsecase, s.cfg, logger).Run(ctx)
+	go jobhandler.NewRegionSyncJobHandler(s.usecases.regionSyncUsecase, s.cfg, logger).Run(ctx)
+	go jobhandler.NewNotificationJobHandler(s.usecases.notificationUsecase, logger).Run(ctx)
+	go jobhandler.NewOutboxJobHandler(s.usecases.eventUsecase, s.cfg, logger).Run(ctx)
+	go jobhandler.NewWebhookJobHandler(s.usecases.webhookUsecase, s.cfg, logger).Run(ctx)
+	go jobhandler.NewChatJobHandler(s.usecases.chatUsecase, logger).Run(ctx)
+}
+
 func (s *server) startRESTServer(cfg dependency.Config) *http.Server {
 	srv := http.Server{
 		Addr:    fmt.Sprintf(":%d", cfg.Rest.Port),
@@ -252,13 +460,16 @@ func (s *server) startRESTServer(cfg dependency.Config) *http.Server {
 	return &srv
 }
 
-func initGracefulShutdown(restSrv *http.Server, cfg dependency.Config) {
+func initGracefulShutdown(restSrv *http.Server, stopJobs context.CancelFunc, cfg dependency.Config) {
 	quit := make(chan os.Signal, 2)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
 	<-quit
 	log.Println("Shutdown Server ...")
 
+	// stop jobhandler workers
+	stopJobs()
+
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.App.GracefulTimeout)*time.Second)
 	defer cancel()
 
@@ -280,10 +491,15 @@ func InitApp(db *sqlx.DB, rc *redis.Client, cfg dependency.Config, logger depend
 	shared.ValidatorUseJSONName(s.v)
 
 	s.initRepository(db, rc, cfg)
+	s.initPlatformWallet(logger)
 	s.initUsecase(rc)
+	s.registerEventSubscribers()
 	s.initRESTHandler(logger, cfg)
 
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	s.startJobHandler(jobCtx, logger)
+
 	restSrv := s.startRESTServer(cfg)
 
-	initGracefulShutdown(restSrv, cfg)
+	initGracefulShutdown(restSrv, stopJobs, cfg)
 }
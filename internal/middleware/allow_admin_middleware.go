@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/shared"
+)
+
+// IsAdmin only lets through the accounts listed in ADMIN_ACCOUNT_IDS. It must
+// run after AllowAuthenticated.
+func IsAdmin(config dependency.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetInt64(constant.CtxUserId)
+		for _, id := range config.Admin.AccountIDs {
+			if id == userID {
+				c.Next()
+				return
+			}
+		}
+
+		e := shared.ErrUnauthorizedUser
+		c.AbortWithStatusJSON(mapErrorCode[e.Code], e.CreateHTTPErrorMessage())
+	}
+}
@@ -13,15 +13,18 @@ func CORS(config dependency.Config) gin.HandlerFunc {
 		AllowMethods:     []string{"POST", "PUT", "GET", "PATCH", "DELETE"},
 		AllowCredentials: true,
 		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type"},
+		AllowOrigins:     []string{AllowedOrigin(config)},
 	}
 
+	return cors.New(corsConfig)
+}
+
+// AllowedOrigin is the origin of the web client, the only one allowed to
+// call the API from a browser.
+func AllowedOrigin(config dependency.Config) string {
 	if config.App.OriginDomain == "localhost" {
-		origin := "http://localhost:3000"
-		corsConfig.AllowOrigins = []string{origin}
-	} else {
-		origin := fmt.Sprintf("https://%s", config.App.OriginDomain)
-		corsConfig.AllowOrigins = []string{origin}
+		return "http://localhost:3000"
 	}
 
-	return cors.New(corsConfig)
+	return fmt.Sprintf("https://%s", config.App.OriginDomain)
 }
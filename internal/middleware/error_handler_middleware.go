@@ -12,11 +12,13 @@ import (
 )
 
 var mapErrorCode = map[shared.CustomErrorCode]int{
-	shared.BadRequest:     http.StatusBadRequest,
-	shared.Forbidden:      http.StatusForbidden,
-	shared.Unauthorized:   http.StatusUnauthorized,
-	shared.InternalServer: http.StatusInternalServerError,
-	shared.NotFound:       http.StatusNotFound,
+	shared.BadRequest:         http.StatusBadRequest,
+	shared.Forbidden:          http.StatusForbidden,
+	shared.Unauthorized:       http.StatusUnauthorized,
+	shared.InternalServer:     http.StatusInternalServerError,
+	shared.NotFound:           http.StatusNotFound,
+	shared.ServiceUnavailable: http.StatusServiceUnavailable,
+	shared.Conflict:           http.StatusConflict,
 }
 
 func ErrorHandler() gin.HandlerFunc {
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Conversation is between a buyer account and a shop. SellerID is the
+// account of the shop. Each side has its own unread counter and the id of
+// the last message it read.
+type Conversation struct {
+	ID                int64        `db:"id"`
+	BuyerID           int64        `db:"buyer_id"`
+	ShopID            int64        `db:"shop_id"`
+	SellerID          int64        `db:"seller_id"`
+	BuyerUnreadCount  int          `db:"buyer_unread_count"`
+	SellerUnreadCount int          `db:"seller_unread_count"`
+	BuyerLastReadID   int64        `db:"buyer_last_read_id"`
+	SellerLastReadID  int64        `db:"seller_last_read_id"`
+	LastMessageAt     sql.NullTime `db:"last_message_at"`
+	CreatedAt         time.Time    `db:"created_at"`
+	UpdatedAt         time.Time    `db:"updated_at"`
+	DeletedAt         sql.NullTime `db:"deleted_at"`
+}
+
+type ChatMessage struct {
+	ID             int64          `db:"id"`
+	ConversationID int64          `db:"conversation_id"`
+	SenderID       int64          `db:"sender_id"`
+	Type           string         `db:"type"`
+	Text           string         `db:"text"`
+	ImageURL       sql.NullString `db:"image_url"`
+	ProductID      sql.NullInt64  `db:"product_id"`
+	OrderID        sql.NullInt64  `db:"order_id"`
+	CreatedAt      time.Time      `db:"created_at"`
+}
+
+type ChatQuickReply struct {
+	ID        int64        `db:"id"`
+	SellerID  int64        `db:"seller_id"`
+	Title     string       `db:"title"`
+	Body      string       `db:"body"`
+	CreatedAt time.Time    `db:"created_at"`
+	UpdatedAt time.Time    `db:"updated_at"`
+	DeletedAt sql.NullTime `db:"deleted_at"`
+}
@@ -1,8 +1,12 @@
 package model
 
+import "database/sql"
+
 type District struct {
-	ID         int64  `db:"id"`
-	Name       string `db:"name"`
-	ProvinceID int64  `db:"province_id"`
-	PostalCode string `db:"postal_code"`
+	ID         int64          `db:"id"`
+	Name       string         `db:"name"`
+	ProvinceID int64          `db:"province_id"`
+	PostalCode string         `db:"postal_code"`
+	ProviderID sql.NullString `db:"provider_id"`
+	IsActive   bool           `db:"is_active"`
 }
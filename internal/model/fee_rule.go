@@ -0,0 +1,19 @@
+package model
+
+import (
+	"database/sql"
+
+	"github.com/shopspring/decimal"
+)
+
+type FeeRule struct {
+	ID         int64           `db:"id"`
+	Kind       string          `db:"kind"`
+	CategoryID sql.NullInt64   `db:"category_id"`
+	ShopTier   sql.NullString  `db:"shop_tier"`
+	Rate       decimal.Decimal `db:"rate"`
+	FlatAmount decimal.Decimal `db:"flat_amount"`
+	CreatedAt  sql.NullTime    `db:"created_at"`
+	UpdatedAt  sql.NullTime    `db:"updated_at"`
+	DeletedAt  sql.NullTime    `db:"deleted_at"`
+}
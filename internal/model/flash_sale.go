@@ -0,0 +1,34 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type FlashSale struct {
+	ID        int64        `db:"id"`
+	SellerID  int64        `db:"seller_id"`
+	Name      string       `db:"name"`
+	StartAt   time.Time    `db:"start_at"`
+	EndAt     time.Time    `db:"end_at"`
+	CreatedAt time.Time    `db:"created_at"`
+	UpdatedAt time.Time    `db:"updated_at"`
+	DeletedAt sql.NullTime `db:"deleted_at"`
+}
+
+type FlashSaleVariant struct {
+	ID               int64               `db:"id"`
+	FlashSaleID      int64               `db:"flash_sale_id"`
+	ProductVariantID int64               `db:"product_variant_id"`
+	Discount         sql.NullFloat64     `db:"discount"`
+	DiscountedPrice  decimal.NullDecimal `db:"discounted_price"`
+	PurchaseLimit    int                 `db:"purchase_limit"`
+	Quota            int                 `db:"quota"`
+	Sold             int                 `db:"sold"`
+	OriginalDiscount sql.NullFloat64     `db:"original_discount"`
+	Status           string              `db:"status"`
+	CreatedAt        time.Time           `db:"created_at"`
+	UpdatedAt        time.Time           `db:"updated_at"`
+}
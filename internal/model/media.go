@@ -0,0 +1,23 @@
+package model
+
+import "database/sql"
+
+type Media struct {
+	ID           int64          `db:"id"`
+	AccountID    int64          `db:"account_id"`
+	StorageKey   string         `db:"storage_key"`
+	MediaUrl     string         `db:"media_url"`
+	MediaType    string         `db:"media_type"`
+	ContentType  string         `db:"content_type"`
+	Size         int64          `db:"size"`
+	VariantOf    sql.NullInt64  `db:"variant_of"`
+	VariantName  sql.NullString `db:"variant_name"`
+	ThumbnailUrl sql.NullString `db:"thumbnail_url"`
+	MediumUrl    sql.NullString `db:"medium_url"`
+	LargeUrl     sql.NullString `db:"large_url"`
+	ProcessedAt  sql.NullTime   `db:"processed_at"`
+	Attempts     int            `db:"processing_attempts"`
+	PublishedAt  sql.NullTime   `db:"published_at"`
+	CreatedAt    sql.NullTime   `db:"created_at"`
+	DeletedAt    sql.NullTime   `db:"deleted_at"`
+}
@@ -0,0 +1,17 @@
+package model
+
+import "database/sql"
+
+type Notification struct {
+	ID          int64         `db:"id"`
+	AccountID   int64         `db:"account_id"`
+	Type        string        `db:"type"`
+	Title       string        `db:"title"`
+	Message     string        `db:"message"`
+	ReferenceID sql.NullInt64 `db:"reference_id"`
+	EventID     sql.NullInt64 `db:"event_id"`
+	ReadAt      sql.NullTime  `db:"read_at"`
+	CreatedAt   sql.NullTime  `db:"created_at"`
+	UpdatedAt   sql.NullTime  `db:"updated_at"`
+	DeletedAt   sql.NullTime  `db:"deleted_at"`
+}
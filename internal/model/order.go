@@ -10,6 +10,7 @@ type Order struct {
 	ID              int64           `db:"id"`
 	Status          string          `db:"status"`
 	EAT             sql.NullTime    `db:"estimated_time_arrival"`
+	QuotedEAT       sql.NullTime    `db:"quoted_time_arrival"`
 	DeliveryCost    decimal.Decimal `db:"delivery_cost"`
 	CourierId       int64           `db:"courier_id"`
 	SellerId        int64           `db:"seller_id"`
@@ -17,6 +18,14 @@ type Order struct {
 	TransactionId   int64           `db:"transaction_id"`
 	PromotionName   sql.NullString  `db:"promotion_name"`
 	PromotionAmount sql.NullFloat64 `db:"promotion_amount"`
+	PromotionID     sql.NullInt64   `db:"promotion_id"`
+	VoucherAmount   sql.NullFloat64 `db:"voucher_amount"`
+	ServiceFee      decimal.Decimal `db:"service_fee"`
+	Commission      decimal.Decimal `db:"commission_amount"`
+	InvoiceNumber   sql.NullString  `db:"invoice_number"`
+	BuyerAddressID  sql.NullInt64   `db:"buyer_address_id"`
+	CourierService  sql.NullString  `db:"courier_service"`
+	ReceivedAt      sql.NullTime    `db:"received_at"`
 	CreatedAt       sql.NullTime    `db:"created_at"`
 	UpdatedAt       sql.NullTime    `db:"updated_at"`
 	DeletedAt       sql.NullTime    `db:"deleted_at"`
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"database/sql"
+
+	"github.com/shopspring/decimal"
+)
+
+type OrderReturn struct {
+	ID               int64           `db:"id"`
+	OrderID          int64           `db:"order_id"`
+	BuyerID          int64           `db:"buyer_id"`
+	SellerID         int64           `db:"seller_id"`
+	Reason           string          `db:"reason"`
+	Status           string          `db:"status"`
+	Amount           decimal.Decimal `db:"amount"`
+	HeldAmount       decimal.Decimal `db:"held_amount"`
+	SellerNote       sql.NullString  `db:"seller_note"`
+	EscalationReason sql.NullString  `db:"escalation_reason"`
+	AdminNote        sql.NullString  `db:"admin_note"`
+	CreatedAt        sql.NullTime    `db:"created_at"`
+	UpdatedAt        sql.NullTime    `db:"updated_at"`
+	ResolvedAt       sql.NullTime    `db:"resolved_at"`
+	DeletedAt        sql.NullTime    `db:"deleted_at"`
+}
+
+type OrderReturnItem struct {
+	ID            int64           `db:"id"`
+	OrderReturnID int64           `db:"order_return_id"`
+	OrderDetailID int64           `db:"order_detail_id"`
+	Quantity      int             `db:"quantity"`
+	Amount        decimal.Decimal `db:"amount"`
+}
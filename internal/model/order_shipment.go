@@ -0,0 +1,31 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type OrderShipment struct {
+	ID            int64        `db:"id"`
+	OrderID       int64        `db:"order_id"`
+	CourierCode   string       `db:"courier_code"`
+	WaybillNumber string       `db:"waybill_number"`
+	Status        string       `db:"status"`
+	CheckedAt     sql.NullTime `db:"checked_at"`
+	FailedChecks  int          `db:"failed_checks"`
+	DeliveredAt   sql.NullTime `db:"delivered_at"`
+	CreatedAt     sql.NullTime `db:"created_at"`
+	UpdatedAt     sql.NullTime `db:"updated_at"`
+	DeletedAt     sql.NullTime `db:"deleted_at"`
+}
+
+type OrderTrackingEvent struct {
+	ID              int64        `db:"id"`
+	OrderShipmentID int64        `db:"order_shipment_id"`
+	Description     string       `db:"description"`
+	Location        string       `db:"location"`
+	OccurredAt      time.Time    `db:"occurred_at"`
+	CreatedAt       sql.NullTime `db:"created_at"`
+	UpdatedAt       sql.NullTime `db:"updated_at"`
+	DeletedAt       sql.NullTime `db:"deleted_at"`
+}
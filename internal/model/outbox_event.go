@@ -0,0 +1,19 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type OutboxEvent struct {
+	ID          int64          `db:"id"`
+	EventType   string         `db:"event_type"`
+	AggregateID int64          `db:"aggregate_id"`
+	Payload     []byte         `db:"payload"`
+	Attempts    int            `db:"attempts"`
+	AvailableAt time.Time      `db:"available_at"`
+	ProcessedAt sql.NullTime   `db:"processed_at"`
+	LastError   sql.NullString `db:"last_error"`
+	CreatedAt   sql.NullTime   `db:"created_at"`
+	UpdatedAt   sql.NullTime   `db:"updated_at"`
+}
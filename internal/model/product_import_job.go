@@ -0,0 +1,20 @@
+package model
+
+import "database/sql"
+
+type ProductImportJob struct {
+	ID          int64        `db:"id"`
+	AccountID   int64        `db:"account_id"`
+	FileName    string       `db:"file_name"`
+	Status      string       `db:"status"`
+	TotalRows   int          `db:"total_rows"`
+	SuccessRows int          `db:"success_rows"`
+	FailedRows  int          `db:"failed_rows"`
+	Items       string       `db:"items"`
+	Errors      string       `db:"errors"`
+	Processed   int          `db:"processed_items"`
+	CreatedAt   sql.NullTime `db:"created_at"`
+	UpdatedAt   sql.NullTime `db:"updated_at"`
+	FinishedAt  sql.NullTime `db:"finished_at"`
+	DeletedAt   sql.NullTime `db:"deleted_at"`
+}
@@ -3,11 +3,14 @@ package model
 import "database/sql"
 
 type ProductMedia struct {
-	ID        int64        `db:"id"`
-	MediaUrl  string       `db:"media_url"`
-	MediaType string       `db:"media_type"`
-	ProductID int64        `db:"product_id"`
-	CreatedAt sql.NullTime `db:"created_at"`
-	UpdatedAt sql.NullTime `db:"updated_at"`
-	DeletedAt sql.NullTime `db:"deleted_at"`
+	ID           int64          `db:"id"`
+	MediaUrl     string         `db:"media_url"`
+	MediaType    string         `db:"media_type"`
+	ThumbnailUrl sql.NullString `db:"thumbnail_url"`
+	MediumUrl    sql.NullString `db:"medium_url"`
+	LargeUrl     sql.NullString `db:"large_url"`
+	ProductID    int64          `db:"product_id"`
+	CreatedAt    sql.NullTime   `db:"created_at"`
+	UpdatedAt    sql.NullTime   `db:"updated_at"`
+	DeletedAt    sql.NullTime   `db:"deleted_at"`
 }
@@ -14,6 +14,7 @@ type Promotion struct {
 	Percentage   sql.NullFloat64 `db:"percentage"`
 	MinimumSpend decimal.Decimal `db:"minimum_spend"`
 	Quota        int             `db:"quota"`
+	UsageLimit   sql.NullInt64   `db:"usage_limit_per_user"`
 	ShopID       int64           `db:"shop_id"`
 	StartedAt    time.Time       `db:"started_at"`
 	ExpiredAt    time.Time       `db:"expired_at"`
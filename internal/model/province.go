@@ -1,6 +1,10 @@
 package model
 
+import "database/sql"
+
 type Province struct {
-	ID   int64  `sql:"id"`
-	Name string `sql:"name"`
+	ID         int64          `db:"id"`
+	Name       string         `db:"name"`
+	ProviderID sql.NullString `db:"provider_id"`
+	IsActive   bool           `db:"is_active"`
 }
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type SalesReportJob struct {
+	ID          int64          `db:"id"`
+	SellerID    int64          `db:"seller_id"`
+	StartDate   time.Time      `db:"start_date"`
+	EndDate     time.Time      `db:"end_date"`
+	OrderStatus sql.NullString `db:"order_status"`
+	Format      string         `db:"format"`
+	Status      string         `db:"status"`
+	FileName    string         `db:"file_name"`
+	StorageKey  sql.NullString `db:"storage_key"`
+	CreatedAt   sql.NullTime   `db:"created_at"`
+	UpdatedAt   sql.NullTime   `db:"updated_at"`
+	FinishedAt  sql.NullTime   `db:"finished_at"`
+	DeletedAt   sql.NullTime   `db:"deleted_at"`
+}
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"database/sql"
+
+	"github.com/shopspring/decimal"
+)
+
+type ShippingTableRate struct {
+	ID                    int64           `db:"id"`
+	CourierCode           string          `db:"courier_code"`
+	Service               string          `db:"service"`
+	Description           string          `db:"description"`
+	OriginProvinceID      int64           `db:"origin_province_id"`
+	DestinationProvinceID int64           `db:"destination_province_id"`
+	MinWeight             int             `db:"min_weight"`
+	MaxWeight             int             `db:"max_weight"`
+	Price                 decimal.Decimal `db:"price"`
+	ETD                   string          `db:"etd"`
+	CreatedAt             sql.NullTime    `db:"created_at"`
+	UpdatedAt             sql.NullTime    `db:"updated_at"`
+	DeletedAt             sql.NullTime    `db:"deleted_at"`
+}
@@ -3,10 +3,12 @@ package model
 import "database/sql"
 
 type Shop struct {
-	ID        int64          `db:"id"`
-	Name      sql.NullString `db:"name"`
-	AccountId int            `db:"account_id"`
-	CreatedAt sql.NullTime   `db:"created_at"`
-	UpdatedAt sql.NullTime   `db:"updated_at"`
-	DeletedAt sql.NullTime   `db:"deleted_at"`
+	ID              int64          `db:"id"`
+	Name            sql.NullString `db:"name"`
+	AccountId       int            `db:"account_id"`
+	Tier            string         `db:"tier"`
+	InvoiceSequence int64          `db:"invoice_sequence"`
+	CreatedAt       sql.NullTime   `db:"created_at"`
+	UpdatedAt       sql.NullTime   `db:"updated_at"`
+	DeletedAt       sql.NullTime   `db:"deleted_at"`
 }
@@ -0,0 +1,14 @@
+package model
+
+import "database/sql"
+
+type StockMovement struct {
+	ID               int64         `db:"id"`
+	ProductVariantID int64         `db:"product_variant_id"`
+	Quantity         int64         `db:"quantity"`
+	StockAfter       int64         `db:"stock_after"`
+	Reason           string        `db:"reason"`
+	ActorID          sql.NullInt64 `db:"actor_id"`
+	OrderID          sql.NullInt64 `db:"order_id"`
+	CreatedAt        sql.NullTime  `db:"created_at"`
+}
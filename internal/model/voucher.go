@@ -0,0 +1,28 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type Voucher struct {
+	ID                int64               `db:"id"`
+	Code              string              `db:"code"`
+	Name              string              `db:"name"`
+	Issuer            string              `db:"issuer"`
+	ShopID            sql.NullInt64       `db:"shop_id"`
+	Type              string              `db:"type"`
+	Value             decimal.Decimal     `db:"value"`
+	MaxDiscount       decimal.NullDecimal `db:"max_discount"`
+	MinimumSpend      decimal.Decimal     `db:"minimum_spend"`
+	Quota             int                 `db:"quota"`
+	Used              int                 `db:"used"`
+	UsageLimitPerUser int                 `db:"usage_limit_per_user"`
+	StartedAt         time.Time           `db:"started_at"`
+	ExpiredAt         time.Time           `db:"expired_at"`
+	CreatedAt         time.Time           `db:"created_at"`
+	UpdatedAt         time.Time           `db:"updated_at"`
+	DeletedAt         sql.NullTime        `db:"deleted_at"`
+}
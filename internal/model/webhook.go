@@ -0,0 +1,37 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type WebhookEndpoint struct {
+	ID         int64          `db:"id"`
+	SellerID   int64          `db:"seller_id"`
+	URL        string         `db:"url"`
+	Secret     string         `db:"secret"`
+	EventTypes pq.StringArray `db:"event_types"`
+	IsActive   bool           `db:"is_active"`
+	CreatedAt  time.Time      `db:"created_at"`
+	UpdatedAt  time.Time      `db:"updated_at"`
+	DeletedAt  sql.NullTime   `db:"deleted_at"`
+}
+
+type WebhookDelivery struct {
+	ID             int64          `db:"id"`
+	EndpointID     int64          `db:"endpoint_id"`
+	EventID        sql.NullInt64  `db:"event_id"`
+	EventType      string         `db:"event_type"`
+	Payload        []byte         `db:"payload"`
+	Status         string         `db:"status"`
+	Attempts       int            `db:"attempts"`
+	AvailableAt    time.Time      `db:"available_at"`
+	ResponseStatus sql.NullInt64  `db:"response_status"`
+	ResponseBody   sql.NullString `db:"response_body"`
+	LastError      sql.NullString `db:"last_error"`
+	DeliveredAt    sql.NullTime   `db:"delivered_at"`
+	CreatedAt      time.Time      `db:"created_at"`
+	UpdatedAt      time.Time      `db:"updated_at"`
+}
@@ -33,6 +33,15 @@ type (
 		SetLockedWalletForUserID(ctx context.Context, userID int64) error
 		GetLockedWalletByUserID(ctx context.Context, userID int64) (*bool, error)
 		GetRecommendedProduct(ctx context.Context) ([]dto.HomePageProductResponseBody, error)
+		GetSellerDashboard(ctx context.Context, payload dto.DashboardPayload) (*dto.SellerDashboardResponse, error)
+		SetSellerDashboard(ctx context.Context, payload dto.DashboardPayload, dashboard dto.SellerDashboardResponse) error
+		GetShippingRates(ctx context.Context, query dto.ShippingRateQuery) ([]dto.ShippingRate, error)
+		SetShippingRates(ctx context.Context, query dto.ShippingRateQuery, rates []dto.ShippingRate) error
+		SetCheckoutSession(ctx context.Context, session dto.CheckoutSession) error
+		GetCheckoutSession(ctx context.Context, id string) (*dto.CheckoutSession, error)
+		DeleteCheckoutSession(ctx context.Context, id string) error
+		ClaimCheckoutSession(ctx context.Context, id string) (bool, error)
+		ReleaseCheckoutSession(ctx context.Context, id string) error
 	}
 	cacheRepository struct {
 		rd  *redis.Client
@@ -293,6 +302,158 @@ func (r *cacheRepository) GetRecommendedProduct(ctx context.Context) ([]dto.Home
 	return resProducts, nil
 }
 
+// GetSellerDashboard implements CacheRepository.
+func (r *cacheRepository) GetSellerDashboard(ctx context.Context, payload dto.DashboardPayload) (*dto.SellerDashboardResponse, error) {
+	key := fmt.Sprintf(constant.RedisSellerDashboardTemplate, payload.SellerID, payload.StartDate, payload.EndDate, payload.Bucket)
+
+	cmd := r.rd.Get(ctx, key)
+	if err := cmd.Err(); err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	dashboard := new(dto.SellerDashboardResponse)
+	if err := json.Unmarshal([]byte(cmd.Val()), dashboard); err != nil {
+		return nil, err
+	}
+
+	return dashboard, nil
+}
+
+// SetSellerDashboard implements CacheRepository.
+func (r *cacheRepository) SetSellerDashboard(ctx context.Context, payload dto.DashboardPayload, dashboard dto.SellerDashboardResponse) error {
+	expiration := time.Duration(r.cfg.Dashboard.CacheExpiration) * time.Minute
+
+	val, err := json.Marshal(dashboard)
+	if err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf(constant.RedisSellerDashboardTemplate, payload.SellerID, payload.StartDate, payload.EndDate, payload.Bucket)
+	cmd := r.rd.SetEX(ctx, key, val, expiration)
+	if err := cmd.Err(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetShippingRates implements CacheRepository.
+func (r *cacheRepository) GetShippingRates(ctx context.Context, query dto.ShippingRateQuery) ([]dto.ShippingRate, error) {
+	key := fmt.Sprintf(constant.RedisShippingRatesTemplate, query.CourierCode, query.OriginDistrictID, query.DestinationDistrictID, query.Weight)
+
+	cmd := r.rd.Get(ctx, key)
+	if err := cmd.Err(); err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	rates := make([]dto.ShippingRate, 0)
+	if err := json.Unmarshal([]byte(cmd.Val()), &rates); err != nil {
+		return nil, err
+	}
+
+	return rates, nil
+}
+
+// SetShippingRates implements CacheRepository.
+func (r *cacheRepository) SetShippingRates(ctx context.Context, query dto.ShippingRateQuery, rates []dto.ShippingRate) error {
+	expiration := time.Duration(r.cfg.ThirdParty.RajaOngkirCacheExpiration) * time.Minute
+
+	val, err := json.Marshal(rates)
+	if err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf(constant.RedisShippingRatesTemplate, query.CourierCode, query.OriginDistrictID, query.DestinationDistrictID, query.Weight)
+	cmd := r.rd.SetEX(ctx, key, val, expiration)
+	if err := cmd.Err(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetCheckoutSession implements CacheRepository. The session is kept until
+// it expires.
+func (r *cacheRepository) SetCheckoutSession(ctx context.Context, session dto.CheckoutSession) error {
+	val, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf(constant.RedisCheckoutSessionTemplate, session.ID)
+	cmd := r.rd.SetEX(ctx, key, val, time.Until(session.ExpiresAt))
+	if err := cmd.Err(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetCheckoutSession implements CacheRepository.
+func (r *cacheRepository) GetCheckoutSession(ctx context.Context, id string) (*dto.CheckoutSession, error) {
+	key := fmt.Sprintf(constant.RedisCheckoutSessionTemplate, id)
+
+	cmd := r.rd.Get(ctx, key)
+	if err := cmd.Err(); err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	session := new(dto.CheckoutSession)
+	if err := json.Unmarshal([]byte(cmd.Val()), session); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// DeleteCheckoutSession implements CacheRepository.
+func (r *cacheRepository) DeleteCheckoutSession(ctx context.Context, id string) error {
+	key := fmt.Sprintf(constant.RedisCheckoutSessionTemplate, id)
+
+	cmd := r.rd.Del(ctx, key)
+	if err := cmd.Err(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ClaimCheckoutSession implements CacheRepository. Only one caller can
+// claim a session until it is released or the session expires, it is false
+// for everyone else.
+func (r *cacheRepository) ClaimCheckoutSession(ctx context.Context, id string) (bool, error) {
+	key := fmt.Sprintf(constant.RedisCheckoutClaimTemplate, id)
+	expiration := time.Duration(r.cfg.CheckoutSession.Expiration) * time.Minute
+
+	cmd := r.rd.SetNX(ctx, key, 1, expiration)
+	if err := cmd.Err(); err != nil {
+		return false, err
+	}
+
+	return cmd.Val(), nil
+}
+
+// ReleaseCheckoutSession implements CacheRepository.
+func (r *cacheRepository) ReleaseCheckoutSession(ctx context.Context, id string) error {
+	key := fmt.Sprintf(constant.RedisCheckoutClaimTemplate, id)
+
+	cmd := r.rd.Del(ctx, key)
+	if err := cmd.Err(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func NewCacheRepository(rd *redis.Client, cfg dependency.Config) CacheRepository {
 	return &cacheRepository{
 		rd:  rd,
@@ -39,6 +39,7 @@ func (r *cartRepository) FindCheckedCartByAccountID(ctx context.Context, account
 			c.id AS cart_id,
 			p."name" AS product_name,
 			p.id AS product_id,
+			p.status AS product_status,
 			p.thumbnail_url AS image_url,
 			c.product_variant_id,
 			pv.price AS base_price,
@@ -260,6 +261,7 @@ func (r *cartRepository) FindCheckedCartByShopID(ctx context.Context, accountId,
 			c.id AS cart_id,
 			p."name" AS product_name,
 			p.id AS product_id,
+			p.status AS product_status,
 			p.thumbnail_url AS image_url,
 			c.product_variant_id,
 			pv.price AS base_price,
@@ -277,7 +279,7 @@ func (r *cartRepository) FindCheckedCartByShopID(ctx context.Context, accountId,
 		LEFT JOIN variant_types vt ON vt.id = pv.variant_type1_id
 		LEFT JOIN variant_types vt2 ON vt2.id = pv.variant_type2_id 
 		WHERE c.account_id = $1 AND c.is_checked = $2 AND s.id = $3
-		ORDER BY a.id
+		ORDER BY a.id, c.id
 	`
 	err := r.db.SelectContext(ctx, &cartOrders, query, accountId, true, shopId)
 	if err != nil {
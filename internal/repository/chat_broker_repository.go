@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dto"
+)
+
+type (
+	// ChatBroker pushes chat frames to the WebSockets open on every replica.
+	// Each replica listens on a single Redis channel and hands the frames it
+	// receives to the sockets of the accounts they are for.
+	ChatBroker interface {
+		Publish(ctx context.Context, event dto.ChatEvent) error
+		Subscribe(accountID int64) (<-chan dto.ChatFrame, func())
+		Listen(ctx context.Context) error
+	}
+	redisChatBroker struct {
+		rd          *redis.Client
+		mu          sync.RWMutex
+		subscribers map[int64]map[chan dto.ChatFrame]struct{}
+	}
+)
+
+// Publish implements ChatBroker.
+func (b *redisChatBroker) Publish(ctx context.Context, event dto.ChatEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return b.rd.Publish(ctx, constant.RedisChatChannel, payload).Err()
+}
+
+// Subscribe implements ChatBroker. The returned func has to be called once
+// the socket is closed.
+func (b *redisChatBroker) Subscribe(accountID int64) (<-chan dto.ChatFrame, func()) {
+	ch := make(chan dto.ChatFrame, constant.ChatStreamBuffer)
+
+	b.mu.Lock()
+	if b.subscribers[accountID] == nil {
+		b.subscribers[accountID] = make(map[chan dto.ChatFrame]struct{})
+	}
+	b.subscribers[accountID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	unsubscribe := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		delete(b.subscribers[accountID], ch)
+		if len(b.subscribers[accountID]) == 0 {
+			delete(b.subscribers, accountID)
+		}
+	}
+
+	return ch, unsubscribe
+}
+
+// Listen implements ChatBroker. It blocks until ctx is cancelled.
+func (b *redisChatBroker) Listen(ctx context.Context) error {
+	pubsub := b.rd.Subscribe(ctx, constant.RedisChatChannel)
+	defer pubsub.Close()
+
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return err
+	}
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-messages:
+			if !ok {
+				return nil
+			}
+
+			event := dto.ChatEvent{}
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				continue
+			}
+			b.dispatch(event)
+		}
+	}
+}
+
+// dispatch hands a frame to the local sockets of its accounts. A socket
+// that is not keeping up misses it rather than holding the others; the
+// client catches up from the history.
+func (b *redisChatBroker) dispatch(event dto.ChatEvent) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, accountID := range event.AccountIDs {
+		for ch := range b.subscribers[accountID] {
+			select {
+			case ch <- event.Frame:
+			default:
+			}
+		}
+	}
+}
+
+func NewChatBroker(rd *redis.Client) ChatBroker {
+	return &redisChatBroker{
+		rd:          rd,
+		subscribers: make(map[int64]map[chan dto.ChatFrame]struct{}),
+	}
+}
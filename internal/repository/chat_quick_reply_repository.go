@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lil-oren/rest/internal/model"
+	"github.com/lil-oren/rest/internal/shared"
+)
+
+type (
+	ChatQuickReplyRepository interface {
+		Create(ctx context.Context, reply *model.ChatQuickReply) error
+		CountBySellerID(ctx context.Context, sellerID int64) (int, error)
+		FindBySellerID(ctx context.Context, sellerID int64) ([]model.ChatQuickReply, error)
+		Update(ctx context.Context, reply *model.ChatQuickReply) error
+		Delete(ctx context.Context, id, sellerID int64) error
+	}
+	chatQuickReplyRepository struct {
+		db *sqlx.DB
+	}
+)
+
+// Create implements ChatQuickReplyRepository. The id and timestamps of the
+// stored reply are set on reply.
+func (r *chatQuickReplyRepository) Create(ctx context.Context, reply *model.ChatQuickReply) error {
+	qs := `
+	INSERT INTO chat_quick_replies (
+		seller_id,
+		title,
+		body
+	) VALUES
+	($1, $2, $3)
+	RETURNING id, created_at, updated_at
+	`
+
+	return r.db.QueryRowxContext(ctx, qs, reply.SellerID, reply.Title, reply.Body).
+		Scan(&reply.ID, &reply.CreatedAt, &reply.UpdatedAt)
+}
+
+// CountBySellerID implements ChatQuickReplyRepository.
+func (r *chatQuickReplyRepository) CountBySellerID(ctx context.Context, sellerID int64) (int, error) {
+	qs := `
+	SELECT
+		COUNT(1)
+	FROM
+		chat_quick_replies cqr
+	WHERE
+		cqr.seller_id = $1 AND
+		cqr.deleted_at IS NULL
+	`
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, qs, sellerID); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// FindBySellerID implements ChatQuickReplyRepository.
+func (r *chatQuickReplyRepository) FindBySellerID(ctx context.Context, sellerID int64) ([]model.ChatQuickReply, error) {
+	replies := make([]model.ChatQuickReply, 0)
+	qs := `
+	SELECT
+		*
+	FROM
+		chat_quick_replies cqr
+	WHERE
+		cqr.seller_id = $1 AND
+		cqr.deleted_at IS NULL
+	ORDER BY cqr.title, cqr.id
+	`
+
+	if err := r.db.SelectContext(ctx, &replies, qs, sellerID); err != nil {
+		return nil, err
+	}
+
+	return replies, nil
+}
+
+// Update implements ChatQuickReplyRepository.
+func (r *chatQuickReplyRepository) Update(ctx context.Context, reply *model.ChatQuickReply) error {
+	qs := `
+	UPDATE chat_quick_replies
+	SET title = $1, body = $2, updated_at = NOW()
+	WHERE id = $3 AND seller_id = $4 AND deleted_at IS NULL
+	RETURNING created_at, updated_at
+	`
+
+	err := r.db.QueryRowxContext(ctx, qs, reply.Title, reply.Body, reply.ID, reply.SellerID).
+		Scan(&reply.CreatedAt, &reply.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return shared.ErrQuickReplyNotFound
+	}
+
+	return err
+}
+
+// Delete implements ChatQuickReplyRepository.
+func (r *chatQuickReplyRepository) Delete(ctx context.Context, id, sellerID int64) error {
+	qs := `
+	UPDATE chat_quick_replies
+	SET deleted_at = NOW(), updated_at = NOW()
+	WHERE id = $1 AND seller_id = $2 AND deleted_at IS NULL
+	`
+
+	res, err := r.db.ExecContext(ctx, qs, id, sellerID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return shared.ErrQuickReplyNotFound
+	}
+
+	return nil
+}
+
+func NewChatQuickReplyRepository(db *sqlx.DB) ChatQuickReplyRepository {
+	return &chatQuickReplyRepository{
+		db: db,
+	}
+}
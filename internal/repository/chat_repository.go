@@ -0,0 +1,439 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/model"
+	"github.com/lil-oren/rest/internal/shared"
+)
+
+type (
+	ChatRepository interface {
+		FirstShopAccountID(ctx context.Context, shopID int64) (int64, error)
+		FirstOrCreateConversation(ctx context.Context, buyerID, shopID, sellerID int64) (*model.Conversation, error)
+		FirstConversation(ctx context.Context, id, accountID int64) (*model.Conversation, error)
+		FirstConversationDetail(ctx context.Context, id int64) (*dto.ConversationModel, error)
+		FindConversations(ctx context.Context, accountID int64, params dto.ConversationParams) ([]dto.ConversationModel, error)
+		CountConversations(ctx context.Context, accountID int64, params dto.ConversationParams) (int, error)
+		FindMessages(ctx context.Context, conversationID int64, cursor int64, limit int) ([]dto.ChatMessageModel, error)
+		FirstMessage(ctx context.Context, id int64) (*dto.ChatMessageModel, error)
+		CreateMessage(ctx context.Context, message *model.ChatMessage, senderRole constant.ChatRole) error
+		MarkRead(ctx context.Context, conversationID int64, role constant.ChatRole) (int64, error)
+		CountUnread(ctx context.Context, accountID int64) (*dto.ChatUnreadResponse, error)
+		IsShopProduct(ctx context.Context, productID, sellerID int64) (bool, error)
+		IsConversationOrder(ctx context.Context, orderID, buyerID, sellerID int64) (bool, error)
+	}
+	chatRepository struct {
+		db *sqlx.DB
+	}
+)
+
+// conversationDetailQuery selects conversations with their participants and
+// last message, callers add the conditions.
+const conversationDetailQuery = `
+	SELECT
+		c.id,
+		c.buyer_id,
+		a.username AS buyer_name,
+		a.profile_picture_url AS buyer_image_url,
+		c.shop_id,
+		c.seller_id,
+		COALESCE(s.name, '') AS shop_name,
+		c.buyer_unread_count,
+		c.seller_unread_count,
+		m.id AS last_message_id,
+		m.sender_id AS last_message_sender_id,
+		m.type AS last_message_type,
+		m.text AS last_message_text,
+		m.created_at AS last_message_created_at,
+		c.created_at
+	FROM
+		conversations c
+		JOIN accounts a ON a.id = c.buyer_id
+		JOIN shops s ON s.id = c.shop_id
+		LEFT JOIN LATERAL (
+			SELECT
+				*
+			FROM
+				chat_messages cm
+			WHERE
+				cm.conversation_id = c.id
+			ORDER BY cm.id DESC
+			LIMIT 1
+		) m ON TRUE
+	`
+
+// chatMessageQuery selects messages with the cards of the product and order
+// they refer to, callers add the conditions.
+const chatMessageQuery = `
+	SELECT
+		cm.id,
+		cm.conversation_id,
+		cm.sender_id,
+		cm.type,
+		cm.text,
+		cm.image_url,
+		cm.product_id,
+		p.product_code,
+		p.name AS product_name,
+		p.thumbnail_url AS product_image_url,
+		cm.order_id,
+		o.invoice_number AS order_invoice_number,
+		o.status AS order_status,
+		cm.created_at
+	FROM
+		chat_messages cm
+		LEFT JOIN products p ON p.id = cm.product_id
+		LEFT JOIN orders o ON o.id = cm.order_id
+	`
+
+// FirstShopAccountID implements ChatRepository.
+func (r *chatRepository) FirstShopAccountID(ctx context.Context, shopID int64) (int64, error) {
+	qs := `
+	SELECT
+		s.account_id
+	FROM
+		shops s
+	WHERE
+		s.id = $1 AND
+		s.deleted_at IS NULL
+	`
+
+	var accountID int64
+	err := r.db.GetContext(ctx, &accountID, qs, shopID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, shared.ErrShopNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return accountID, nil
+}
+
+// FirstOrCreateConversation implements ChatRepository. A buyer has a single
+// conversation with a shop.
+func (r *chatRepository) FirstOrCreateConversation(ctx context.Context, buyerID, shopID, sellerID int64) (*model.Conversation, error) {
+	conversation := new(model.Conversation)
+	qs := `
+	INSERT INTO conversations (
+		buyer_id,
+		shop_id,
+		seller_id
+	) VALUES
+	($1, $2, $3)
+	ON CONFLICT (buyer_id, shop_id) DO UPDATE
+	SET deleted_at = NULL
+	RETURNING *
+	`
+
+	if err := r.db.GetContext(ctx, conversation, qs, buyerID, shopID, sellerID); err != nil {
+		return nil, err
+	}
+
+	return conversation, nil
+}
+
+// FirstConversation implements ChatRepository. Only the buyer and the
+// seller of a conversation find it.
+func (r *chatRepository) FirstConversation(ctx context.Context, id, accountID int64) (*model.Conversation, error) {
+	conversation := new(model.Conversation)
+	qs := `
+	SELECT
+		*
+	FROM
+		conversations c
+	WHERE
+		c.id = $1 AND
+		(c.buyer_id = $2 OR c.seller_id = $2) AND
+		c.deleted_at IS NULL
+	`
+
+	err := r.db.GetContext(ctx, conversation, qs, id, accountID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, shared.ErrConversationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return conversation, nil
+}
+
+// FirstConversationDetail implements ChatRepository.
+func (r *chatRepository) FirstConversationDetail(ctx context.Context, id int64) (*dto.ConversationModel, error) {
+	conversation := new(dto.ConversationModel)
+	qs := conversationDetailQuery + `
+	WHERE
+		c.id = $1 AND
+		c.deleted_at IS NULL
+	`
+
+	err := r.db.GetContext(ctx, conversation, qs, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, shared.ErrConversationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return conversation, nil
+}
+
+// FindConversations implements ChatRepository. The conversations of the
+// account on the side of params.Role come most recently active first. A
+// seller only sees a conversation once the buyer wrote something.
+func (r *chatRepository) FindConversations(ctx context.Context, accountID int64, params dto.ConversationParams) ([]dto.ConversationModel, error) {
+	conversations := make([]dto.ConversationModel, 0)
+	qs := conversationDetailQuery + `
+	WHERE
+		(CASE WHEN $2 = 'seller' THEN c.seller_id ELSE c.buyer_id END) = $1 AND
+		($2 <> 'seller' OR c.last_message_at IS NOT NULL) AND
+		c.deleted_at IS NULL
+	ORDER BY
+		COALESCE(c.last_message_at, c.created_at) DESC,
+		c.id DESC
+	LIMIT $3
+	OFFSET $4
+	`
+
+	offset := (params.Page - 1) * constant.ChatDefaultConversations
+	err := r.db.SelectContext(ctx, &conversations, qs, accountID, params.Role, constant.ChatDefaultConversations, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return conversations, nil
+}
+
+// CountConversations implements ChatRepository.
+func (r *chatRepository) CountConversations(ctx context.Context, accountID int64, params dto.ConversationParams) (int, error) {
+	qs := `
+	SELECT
+		COUNT(1)
+	FROM
+		conversations c
+	WHERE
+		(CASE WHEN $2 = 'seller' THEN c.seller_id ELSE c.buyer_id END) = $1 AND
+		($2 <> 'seller' OR c.last_message_at IS NOT NULL) AND
+		c.deleted_at IS NULL
+	`
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, qs, accountID, params.Role); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// FindMessages implements ChatRepository. It returns up to limit messages
+// older than the message with id cursor, newest first. A zero cursor starts
+// from the newest message.
+func (r *chatRepository) FindMessages(ctx context.Context, conversationID int64, cursor int64, limit int) ([]dto.ChatMessageModel, error) {
+	messages := make([]dto.ChatMessageModel, 0)
+	qs := chatMessageQuery + `
+	WHERE
+		cm.conversation_id = $1 AND
+		($2 = 0 OR cm.id < $2)
+	ORDER BY cm.id DESC
+	LIMIT $3
+	`
+
+	if err := r.db.SelectContext(ctx, &messages, qs, conversationID, cursor, limit); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// FirstMessage implements ChatRepository.
+func (r *chatRepository) FirstMessage(ctx context.Context, id int64) (*dto.ChatMessageModel, error) {
+	message := new(dto.ChatMessageModel)
+	qs := chatMessageQuery + `
+	WHERE
+		cm.id = $1
+	`
+
+	if err := r.db.GetContext(ctx, message, qs, id); err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// CreateMessage implements ChatRepository. The message counts as unread for
+// the other side of the conversation and as read for the sender. The id and
+// creation time of the stored message are set on message.
+func (r *chatRepository) CreateMessage(ctx context.Context, message *model.ChatMessage, senderRole constant.ChatRole) error {
+	qs1 := `
+	INSERT INTO chat_messages (
+		conversation_id,
+		sender_id,
+		type,
+		text,
+		image_url,
+		product_id,
+		order_id
+	) VALUES
+	($1, $2, $3, $4, $5, $6, $7)
+	RETURNING id, created_at
+	`
+
+	qs2 := `
+	UPDATE conversations
+	SET
+		buyer_unread_count = buyer_unread_count + 1,
+		seller_last_read_id = $1,
+		last_message_at = $2,
+		updated_at = NOW()
+	WHERE id = $3
+	`
+	if senderRole == constant.BuyerChatRole {
+		qs2 = `
+		UPDATE conversations
+		SET
+			seller_unread_count = seller_unread_count + 1,
+			buyer_last_read_id = $1,
+			last_message_at = $2,
+			updated_at = NOW()
+		WHERE id = $3
+		`
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowxContext(ctx, qs1,
+		message.ConversationID,
+		message.SenderID,
+		message.Type,
+		message.Text,
+		message.ImageURL,
+		message.ProductID,
+		message.OrderID,
+	).Scan(&message.ID, &message.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, qs2, message.ID, message.CreatedAt, message.ConversationID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// MarkRead implements ChatRepository. Everything in the conversation counts
+// as read for role, the id of the last message read is returned.
+func (r *chatRepository) MarkRead(ctx context.Context, conversationID int64, role constant.ChatRole) (int64, error) {
+	qs := `
+	UPDATE conversations c
+	SET
+		seller_unread_count = 0,
+		seller_last_read_id = COALESCE((SELECT MAX(cm.id) FROM chat_messages cm WHERE cm.conversation_id = c.id), 0),
+		updated_at = NOW()
+	WHERE id = $1
+	RETURNING seller_last_read_id
+	`
+	if role == constant.BuyerChatRole {
+		qs = `
+		UPDATE conversations c
+		SET
+			buyer_unread_count = 0,
+			buyer_last_read_id = COALESCE((SELECT MAX(cm.id) FROM chat_messages cm WHERE cm.conversation_id = c.id), 0),
+			updated_at = NOW()
+		WHERE id = $1
+		RETURNING buyer_last_read_id
+		`
+	}
+
+	var lastReadID int64
+	if err := r.db.GetContext(ctx, &lastReadID, qs, conversationID); err != nil {
+		return 0, err
+	}
+
+	return lastReadID, nil
+}
+
+// CountUnread implements ChatRepository.
+func (r *chatRepository) CountUnread(ctx context.Context, accountID int64) (*dto.ChatUnreadResponse, error) {
+	res := new(dto.ChatUnreadResponse)
+	qs := `
+	SELECT
+		COALESCE(SUM(c.buyer_unread_count) FILTER (WHERE c.buyer_id = $1), 0) AS buyer,
+		COALESCE(SUM(c.seller_unread_count) FILTER (WHERE c.seller_id = $1), 0) AS seller
+	FROM
+		conversations c
+	WHERE
+		(c.buyer_id = $1 OR c.seller_id = $1) AND
+		c.deleted_at IS NULL
+	`
+
+	if err := r.db.QueryRowxContext(ctx, qs, accountID).Scan(&res.Buyer, &res.Seller); err != nil {
+		return nil, err
+	}
+	res.Total = res.Buyer + res.Seller
+
+	return res, nil
+}
+
+// IsShopProduct implements ChatRepository.
+func (r *chatRepository) IsShopProduct(ctx context.Context, productID, sellerID int64) (bool, error) {
+	qs := `
+	SELECT EXISTS (
+		SELECT
+			1
+		FROM
+			products p
+		WHERE
+			p.id = $1 AND
+			p.seller_id = $2 AND
+			p.deleted_at IS NULL
+	)
+	`
+
+	var exists bool
+	if err := r.db.GetContext(ctx, &exists, qs, productID, sellerID); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// IsConversationOrder implements ChatRepository.
+func (r *chatRepository) IsConversationOrder(ctx context.Context, orderID, buyerID, sellerID int64) (bool, error) {
+	qs := `
+	SELECT EXISTS (
+		SELECT
+			1
+		FROM
+			orders o
+		WHERE
+			o.id = $1 AND
+			o.buyer_id = $2 AND
+			o.seller_id = $3
+	)
+	`
+
+	var exists bool
+	if err := r.db.GetContext(ctx, &exists, qs, orderID, buyerID, sellerID); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func NewChatRepository(db *sqlx.DB) ChatRepository {
+	return &chatRepository{
+		db: db,
+	}
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dto"
+)
+
+type (
+	DashboardRepository interface {
+		FindSummary(ctx context.Context, payload dto.DashboardPayload) (*dto.DashboardSummaryModel, error)
+		FindSeries(ctx context.Context, payload dto.DashboardPayload) ([]dto.DashboardBucketModel, error)
+		FindEngagement(ctx context.Context, payload dto.DashboardPayload) (*dto.DashboardEngagementModel, error)
+		FindRating(ctx context.Context, payload dto.DashboardPayload) (*dto.DashboardRatingModel, error)
+		FindTopProducts(ctx context.Context, payload dto.DashboardPayload) ([]dto.DashboardTopProductModel, error)
+		FindTopCategories(ctx context.Context, payload dto.DashboardPayload) ([]dto.DashboardTopCategoryModel, error)
+	}
+	dashboardRepository struct {
+		db *sqlx.DB
+	}
+)
+
+// sellerOrdersCTE selects the seller's orders placed between two dates with
+// their units and revenue. Revenue is what the seller earns from the products,
+// so promotions and shop vouchers are taken off while platform vouchers are
+// not.
+const sellerOrdersCTE = `
+	WITH so AS (
+		SELECT
+			o.id,
+			o.status,
+			o.created_at,
+			COALESCE(SUM(od.quantity), 0) AS units,
+			COALESCE(SUM(od.sub_total_price), 0)
+				- COALESCE(o.promotion_amount, 0)
+				- COALESCE((
+					SELECT SUM(ov.amount) FROM order_vouchers ov
+					WHERE ov.order_id = o.id AND ov.issuer = '` + constant.VoucherIssuerShop + `' AND ov.released_at IS NULL
+				), 0) AS revenue
+		FROM orders o
+		LEFT JOIN order_details od ON od.order_id = o.id
+		WHERE o.seller_id = $1
+		AND o.deleted_at IS NULL
+		AND o.created_at >= $2::date
+		AND o.created_at < $3::date + 1
+		GROUP BY o.id
+	)
+`
+
+// FindSummary implements DashboardRepository.
+func (r *dashboardRepository) FindSummary(ctx context.Context, payload dto.DashboardPayload) (*dto.DashboardSummaryModel, error) {
+	summary := new(dto.DashboardSummaryModel)
+	qs := sellerOrdersCTE + `
+	SELECT
+		COUNT(so.id) FILTER (WHERE so.status <> $4) AS orders,
+		COUNT(so.id) FILTER (WHERE so.status = $4) AS cancelled_orders,
+		COALESCE(SUM(so.revenue) FILTER (WHERE so.status <> $4), 0) AS revenue,
+		COALESCE(SUM(so.units) FILTER (WHERE so.status <> $4), 0) AS units_sold
+	FROM so
+	`
+
+	err := r.db.GetContext(ctx, summary, qs, payload.SellerID, payload.StartDate, payload.EndDate, constant.CancelOrderStatus)
+	if err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+// FindSeries implements DashboardRepository. Buckets without orders are
+// returned with zeroes.
+func (r *dashboardRepository) FindSeries(ctx context.Context, payload dto.DashboardPayload) ([]dto.DashboardBucketModel, error) {
+	series := make([]dto.DashboardBucketModel, 0)
+	qs := sellerOrdersCTE + `
+	SELECT
+		to_char(b.bucket, 'YYYY-MM-DD') AS bucket_start,
+		COUNT(so.id) AS orders,
+		COALESCE(SUM(so.revenue), 0) AS revenue,
+		COALESCE(SUM(so.units), 0) AS units_sold
+	FROM generate_series(date_trunc($5, $2::date), $3::date, ('1 ' || $5)::interval) AS b(bucket)
+	LEFT JOIN so ON date_trunc($5, so.created_at) = b.bucket AND so.status <> $4
+	GROUP BY b.bucket
+	ORDER BY b.bucket
+	`
+
+	unit := "day"
+	if payload.Bucket == constant.DashboardBucketWeekly {
+		unit = "week"
+	}
+
+	err := r.db.SelectContext(ctx, &series, qs, payload.SellerID, payload.StartDate, payload.EndDate, constant.CancelOrderStatus, unit)
+	if err != nil {
+		return nil, err
+	}
+
+	return series, nil
+}
+
+// FindEngagement implements DashboardRepository.
+func (r *dashboardRepository) FindEngagement(ctx context.Context, payload dto.DashboardPayload) (*dto.DashboardEngagementModel, error) {
+	engagement := new(dto.DashboardEngagementModel)
+	qs := `
+	SELECT
+		COALESCE(SUM(s.views), 0) AS product_views,
+		COALESCE(SUM(s.add_to_carts), 0) AS add_to_carts
+	FROM product_daily_stats s
+	JOIN products p ON p.id = s.product_id
+	WHERE p.seller_id = $1
+	AND s.date BETWEEN $2::date AND $3::date
+	`
+
+	err := r.db.GetContext(ctx, engagement, qs, payload.SellerID, payload.StartDate, payload.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return engagement, nil
+}
+
+// FindRating implements DashboardRepository.
+func (r *dashboardRepository) FindRating(ctx context.Context, payload dto.DashboardPayload) (*dto.DashboardRatingModel, error) {
+	rating := new(dto.DashboardRatingModel)
+	qs := `
+	SELECT
+		COALESCE(AVG(r.rating), 0) AS average_rating,
+		COUNT(r.id) AS review_count
+	FROM reviews r
+	JOIN products p ON p.product_code = r.product_code
+	WHERE p.seller_id = $1
+	AND r.created_at >= $2::date
+	AND r.created_at < $3::date + 1
+	`
+
+	err := r.db.GetContext(ctx, rating, qs, payload.SellerID, payload.StartDate, payload.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return rating, nil
+}
+
+// FindTopProducts implements DashboardRepository. Products are ranked by the
+// value of their order lines.
+func (r *dashboardRepository) FindTopProducts(ctx context.Context, payload dto.DashboardPayload) ([]dto.DashboardTopProductModel, error) {
+	products := make([]dto.DashboardTopProductModel, 0)
+	qs := sellerOrdersCTE + `
+	SELECT
+		od.product_code,
+		MAX(od.product_name) AS product_name,
+		SUM(od.quantity) AS units_sold,
+		SUM(od.sub_total_price) AS revenue
+	FROM so
+	JOIN order_details od ON od.order_id = so.id
+	WHERE so.status <> $4
+	GROUP BY od.product_code
+	ORDER BY revenue DESC, units_sold DESC
+	LIMIT $5
+	`
+
+	err := r.db.SelectContext(ctx, &products, qs, payload.SellerID, payload.StartDate, payload.EndDate, constant.CancelOrderStatus, constant.DashboardTopItems)
+	if err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
+
+// FindTopCategories implements DashboardRepository. Sales are grouped by the
+// top level category of the product.
+func (r *dashboardRepository) FindTopCategories(ctx context.Context, payload dto.DashboardPayload) ([]dto.DashboardTopCategoryModel, error) {
+	categories := make([]dto.DashboardTopCategoryModel, 0)
+	qs := sellerOrdersCTE + `
+	SELECT
+		c.id AS category_id,
+		c.name AS category_name,
+		SUM(od.quantity) AS units_sold,
+		SUM(od.sub_total_price) AS revenue
+	FROM so
+	JOIN order_details od ON od.order_id = so.id
+	JOIN products p ON p.product_code = od.product_code
+	JOIN product_categories pc ON pc.product_id = p.id
+	JOIN categories c ON c.id = pc.category_id AND c.level = 1
+	WHERE so.status <> $4
+	GROUP BY c.id, c.name
+	ORDER BY revenue DESC, units_sold DESC
+	LIMIT $5
+	`
+
+	err := r.db.SelectContext(ctx, &categories, qs, payload.SellerID, payload.StartDate, payload.EndDate, constant.CancelOrderStatus, constant.DashboardTopItems)
+	if err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func NewDashboardRepository(db *sqlx.DB) DashboardRepository {
+	return &dashboardRepository{
+		db: db,
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/lil-oren/rest/internal/model"
 )
 
@@ -11,6 +12,8 @@ type (
 	DistrictRepository interface {
 		FindByProvinceID(ctx context.Context, provinceId int64) ([]model.District, error)
 		FirstByID(ctx context.Context, districtID int64) (*model.District, error)
+		FindAll(ctx context.Context) ([]model.District, error)
+		Sync(ctx context.Context, districts []model.District, inactiveIDs []int64) error
 	}
 	districtRepository struct {
 		db *sqlx.DB
@@ -44,7 +47,7 @@ func (r *districtRepository) FirstByID(ctx context.Context, districtID int64) (*
 func (r *districtRepository) FindByProvinceID(ctx context.Context, provinceId int64) ([]model.District, error) {
 	districts := make([]model.District, 0)
 
-	qs := `SELECT * FROM districts WHERE province_id = $1`
+	qs := `SELECT * FROM districts WHERE province_id = $1 AND is_active`
 
 	err := r.db.SelectContext(ctx, &districts, qs, provinceId)
 	if err != nil {
@@ -54,6 +57,83 @@ func (r *districtRepository) FindByProvinceID(ctx context.Context, provinceId in
 	return districts, nil
 }
 
+// FindAll implements DistrictRepository. Unlike FindByProvinceID it also
+// returns the districts the provider no longer lists.
+func (r *districtRepository) FindAll(ctx context.Context) ([]model.District, error) {
+	districts := make([]model.District, 0)
+
+	qs := `SELECT * FROM districts ORDER BY id`
+
+	if err := r.db.SelectContext(ctx, &districts, qs); err != nil {
+		return nil, err
+	}
+
+	return districts, nil
+}
+
+// Sync implements DistrictRepository. Districts without an id are added,
+// the others are updated and made active again, and inactiveIDs are hidden
+// from new addresses.
+func (r *districtRepository) Sync(ctx context.Context, districts []model.District, inactiveIDs []int64) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	qs1 := `
+	INSERT INTO districts (
+		name,
+		province_id,
+		postal_code,
+		provider_id,
+		is_active
+	) VALUES (
+		$1,
+		$2,
+		$3,
+		$4,
+		TRUE
+	)
+	`
+
+	qs2 := `
+	UPDATE districts
+	SET name = $1, province_id = $2, postal_code = $3, provider_id = $4, is_active = TRUE
+	WHERE id = $5
+	`
+
+	qs3 := `
+	UPDATE districts
+	SET is_active = FALSE
+	WHERE id = ANY($1)
+	`
+
+	for _, d := range districts {
+		if d.ID == 0 {
+			if _, err := tx.ExecContext(ctx, qs1, d.Name, d.ProvinceID, d.PostalCode, d.ProviderID); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, qs2, d.Name, d.ProvinceID, d.PostalCode, d.ProviderID, d.ID); err != nil {
+			return err
+		}
+	}
+
+	if len(inactiveIDs) != 0 {
+		if _, err := tx.ExecContext(ctx, qs3, pq.Array(inactiveIDs)); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func NewDistrictRepository(db *sqlx.DB) DistrictRepository {
 	return &districtRepository{
 		db: db,
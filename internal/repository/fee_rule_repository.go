@@ -0,0 +1,269 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/model"
+	"github.com/lil-oren/rest/internal/shared"
+)
+
+type (
+	FeeRuleRepository interface {
+		Create(ctx context.Context, rule *model.FeeRule) (*int64, error)
+		Update(ctx context.Context, rule *model.FeeRule) error
+		Delete(ctx context.Context, id int64) error
+		Find(ctx context.Context, params dto.FeeRuleParams) ([]model.FeeRule, error)
+		Count(ctx context.Context, params dto.FeeRuleParams) (int, error)
+		FindAll(ctx context.Context) ([]model.FeeRule, error)
+		FirstShopTier(ctx context.Context, sellerID int64) (string, error)
+		FindProductCategories(ctx context.Context, productIDs []int64) ([]dto.ProductCategoryLevel, error)
+	}
+	feeRuleRepository struct {
+		db *sqlx.DB
+	}
+)
+
+// Create implements FeeRuleRepository. There is at most one rule of a kind
+// for a category and shop tier.
+func (r *feeRuleRepository) Create(ctx context.Context, rule *model.FeeRule) (*int64, error) {
+	qs := `
+	INSERT INTO fee_rules (
+		kind,
+		category_id,
+		shop_tier,
+		rate,
+		flat_amount
+	) VALUES
+	($1, $2, $3, $4, $5)
+	RETURNING (id)
+	`
+
+	if err := r.checkScope(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	id := new(int64)
+	err := r.db.QueryRowxContext(ctx, qs,
+		rule.Kind,
+		rule.CategoryID,
+		rule.ShopTier,
+		rule.Rate,
+		rule.FlatAmount,
+	).Scan(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return id, nil
+}
+
+// Update implements FeeRuleRepository.
+func (r *feeRuleRepository) Update(ctx context.Context, rule *model.FeeRule) error {
+	qs := `
+	UPDATE fee_rules
+	SET
+		kind = $1,
+		category_id = $2,
+		shop_tier = $3,
+		rate = $4,
+		flat_amount = $5,
+		updated_at = NOW()
+	WHERE id = $6 AND deleted_at IS NULL
+	`
+
+	if err := r.checkScope(ctx, rule); err != nil {
+		return err
+	}
+
+	res, err := r.db.ExecContext(ctx, qs,
+		rule.Kind,
+		rule.CategoryID,
+		rule.ShopTier,
+		rule.Rate,
+		rule.FlatAmount,
+		rule.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return feeRuleAffected(res)
+}
+
+// Delete implements FeeRuleRepository.
+func (r *feeRuleRepository) Delete(ctx context.Context, id int64) error {
+	qs := `
+	UPDATE fee_rules
+	SET deleted_at = NOW()
+	WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	res, err := r.db.ExecContext(ctx, qs, id)
+	if err != nil {
+		return err
+	}
+
+	return feeRuleAffected(res)
+}
+
+// Find implements FeeRuleRepository.
+func (r *feeRuleRepository) Find(ctx context.Context, params dto.FeeRuleParams) ([]model.FeeRule, error) {
+	rules := make([]model.FeeRule, 0)
+	qs := `
+	SELECT
+		*
+	FROM
+		fee_rules fr
+	WHERE
+		($1 = '' OR fr.kind = $1) AND
+		fr.deleted_at IS NULL
+	ORDER BY
+		fr.kind,
+		fr.category_id NULLS FIRST,
+		fr.shop_tier NULLS FIRST,
+		fr.id
+	LIMIT $2
+	OFFSET $3
+	`
+
+	offset := (params.Page - 1) * constant.FeeRuleDefaultItems
+	err := r.db.SelectContext(ctx, &rules, qs, params.Kind, constant.FeeRuleDefaultItems, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// Count implements FeeRuleRepository.
+func (r *feeRuleRepository) Count(ctx context.Context, params dto.FeeRuleParams) (int, error) {
+	qs := `
+	SELECT
+		COUNT(1)
+	FROM
+		fee_rules fr
+	WHERE
+		($1 = '' OR fr.kind = $1) AND
+		fr.deleted_at IS NULL
+	`
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, qs, params.Kind); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// FindAll implements FeeRuleRepository.
+func (r *feeRuleRepository) FindAll(ctx context.Context) ([]model.FeeRule, error) {
+	rules := make([]model.FeeRule, 0)
+	qs := `
+	SELECT
+		*
+	FROM
+		fee_rules fr
+	WHERE
+		fr.deleted_at IS NULL
+	ORDER BY fr.id
+	`
+
+	if err := r.db.SelectContext(ctx, &rules, qs); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// FirstShopTier implements FeeRuleRepository. A seller without a shop is
+// on the regular tier.
+func (r *feeRuleRepository) FirstShopTier(ctx context.Context, sellerID int64) (string, error) {
+	qs := `SELECT s.tier FROM shops s WHERE s.account_id = $1`
+
+	var tier string
+	if err := r.db.GetContext(ctx, &tier, qs, sellerID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return string(constant.RegularShopTier), nil
+		}
+		return "", err
+	}
+
+	return tier, nil
+}
+
+// FindProductCategories implements FeeRuleRepository.
+func (r *feeRuleRepository) FindProductCategories(ctx context.Context, productIDs []int64) ([]dto.ProductCategoryLevel, error) {
+	categories := make([]dto.ProductCategoryLevel, 0)
+	qs := `
+	SELECT
+		pc.product_id,
+		pc.category_id,
+		c.level
+	FROM
+		product_categories pc
+	JOIN categories c ON
+		c.id = pc.category_id
+	WHERE
+		pc.product_id = ANY($1) AND
+		pc.deleted_at IS NULL
+	`
+
+	if err := r.db.SelectContext(ctx, &categories, qs, pq.Array(productIDs)); err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+// checkScope fails when another rule of the same kind already covers the
+// category and shop tier of rule.
+func (r *feeRuleRepository) checkScope(ctx context.Context, rule *model.FeeRule) error {
+	qs := `
+	SELECT EXISTS (
+		SELECT
+			1
+		FROM
+			fee_rules fr
+		WHERE
+			fr.kind = $1 AND
+			COALESCE(fr.category_id, 0) = COALESCE($2, 0) AND
+			COALESCE(fr.shop_tier, '') = COALESCE($3, '') AND
+			fr.id != $4 AND
+			fr.deleted_at IS NULL
+	)
+	`
+
+	var exists bool
+	if err := r.db.GetContext(ctx, &exists, qs, rule.Kind, rule.CategoryID, rule.ShopTier, rule.ID); err != nil {
+		return err
+	}
+	if exists {
+		return shared.ErrFeeRuleExists
+	}
+
+	return nil
+}
+
+func feeRuleAffected(res sql.Result) error {
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return shared.ErrFeeRuleNotFound
+	}
+
+	return nil
+}
+
+func NewFeeRuleRepository(db *sqlx.DB) FeeRuleRepository {
+	return &feeRuleRepository{
+		db: db,
+	}
+}
@@ -0,0 +1,479 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/model"
+	"github.com/lil-oren/rest/internal/shared"
+)
+
+type (
+	FlashSaleRepository interface {
+		Create(ctx context.Context, flashSale *model.FlashSale, variants []model.FlashSaleVariant) (*int64, error)
+		FirstByID(ctx context.Context, id int64) (*model.FlashSale, error)
+		FindBySellerID(ctx context.Context, sellerID int64, page int) ([]model.FlashSale, error)
+		CountBySellerID(ctx context.Context, sellerID int64) (int, error)
+		FindVariantsByFlashSaleIDs(ctx context.Context, ids []int64) ([]dto.FlashSaleVariantModel, error)
+		FindActiveByProductID(ctx context.Context, productID int64) ([]dto.ActiveFlashSaleVariant, error)
+		Cancel(ctx context.Context, id int64) error
+		ApplyFlashSales(ctx context.Context) error
+	}
+	flashSaleRepository struct {
+		db *sqlx.DB
+	}
+)
+
+// Create implements FlashSaleRepository. Every variant must belong to the
+// seller and must not be part of another flash sale in an overlapping period.
+func (r *flashSaleRepository) Create(ctx context.Context, flashSale *model.FlashSale, variants []model.FlashSaleVariant) (*int64, error) {
+	qs1 := `
+	INSERT INTO flash_sales (seller_id, name, start_at, end_at) VALUES
+	($1, $2, $3, $4)
+	RETURNING (id)
+	`
+
+	qs2 := `
+	SELECT
+		pv.price
+	FROM product_variants pv
+	JOIN products p ON p.id = pv.product_id
+	WHERE pv.id = $1 AND p.seller_id = $2 AND p.deleted_at IS NULL AND pv.deleted_at IS NULL
+	FOR UPDATE OF pv
+	`
+
+	qs3 := `
+	SELECT EXISTS (
+		SELECT
+			1
+		FROM flash_sale_variants fsv
+		JOIN flash_sales fs ON fs.id = fsv.flash_sale_id
+		WHERE fsv.product_variant_id = $1
+		AND fsv.status IN ($2, $3)
+		AND fs.deleted_at IS NULL
+		AND fs.start_at < $5 AND fs.end_at > $4
+	)
+	`
+
+	qs4 := `
+	INSERT INTO flash_sale_variants (
+		flash_sale_id,
+		product_variant_id,
+		discount,
+		discounted_price,
+		purchase_limit,
+		quota,
+		status
+	) VALUES
+	($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	id := new(int64)
+	err = tx.QueryRowx(qs1, flashSale.SellerID, flashSale.Name, flashSale.StartAt, flashSale.EndAt).Scan(id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range variants {
+		var price float64
+		if err := tx.QueryRowx(qs2, v.ProductVariantID, flashSale.SellerID).Scan(&price); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, shared.ErrProductVariantNotFound
+			}
+			return nil, err
+		}
+		if v.DiscountedPrice.Valid && v.DiscountedPrice.Decimal.InexactFloat64() >= price {
+			return nil, shared.ErrInvalidFlashSaleDiscount
+		}
+
+		var overlap bool
+		err := tx.QueryRowx(qs3, v.ProductVariantID, constant.FlashSaleStatusScheduled, constant.FlashSaleStatusActive, flashSale.StartAt, flashSale.EndAt).Scan(&overlap)
+		if err != nil {
+			return nil, err
+		}
+		if overlap {
+			return nil, shared.ErrFlashSaleOverlap
+		}
+
+		_, err = tx.Exec(qs4, id, v.ProductVariantID, v.Discount, v.DiscountedPrice, v.PurchaseLimit, v.Quota, constant.FlashSaleStatusScheduled)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return id, nil
+}
+
+// FirstByID implements FlashSaleRepository.
+func (r *flashSaleRepository) FirstByID(ctx context.Context, id int64) (*model.FlashSale, error) {
+	flashSale := new(model.FlashSale)
+	qs := `SELECT * FROM flash_sales fs WHERE fs.id = $1 AND fs.deleted_at IS NULL`
+
+	if err := r.db.GetContext(ctx, flashSale, qs, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, shared.ErrFlashSaleNotFound
+		}
+		return nil, err
+	}
+
+	return flashSale, nil
+}
+
+// FindBySellerID implements FlashSaleRepository.
+func (r *flashSaleRepository) FindBySellerID(ctx context.Context, sellerID int64, page int) ([]model.FlashSale, error) {
+	flashSales := make([]model.FlashSale, 0)
+	qs := `
+	SELECT * FROM flash_sales fs
+	WHERE fs.seller_id = $1 AND fs.deleted_at IS NULL
+	ORDER BY fs.start_at DESC
+	LIMIT $2 OFFSET $3
+	`
+
+	limit := constant.FlashSaleDefaultItems
+	offset := (page - 1) * limit
+	if err := r.db.SelectContext(ctx, &flashSales, qs, sellerID, limit, offset); err != nil {
+		return nil, err
+	}
+
+	return flashSales, nil
+}
+
+// CountBySellerID implements FlashSaleRepository.
+func (r *flashSaleRepository) CountBySellerID(ctx context.Context, sellerID int64) (int, error) {
+	var count int
+	qs := `SELECT COUNT(fs.id) FROM flash_sales fs WHERE fs.seller_id = $1 AND fs.deleted_at IS NULL`
+
+	if err := r.db.GetContext(ctx, &count, qs, sellerID); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// FindVariantsByFlashSaleIDs implements FlashSaleRepository.
+func (r *flashSaleRepository) FindVariantsByFlashSaleIDs(ctx context.Context, ids []int64) ([]dto.FlashSaleVariantModel, error) {
+	variants := make([]dto.FlashSaleVariantModel, 0)
+	if len(ids) == 0 {
+		return variants, nil
+	}
+
+	qs, args, err := sqlx.In(`
+	SELECT
+		fsv.flash_sale_id,
+		fsv.product_variant_id,
+		p.product_code,
+		p.name AS product_name,
+		COALESCE(vt1.name, '') AS variant_type1_name,
+		COALESCE(vt2.name, '') AS variant_type2_name,
+		pv.price,
+		fsv.discount,
+		fsv.discounted_price,
+		fsv.purchase_limit,
+		fsv.quota,
+		fsv.sold,
+		fsv.status
+	FROM flash_sale_variants fsv
+	JOIN product_variants pv ON pv.id = fsv.product_variant_id
+	JOIN products p ON p.id = pv.product_id
+	LEFT JOIN variant_types vt1 ON vt1.id = pv.variant_type1_id
+	LEFT JOIN variant_types vt2 ON vt2.id = pv.variant_type2_id
+	WHERE fsv.flash_sale_id IN (?)
+	ORDER BY fsv.flash_sale_id, fsv.id
+	`, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.db.SelectContext(ctx, &variants, r.db.Rebind(qs), args...); err != nil {
+		return nil, err
+	}
+
+	return variants, nil
+}
+
+// FindActiveByProductID implements FlashSaleRepository.
+func (r *flashSaleRepository) FindActiveByProductID(ctx context.Context, productID int64) ([]dto.ActiveFlashSaleVariant, error) {
+	variants := make([]dto.ActiveFlashSaleVariant, 0)
+	qs := `
+	SELECT
+		fsv.product_variant_id,
+		fs.end_at,
+		fsv.purchase_limit,
+		fsv.quota,
+		fsv.sold
+	FROM flash_sale_variants fsv
+	JOIN flash_sales fs ON fs.id = fsv.flash_sale_id
+	JOIN product_variants pv ON pv.id = fsv.product_variant_id
+	WHERE pv.product_id = $1 AND fsv.status = $2
+	`
+
+	if err := r.db.SelectContext(ctx, &variants, qs, productID, constant.FlashSaleStatusActive); err != nil {
+		return nil, err
+	}
+
+	return variants, nil
+}
+
+// Cancel implements FlashSaleRepository. Running variants get their original
+// discount back immediately.
+func (r *flashSaleRepository) Cancel(ctx context.Context, id int64) error {
+	qs1 := `
+	UPDATE product_variants pv
+	SET discount = fsv.original_discount, updated_at = now()
+	FROM flash_sale_variants fsv
+	WHERE fsv.product_variant_id = pv.id AND fsv.flash_sale_id = $1 AND fsv.status = $2
+	`
+
+	qs2 := `
+	UPDATE flash_sale_variants
+	SET status = $1, updated_at = now()
+	WHERE flash_sale_id = $2 AND status IN ($3, $4)
+	`
+
+	qs3 := `
+	UPDATE flash_sales
+	SET deleted_at = now(), updated_at = now()
+	WHERE id = $1
+	`
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(qs1, id, constant.FlashSaleStatusActive); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(qs2, constant.FlashSaleStatusCancelled, id, constant.FlashSaleStatusScheduled, constant.FlashSaleStatusActive)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(qs3, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ApplyFlashSales implements FlashSaleRepository. Flash sales that ended or
+// sold out restore the variant's original discount, then flash sales that
+// started write their discount to the variant, so every price read from
+// product_variants.discount follows the running campaign. Every flash sale
+// that started records a FLASH_SALE_STARTED event.
+func (r *flashSaleRepository) ApplyFlashSales(ctx context.Context) error {
+	qs1 := `
+	WITH ended AS (
+		UPDATE flash_sale_variants fsv
+		SET status = $1, updated_at = now()
+		FROM flash_sales fs
+		WHERE fs.id = fsv.flash_sale_id
+		AND fsv.status = $2
+		AND (fs.end_at <= now() OR (fsv.quota > 0 AND fsv.sold >= fsv.quota))
+		RETURNING fsv.product_variant_id, fsv.original_discount
+	)
+	UPDATE product_variants pv
+	SET discount = ended.original_discount, updated_at = now()
+	FROM ended
+	WHERE pv.id = ended.product_variant_id
+	`
+
+	qs2 := `
+	UPDATE flash_sale_variants fsv
+	SET status = $1, updated_at = now()
+	FROM flash_sales fs
+	WHERE fs.id = fsv.flash_sale_id AND fsv.status = $2 AND fs.end_at <= now()
+	`
+
+	qs3 := `
+	WITH started AS (
+		UPDATE flash_sale_variants fsv
+		SET status = $1, original_discount = pv.discount, updated_at = now()
+		FROM flash_sales fs, product_variants pv
+		WHERE fs.id = fsv.flash_sale_id
+		AND pv.id = fsv.product_variant_id
+		AND fsv.status = $2
+		AND fs.deleted_at IS NULL
+		AND fs.start_at <= now() AND fs.end_at > now()
+		RETURNING fsv.flash_sale_id, fsv.product_variant_id, fsv.discount, fsv.discounted_price
+	)
+	UPDATE product_variants pv
+	SET discount = CASE
+			WHEN started.discounted_price IS NOT NULL THEN GREATEST((pv.price - started.discounted_price) * 100 / pv.price, 0)
+			ELSE started.discount
+		END,
+		updated_at = now()
+	FROM started
+	WHERE pv.id = started.product_variant_id
+	RETURNING started.flash_sale_id
+	`
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(qs1, constant.FlashSaleStatusEnded, constant.FlashSaleStatusActive); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(qs2, constant.FlashSaleStatusEnded, constant.FlashSaleStatusScheduled); err != nil {
+		return err
+	}
+
+	startedIDs := make([]int64, 0)
+	if err := tx.Select(&startedIDs, qs3, constant.FlashSaleStatusActive, constant.FlashSaleStatusScheduled); err != nil {
+		return err
+	}
+
+	seen := make(map[int64]bool)
+	for _, id := range startedIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		if err := createFlashSaleStartedEvent(tx, id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ReserveFlashSale counts quantity of an order against the running flash sale
+// of a product variant, if any. The variant gets its original discount back
+// as soon as the campaign stock is sold out.
+func ReserveFlashSale(tx *sqlx.Tx, productVariantID, buyerID, orderID int64, quantity int) error {
+	qs1 := `
+	SELECT
+		fsv.id,
+		fsv.purchase_limit,
+		fsv.quota,
+		fsv.sold,
+		fsv.original_discount
+	FROM flash_sale_variants fsv
+	WHERE fsv.product_variant_id = $1 AND fsv.status = $2
+	FOR UPDATE
+	`
+
+	qs2 := `
+	SELECT
+		COALESCE(SUM(fsp.quantity), 0)
+	FROM flash_sale_purchases fsp
+	WHERE fsp.flash_sale_variant_id = $1 AND fsp.account_id = $2
+	`
+
+	qs3 := `
+	UPDATE flash_sale_variants
+	SET sold = sold + $1, updated_at = now()
+	WHERE id = $2
+	`
+
+	qs4 := `
+	INSERT INTO flash_sale_purchases (flash_sale_variant_id, account_id, order_id, quantity) VALUES
+	($1, $2, $3, $4)
+	`
+
+	qs5 := `
+	UPDATE flash_sale_variants
+	SET status = $1, updated_at = now()
+	WHERE id = $2
+	`
+
+	qs6 := `
+	UPDATE product_variants
+	SET discount = $1, updated_at = now()
+	WHERE id = $2
+	`
+
+	variant := new(model.FlashSaleVariant)
+	err := tx.QueryRowx(qs1, productVariantID, constant.FlashSaleStatusActive).Scan(
+		&variant.ID,
+		&variant.PurchaseLimit,
+		&variant.Quota,
+		&variant.Sold,
+		&variant.OriginalDiscount,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	if variant.PurchaseLimit > 0 {
+		var bought int
+		if err := tx.QueryRowx(qs2, variant.ID, buyerID).Scan(&bought); err != nil {
+			return err
+		}
+		if bought+quantity > variant.PurchaseLimit {
+			return shared.ErrFlashSalePurchaseLimit
+		}
+	}
+
+	if variant.Quota > 0 && variant.Sold+quantity > variant.Quota {
+		return shared.ErrFlashSaleSoldOut
+	}
+
+	if _, err := tx.Exec(qs3, quantity, variant.ID); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(qs4, variant.ID, buyerID, orderID, quantity); err != nil {
+		return err
+	}
+
+	if variant.Quota > 0 && variant.Sold+quantity == variant.Quota {
+		if _, err := tx.Exec(qs5, constant.FlashSaleStatusEnded, variant.ID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(qs6, variant.OriginalDiscount, productVariantID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReleaseFlashSale gives the flash sale stock taken by a cancelled order back
+// to its campaigns.
+func ReleaseFlashSale(tx *sqlx.Tx, orderID int64) error {
+	qs := `
+	WITH released AS (
+		DELETE FROM flash_sale_purchases
+		WHERE order_id = $1
+		RETURNING flash_sale_variant_id, quantity
+	)
+	UPDATE flash_sale_variants fsv
+	SET sold = fsv.sold - released.quantity, updated_at = now()
+	FROM released
+	WHERE fsv.id = released.flash_sale_variant_id
+	`
+
+	_, err := tx.Exec(qs, orderID)
+	return err
+}
+
+func NewFlashSaleRepository(db *sqlx.DB) FlashSaleRepository {
+	return &flashSaleRepository{
+		db: db,
+	}
+}
@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/model"
+	"github.com/lil-oren/rest/internal/shared"
+)
+
+type (
+	InventoryRepository interface {
+		FindStockMovements(ctx context.Context, payload dto.StockHistoryPayload) ([]dto.StockMovementModel, error)
+		CountStockMovements(ctx context.Context, payload dto.StockHistoryPayload) (int, error)
+		BulkUpdateStock(ctx context.Context, payload dto.BulkUpdateStockPayload) (int, error)
+		UpdateLowStockThreshold(ctx context.Context, payload dto.UpdateLowStockThresholdPayload) error
+		FindLowStockVariants(ctx context.Context, defaultThreshold int64) ([]dto.LowStockVariant, error)
+		MarkLowStockNotified(ctx context.Context, variantIDs []int64) error
+		ResetLowStockNotified(ctx context.Context, defaultThreshold int64) error
+	}
+	inventoryRepository struct {
+		db *sqlx.DB
+	}
+)
+
+const stockMovementFromClause = `
+	FROM stock_movements sm
+	JOIN product_variants pv ON pv.id = sm.product_variant_id
+	JOIN products p ON p.id = pv.product_id
+	LEFT JOIN variant_types vt1 ON vt1.id = pv.variant_type1_id
+	LEFT JOIN variant_types vt2 ON vt2.id = pv.variant_type2_id
+	LEFT JOIN accounts a ON a.id = sm.actor_id
+	WHERE p.product_code = $1 AND p.seller_id = $2
+`
+
+// FindStockMovements implements InventoryRepository.
+func (r *inventoryRepository) FindStockMovements(ctx context.Context, payload dto.StockHistoryPayload) ([]dto.StockMovementModel, error) {
+	movements := make([]dto.StockMovementModel, 0)
+	qs := `
+	SELECT
+		sm.id,
+		COALESCE(vt1.name, '') AS variant_type1_name,
+		COALESCE(vt2.name, '') AS variant_type2_name,
+		sm.quantity,
+		sm.stock_after,
+		sm.reason,
+		COALESCE(a.username, '') AS actor_name,
+		sm.order_id,
+		to_char(sm.created_at, 'YYYY-MM-DD"T"HH24:MI:SSOF') AS created_at
+	` + stockMovementFromClause + `
+	ORDER BY sm.created_at DESC, sm.id DESC
+	LIMIT $3 OFFSET $4
+	`
+
+	offset := (payload.Page - 1) * constant.StockHistoryDefaultItems
+	err := r.db.SelectContext(ctx, &movements, qs, payload.ProductCode, payload.SellerID, constant.StockHistoryDefaultItems, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return movements, nil
+}
+
+// CountStockMovements implements InventoryRepository.
+func (r *inventoryRepository) CountStockMovements(ctx context.Context, payload dto.StockHistoryPayload) (int, error) {
+	var count int
+	qs := `SELECT COUNT(sm.id) ` + stockMovementFromClause
+
+	err := r.db.GetContext(ctx, &count, qs, payload.ProductCode, payload.SellerID)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// BulkUpdateStock implements InventoryRepository. Every variant must belong to
+// the seller, otherwise nothing is updated. It returns the number of variants
+// whose stock actually changed.
+func (r *inventoryRepository) BulkUpdateStock(ctx context.Context, payload dto.BulkUpdateStockPayload) (int, error) {
+	qs1 := `
+	SELECT
+		pv.stock
+	FROM product_variants pv
+	JOIN products p ON p.id = pv.product_id
+	WHERE pv.id = $1 AND p.seller_id = $2 AND p.deleted_at IS NULL AND pv.deleted_at IS NULL
+	FOR UPDATE OF pv
+	`
+
+	qs2 := `
+	UPDATE product_variants
+	SET stock = $1, updated_at = now()
+	WHERE id = $2
+	`
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	updated := 0
+	for _, item := range payload.Items {
+		var stock int64
+		err := tx.QueryRowx(qs1, item.ProductVariantID, payload.SellerID).Scan(&stock)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return 0, shared.ErrProductVariantNotFound
+			}
+			return 0, err
+		}
+
+		if stock == item.Stock {
+			continue
+		}
+
+		if _, err := tx.Exec(qs2, item.Stock, item.ProductVariantID); err != nil {
+			return 0, err
+		}
+
+		movement := model.StockMovement{
+			ProductVariantID: item.ProductVariantID,
+			Quantity:         item.Stock - stock,
+			StockAfter:       item.Stock,
+			Reason:           constant.StockReasonBulkUpdate,
+			ActorID:          sql.NullInt64{Int64: payload.SellerID, Valid: true},
+		}
+		if err := CreateStockMovement(tx, movement); err != nil {
+			return 0, err
+		}
+		updated++
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return updated, nil
+}
+
+// UpdateLowStockThreshold implements InventoryRepository. A nil threshold
+// makes the product fall back to the configured default.
+func (r *inventoryRepository) UpdateLowStockThreshold(ctx context.Context, payload dto.UpdateLowStockThresholdPayload) error {
+	qs := `
+	UPDATE products
+	SET low_stock_threshold = $1, updated_at = $2
+	WHERE product_code = $3 AND seller_id = $4 AND deleted_at IS NULL
+	`
+
+	res, err := r.db.ExecContext(ctx, qs, payload.Threshold, time.Now(), payload.ProductCode, payload.SellerID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return shared.ErrProductNotFound
+	}
+
+	return nil
+}
+
+// FindLowStockVariants implements InventoryRepository. Only variants that
+// have not been reported since they last dropped to the threshold are returned.
+func (r *inventoryRepository) FindLowStockVariants(ctx context.Context, defaultThreshold int64) ([]dto.LowStockVariant, error) {
+	variants := make([]dto.LowStockVariant, 0)
+	qs := `
+	SELECT
+		pv.id AS product_variant_id,
+		p.product_code,
+		p.name AS product_name,
+		COALESCE(vt1.name, '') AS variant_type1_name,
+		COALESCE(vt2.name, '') AS variant_type2_name,
+		pv.stock,
+		COALESCE(p.low_stock_threshold, $1) AS threshold,
+		a.id AS seller_id,
+		COALESCE(s.name, a.username) AS seller_name,
+		a.email AS seller_email
+	FROM product_variants pv
+	JOIN products p ON p.id = pv.product_id
+	JOIN accounts a ON a.id = p.seller_id
+	LEFT JOIN shops s ON s.account_id = a.id
+	LEFT JOIN variant_types vt1 ON vt1.id = pv.variant_type1_id
+	LEFT JOIN variant_types vt2 ON vt2.id = pv.variant_type2_id
+	WHERE pv.low_stock_notified_at IS NULL
+	AND pv.deleted_at IS NULL
+	AND p.deleted_at IS NULL
+	AND p.status <> $2
+	AND pv.stock <= COALESCE(p.low_stock_threshold, $1)
+	ORDER BY a.id, p.id, pv.id
+	`
+
+	err := r.db.SelectContext(ctx, &variants, qs, defaultThreshold, constant.ProductStatusArchived)
+	if err != nil {
+		return nil, err
+	}
+
+	return variants, nil
+}
+
+// MarkLowStockNotified implements InventoryRepository.
+func (r *inventoryRepository) MarkLowStockNotified(ctx context.Context, variantIDs []int64) error {
+	if len(variantIDs) == 0 {
+		return nil
+	}
+
+	qs, args, err := sqlx.In(`UPDATE product_variants SET low_stock_notified_at = now() WHERE id IN (?)`, variantIDs)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, r.db.Rebind(qs), args...)
+	return err
+}
+
+// ResetLowStockNotified implements InventoryRepository. Variants restocked
+// above their threshold are re-armed so the next drop is reported again.
+func (r *inventoryRepository) ResetLowStockNotified(ctx context.Context, defaultThreshold int64) error {
+	qs := `
+	UPDATE product_variants pv
+	SET low_stock_notified_at = NULL
+	FROM products p
+	WHERE p.id = pv.product_id
+	AND pv.low_stock_notified_at IS NOT NULL
+	AND pv.stock > COALESCE(p.low_stock_threshold, $1)
+	`
+
+	_, err := r.db.ExecContext(ctx, qs, defaultThreshold)
+	return err
+}
+
+// CreateStockMovement records a change of a variant's stock within tx.
+// Movements that leave the stock unchanged are not recorded.
+func CreateStockMovement(tx *sqlx.Tx, movement model.StockMovement) error {
+	if movement.Quantity == 0 {
+		return nil
+	}
+
+	qs := `
+	INSERT INTO stock_movements (product_variant_id, quantity, stock_after, reason, actor_id, order_id) VALUES
+	($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := tx.Exec(qs,
+		movement.ProductVariantID,
+		movement.Quantity,
+		movement.StockAfter,
+		movement.Reason,
+		movement.ActorID,
+		movement.OrderID,
+	)
+	return err
+}
+
+func NewInventoryRepository(db *sqlx.DB) InventoryRepository {
+	return &inventoryRepository{
+		db: db,
+	}
+}
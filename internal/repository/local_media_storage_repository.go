@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/shared"
+)
+
+type (
+	localMediaStorage struct {
+		cfg dependency.Config
+	}
+)
+
+// Put implements MediaStorage.
+func (s *localMediaStorage) Put(ctx context.Context, key string, contentType string, body io.Reader, size int64) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, body); err != nil {
+		_ = os.Remove(path)
+		return err
+	}
+
+	return nil
+}
+
+// Open implements MediaStorage.
+func (s *localMediaStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, shared.ErrMediaNotFound
+		}
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// Delete implements MediaStorage.
+func (s *localMediaStorage) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// URL implements MediaStorage.
+func (s *localMediaStorage) URL(key string) string {
+	return strings.TrimRight(s.cfg.MediaStorage.PublicBaseURL, "/") + constant.MediaFilesPath + key
+}
+
+// SignedURL implements MediaStorage.
+func (s *localMediaStorage) SignedURL(key string, expiration time.Duration) (string, error) {
+	expires := time.Now().Add(expiration).Unix()
+
+	q := url.Values{}
+	q.Set(constant.MediaSignatureExpires, strconv.FormatInt(expires, 10))
+	q.Set(constant.MediaSignatureQuery, SignMediaKey(s.cfg.MediaStorage.SigningSecret, key, expires))
+
+	return fmt.Sprintf("%s?%s", s.URL(key), q.Encode()), nil
+}
+
+func (s *localMediaStorage) path(key string) (string, error) {
+	root, err := filepath.Abs(s.cfg.MediaStorage.LocalDir)
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(root, filepath.FromSlash(key))
+	if !strings.HasPrefix(path, root+string(filepath.Separator)) {
+		return "", shared.ErrMediaNotFound
+	}
+
+	return path, nil
+}
+
+func NewLocalMediaStorage(cfg dependency.Config) MediaStorage {
+	return &localMediaStorage{
+		cfg: cfg,
+	}
+}
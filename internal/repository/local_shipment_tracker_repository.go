@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/model"
+)
+
+type (
+	localShipmentTracker struct {
+		cfg dependency.Config
+	}
+)
+
+// Track implements ShipmentTracker. It needs no courier and walks every
+// shipment from pickup to delivery in SHIPMENT_LOCAL_DELIVERY_TIME minutes
+// so the flow can be tried out locally.
+func (t *localShipmentTracker) Track(ctx context.Context, shipment *model.OrderShipment) (*dto.ShipmentTrackingResult, error) {
+	shippedAt := shipment.CreatedAt.Time
+	deliveryTime := time.Duration(t.cfg.Shipment.LocalDeliveryTime) * time.Minute
+
+	steps := []dto.ShipmentTrackingEvent{
+		{Description: constant.LocalShipmentPickedUpEvent, OccurredAt: shippedAt},
+		{Description: constant.LocalShipmentInTransitEvent, OccurredAt: shippedAt.Add(deliveryTime / 2)},
+		{Description: constant.LocalShipmentDeliveredEvent, OccurredAt: shippedAt.Add(deliveryTime)},
+	}
+
+	now := time.Now()
+	res := &dto.ShipmentTrackingResult{
+		Events: make([]dto.ShipmentTrackingEvent, 0, len(steps)),
+	}
+	for _, step := range steps {
+		if step.OccurredAt.After(now) {
+			break
+		}
+		res.Events = append(res.Events, step)
+	}
+	res.Delivered = len(res.Events) == len(steps)
+
+	return res, nil
+}
+
+func NewLocalShipmentTracker(cfg dependency.Config) ShipmentTracker {
+	return &localShipmentTracker{
+		cfg: cfg,
+	}
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/shared"
+)
+
+type (
+	// MediaFetcher downloads media hosted elsewhere, such as the images of
+	// products moved from another platform.
+	MediaFetcher interface {
+		Fetch(ctx context.Context, rawURL string, limit int64) ([]byte, error)
+	}
+	httpMediaFetcher struct {
+		client *http.Client
+	}
+)
+
+// Fetch implements MediaFetcher. Media larger than limit is not read.
+func (f *httpMediaFetcher) Fetch(ctx context.Context, rawURL string, limit int64) ([]byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return nil, shared.ErrFetchMedia
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, shared.ErrFetchMedia
+	}
+
+	res, err := f.client.Do(req)
+	if err != nil {
+		return nil, shared.ErrFetchMedia
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, shared.ErrFetchMedia
+	}
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, limit+1))
+	if err != nil {
+		return nil, shared.ErrFetchMedia
+	}
+	if int64(len(body)) > limit {
+		return nil, shared.ErrMediaTooLarge
+	}
+
+	return body, nil
+}
+
+func NewMediaFetcher() MediaFetcher {
+	return &httpMediaFetcher{
+		client: dependency.NewPublicHTTPClient(constant.MediaFetchTimeout),
+	}
+}
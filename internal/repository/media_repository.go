@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/model"
+	"github.com/lil-oren/rest/internal/shared"
+)
+
+type (
+	MediaRepository interface {
+		Create(ctx context.Context, media *model.Media) (*int64, error)
+		FirstByID(ctx context.Context, id int64) (*model.Media, error)
+		FirstByStorageKey(ctx context.Context, key string) (*model.Media, error)
+		FindByAccountIDAndURLs(ctx context.Context, accountID int64, urls []string) ([]model.Media, error)
+		FindByURLs(ctx context.Context, urls []string) ([]model.Media, error)
+		Publish(ctx context.Context, ids []int64) error
+		FindUnprocessedImages(ctx context.Context, limit int) ([]model.Media, error)
+		SaveVariants(ctx context.Context, parentID int64, variants []model.Media) error
+		MarkProcessingFailed(ctx context.Context, id int64) error
+	}
+	mediaRepository struct {
+		db *sqlx.DB
+	}
+)
+
+// Create implements MediaRepository.
+func (r *mediaRepository) Create(ctx context.Context, media *model.Media) (*int64, error) {
+	qs := `
+	INSERT INTO medias (
+		account_id,
+		storage_key,
+		media_url,
+		media_type,
+		content_type,
+		size
+	) VALUES
+	($1, $2, $3, $4, $5, $6)
+	RETURNING (id)
+	`
+
+	id := new(int64)
+	err := r.db.QueryRowxContext(ctx, qs,
+		media.AccountID,
+		media.StorageKey,
+		media.MediaUrl,
+		media.MediaType,
+		media.ContentType,
+		media.Size,
+	).Scan(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return id, nil
+}
+
+// FirstByID implements MediaRepository.
+func (r *mediaRepository) FirstByID(ctx context.Context, id int64) (*model.Media, error) {
+	media := new(model.Media)
+	qs := `SELECT * FROM medias m WHERE m.id = $1 AND m.deleted_at IS NULL`
+	if err := r.db.GetContext(ctx, media, qs, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, shared.ErrMediaNotFound
+		}
+		return nil, err
+	}
+
+	return media, nil
+}
+
+// FirstByStorageKey implements MediaRepository.
+func (r *mediaRepository) FirstByStorageKey(ctx context.Context, key string) (*model.Media, error) {
+	media := new(model.Media)
+	qs := `SELECT * FROM medias m WHERE m.storage_key = $1 AND m.deleted_at IS NULL`
+	if err := r.db.GetContext(ctx, media, qs, key); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, shared.ErrMediaNotFound
+		}
+		return nil, err
+	}
+
+	return media, nil
+}
+
+// FindByAccountIDAndURLs implements MediaRepository.
+func (r *mediaRepository) FindByAccountIDAndURLs(ctx context.Context, accountID int64, urls []string) ([]model.Media, error) {
+	medias := make([]model.Media, 0)
+	qs := `
+	SELECT
+		*
+	FROM
+		medias m
+	WHERE
+		m.account_id = $1 AND
+		m.media_url = ANY($2) AND
+		m.variant_of IS NULL AND
+		m.deleted_at IS NULL
+	`
+
+	if err := r.db.SelectContext(ctx, &medias, qs, accountID, pq.Array(urls)); err != nil {
+		return nil, err
+	}
+
+	return medias, nil
+}
+
+// FindByURLs implements MediaRepository.
+func (r *mediaRepository) FindByURLs(ctx context.Context, urls []string) ([]model.Media, error) {
+	medias := make([]model.Media, 0)
+	qs := `
+	SELECT
+		*
+	FROM
+		medias m
+	WHERE
+		m.media_url = ANY($1) AND
+		m.variant_of IS NULL AND
+		m.deleted_at IS NULL
+	`
+
+	if err := r.db.SelectContext(ctx, &medias, qs, pq.Array(urls)); err != nil {
+		return nil, err
+	}
+
+	return medias, nil
+}
+
+// Publish implements MediaRepository. Published media and their variants
+// are served without a signed url.
+func (r *mediaRepository) Publish(ctx context.Context, ids []int64) error {
+	qs := `
+	UPDATE medias
+	SET
+		published_at = NOW()
+	WHERE
+		id = ANY($1) AND
+		published_at IS NULL
+	`
+
+	_, err := r.db.ExecContext(ctx, qs, pq.Array(ids))
+	return err
+}
+
+// FindUnprocessedImages implements MediaRepository.
+func (r *mediaRepository) FindUnprocessedImages(ctx context.Context, limit int) ([]model.Media, error) {
+	medias := make([]model.Media, 0)
+	qs := `
+	SELECT
+		*
+	FROM
+		medias m
+	WHERE
+		m.media_type = $1 AND
+		m.variant_of IS NULL AND
+		m.processed_at IS NULL AND
+		m.processing_attempts < $3 AND
+		m.deleted_at IS NULL
+	ORDER BY m.id
+	LIMIT $2
+	`
+
+	if err := r.db.SelectContext(ctx, &medias, qs, constant.ImageTypeDefault, limit, constant.MediaProcessingAttempts); err != nil {
+		return nil, err
+	}
+
+	return medias, nil
+}
+
+// SaveVariants implements MediaRepository.
+func (r *mediaRepository) SaveVariants(ctx context.Context, parentID int64, variants []model.Media) error {
+	qs1 := `
+	INSERT INTO medias (
+		account_id,
+		storage_key,
+		media_url,
+		media_type,
+		content_type,
+		size,
+		variant_of,
+		variant_name,
+		processed_at
+	) VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+	`
+
+	qs2 := `
+	UPDATE medias
+	SET
+		thumbnail_url = NULLIF($1, ''),
+		medium_url = NULLIF($2, ''),
+		large_url = NULLIF($3, ''),
+		processed_at = NOW()
+	WHERE id = $4
+	`
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	urls := make(map[string]string)
+	for _, variant := range variants {
+		_, err := tx.ExecContext(ctx, qs1,
+			variant.AccountID,
+			variant.StorageKey,
+			variant.MediaUrl,
+			variant.MediaType,
+			variant.ContentType,
+			variant.Size,
+			parentID,
+			variant.VariantName.String,
+		)
+		if err != nil {
+			return err
+		}
+		urls[variant.VariantName.String] = variant.MediaUrl
+	}
+
+	_, err = tx.ExecContext(ctx, qs2,
+		urls[constant.ThumbnailMediaVariant],
+		urls[constant.MediumMediaVariant],
+		urls[constant.LargeMediaVariant],
+		parentID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// MarkProcessingFailed implements MediaRepository. The image is left out of
+// processing once it has failed constant.MediaProcessingAttempts times.
+func (r *mediaRepository) MarkProcessingFailed(ctx context.Context, id int64) error {
+	qs := `
+	UPDATE medias
+	SET processing_attempts = processing_attempts + 1
+	WHERE id = $1
+	`
+
+	if _, err := r.db.ExecContext(ctx, qs, id); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func NewMediaRepository(db *sqlx.DB) MediaRepository {
+	return &mediaRepository{
+		db: db,
+	}
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dependency"
+)
+
+type (
+	MediaStorage interface {
+		Put(ctx context.Context, key string, contentType string, body io.Reader, size int64) error
+		Open(ctx context.Context, key string) (io.ReadCloser, error)
+		Delete(ctx context.Context, key string) error
+		URL(key string) string
+		SignedURL(key string, expiration time.Duration) (string, error)
+	}
+)
+
+// NewMediaStorage picks the storage backend from MEDIA_STORAGE_DRIVER, any
+// unknown driver falls back to the local filesystem.
+func NewMediaStorage(cfg dependency.Config) MediaStorage {
+	if constant.MediaStorageDriver(cfg.MediaStorage.Driver) == constant.S3MediaStorageDriver {
+		return NewS3MediaStorage(cfg)
+	}
+
+	return NewLocalMediaStorage(cfg)
+}
+
+// SignMediaKey returns the signature used by locally served signed media URLs.
+func SignMediaKey(secret, key string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(fmt.Sprintf("%s:%d", key, expires)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dto"
+)
+
+type (
+	// NotificationBroker pushes notifications to the streams open on every
+	// replica. Each replica listens on a single Redis channel and hands the
+	// notifications it receives to the streams of their account.
+	NotificationBroker interface {
+		Publish(ctx context.Context, event dto.NotificationEvent) error
+		Subscribe(accountID int64) (<-chan dto.NotificationResponse, func())
+		Listen(ctx context.Context) error
+	}
+	redisNotificationBroker struct {
+		rd          *redis.Client
+		mu          sync.RWMutex
+		subscribers map[int64]map[chan dto.NotificationResponse]struct{}
+	}
+)
+
+// Publish implements NotificationBroker.
+func (b *redisNotificationBroker) Publish(ctx context.Context, event dto.NotificationEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return b.rd.Publish(ctx, constant.RedisNotificationChannel, payload).Err()
+}
+
+// Subscribe implements NotificationBroker. The returned func has to be
+// called once the stream is closed.
+func (b *redisNotificationBroker) Subscribe(accountID int64) (<-chan dto.NotificationResponse, func()) {
+	ch := make(chan dto.NotificationResponse, constant.NotificationStreamBuffer)
+
+	b.mu.Lock()
+	if b.subscribers[accountID] == nil {
+		b.subscribers[accountID] = make(map[chan dto.NotificationResponse]struct{})
+	}
+	b.subscribers[accountID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	unsubscribe := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		delete(b.subscribers[accountID], ch)
+		if len(b.subscribers[accountID]) == 0 {
+			delete(b.subscribers, accountID)
+		}
+	}
+
+	return ch, unsubscribe
+}
+
+// Listen implements NotificationBroker. It blocks until ctx is cancelled.
+func (b *redisNotificationBroker) Listen(ctx context.Context) error {
+	pubsub := b.rd.Subscribe(ctx, constant.RedisNotificationChannel)
+	defer pubsub.Close()
+
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return err
+	}
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-messages:
+			if !ok {
+				return nil
+			}
+
+			event := dto.NotificationEvent{}
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				continue
+			}
+			b.dispatch(event)
+		}
+	}
+}
+
+// dispatch hands a notification to the local streams of its account. A
+// stream that is not keeping up misses it rather than holding the others.
+func (b *redisNotificationBroker) dispatch(event dto.NotificationEvent) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subscribers[event.AccountID] {
+		select {
+		case ch <- event.Notification:
+		default:
+		}
+	}
+}
+
+func NewNotificationBroker(rd *redis.Client) NotificationBroker {
+	return &redisNotificationBroker{
+		rd:          rd,
+		subscribers: make(map[int64]map[chan dto.NotificationResponse]struct{}),
+	}
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/model"
+	"github.com/lil-oren/rest/internal/shared"
+)
+
+type (
+	NotificationRepository interface {
+		Create(ctx context.Context, notification *model.Notification) error
+		Find(ctx context.Context, accountID int64, params dto.NotificationParams) ([]model.Notification, error)
+		Count(ctx context.Context, accountID int64, params dto.NotificationParams) (int, error)
+		CountUnread(ctx context.Context, accountID int64) (int, error)
+		MarkRead(ctx context.Context, accountID, id int64) error
+		MarkAllRead(ctx context.Context, accountID int64) error
+	}
+	notificationRepository struct {
+		db *sqlx.DB
+	}
+)
+
+// Create implements NotificationRepository. The id and creation time of the
+// stored notification are set on notification. An account is notified of
+// an event only once, so when it already was the id is left at zero.
+func (r *notificationRepository) Create(ctx context.Context, notification *model.Notification) error {
+	qs := `
+	INSERT INTO notifications (
+		account_id,
+		type,
+		title,
+		message,
+		reference_id,
+		event_id
+	) VALUES
+	($1, $2, $3, $4, $5, $6)
+	ON CONFLICT (event_id, account_id) DO NOTHING
+	RETURNING id, created_at
+	`
+
+	err := r.db.QueryRowxContext(ctx, qs,
+		notification.AccountID,
+		notification.Type,
+		notification.Title,
+		notification.Message,
+		notification.ReferenceID,
+		notification.EventID,
+	).Scan(&notification.ID, &notification.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+
+	return err
+}
+
+// Find implements NotificationRepository.
+func (r *notificationRepository) Find(ctx context.Context, accountID int64, params dto.NotificationParams) ([]model.Notification, error) {
+	notifications := make([]model.Notification, 0)
+	qs := `
+	SELECT
+		*
+	FROM
+		notifications n
+	WHERE
+		n.account_id = $1 AND
+		(NOT $2 OR n.read_at IS NULL) AND
+		n.deleted_at IS NULL
+	ORDER BY
+		n.created_at DESC,
+		n.id DESC
+	LIMIT $3
+	OFFSET $4
+	`
+
+	offset := (params.Page - 1) * constant.NotificationDefaultItems
+	err := r.db.SelectContext(ctx, &notifications, qs, accountID, params.Unread, constant.NotificationDefaultItems, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+// Count implements NotificationRepository.
+func (r *notificationRepository) Count(ctx context.Context, accountID int64, params dto.NotificationParams) (int, error) {
+	qs := `
+	SELECT
+		COUNT(1)
+	FROM
+		notifications n
+	WHERE
+		n.account_id = $1 AND
+		(NOT $2 OR n.read_at IS NULL) AND
+		n.deleted_at IS NULL
+	`
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, qs, accountID, params.Unread); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// CountUnread implements NotificationRepository.
+func (r *notificationRepository) CountUnread(ctx context.Context, accountID int64) (int, error) {
+	return r.Count(ctx, accountID, dto.NotificationParams{Unread: true})
+}
+
+// MarkRead implements NotificationRepository. Marking a notification that
+// was already read again is not an error.
+func (r *notificationRepository) MarkRead(ctx context.Context, accountID, id int64) error {
+	qs := `
+	UPDATE notifications
+	SET
+		read_at = COALESCE(read_at, NOW()),
+		updated_at = NOW()
+	WHERE
+		id = $1 AND
+		account_id = $2 AND
+		deleted_at IS NULL
+	`
+
+	res, err := r.db.ExecContext(ctx, qs, id, accountID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return shared.ErrNotificationNotFound
+	}
+
+	return nil
+}
+
+// MarkAllRead implements NotificationRepository.
+func (r *notificationRepository) MarkAllRead(ctx context.Context, accountID int64) error {
+	qs := `
+	UPDATE notifications
+	SET
+		read_at = NOW(),
+		updated_at = NOW()
+	WHERE
+		account_id = $1 AND
+		read_at IS NULL AND
+		deleted_at IS NULL
+	`
+
+	_, err := r.db.ExecContext(ctx, qs, accountID)
+	return err
+}
+
+func NewNotificationRepository(db *sqlx.DB) NotificationRepository {
+	return &notificationRepository{
+		db: db,
+	}
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lil-oren/rest/internal/model"
 )
 
 type (
 	OrderDetailRepository interface {
 		CountOrderByProductCode(ctx context.Context, productCode string) (*int, error)
+		FindByOrderID(ctx context.Context, orderID int64) ([]model.OrderDetail, error)
 	}
 	orderDetailRepository struct {
 		db *sqlx.DB
@@ -40,6 +42,27 @@ func (r *orderDetailRepository) CountOrderByProductCode(ctx context.Context, pro
 	return count, nil
 }
 
+// FindByOrderID implements OrderDetailRepository.
+func (r *orderDetailRepository) FindByOrderID(ctx context.Context, orderID int64) ([]model.OrderDetail, error) {
+	details := make([]model.OrderDetail, 0)
+	qs := `
+	SELECT
+		*
+	FROM
+		order_details od
+	WHERE
+		od.order_id = $1 AND
+		od.deleted_at IS NULL
+	ORDER BY od.id
+	`
+
+	if err := r.db.SelectContext(ctx, &details, qs, orderID); err != nil {
+		return nil, err
+	}
+
+	return details, nil
+}
+
 func NewOrderDetailRepository(db *sqlx.DB) OrderDetailRepository {
 	return &orderDetailRepository{
 		db: db,
@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/shared"
+)
+
+type (
+	OrderDocumentRepository interface {
+		FindByOrderIDs(ctx context.Context, orderIDs []int64) ([]dto.OrderDocumentModel, error)
+		FindItemsByOrderIDs(ctx context.Context, orderIDs []int64) ([]dto.OrderDocumentItemModel, error)
+		AssignInvoiceNumber(ctx context.Context, orderID int64) (string, error)
+	}
+	orderDocumentRepository struct {
+		db *sqlx.DB
+	}
+)
+
+// FindByOrderIDs implements OrderDocumentRepository. Orders are returned in
+// the order of orderIDs. Orders placed before the delivery address was kept
+// with the order are shipped to the default address of the buyer.
+func (r *orderDocumentRepository) FindByOrderIDs(ctx context.Context, orderIDs []int64) ([]dto.OrderDocumentModel, error) {
+	documents := make([]dto.OrderDocumentModel, 0, len(orderIDs))
+	qs := `
+	SELECT
+		o.id,
+		o.status,
+		o.invoice_number,
+		o.created_at,
+		o.seller_id,
+		o.buyer_id,
+		o.delivery_cost,
+		o.promotion_name,
+		o.promotion_amount,
+		o.voucher_amount,
+		o.service_fee,
+		t.amount AS payment_amount,
+		COALESCE(s.name, '') AS shop_name,
+		a.username AS buyer_username,
+		a.email AS buyer_email,
+		COALESCE(c.name, '') AS courier_name,
+		COALESCE(o.courier_service, c.service_name) AS courier_service,
+		os.waybill_number,
+		ba.receiver_name,
+		ba.receiver_phone_number,
+		ba.detail AS receiver_address,
+		bd.name AS receiver_district,
+		bp.name AS receiver_province,
+		ba.postal_code AS receiver_postal_code,
+		sa.receiver_name AS sender_name,
+		sa.receiver_phone_number AS sender_phone_number,
+		sa.detail AS sender_address,
+		sd.name AS sender_district,
+		sp.name AS sender_province,
+		sa.postal_code AS sender_postal_code
+	FROM
+		orders o
+	JOIN transactions t ON
+		t.id = o.transaction_id
+	JOIN accounts a ON
+		a.id = o.buyer_id
+	LEFT JOIN shops s ON
+		s.account_id = o.seller_id
+	LEFT JOIN couriers c ON
+		c.id = o.courier_id
+	LEFT JOIN order_shipments os ON
+		os.order_id = o.id AND
+		os.deleted_at IS NULL
+	LEFT JOIN account_addresses ba ON
+		ba.id = COALESCE(o.buyer_address_id, (
+			SELECT
+				aa.id
+			FROM
+				account_addresses aa
+			WHERE
+				aa.account_id = o.buyer_id AND
+				aa.is_default AND
+				aa.deleted_at IS NULL
+			LIMIT 1
+		))
+	LEFT JOIN districts bd ON
+		bd.id = ba.district_id
+	LEFT JOIN provinces bp ON
+		bp.id = ba.province_id
+	LEFT JOIN account_addresses sa ON
+		sa.account_id = o.seller_id AND
+		sa.is_shop AND
+		sa.deleted_at IS NULL
+	LEFT JOIN districts sd ON
+		sd.id = sa.district_id
+	LEFT JOIN provinces sp ON
+		sp.id = sa.province_id
+	WHERE
+		o.id = ANY($1) AND
+		o.deleted_at IS NULL
+	ORDER BY
+		array_position($1, o.id)
+	`
+
+	if err := r.db.SelectContext(ctx, &documents, qs, pq.Array(orderIDs)); err != nil {
+		return nil, err
+	}
+
+	return documents, nil
+}
+
+// FindItemsByOrderIDs implements OrderDocumentRepository.
+func (r *orderDocumentRepository) FindItemsByOrderIDs(ctx context.Context, orderIDs []int64) ([]dto.OrderDocumentItemModel, error) {
+	items := make([]dto.OrderDocumentItemModel, 0)
+	qs := `
+	SELECT
+		od.order_id,
+		od.product_code,
+		od.product_name,
+		od.variant_name,
+		od.sub_total_price,
+		od.quantity,
+		p.weight
+	FROM
+		order_details od
+	LEFT JOIN products p ON
+		p.product_code = od.product_code
+	WHERE
+		od.order_id = ANY($1) AND
+		od.deleted_at IS NULL
+	ORDER BY
+		od.order_id,
+		od.id
+	`
+
+	if err := r.db.SelectContext(ctx, &items, qs, pq.Array(orderIDs)); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// AssignInvoiceNumber implements OrderDocumentRepository. It numbers orders
+// placed before invoices were numbered on checkout.
+func (r *orderDocumentRepository) AssignInvoiceNumber(ctx context.Context, orderID int64) (string, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	number, err := AssignInvoiceNumber(tx, orderID)
+	if err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+
+	return number, nil
+}
+
+// AssignInvoiceNumber gives an order the next invoice number of the shop of
+// its seller, unless it already has one, and returns the number.
+func AssignInvoiceNumber(tx *sqlx.Tx, orderID int64) (string, error) {
+	qs1 := `
+	SELECT
+		o.invoice_number,
+		o.seller_id,
+		COALESCE(o.created_at, NOW())
+	FROM
+		orders o
+	WHERE
+		o.id = $1
+	FOR UPDATE
+	`
+
+	qs2 := `
+	UPDATE shops
+	SET
+		invoice_sequence = invoice_sequence + 1,
+		updated_at = NOW()
+	WHERE account_id = $1
+	RETURNING id, invoice_sequence
+	`
+
+	qs3 := `
+	UPDATE orders
+	SET invoice_number = $1
+	WHERE id = $2
+	`
+
+	var (
+		number    sql.NullString
+		sellerID  int64
+		createdAt time.Time
+	)
+	if err := tx.QueryRowx(qs1, orderID).Scan(&number, &sellerID, &createdAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", shared.ErrOrderIDNotFount
+		}
+		return "", err
+	}
+	if number.Valid {
+		return number.String, nil
+	}
+
+	var shopID, sequence int64
+	if err := tx.QueryRowx(qs2, sellerID).Scan(&shopID, &sequence); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", shared.ErrShopNotFound
+		}
+		return "", err
+	}
+
+	invoiceNumber := fmt.Sprintf(constant.InvoiceNumberTemplate, createdAt.Format(constant.InvoiceNumberDateLayout), shopID, sequence)
+	if _, err := tx.Exec(qs3, invoiceNumber, orderID); err != nil {
+		return "", err
+	}
+
+	return invoiceNumber, nil
+}
+
+func NewOrderDocumentRepository(db *sqlx.DB) OrderDocumentRepository {
+	return &orderDocumentRepository{
+		db: db,
+	}
+}
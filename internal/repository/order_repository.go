@@ -22,11 +22,14 @@ type (
 		FirstOrderByOrderID(ctx context.Context, orderId int64) (*model.Order, error)
 		FindOrderBySellerIDMetadata(ctx context.Context, sellerId int64, params *dto.OrderSellerParams) ([]model.Order, error)
 		FindOrderByBuyerIDMetadata(ctx context.Context, buyerId int64, params *dto.OrderParams) ([]model.Order, error)
-		CreateOrder(ctx context.Context, accountId int64, priceList, delivery []decimal.Decimal, promotionAmount []float64, promotionName []string, payload dto.CreateOrderRequestPayload, transaction []*model.Transaction) error
+		CreateOrder(ctx context.Context, accountId int64, priceList, delivery []decimal.Decimal, etaList []sql.NullTime, promotionAmount []float64, promotionName []string, vouchers [][]dto.OrderVoucher, fees []dto.OrderFee, payload dto.CreateOrderRequestPayload, transaction []*model.Transaction) error
 		CreateOrderProductVariant(ctx context.Context, orderId int64, payload dto.CartOrderModel, totalPrice decimal.Decimal) error
 		UpdateOrderStatus(ctx context.Context, orderId int64, status constant.OrderStatusType, eat *time.Time) error
 		UpdateCancelOrder(ctx context.Context, orderId, accountId int64, transaction *model.Transaction) error
 		UpdateReceiveOrder(ctx context.Context, orderId, buyerId, sellerId int64, transaction *model.Transaction) error
+		UpdateDeliverOrder(ctx context.Context, orderId int64, eat time.Time, waybillNumber string) error
+		CountSalesReportOrders(ctx context.Context, payload dto.SalesReportPayload) (int, error)
+		FindSalesReportRows(ctx context.Context, payload dto.SalesReportPayload) ([]dto.SalesReportRowModel, error)
 	}
 	orderRepository struct {
 		db *sqlx.DB
@@ -43,22 +46,12 @@ func (r *orderRepository) UpdateOrderStatus(ctx context.Context, orderId int64,
 	}
 	query += "\nWHERE id = $3"
 
-	qs := `
-	UPDATE promotions
-	SET quota = quota-1, updated_at = $1
-	WHERE id = (
-		SELECT
-			p.id
-		FROM
-			orders o
-			LEFT JOIN promotions p ON o.promotion_name = p."name"
-		WHERE o.id = $2
-
-	)
-	`
 	qDetail := `
 		SELECT * FROM order_details od WHERE od.order_id = $1;
 	`
+	qSeller := `
+		SELECT o.seller_id FROM orders o WHERE o.id = $1;
+	`
 
 	orderDetails := make([]model.OrderDetail, 0)
 
@@ -78,30 +71,42 @@ func (r *orderRepository) UpdateOrderStatus(ctx context.Context, orderId int64,
 		if err != nil {
 			return err
 		}
+
+		var sellerId int64
+		err = tx.Get(&sellerId, qSeller, orderId)
+		if err != nil {
+			return err
+		}
+
+		movement := model.StockMovement{
+			Reason:  constant.StockReasonOrderReserve,
+			ActorID: sql.NullInt64{Int64: sellerId, Valid: true},
+			OrderID: sql.NullInt64{Int64: orderId, Valid: true},
+		}
 		for _, orderDetail := range orderDetails {
 			variants := strings.Split(orderDetail.VariantName, "-")
 			variantLength := len(variants)
 			if variantLength == 1 {
 				if variants[0] == "" {
-					if err := DecreaseStock(tx, orderDetail.ProductCode, orderDetail.Quantity, constant.ProductVariantDefault, constant.ProductVariantDefault); err != nil {
+					if err := DecreaseStock(tx, orderDetail.ProductCode, orderDetail.Quantity, constant.ProductVariantDefault, constant.ProductVariantDefault, movement); err != nil {
 						return err
 					}
 					continue
 				}
-				if err := DecreaseStock(tx, orderDetail.ProductCode, orderDetail.Quantity, variants[0], constant.ProductVariantDefault); err != nil {
+				if err := DecreaseStock(tx, orderDetail.ProductCode, orderDetail.Quantity, variants[0], constant.ProductVariantDefault, movement); err != nil {
 					return err
 				}
 				continue
 			}
 
-			if err := DecreaseStock(tx, orderDetail.ProductCode, orderDetail.Quantity, variants[0], variants[1]); err != nil {
+			if err := DecreaseStock(tx, orderDetail.ProductCode, orderDetail.Quantity, variants[0], variants[1], movement); err != nil {
 				return err
 			}
 		}
-		_, err = tx.Exec(qs, time.Now(), orderId)
-		if err != nil {
-			return err
-		}
+	}
+
+	if err := CreateOrderEvent(tx, constant.OrderStatusChangedEvent, orderId); err != nil {
+		return err
 	}
 
 	if err = tx.Commit(); err != nil {
@@ -128,7 +133,9 @@ func (r *orderRepository) FindOrderBySellerID(ctx context.Context, sellerId int6
 			aa.detail AS address_detail,
 			c."name" AS courier_name,
 			o.estimated_time_arrival,
-			o.promotion_amount
+			o.quoted_time_arrival,
+			o.promotion_amount,
+			o.commission_amount
 		FROM (
 			SELECT * FROM orders o ORDER BY o.updated_at DESC LIMIT $1 OFFSET $2 
 		) AS o
@@ -202,7 +209,8 @@ func (r *orderRepository) FindOrderByBuyerID(ctx context.Context, buyerId int64,
 		c."name" AS courier_name,
 		o.delivery_cost,
 		o.estimated_time_arrival,
-		o.promotion_amount
+		o.promotion_amount,
+		o.voucher_amount
 	FROM (
 		SELECT * FROM orders o ORDER BY o.updated_at DESC LIMIT $1 OFFSET $2 
 	) AS o
@@ -267,7 +275,7 @@ func (r *orderRepository) CreateOrderProductVariant(ctx context.Context, orderId
 	return nil
 }
 
-func (r *orderRepository) CreateOrder(ctx context.Context, accountId int64, priceList, delivery []decimal.Decimal, promotionAmount []float64, promotionName []string, payload dto.CreateOrderRequestPayload, transaction []*model.Transaction) error {
+func (r *orderRepository) CreateOrder(ctx context.Context, accountId int64, priceList, delivery []decimal.Decimal, etaList []sql.NullTime, promotionAmount []float64, promotionName []string, vouchers [][]dto.OrderVoucher, fees []dto.OrderFee, payload dto.CreateOrderRequestPayload, transaction []*model.Transaction) error {
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return err
@@ -283,6 +291,7 @@ func (r *orderRepository) CreateOrder(ctx context.Context, accountId int64, pric
 		p.product_code AS product_code,
 		p."name" AS product_name,
 		p.id AS product_id,
+		p.status AS product_status,
 		p.thumbnail_url AS image_url,
 		c.product_variant_id,
 		pv.price AS base_price,
@@ -322,7 +331,15 @@ func (r *orderRepository) CreateOrder(ctx context.Context, accountId int64, pric
 		delivery_cost,
 		transaction_id,
 		promotion_name,
-		promotion_amount
+		promotion_amount,
+		voucher_amount,
+		promotion_id,
+		estimated_time_arrival,
+		quoted_time_arrival,
+		service_fee,
+		commission_amount,
+		buyer_address_id,
+		courier_service
 		) VALUES (
 			$1,
 			$2,
@@ -331,7 +348,15 @@ func (r *orderRepository) CreateOrder(ctx context.Context, accountId int64, pric
 			$5,
 			$6,
 			$7,
-			$8
+			$8,
+			$9,
+			$10,
+			$11,
+			$11,
+			$12,
+			$13,
+			$14,
+			$15
 		) RETURNING (id)
 	`
 
@@ -360,6 +385,7 @@ func (r *orderRepository) CreateOrder(ctx context.Context, accountId int64, pric
 		WHERE id = $1
 	`
 
+	usageIDs := make(map[int64]int64)
 	for idx, order := range payload.Orders {
 		transactionID, err := r.tr.CreateTransaction(tx, transaction[idx])
 		if err != nil {
@@ -374,18 +400,59 @@ func (r *orderRepository) CreateOrder(ctx context.Context, accountId int64, pric
 		if err != nil {
 			return err
 		}
+		for _, cartOrder := range cartOrders {
+			if cartOrder.ProductStatus != constant.ProductStatusActive {
+				return shared.ErrProductNotActive
+			}
+		}
 
 		promoDec := decimal.NewFromFloat(promotionAmount[idx])
+		voucherDec := decimal.Zero
+		for _, v := range vouchers[idx] {
+			voucherDec = voucherDec.Add(v.Amount)
+		}
+		promotionID := sql.NullInt64{
+			Int64: int64(order.PromotionId),
+			Valid: order.PromotionId != 0 && promotionAmount[idx] > 0,
+		}
 
 		err = tx.QueryRowx(queryCourier, order.CourierId).Scan(&courierId)
 		if err != nil {
 			return err
 		}
 
-		err = tx.QueryRowx(qs2, constant.NewOrderStatus, courierId, cartOrders[0].SellerID, accountId, delivery[idx], transactionID, promotionName[idx], promoDec).Scan(&orderId)
+		err = tx.QueryRowx(qs2, constant.NewOrderStatus, courierId, cartOrders[0].SellerID, accountId, delivery[idx], transactionID, promotionName[idx], promoDec, voucherDec, promotionID, etaList[idx], fees[idx].ServiceFee, fees[idx].Commission, payload.BuyerAddressId, order.CourierService).Scan(&orderId)
 		if err != nil {
 			return err
 		}
+
+		if _, err := AssignInvoiceNumber(tx, orderId); err != nil {
+			return err
+		}
+
+		if err := CreateOrderEvent(tx, constant.OrderCreatedEvent, orderId); err != nil {
+			return err
+		}
+
+		if promotionID.Valid {
+			if err := ReservePromotion(tx, promotionID.Int64, accountId, orderId); err != nil {
+				return err
+			}
+		}
+
+		for _, v := range vouchers[idx] {
+			usageID, ok := usageIDs[v.VoucherID]
+			if !ok {
+				usageID, err = RedeemVoucher(tx, v.VoucherID, accountId)
+				if err != nil {
+					return err
+				}
+				usageIDs[v.VoucherID] = usageID
+			}
+			if err := CreateOrderVoucher(tx, orderId, usageID, v); err != nil {
+				return err
+			}
+		}
 		for _, cart := range cartOrders {
 			if cart.Variant1Name == constant.ProductVariantDefault {
 				cart.Variant1Name = ""
@@ -402,6 +469,9 @@ func (r *orderRepository) CreateOrder(ctx context.Context, accountId int64, pric
 				return err
 			}
 			priceList = priceList[1:]
+			if err := ReserveFlashSale(tx, cart.ProductVariantID, accountId, orderId, cart.Qty); err != nil {
+				return err
+			}
 			_, err = tx.Exec(qs4, cart.CartID)
 			if err != nil {
 				return err
@@ -432,6 +502,18 @@ func (r *orderRepository) UpdateCancelOrder(ctx context.Context, orderId, accoun
 		return err
 	}
 
+	if err := ReleaseFlashSale(tx, orderId); err != nil {
+		return err
+	}
+
+	if err := ReleaseVoucher(tx, orderId); err != nil {
+		return err
+	}
+
+	if err := ReleasePromotion(tx, orderId); err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE orders 
 		SET status = $1, updated_at = $2
@@ -442,6 +524,70 @@ func (r *orderRepository) UpdateCancelOrder(ctx context.Context, orderId, accoun
 		return err
 	}
 
+	if err := CreateOrderEvent(tx, constant.OrderStatusChangedEvent, orderId); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateDeliverOrder marks a processed order as delivered and starts
+// tracking its shipment with the courier of the order.
+func (r *orderRepository) UpdateDeliverOrder(ctx context.Context, orderId int64, eat time.Time, waybillNumber string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	qs1 := `
+	UPDATE orders
+	SET status = $1, estimated_time_arrival = $2, updated_at = NOW()
+	WHERE id = $3 AND status = $4
+	`
+
+	qs2 := `
+	INSERT INTO order_shipments (
+		order_id,
+		courier_code,
+		waybill_number,
+		status
+	)
+	SELECT
+		o.id,
+		c.code,
+		$2,
+		$3
+	FROM
+		orders o
+		JOIN couriers c ON c.id = o.courier_id
+	WHERE o.id = $1
+	`
+
+	res, err := tx.Exec(qs1, constant.DeliverOrderStatus, eat, orderId, constant.ProcessOrderStatus)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return shared.ErrWrongInitialStatus
+	}
+
+	if _, err := tx.Exec(qs2, orderId, waybillNumber, constant.InTransitShipmentStatus); err != nil {
+		return err
+	}
+
+	if err := CreateOrderEvent(tx, constant.OrderStatusChangedEvent, orderId); err != nil {
+		return err
+	}
+
 	if err = tx.Commit(); err != nil {
 		return err
 	}
@@ -456,18 +602,58 @@ func (r *orderRepository) UpdateReceiveOrder(ctx context.Context, orderId, buyer
 	}
 	defer tx.Rollback()
 
-	_, err = r.tr.CreateTransaction(tx, transaction)
+	if err := CompleteOrder(tx, r.tr, orderId, buyerId, sellerId, transaction); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CompleteOrder pays the seller for an order within tx and marks it as
+// received. The commission on the amount of transaction goes to the
+// platform wallet instead of the seller, as does the service fee the buyer
+// paid. Nothing is paid to the seller when the whole order has been
+// refunded.
+func CompleteOrder(tx *sqlx.Tx, tr TransactionRepository, orderId, buyerId, sellerId int64, transaction *model.Transaction) error {
+	fee, err := OrderPlatformFee(tx, orderId, transaction.Amount)
 	if err != nil {
 		return err
 	}
 
-	if err := TransferTempSeller(tx, buyerId, sellerId, transaction.Amount); err != nil {
+	if err := payPlatform(tx, tr, transaction.FromWalletID.Int64, fee.ServiceFee, constant.ServiceFeeTitle); err != nil {
 		return err
 	}
 
+	if transaction.Amount.IsPositive() {
+		if err := payPlatform(tx, tr, transaction.FromWalletID.Int64, fee.Commission, constant.CommissionTitle); err != nil {
+			return err
+		}
+
+		transaction.Amount = transaction.Amount.Sub(fee.Commission)
+		if _, err := tr.CreateTransaction(tx, transaction); err != nil {
+			return err
+		}
+
+		if err := TransferTempSeller(tx, buyerId, sellerId, transaction.Amount); err != nil {
+			return err
+		}
+
+		subsidy, err := PlatformVoucherSubsidy(tx, orderId)
+		if err != nil {
+			return err
+		}
+		if err := payFromPlatform(tx, tr, transaction.ToWalletID, subsidy, constant.VoucherSubsidyTitle); err != nil {
+			return err
+		}
+	}
+
 	query := `
 		UPDATE orders 
-		SET status = $1, updated_at = $2
+		SET status = $1, received_at = $2, updated_at = $2
 		WHERE id = $3
 	`
 	_, err = tx.Exec(query, constant.ReceiveOrderStatus, time.Now(), orderId)
@@ -475,11 +661,153 @@ func (r *orderRepository) UpdateReceiveOrder(ctx context.Context, orderId, buyer
 		return err
 	}
 
-	if err = tx.Commit(); err != nil {
+	return CreateOrderEvent(tx, constant.OrderStatusChangedEvent, orderId)
+}
+
+// OrderPlatformFee is what the platform keeps of an order when amount of it
+// is paid to the seller. The commission shrinks with the part of the order
+// that has been refunded to the buyer.
+func OrderPlatformFee(tx *sqlx.Tx, orderId int64, amount decimal.Decimal) (*dto.OrderFee, error) {
+	qs := `
+	SELECT
+		o.service_fee,
+		o.commission_amount,
+		t.amount
+	FROM
+		orders o
+	JOIN transactions t ON
+		t.id = o.transaction_id
+	WHERE
+		o.id = $1
+	`
+
+	fee := new(dto.OrderFee)
+	var payment decimal.Decimal
+	if err := tx.QueryRowx(qs, orderId).Scan(&fee.ServiceFee, &fee.Commission, &payment); err != nil {
+		return nil, err
+	}
+
+	orderAmount := payment.Sub(fee.ServiceFee)
+	if amount.LessThan(orderAmount) && orderAmount.IsPositive() {
+		fee.Commission = fee.Commission.Mul(amount).Div(orderAmount).Round(2)
+	}
+	if fee.Commission.GreaterThan(amount) {
+		fee.Commission = decimal.Max(amount, decimal.Zero)
+	}
+
+	return fee, nil
+}
+
+// payPlatform moves amount from a wallet to the platform wallet within tx.
+func payPlatform(tx *sqlx.Tx, tr TransactionRepository, fromWalletID int64, amount decimal.Decimal, title constant.TransactionTitle) error {
+	if !amount.IsPositive() {
+		return nil
+	}
+
+	platformWalletID, err := PlatformWalletID(tx)
+	if err != nil {
 		return err
 	}
 
-	return nil
+	feeTx := &model.Transaction{
+		Amount:       amount,
+		Title:        title,
+		FromWalletID: sql.NullInt64{Int64: fromWalletID, Valid: true},
+		ToWalletID:   platformWalletID,
+	}
+	if _, err := tr.CreateTransaction(tx, feeTx); err != nil {
+		return err
+	}
+
+	return TransferWallets(tx, fromWalletID, platformWalletID, amount)
+}
+
+// payFromPlatform moves amount from the platform wallet to a wallet within
+// tx. The platform wallet may go below zero: what it pays out, like voucher
+// subsidies, is owed whether or not fees have covered it yet.
+func payFromPlatform(tx *sqlx.Tx, tr TransactionRepository, toWalletID int64, amount decimal.Decimal, title constant.TransactionTitle) error {
+	if !amount.IsPositive() {
+		return nil
+	}
+
+	platformWalletID, err := PlatformWalletID(tx)
+	if err != nil {
+		return err
+	}
+
+	payTx := &model.Transaction{
+		Amount:       amount,
+		Title:        title,
+		FromWalletID: sql.NullInt64{Int64: platformWalletID, Valid: true},
+		ToWalletID:   toWalletID,
+	}
+	if _, err := tr.CreateTransaction(tx, payTx); err != nil {
+		return err
+	}
+
+	return TransferWallets(tx, platformWalletID, toWalletID, amount)
+}
+
+// CountSalesReportOrders implements OrderRepository.
+func (r *orderRepository) CountSalesReportOrders(ctx context.Context, payload dto.SalesReportPayload) (int, error) {
+	qs := `
+	SELECT
+		COUNT(o.id)
+	FROM orders o
+	WHERE o.seller_id = $1
+	AND o.deleted_at IS NULL
+	AND o.created_at >= $2::date
+	AND o.created_at < $3::date + 1
+	AND ($4 = '' OR o.status = $4)
+	`
+
+	var total int
+	err := r.db.GetContext(ctx, &total, qs, payload.SellerID, payload.StartDate, payload.EndDate, payload.Status)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// FindSalesReportRows implements OrderRepository. Every order line is
+// returned with the amounts of its order, ordered by order date. Only shop
+// vouchers are counted since platform vouchers are paid back to the seller.
+func (r *orderRepository) FindSalesReportRows(ctx context.Context, payload dto.SalesReportPayload) ([]dto.SalesReportRowModel, error) {
+	rows := make([]dto.SalesReportRowModel, 0)
+	qs := `
+	SELECT
+		o.id AS order_id,
+		o.created_at AS order_date,
+		o.status,
+		COALESCE(a.full_name, a.username) AS buyer_name,
+		od.product_name,
+		od.variant_name,
+		od.quantity,
+		od.sub_total_price,
+		o.promotion_amount,
+		COALESCE((
+			SELECT SUM(ov.amount) FROM order_vouchers ov
+			WHERE ov.order_id = o.id AND ov.issuer = $5 AND ov.released_at IS NULL
+		), 0) AS voucher_amount,
+		o.delivery_cost
+	FROM orders o
+	JOIN order_details od ON od.order_id = o.id
+	JOIN accounts a ON a.id = o.buyer_id
+	WHERE o.seller_id = $1
+	AND o.deleted_at IS NULL
+	AND o.created_at >= $2::date
+	AND o.created_at < $3::date + 1
+	AND ($4 = '' OR o.status = $4)
+	ORDER BY o.created_at, o.id, od.id
+	`
+
+	err := r.db.SelectContext(ctx, &rows, qs, payload.SellerID, payload.StartDate, payload.EndDate, payload.Status, constant.VoucherIssuerShop)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
 }
 
 func NewOrderRepository(db *sqlx.DB, tr TransactionRepository) OrderRepository {
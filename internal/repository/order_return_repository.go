@@ -0,0 +1,558 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/model"
+	"github.com/lil-oren/rest/internal/shared"
+	"github.com/shopspring/decimal"
+)
+
+type (
+	OrderReturnRepository interface {
+		Create(ctx context.Context, ret *model.OrderReturn, items []model.OrderReturnItem, imageUrls []string) (*int64, error)
+		FirstByID(ctx context.Context, id int64) (*model.OrderReturn, error)
+		HasOpenReturn(ctx context.Context, orderID int64) (bool, error)
+		Find(ctx context.Context, filter dto.OrderReturnFilter) ([]model.OrderReturn, error)
+		Count(ctx context.Context, filter dto.OrderReturnFilter) (int, error)
+		FindItemsByReturnIDs(ctx context.Context, ids []int64) ([]dto.OrderReturnItemModel, error)
+		FindImagesByReturnIDs(ctx context.Context, ids []int64) ([]dto.OrderReturnImageModel, error)
+		Reject(ctx context.Context, ret *model.OrderReturn) error
+		Escalate(ctx context.Context, ret *model.OrderReturn) error
+		Close(ctx context.Context, ret *model.OrderReturn) error
+		Refund(ctx context.Context, ret *model.OrderReturn, orderAmount decimal.Decimal) error
+	}
+	orderReturnRepository struct {
+		db *sqlx.DB
+		tr TransactionRepository
+	}
+)
+
+// Create implements OrderReturnRepository. An order can only be returned
+// once. When the seller has already been paid, the refund amount is held
+// back from the SHOP wallet right away.
+func (r *orderReturnRepository) Create(ctx context.Context, ret *model.OrderReturn, items []model.OrderReturnItem, imageUrls []string) (*int64, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	qs1 := `SELECT EXISTS (SELECT 1 FROM order_returns WHERE order_id = $1 AND deleted_at IS NULL)`
+
+	qs2 := `
+	INSERT INTO order_returns (
+		order_id,
+		buyer_id,
+		seller_id,
+		reason,
+		status,
+		amount
+	) VALUES
+	($1, $2, $3, $4, $5, $6)
+	RETURNING (id)
+	`
+
+	qs3 := `
+	INSERT INTO order_return_items (order_return_id, order_detail_id, quantity, amount)
+	VALUES
+	(:order_return_id, :order_detail_id, :quantity, :amount)
+	`
+
+	qs4 := `INSERT INTO order_return_medias (order_return_id, image_url) VALUES ($1, $2)`
+
+	if _, err := lockOrderStatus(tx, ret.OrderID); err != nil {
+		return nil, err
+	}
+
+	var exists bool
+	if err := tx.Get(&exists, qs1, ret.OrderID); err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, shared.ErrOrderReturnExists
+	}
+
+	id := new(int64)
+	err = tx.QueryRowx(qs2, ret.OrderID, ret.BuyerID, ret.SellerID, ret.Reason, constant.RequestedReturnStatus, ret.Amount).Scan(id)
+	if err != nil {
+		return nil, err
+	}
+	ret.ID = *id
+
+	if len(items) != 0 {
+		for idx := range items {
+			items[idx].OrderReturnID = *id
+		}
+		if _, err := tx.NamedExec(qs3, items); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, url := range imageUrls {
+		if _, err := tx.Exec(qs4, *id, url); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := holdOrderReturn(tx, r.tr, ret); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return id, nil
+}
+
+// FirstByID implements OrderReturnRepository.
+func (r *orderReturnRepository) FirstByID(ctx context.Context, id int64) (*model.OrderReturn, error) {
+	ret := new(model.OrderReturn)
+	qs := `SELECT * FROM order_returns WHERE id = $1 AND deleted_at IS NULL`
+
+	if err := r.db.GetContext(ctx, ret, qs, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, shared.ErrOrderReturnNotFound
+		}
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// HasOpenReturn implements OrderReturnRepository. A return is open while it
+// waits for the seller or for arbitration.
+func (r *orderReturnRepository) HasOpenReturn(ctx context.Context, orderID int64) (bool, error) {
+	qs := `
+	SELECT EXISTS (
+		SELECT 1 FROM order_returns
+		WHERE order_id = $1 AND status IN ($2, $3) AND deleted_at IS NULL
+	)
+	`
+
+	var open bool
+	err := r.db.GetContext(ctx, &open, qs, orderID, constant.RequestedReturnStatus, constant.EscalatedReturnStatus)
+	if err != nil {
+		return false, err
+	}
+
+	return open, nil
+}
+
+// Find implements OrderReturnRepository. Zero filter fields are ignored.
+func (r *orderReturnRepository) Find(ctx context.Context, filter dto.OrderReturnFilter) ([]model.OrderReturn, error) {
+	rets := make([]model.OrderReturn, 0)
+	qs := `
+	SELECT * FROM order_returns
+	WHERE deleted_at IS NULL
+	AND ($1::bigint = 0 OR buyer_id = $1)
+	AND ($2::bigint = 0 OR seller_id = $2)
+	AND ($3 = '' OR status = $3)
+	ORDER BY id DESC
+	LIMIT $4 OFFSET $5
+	`
+
+	offset := (filter.Page - 1) * constant.OrderReturnDefaultItems
+	err := r.db.SelectContext(ctx, &rets, qs, filter.BuyerID, filter.SellerID, filter.Status, constant.OrderReturnDefaultItems, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return rets, nil
+}
+
+// Count implements OrderReturnRepository.
+func (r *orderReturnRepository) Count(ctx context.Context, filter dto.OrderReturnFilter) (int, error) {
+	var count int
+	qs := `
+	SELECT COUNT(id) FROM order_returns
+	WHERE deleted_at IS NULL
+	AND ($1::bigint = 0 OR buyer_id = $1)
+	AND ($2::bigint = 0 OR seller_id = $2)
+	AND ($3 = '' OR status = $3)
+	`
+
+	err := r.db.GetContext(ctx, &count, qs, filter.BuyerID, filter.SellerID, filter.Status)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// FindItemsByReturnIDs implements OrderReturnRepository.
+func (r *orderReturnRepository) FindItemsByReturnIDs(ctx context.Context, ids []int64) ([]dto.OrderReturnItemModel, error) {
+	items := make([]dto.OrderReturnItemModel, 0)
+	if len(ids) == 0 {
+		return items, nil
+	}
+
+	qs, args, err := sqlx.In(`
+	SELECT
+		ori.order_return_id,
+		ori.order_detail_id,
+		od.product_name,
+		od.variant_name,
+		ori.quantity,
+		ori.amount
+	FROM order_return_items ori
+	JOIN order_details od ON od.id = ori.order_detail_id
+	WHERE ori.order_return_id IN (?)
+	ORDER BY ori.order_return_id, ori.id
+	`, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.SelectContext(ctx, &items, r.db.Rebind(qs), args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// FindImagesByReturnIDs implements OrderReturnRepository.
+func (r *orderReturnRepository) FindImagesByReturnIDs(ctx context.Context, ids []int64) ([]dto.OrderReturnImageModel, error) {
+	images := make([]dto.OrderReturnImageModel, 0)
+	if len(ids) == 0 {
+		return images, nil
+	}
+
+	qs, args, err := sqlx.In(`SELECT order_return_id, image_url FROM order_return_medias WHERE order_return_id IN (?) ORDER BY id`, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.SelectContext(ctx, &images, r.db.Rebind(qs), args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return images, nil
+}
+
+// Reject implements OrderReturnRepository. The held money goes back to the
+// seller until the buyer escalates.
+func (r *orderReturnRepository) Reject(ctx context.Context, ret *model.OrderReturn) error {
+	return r.update(ctx, ret, constant.RejectedReturnStatus, false, releaseOrderReturn)
+}
+
+// Escalate implements OrderReturnRepository. Money is held again if the
+// seller has been paid in the meantime.
+func (r *orderReturnRepository) Escalate(ctx context.Context, ret *model.OrderReturn) error {
+	return r.update(ctx, ret, constant.EscalatedReturnStatus, false, holdOrderReturn)
+}
+
+// Close implements OrderReturnRepository.
+func (r *orderReturnRepository) Close(ctx context.Context, ret *model.OrderReturn) error {
+	return r.update(ctx, ret, constant.ClosedReturnStatus, true, releaseOrderReturn)
+}
+
+// Refund implements OrderReturnRepository. The refund comes out of the
+// buyer's TEMP wallet, where it is either held or still waiting for the
+// order to be received. In the latter case the rest of orderAmount is paid
+// to the seller and the order is completed. In the former the commission
+// kept on the refund is given back to the seller. Either way the seller
+// gives back the share of the platform voucher subsidy that paid for the
+// refund.
+func (r *orderReturnRepository) Refund(ctx context.Context, ret *model.OrderReturn, orderAmount decimal.Decimal) error {
+	return r.update(ctx, ret, constant.RefundedReturnStatus, true, func(tx *sqlx.Tx, tr TransactionRepository, ret *model.OrderReturn) error {
+		orderStatus, err := lockOrderStatus(tx, ret.OrderID)
+		if err != nil {
+			return err
+		}
+
+		tempWalletID, err := activeWalletID(tx, ret.BuyerID, constant.TempWalletType)
+		if err != nil {
+			return err
+		}
+		userWalletID, err := activeWalletID(tx, ret.BuyerID, constant.UserWalletType)
+		if err != nil {
+			return err
+		}
+
+		refundTx := &model.Transaction{
+			Amount:       ret.Amount,
+			Title:        constant.ReturnRefundTitle,
+			FromWalletID: sql.NullInt64{Int64: tempWalletID, Valid: true},
+			ToWalletID:   userWalletID,
+		}
+		if _, err := tr.CreateTransaction(tx, refundTx); err != nil {
+			return err
+		}
+		if err := RefundTempUser(tx, ret.BuyerID, ret.Amount); err != nil {
+			return err
+		}
+		ret.HeldAmount = decimal.Zero
+
+		if orderStatus == string(constant.ReceiveOrderStatus) {
+			if err := refundCommission(tx, tr, ret); err != nil {
+				return err
+			}
+			return reverseSubsidy(tx, tr, ret, orderAmount)
+		}
+		if orderStatus != string(constant.ArriveOrderStatus) {
+			return nil
+		}
+
+		shopWalletID, err := activeWalletID(tx, ret.SellerID, constant.ShopWalletType)
+		if err != nil {
+			return err
+		}
+		receiveTx := &model.Transaction{
+			Amount:       orderAmount.Sub(ret.Amount),
+			Title:        constant.TransferTitle,
+			FromWalletID: sql.NullInt64{Int64: tempWalletID, Valid: true},
+			ToWalletID:   shopWalletID,
+		}
+
+		if err := CompleteOrder(tx, tr, ret.OrderID, ret.BuyerID, ret.SellerID, receiveTx); err != nil {
+			return err
+		}
+
+		return reverseSubsidy(tx, tr, ret, orderAmount)
+	})
+}
+
+// update moves ret to status within a transaction after running move, which
+// settles the money of the return. It fails when ret has changed since it
+// was read.
+func (r *orderReturnRepository) update(ctx context.Context, ret *model.OrderReturn, status constant.OrderReturnStatusType, resolved bool,
+	move func(tx *sqlx.Tx, tr TransactionRepository, ret *model.OrderReturn) error) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	qs1 := `SELECT status FROM order_returns WHERE id = $1 FOR UPDATE`
+
+	qs2 := `
+	UPDATE order_returns
+	SET
+		status = $1,
+		held_amount = $2,
+		seller_note = $3,
+		escalation_reason = $4,
+		admin_note = $5,
+		resolved_at = CASE WHEN $6 THEN NOW() END,
+		updated_at = NOW()
+	WHERE id = $7
+	`
+
+	var current string
+	if err := tx.Get(&current, qs1, ret.ID); err != nil {
+		return err
+	}
+	if current != ret.Status {
+		return shared.ErrInvalidReturnStatus
+	}
+
+	if err := move(tx, r.tr, ret); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(qs2, status, ret.HeldAmount, ret.SellerNote, ret.EscalationReason, ret.AdminNote, resolved, ret.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	ret.Status = string(status)
+
+	return nil
+}
+
+// holdOrderReturn moves the amount of ret from the seller's SHOP wallet to
+// the buyer's TEMP wallet when the seller has already been paid. The SHOP
+// balance may go below zero if the seller has withdrawn the money.
+func holdOrderReturn(tx *sqlx.Tx, tr TransactionRepository, ret *model.OrderReturn) error {
+	orderStatus, err := lockOrderStatus(tx, ret.OrderID)
+	if err != nil {
+		return err
+	}
+	if orderStatus != string(constant.ReceiveOrderStatus) || ret.HeldAmount.IsPositive() {
+		return nil
+	}
+
+	shopWalletID, err := activeWalletID(tx, ret.SellerID, constant.ShopWalletType)
+	if err != nil {
+		return err
+	}
+	tempWalletID, err := activeWalletID(tx, ret.BuyerID, constant.TempWalletType)
+	if err != nil {
+		return err
+	}
+
+	holdTx := &model.Transaction{
+		Amount:       ret.Amount,
+		Title:        constant.ReturnHoldTitle,
+		FromWalletID: sql.NullInt64{Int64: shopWalletID, Valid: true},
+		ToWalletID:   tempWalletID,
+	}
+	if _, err := tr.CreateTransaction(tx, holdTx); err != nil {
+		return err
+	}
+
+	query1 := `
+	UPDATE wallets
+	SET balance = balance-$1
+	WHERE id = $2 AND is_active
+	`
+
+	query2 := `
+	UPDATE wallets
+	SET balance = balance+$1
+	WHERE id = $2 AND is_active
+	`
+
+	if _, err := tx.Exec(query1, ret.Amount, shopWalletID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(query2, ret.Amount, tempWalletID); err != nil {
+		return err
+	}
+
+	ret.HeldAmount = ret.Amount
+	qs := `UPDATE order_returns SET held_amount = $1 WHERE id = $2`
+	if _, err := tx.Exec(qs, ret.HeldAmount, ret.ID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// releaseOrderReturn gives the money held for ret back to the seller.
+func releaseOrderReturn(tx *sqlx.Tx, tr TransactionRepository, ret *model.OrderReturn) error {
+	if !ret.HeldAmount.IsPositive() {
+		return nil
+	}
+
+	tempWalletID, err := activeWalletID(tx, ret.BuyerID, constant.TempWalletType)
+	if err != nil {
+		return err
+	}
+	shopWalletID, err := activeWalletID(tx, ret.SellerID, constant.ShopWalletType)
+	if err != nil {
+		return err
+	}
+
+	releaseTx := &model.Transaction{
+		Amount:       ret.HeldAmount,
+		Title:        constant.ReturnReleaseTitle,
+		FromWalletID: sql.NullInt64{Int64: tempWalletID, Valid: true},
+		ToWalletID:   shopWalletID,
+	}
+	if _, err := tr.CreateTransaction(tx, releaseTx); err != nil {
+		return err
+	}
+	if err := TransferTempSeller(tx, ret.BuyerID, ret.SellerID, ret.HeldAmount); err != nil {
+		return err
+	}
+
+	ret.HeldAmount = decimal.Zero
+
+	return nil
+}
+
+// refundCommission gives the seller back the commission the platform kept
+// on the amount of ret, which the seller has paid back to the buyer.
+func refundCommission(tx *sqlx.Tx, tr TransactionRepository, ret *model.OrderReturn) error {
+	fee, err := OrderPlatformFee(tx, ret.OrderID, ret.Amount)
+	if err != nil {
+		return err
+	}
+	if !fee.Commission.IsPositive() {
+		return nil
+	}
+
+	platformWalletID, err := PlatformWalletID(tx)
+	if err != nil {
+		return err
+	}
+	shopWalletID, err := activeWalletID(tx, ret.SellerID, constant.ShopWalletType)
+	if err != nil {
+		return err
+	}
+
+	backTx := &model.Transaction{
+		Amount:       fee.Commission,
+		Title:        constant.CommissionBackTitle,
+		FromWalletID: sql.NullInt64{Int64: platformWalletID, Valid: true},
+		ToWalletID:   shopWalletID,
+	}
+	if _, err := tr.CreateTransaction(tx, backTx); err != nil {
+		return err
+	}
+
+	return TransferWallets(tx, platformWalletID, shopWalletID, fee.Commission)
+}
+
+// reverseSubsidy takes back from the seller the share of the platform
+// voucher subsidy paid on the order that matches the share of orderAmount
+// refunded by ret.
+func reverseSubsidy(tx *sqlx.Tx, tr TransactionRepository, ret *model.OrderReturn, orderAmount decimal.Decimal) error {
+	subsidy, err := PlatformVoucherSubsidy(tx, ret.OrderID)
+	if err != nil {
+		return err
+	}
+	if !subsidy.IsPositive() {
+		return nil
+	}
+
+	share := subsidy
+	if ret.Amount.LessThan(orderAmount) && orderAmount.IsPositive() {
+		share = subsidy.Mul(ret.Amount).Div(orderAmount).Round(2)
+	}
+
+	shopWalletID, err := activeWalletID(tx, ret.SellerID, constant.ShopWalletType)
+	if err != nil {
+		return err
+	}
+
+	return payPlatform(tx, tr, shopWalletID, share, constant.SubsidyBackTitle)
+}
+
+func lockOrderStatus(tx *sqlx.Tx, orderID int64) (string, error) {
+	var status string
+	qs := `SELECT status FROM orders WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+
+	if err := tx.Get(&status, qs, orderID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", shared.ErrOrderIDNotFount
+		}
+		return "", err
+	}
+
+	return status, nil
+}
+
+func activeWalletID(tx *sqlx.Tx, accountID int64, category constant.WalletType) (int64, error) {
+	var id int64
+	qs := `SELECT id FROM wallets WHERE account_id = $1 AND category = $2 AND is_active`
+
+	if err := tx.Get(&id, qs, accountID, category); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, shared.ErrUpdateInactiveWallet
+		}
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func NewOrderReturnRepository(db *sqlx.DB, tr TransactionRepository) OrderReturnRepository {
+	return &orderReturnRepository{
+		db: db,
+		tr: tr,
+	}
+}
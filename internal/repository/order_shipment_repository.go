@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/model"
+	"github.com/lil-oren/rest/internal/shared"
+)
+
+type (
+	OrderShipmentRepository interface {
+		FirstByOrderID(ctx context.Context, orderID int64) (*model.OrderShipment, error)
+		FindEventsByShipmentID(ctx context.Context, shipmentID int64) ([]model.OrderTrackingEvent, error)
+		ClaimDue(ctx context.Context, checkedBefore time.Time) (*model.OrderShipment, error)
+		SaveTracking(ctx context.Context, shipment *model.OrderShipment, events []model.OrderTrackingEvent) error
+		MarkTrackingFailed(ctx context.Context, id int64, maxFailedChecks int) error
+	}
+	orderShipmentRepository struct {
+		db *sqlx.DB
+	}
+)
+
+// FirstByOrderID implements OrderShipmentRepository.
+func (r *orderShipmentRepository) FirstByOrderID(ctx context.Context, orderID int64) (*model.OrderShipment, error) {
+	shipment := new(model.OrderShipment)
+	qs := `
+	SELECT
+		*
+	FROM
+		order_shipments os
+	WHERE
+		os.order_id = $1 AND
+		os.deleted_at IS NULL
+	`
+
+	if err := r.db.GetContext(ctx, shipment, qs, orderID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, shared.ErrOrderShipmentNotFound
+		}
+		return nil, err
+	}
+
+	return shipment, nil
+}
+
+// FindEventsByShipmentID implements OrderShipmentRepository.
+func (r *orderShipmentRepository) FindEventsByShipmentID(ctx context.Context, shipmentID int64) ([]model.OrderTrackingEvent, error) {
+	events := make([]model.OrderTrackingEvent, 0)
+	qs := `
+	SELECT
+		*
+	FROM
+		order_tracking_events ote
+	WHERE
+		ote.order_shipment_id = $1 AND
+		ote.deleted_at IS NULL
+	ORDER BY ote.occurred_at DESC, ote.id DESC
+	`
+
+	if err := r.db.SelectContext(ctx, &events, qs, shipmentID); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// ClaimDue implements OrderShipmentRepository. It marks the shipment in
+// transit checked the longest ago, and not since checkedBefore, as checked
+// and returns it, or returns nil when there is none.
+func (r *orderShipmentRepository) ClaimDue(ctx context.Context, checkedBefore time.Time) (*model.OrderShipment, error) {
+	shipment := new(model.OrderShipment)
+	qs := `
+	UPDATE order_shipments
+	SET checked_at = NOW(), updated_at = NOW()
+	WHERE id = (
+		SELECT
+			os.id
+		FROM
+			order_shipments os
+		WHERE
+			os.status = $1 AND
+			(os.checked_at IS NULL OR os.checked_at < $2) AND
+			os.deleted_at IS NULL
+		ORDER BY os.checked_at NULLS FIRST, os.id
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING *
+	`
+
+	err := r.db.GetContext(ctx, shipment, qs, constant.InTransitShipmentStatus, checkedBefore)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return shipment, nil
+}
+
+// SaveTracking implements OrderShipmentRepository. The events replace the
+// ones stored before since couriers always report the whole history. A
+// delivered shipment moves its order from DELIVER to ARRIVE.
+func (r *orderShipmentRepository) SaveTracking(ctx context.Context, shipment *model.OrderShipment, events []model.OrderTrackingEvent) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	qs1 := `DELETE FROM order_tracking_events WHERE order_shipment_id = $1`
+
+	qs2 := `
+	INSERT INTO order_tracking_events (
+		order_shipment_id,
+		description,
+		location,
+		occurred_at
+	) VALUES
+	($1, $2, $3, $4)
+	`
+
+	qs3 := `
+	UPDATE order_shipments
+	SET status = $1, delivered_at = $2, failed_checks = 0, updated_at = NOW()
+	WHERE id = $3
+	`
+
+	qs4 := `
+	UPDATE orders
+	SET status = $1, updated_at = NOW()
+	WHERE id = $2 AND status = $3
+	`
+
+	if _, err := tx.Exec(qs1, shipment.ID); err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		if _, err := tx.Exec(qs2, shipment.ID, event.Description, event.Location, event.OccurredAt); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(qs3, shipment.Status, shipment.DeliveredAt, shipment.ID); err != nil {
+		return err
+	}
+
+	if shipment.Status == string(constant.DeliveredShipmentStatus) {
+		res, err := tx.Exec(qs4, constant.ArriveOrderStatus, shipment.OrderID, constant.DeliverOrderStatus)
+		if err != nil {
+			return err
+		}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows > 0 {
+			if err := CreateOrderEvent(tx, constant.OrderStatusChangedEvent, shipment.OrderID); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MarkTrackingFailed implements OrderShipmentRepository. A shipment the
+// tracker failed on maxFailedChecks times in a row is no longer tracked.
+func (r *orderShipmentRepository) MarkTrackingFailed(ctx context.Context, id int64, maxFailedChecks int) error {
+	qs := `
+	UPDATE order_shipments
+	SET
+		failed_checks = failed_checks + 1,
+		status = CASE WHEN failed_checks + 1 >= $2 THEN $3 ELSE status END,
+		updated_at = NOW()
+	WHERE id = $1
+	`
+
+	if _, err := r.db.ExecContext(ctx, qs, id, maxFailedChecks, constant.UntrackedShipmentStatus); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func NewOrderShipmentRepository(db *sqlx.DB) OrderShipmentRepository {
+	return &orderShipmentRepository{
+		db: db,
+	}
+}
@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/model"
+)
+
+type (
+	OutboxRepository interface {
+		Claim(ctx context.Context, limit, maxAttempts int, lease time.Duration) ([]model.OutboxEvent, error)
+		MarkProcessed(ctx context.Context, id int64) error
+		MarkFailed(ctx context.Context, id int64, retryAt time.Time, reason string) error
+	}
+	outboxRepository struct {
+		db *sqlx.DB
+	}
+)
+
+// Claim implements OutboxRepository. It takes up to limit events that are
+// due and counts an attempt for each of them. Claimed events are not due
+// again until lease has passed, so an event whose dispatcher died before
+// marking it is picked up again later.
+func (r *outboxRepository) Claim(ctx context.Context, limit, maxAttempts int, lease time.Duration) ([]model.OutboxEvent, error) {
+	events := make([]model.OutboxEvent, 0)
+	qs := `
+	UPDATE outbox_events
+	SET
+		attempts = attempts + 1,
+		available_at = NOW() + $1 * INTERVAL '1 second',
+		updated_at = NOW()
+	WHERE id IN (
+		SELECT
+			oe.id
+		FROM
+			outbox_events oe
+		WHERE
+			oe.processed_at IS NULL AND
+			oe.available_at <= NOW() AND
+			oe.attempts < $2
+		ORDER BY oe.id
+		LIMIT $3
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING *
+	`
+
+	err := r.db.SelectContext(ctx, &events, qs, lease.Seconds(), maxAttempts, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// MarkProcessed implements OutboxRepository.
+func (r *outboxRepository) MarkProcessed(ctx context.Context, id int64) error {
+	qs := `
+	UPDATE outbox_events
+	SET processed_at = NOW(), last_error = NULL, updated_at = NOW()
+	WHERE id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, qs, id)
+	return err
+}
+
+// MarkFailed implements OutboxRepository.
+func (r *outboxRepository) MarkFailed(ctx context.Context, id int64, retryAt time.Time, reason string) error {
+	qs := `
+	UPDATE outbox_events
+	SET available_at = $1, last_error = $2, updated_at = NOW()
+	WHERE id = $3
+	`
+
+	_, err := r.db.ExecContext(ctx, qs, retryAt, reason, id)
+	return err
+}
+
+// CreateOutboxEvent records an event within tx, so that it is dispatched if
+// and only if the change it is about is committed.
+func CreateOutboxEvent(tx *sqlx.Tx, eventType constant.EventType, aggregateID int64, payload interface{}) error {
+	qs := `
+	INSERT INTO outbox_events (
+		event_type,
+		aggregate_id,
+		payload
+	) VALUES
+	($1, $2, $3)
+	`
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(qs, eventType, aggregateID, data)
+	return err
+}
+
+// CreateOrderEvent records an event about an order within tx, with the
+// status the order has in tx.
+func CreateOrderEvent(tx *sqlx.Tx, eventType constant.EventType, orderID int64) error {
+	qs := `
+	SELECT
+		o.id AS order_id,
+		o.buyer_id,
+		o.seller_id,
+		o.status
+	FROM
+		orders o
+	WHERE
+		o.id = $1
+	`
+
+	payload := dto.OrderEventPayload{}
+	if err := tx.Get(&payload, qs, orderID); err != nil {
+		return err
+	}
+
+	return CreateOutboxEvent(tx, eventType, orderID, payload)
+}
+
+// createWalletCreditedEvent records that a transaction credited its wallet.
+func createWalletCreditedEvent(tx *sqlx.Tx, transactionID int64, transaction *model.Transaction) error {
+	qs := `
+	SELECT
+		w.category,
+		w.account_id
+	FROM
+		wallets w
+	WHERE
+		w.id = $1
+	`
+
+	payload := dto.WalletCreditedPayload{
+		TransactionID: transactionID,
+		WalletID:      transaction.ToWalletID,
+		Amount:        transaction.Amount,
+		Title:         string(transaction.Title),
+	}
+	if err := tx.QueryRowx(qs, transaction.ToWalletID).Scan(&payload.WalletType, &payload.AccountID); err != nil {
+		return err
+	}
+
+	return CreateOutboxEvent(tx, constant.WalletCreditedEvent, transaction.ToWalletID, payload)
+}
+
+// createProductUpdatedEvent records that a product of a seller changed.
+func createProductUpdatedEvent(tx *sqlx.Tx, productID, sellerID int64) error {
+	return CreateOutboxEvent(tx, constant.ProductUpdatedEvent, productID, dto.ProductUpdatedPayload{
+		ProductID: productID,
+		SellerID:  sellerID,
+	})
+}
+
+// createReviewPostedEvent records that a buyer reviewed a product.
+func createReviewPostedEvent(tx *sqlx.Tx, review *model.Review) error {
+	qs := `
+	SELECT
+		p.id AS product_id,
+		p.seller_id
+	FROM
+		products p
+	WHERE
+		p.product_code = $1
+	`
+
+	payload := dto.ReviewPostedPayload{
+		ReviewID:    review.ID,
+		ProductCode: review.ProductCode,
+		AccountID:   review.AccountID,
+		Rating:      review.Rating,
+		Comment:     review.Comment,
+	}
+	if err := tx.Get(&payload, qs, review.ProductCode); err != nil {
+		return err
+	}
+
+	return CreateOutboxEvent(tx, constant.ReviewPostedEvent, review.ID, payload)
+}
+
+// createFlashSaleStartedEvent records that a flash sale started within tx.
+func createFlashSaleStartedEvent(tx *sqlx.Tx, flashSaleID int64) error {
+	qs1 := `
+	SELECT
+		fs.id,
+		fs.seller_id,
+		fs.name
+	FROM
+		flash_sales fs
+	WHERE
+		fs.id = $1
+	`
+
+	qs2 := `
+	SELECT DISTINCT
+		w.account_id
+	FROM
+		wishlists w
+		JOIN product_variants pv ON pv.product_id = w.product_id
+		JOIN flash_sale_variants fsv ON fsv.product_variant_id = pv.id
+	WHERE
+		fsv.flash_sale_id = $1 AND
+		w.account_id <> $2
+	`
+
+	payload := dto.FlashSaleStartedPayload{}
+	if err := tx.Get(&payload, qs1, flashSaleID); err != nil {
+		return err
+	}
+
+	payload.WisherIDs = make([]int64, 0)
+	if err := tx.Select(&payload.WisherIDs, qs2, flashSaleID, payload.SellerID); err != nil {
+		return err
+	}
+
+	return CreateOutboxEvent(tx, constant.FlashSaleStartedEvent, flashSaleID, payload)
+}
+
+func NewOutboxRepository(db *sqlx.DB) OutboxRepository {
+	return &outboxRepository{
+		db: db,
+	}
+}
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/model"
+	"github.com/lil-oren/rest/internal/shared"
+)
+
+type (
+	ProductImportJobRepository interface {
+		Create(ctx context.Context, job *model.ProductImportJob) (*int64, error)
+		FirstByIDAndAccountID(ctx context.Context, id, accountID int64) (*model.ProductImportJob, error)
+		ClaimPending(ctx context.Context, lease time.Duration) (*model.ProductImportJob, error)
+		Checkpoint(ctx context.Context, job *model.ProductImportJob) error
+		Finish(ctx context.Context, job *model.ProductImportJob) error
+		Fail(ctx context.Context, job *model.ProductImportJob) error
+	}
+	productImportJobRepository struct {
+		db *sqlx.DB
+	}
+)
+
+// Create implements ProductImportJobRepository.
+func (r *productImportJobRepository) Create(ctx context.Context, job *model.ProductImportJob) (*int64, error) {
+	qs := `
+	INSERT INTO product_import_jobs (
+		account_id,
+		file_name,
+		status,
+		total_rows,
+		success_rows,
+		failed_rows,
+		items,
+		errors
+	) VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8)
+	RETURNING (id)
+	`
+
+	id := new(int64)
+	err := r.db.QueryRowxContext(ctx, qs,
+		job.AccountID,
+		job.FileName,
+		job.Status,
+		job.TotalRows,
+		job.SuccessRows,
+		job.FailedRows,
+		job.Items,
+		job.Errors,
+	).Scan(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return id, nil
+}
+
+// FirstByIDAndAccountID implements ProductImportJobRepository.
+func (r *productImportJobRepository) FirstByIDAndAccountID(ctx context.Context, id, accountID int64) (*model.ProductImportJob, error) {
+	job := new(model.ProductImportJob)
+	qs := `
+	SELECT
+		*
+	FROM
+		product_import_jobs pij
+	WHERE
+		pij.id = $1 AND
+		pij.account_id = $2 AND
+		pij.deleted_at IS NULL
+	`
+
+	if err := r.db.GetContext(ctx, job, qs, id, accountID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, shared.ErrProductImportJobNotFound
+		}
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// ClaimPending implements ProductImportJobRepository. It marks the oldest
+// pending job as processing and returns it, or returns nil when there is none.
+// A processing job that has not made progress within lease was left by a
+// worker that died and is claimed again.
+func (r *productImportJobRepository) ClaimPending(ctx context.Context, lease time.Duration) (*model.ProductImportJob, error) {
+	job := new(model.ProductImportJob)
+	qs := `
+	UPDATE product_import_jobs
+	SET status = $1, updated_at = NOW()
+	WHERE id = (
+		SELECT
+			pij.id
+		FROM
+			product_import_jobs pij
+		WHERE
+			(
+				pij.status = $2 OR
+				(pij.status = $1 AND pij.updated_at <= NOW() - $3 * INTERVAL '1 second')
+			) AND
+			pij.deleted_at IS NULL
+		ORDER BY pij.id
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING *
+	`
+
+	err := r.db.GetContext(ctx, job, qs, constant.ProductImportStatusProcessing, constant.ProductImportStatusPending, lease.Seconds())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Checkpoint implements ProductImportJobRepository. It saves the progress
+// of a processing job and renews its lease.
+func (r *productImportJobRepository) Checkpoint(ctx context.Context, job *model.ProductImportJob) error {
+	qs := `
+	UPDATE product_import_jobs
+	SET
+		processed_items = $1,
+		success_rows = $2,
+		failed_rows = $3,
+		errors = $4,
+		updated_at = NOW()
+	WHERE id = $5
+	`
+
+	_, err := r.db.ExecContext(ctx, qs,
+		job.Processed,
+		job.SuccessRows,
+		job.FailedRows,
+		job.Errors,
+		job.ID,
+	)
+
+	return err
+}
+
+// Finish implements ProductImportJobRepository.
+func (r *productImportJobRepository) Finish(ctx context.Context, job *model.ProductImportJob) error {
+	qs := `
+	UPDATE product_import_jobs
+	SET
+		status = $1,
+		success_rows = $2,
+		failed_rows = $3,
+		errors = $4,
+		updated_at = NOW(),
+		finished_at = NOW()
+	WHERE id = $5
+	`
+
+	_, err := r.db.ExecContext(ctx, qs,
+		constant.ProductImportStatusDone,
+		job.SuccessRows,
+		job.FailedRows,
+		job.Errors,
+		job.ID,
+	)
+
+	return err
+}
+
+// Fail implements ProductImportJobRepository.
+func (r *productImportJobRepository) Fail(ctx context.Context, job *model.ProductImportJob) error {
+	qs := `
+	UPDATE product_import_jobs
+	SET
+		status = $1,
+		errors = $2,
+		updated_at = NOW(),
+		finished_at = NOW()
+	WHERE id = $3
+	`
+
+	_, err := r.db.ExecContext(ctx, qs,
+		constant.ProductImportStatusFailed,
+		job.Errors,
+		job.ID,
+	)
+
+	return err
+}
+
+func NewProductImportJobRepository(db *sqlx.DB) ProductImportJobRepository {
+	return &productImportJobRepository{
+		db: db,
+	}
+}
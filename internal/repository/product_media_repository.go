@@ -10,6 +10,7 @@ import (
 type (
 	ProductMediaRepository interface {
 		FindProductMediaByProductID(ctx context.Context, id int64) ([]model.ProductMedia, error)
+		SyncVariants(ctx context.Context) error
 	}
 	productMediaRepository struct {
 		db *sqlx.DB
@@ -26,6 +27,29 @@ func (r *productMediaRepository) FindProductMediaByProductID(ctx context.Context
 	return productMedias, nil
 }
 
+// SyncVariants copies the processed variant urls of uploaded medias to the
+// product medias referencing them.
+func (r *productMediaRepository) SyncVariants(ctx context.Context) error {
+	query := `
+	UPDATE product_medias pm
+	SET
+		thumbnail_url = m.thumbnail_url,
+		medium_url = m.medium_url,
+		large_url = m.large_url,
+		updated_at = NOW()
+	FROM
+		medias m
+	WHERE
+		pm.media_url = m.media_url AND
+		m.variant_of IS NULL AND
+		m.deleted_at IS NULL AND
+		m.thumbnail_url IS NOT NULL AND
+		pm.thumbnail_url IS DISTINCT FROM m.thumbnail_url
+	`
+	_, err := r.db.ExecContext(ctx, query)
+	return err
+}
+
 func NewProductMediaRepository(db *sqlx.DB) ProductMediaRepository {
 	return &productMediaRepository{
 		db: db,
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/lil-oren/rest/internal/constant"
@@ -19,12 +20,16 @@ type (
 		FirstProductDetail(ctx context.Context, id int64) (*model.Product, error)
 		FindProductBySearchTerm(ctx context.Context, payload dto.SearchProductPayload) ([]dto.SearchProductResponseItem, error)
 		CountProductBySearchTerm(ctx context.Context, payload dto.CountProductBySearchTermPayload) (*int, error)
+		FindSearchFacets(ctx context.Context, payload dto.CountProductBySearchTermPayload) (*dto.SearchProductFacets, error)
 		CreateProduct(ctx context.Context, payload dto.AddProduct, accountId int, productCode string, mediaType []string) error
 		FindProductDetail(ctx context.Context, productCode string) ([]dto.GetProductDetail, error)
 		FindProductVariants(ctx context.Context, productId int) ([]dto.GetProductVariant, error)
 		FirstProductByCode(ctx context.Context, code string) (*model.Product, error)
 		UpdateProduct(ctx context.Context, payload dto.UpdateProductPayload, categories []interface{}, mediaType []string, accountId int64) error
 		DeleteProduct(ctx context.Context, productCode string, sellerId int64) error
+		FindExportRowsBySellerID(ctx context.Context, sellerID int64) ([]dto.ProductExportRow, error)
+		UpdateProductStatus(ctx context.Context, payload dto.UpdateProductStatusPayload) error
+		ApplyProductSchedules(ctx context.Context) error
 	}
 	productRepository struct {
 		db *sqlx.DB
@@ -54,99 +59,25 @@ func (r *productRepository) DeleteProduct(ctx context.Context, productCode strin
 
 // CountProductBySearchTerm implements ProductRepository.
 func (r *productRepository) CountProductBySearchTerm(ctx context.Context, payload dto.CountProductBySearchTermPayload) (*int, error) {
-	searchTerm := "%"
-	if payload.SearchTerm != "" {
-		searchTerm = "%" + payload.SearchTerm + "%"
-	}
+	categoryCondition, condition := searchProductConditions(payload)
 
 	qs := `
 	SELECT
 		COUNT(1) as count
-	FROM
-		(
-			SELECT 
-				DISTINCT ON (p.id)
-				p.*,
-				pc.category_id
-			FROM
-				products p
-			LEFT JOIN product_categories pc
-				ON pc.product_id = p.id
-			WHERE 
-				p.name ILIKE :search_term
-				%s
-		) p
-	LEFT JOIN
-			(
-			SELECT
-				DISTINCT ON
-				(pv.product_id) pv.product_id,
-				pv.discount,
-				pv.price
-			FROM
-				product_variants pv
-			ORDER BY
-				pv.product_id ASC
-		) pv ON
-		p.id = pv.product_id
-	LEFT JOIN shops s
-		ON
-		s.account_id = p.seller_id
-	LEFT JOIN account_addresses aa
-		ON
-		s.account_id = aa.account_id
-		AND aa.is_shop
-	LEFT JOIN districts d
-		ON
-		aa.district_id = d.id
-	WHERE
-		%s
-		AND
-		%s
-		AND
-		%s
-	`
+	` + searchProductFromClause
+	qs = fmt.Sprintf(qs, categoryCondition, condition)
 
 	args := map[string]interface{}{
-		"search_term": searchTerm,
-	}
-
-	if payload.CategoryID > 0 {
-		condition := "AND pc.category_id = %d"
-		condition = fmt.Sprintf(condition, payload.CategoryID)
-		qs = fmt.Sprintf(qs, condition, "%s", "%s", "%s")
-	} else {
-		qs = fmt.Sprintf(qs, "", "%s", "%s", "%s")
-	}
-
-	if payload.DistrictIDs != "" {
-		condition := "aa.district_id IN (%s)"
-		condition = fmt.Sprintf(condition, payload.DistrictIDs)
-		qs = fmt.Sprintf(qs, condition, "%s", "%s")
-	} else {
-		qs = fmt.Sprintf(qs, "TRUE", "%s", "%s")
-	}
-
-	if payload.MinPrice > 0 {
-		condition := "pv.price >= %f"
-		condition = fmt.Sprintf(condition, payload.MinPrice)
-		qs = fmt.Sprintf(qs, condition, "%s")
-	} else {
-		qs = fmt.Sprintf(qs, "TRUE", "%s")
-	}
-
-	if payload.MaxPrice > 0 {
-		condition := "pv.price <= %f"
-		condition = fmt.Sprintf(condition, payload.MaxPrice)
-		qs = fmt.Sprintf(qs, condition)
-	} else {
-		qs = fmt.Sprintf(qs, "TRUE")
+		"search_term":    searchProductTerm(payload.SearchTerm),
+		"product_status": constant.ProductStatusActive,
+		"min_rating":     payload.MinRating,
 	}
 
 	rows, err := r.db.NamedQueryContext(ctx, qs, args)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	rows.Next()
 
@@ -164,6 +95,9 @@ func (r *productRepository) FindProductBySearchTerm(ctx context.Context, payload
 	SELECT
 		p.product_code AS product_code,
 		p.thumbnail_url as thumbnail_url,
+		COALESCE(tm.thumbnail_url, '') AS image_thumbnail_url,
+		COALESCE(tm.medium_url, '') AS image_medium_url,
+		COALESCE(tm.large_url, '') AS image_large_url,
 		p.name AS product_name,
 		pv.price as base_price,
 		pv.discount as discount,
@@ -174,6 +108,249 @@ func (r *productRepository) FindProductBySearchTerm(ctx context.Context, payload
 			WHEN mp.count_purchased IS NULL THEN 0
 			ELSE mp.count_purchased
 		END) AS total_sold
+	` + searchProductFromClause + `
+	ORDER BY
+		%s
+	OFFSET :offset
+	LIMIT 30
+	`
+
+	start := 0
+	if payload.Page > 1 {
+		start = (payload.Page - 1) * 30
+	}
+
+	args := map[string]interface{}{
+		"offset":         start,
+		"search_term":    searchProductTerm(payload.SearchTerm),
+		"product_status": constant.ProductStatusActive,
+		"min_rating":     payload.MinRating,
+	}
+
+	categoryCondition, condition := searchProductConditions(dto.CountProductBySearchTermPayload{
+		SearchTerm:  payload.SearchTerm,
+		DistrictIDs: payload.DistrictIDs,
+		CategoryID:  payload.CategoryID,
+		MinPrice:    payload.MinPrice,
+		MaxPrice:    payload.MaxPrice,
+		MinRating:   payload.MinRating,
+		InStock:     payload.InStock,
+	})
+
+	orderBy := "p.created_at DESC"
+	switch payload.SortBy {
+	case "created_at":
+		{
+			if payload.SortDesc {
+				orderBy = "p.created_at DESC"
+				break
+			}
+
+			orderBy = "p.created_at ASC"
+		}
+	case "price":
+		{
+			if payload.SortDesc {
+				orderBy = "base_price DESC"
+				break
+			}
+
+			orderBy = "base_price ASC"
+		}
+	case "most_purchased":
+		{
+			if payload.SortDesc {
+				orderBy = "total_sold DESC"
+				break
+			}
+
+			orderBy = "total_sold ASC"
+		}
+	}
+
+	qs = fmt.Sprintf(qs, categoryCondition, condition, orderBy)
+
+	stmt, err := r.db.PrepareNamedContext(ctx, qs)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	m := make([]dto.SearchProductResponseItem, 0)
+	if err := stmt.SelectContext(ctx, &m, args); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// FindSearchFacets implements ProductRepository. A product ships for free
+// when its shop has a free shipping voucher that can be used on it now.
+func (r *productRepository) FindSearchFacets(ctx context.Context, payload dto.CountProductBySearchTermPayload) (*dto.SearchProductFacets, error) {
+	categoryCondition, condition := searchProductConditions(payload)
+	filtered := `
+	WITH fp AS (
+		SELECT
+			p.id,
+			aa.district_id,
+			d.name AS district_name,
+			pv.price,
+			pv.discount,
+			COALESCE(rt.rating, 0) AS rating,
+			EXISTS (
+				SELECT 1
+				FROM vouchers v
+				WHERE
+					v.shop_id = p.seller_id
+					AND v.type = :free_shipping_type
+					AND v.started_at <= NOW()
+					AND v.expired_at > NOW()
+					AND v.used < v.quota
+					AND v.deleted_at IS NULL
+					AND (
+						NOT EXISTS (SELECT 1 FROM voucher_categories vc WHERE vc.voucher_id = v.id)
+						OR EXISTS (
+							SELECT 1
+							FROM product_categories pc
+							JOIN categories c ON c.id = pc.category_id
+							JOIN voucher_categories vc ON vc.category_id IN (c.id, c.parent_category)
+							WHERE vc.voucher_id = v.id AND pc.product_id = p.id
+						)
+					)
+			) AS free_shipping
+	` + fmt.Sprintf(searchProductFromClause, categoryCondition, condition) + `
+	)
+	`
+
+	args := map[string]interface{}{
+		"search_term":        searchProductTerm(payload.SearchTerm),
+		"product_status":     constant.ProductStatusActive,
+		"min_rating":         payload.MinRating,
+		"free_shipping_type": constant.VoucherTypeFreeShipping,
+	}
+
+	res := &dto.SearchProductFacets{
+		Categories:  make([]dto.SearchFacetItem, 0),
+		Districts:   make([]dto.SearchFacetItem, 0),
+		PriceRanges: make([]dto.SearchPriceRangeFacet, 0),
+		Ratings:     make([]dto.SearchRatingFacet, 0),
+	}
+
+	qsCategory := filtered + `
+	SELECT
+		c.id,
+		c.name,
+		COUNT(DISTINCT fp.id) AS count
+	FROM
+		fp
+		INNER JOIN product_categories pc ON pc.product_id = fp.id
+		INNER JOIN categories c ON c.id = pc.category_id
+	GROUP BY
+		c.id, c.name
+	ORDER BY
+		count DESC, c.id ASC
+	`
+
+	if err := r.selectNamed(ctx, &res.Categories, qsCategory, args); err != nil {
+		return nil, err
+	}
+
+	qsDistrict := filtered + `
+	SELECT
+		fp.district_id AS id,
+		fp.district_name AS name,
+		COUNT(1) AS count
+	FROM
+		fp
+	WHERE
+		fp.district_id IS NOT NULL
+	GROUP BY
+		fp.district_id, fp.district_name
+	ORDER BY
+		count DESC, fp.district_id ASC
+	`
+
+	if err := r.selectNamed(ctx, &res.Districts, qsDistrict, args); err != nil {
+		return nil, err
+	}
+
+	counters := make([]string, 0)
+	for _, pr := range constant.SearchPriceRangeFacets {
+		if pr.Max > 0 {
+			counters = append(counters, fmt.Sprintf("COUNT(1) FILTER (WHERE fp.price >= %f AND fp.price < %f)", pr.Min, pr.Max))
+			continue
+		}
+		counters = append(counters, fmt.Sprintf("COUNT(1) FILTER (WHERE fp.price >= %f)", pr.Min))
+	}
+
+	for _, rating := range constant.SearchRatingFacets {
+		counters = append(counters, fmt.Sprintf("COUNT(1) FILTER (WHERE fp.rating >= %d)", rating))
+	}
+
+	counters = append(counters, "COUNT(1) FILTER (WHERE fp.discount > 0)")
+	counters = append(counters, "COUNT(1) FILTER (WHERE fp.free_shipping)")
+
+	qsCounter := filtered + `
+	SELECT
+		` + strings.Join(counters, ",\n\t\t") + `
+	FROM
+		fp
+	`
+
+	rows, err := r.db.NamedQueryContext(ctx, qsCounter, args)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make([]int, len(counters))
+	dest := make([]interface{}, len(counters))
+	for i := range counts {
+		dest[i] = &counts[i]
+	}
+
+	rows.Next()
+	if err := rows.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	for i, pr := range constant.SearchPriceRangeFacets {
+		res.PriceRanges = append(res.PriceRanges, dto.SearchPriceRangeFacet{
+			MinPrice: pr.Min,
+			MaxPrice: pr.Max,
+			Count:    counts[i],
+		})
+	}
+	counts = counts[len(constant.SearchPriceRangeFacets):]
+
+	for i, rating := range constant.SearchRatingFacets {
+		res.Ratings = append(res.Ratings, dto.SearchRatingFacet{
+			MinRating: rating,
+			Count:     counts[i],
+		})
+	}
+	counts = counts[len(constant.SearchRatingFacets):]
+
+	res.Discounted = counts[0]
+	res.FreeShipping = counts[1]
+
+	return res, nil
+}
+
+func (r *productRepository) selectNamed(ctx context.Context, dest interface{}, qs string, args map[string]interface{}) error {
+	stmt, err := r.db.PrepareNamedContext(ctx, qs)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	return stmt.SelectContext(ctx, dest, args)
+}
+
+// searchProductFromClause is shared by the product search, its count and
+// its facets so all of them work on the same filtered set. It expects the
+// category condition and the WHERE condition from searchProductConditions.
+const searchProductFromClause = `
 	FROM
 		(
 			SELECT 
@@ -186,6 +363,7 @@ func (r *productRepository) FindProductBySearchTerm(ctx context.Context, payload
 				ON pc.product_id = p.id
 			WHERE 
 				p.name ILIKE :search_term
+				AND p.status = :product_status
 				%s
 		) p
 	LEFT JOIN
@@ -201,6 +379,16 @@ func (r *productRepository) FindProductBySearchTerm(ctx context.Context, payload
 				pv.product_id ASC
 		) pv ON
 		p.id = pv.product_id
+	LEFT JOIN
+		(
+			SELECT
+				pv.product_id,
+				SUM(pv.stock) AS total_stock
+			FROM
+				product_variants pv
+			GROUP BY pv.product_id
+		) st ON
+		p.id = st.product_id
 	LEFT JOIN 
 	(
 		SELECT
@@ -210,6 +398,20 @@ func (r *productRepository) FindProductBySearchTerm(ctx context.Context, payload
 		GROUP BY od.product_code
 	) mp ON 
 	p.product_code = mp.product_code
+	LEFT JOIN
+	(
+		SELECT
+			r.product_code,
+			AVG(r.rating) AS rating
+		FROM reviews r
+		GROUP BY r.product_code
+	) rt ON
+	p.product_code = rt.product_code
+	LEFT JOIN medias tm
+		ON
+		tm.media_url = p.thumbnail_url
+		AND tm.variant_of IS NULL
+		AND tm.deleted_at IS NULL
 	LEFT JOIN shops s
 		ON
 		s.account_id = p.seller_id
@@ -222,105 +424,44 @@ func (r *productRepository) FindProductBySearchTerm(ctx context.Context, payload
 		aa.district_id = d.id
 	WHERE
 		%s
-		AND
-		%s
-		AND
-		%s
-	ORDER BY
-		%s
-	OFFSET :offset
-	LIMIT 30
 	`
 
-	start := 0
-	searchTerm := "%"
-
-	if payload.SearchTerm != "" {
-		searchTerm = "%" + payload.SearchTerm + "%"
-	}
-
-	if payload.Page > 1 {
-		start = (payload.Page - 1) * 30
+func searchProductTerm(term string) string {
+	if term == "" {
+		return "%"
 	}
 
-	args := map[string]interface{}{
-		"offset":      start,
-		"search_term": searchTerm,
-	}
+	return "%" + term + "%"
+}
 
+func searchProductConditions(payload dto.CountProductBySearchTermPayload) (string, string) {
+	categoryCondition := ""
 	if payload.CategoryID > 0 {
-		condition := "AND pc.category_id = %d"
-		condition = fmt.Sprintf(condition, payload.CategoryID)
-		qs = fmt.Sprintf(qs, condition, "%s", "%s", "%s", "%s")
-	} else {
-		qs = fmt.Sprintf(qs, "", "%s", "%s", "%s", "%s")
+		categoryCondition = fmt.Sprintf("AND pc.category_id = %d", payload.CategoryID)
 	}
 
+	conditions := []string{"TRUE"}
 	if payload.DistrictIDs != "" {
-		condition := "aa.district_id IN (%s)"
-		condition = fmt.Sprintf(condition, payload.DistrictIDs)
-		qs = fmt.Sprintf(qs, condition, "%s", "%s", "%s")
-	} else {
-		qs = fmt.Sprintf(qs, "TRUE", "%s", "%s", "%s")
+		conditions = append(conditions, fmt.Sprintf("aa.district_id IN (%s)", payload.DistrictIDs))
 	}
 
 	if payload.MinPrice > 0 {
-		condition := "pv.price >= %f"
-		condition = fmt.Sprintf(condition, payload.MinPrice)
-		qs = fmt.Sprintf(qs, condition, "%s", "%s")
-	} else {
-		qs = fmt.Sprintf(qs, "TRUE", "%s", "%s")
+		conditions = append(conditions, fmt.Sprintf("pv.price >= %f", payload.MinPrice))
 	}
 
 	if payload.MaxPrice > 0 {
-		condition := "pv.price <= %f"
-		condition = fmt.Sprintf(condition, payload.MaxPrice)
-		qs = fmt.Sprintf(qs, condition, "%s")
-	} else {
-		qs = fmt.Sprintf(qs, "TRUE", "%s")
+		conditions = append(conditions, fmt.Sprintf("pv.price <= %f", payload.MaxPrice))
 	}
 
-	switch payload.SortBy {
-	case "created_at":
-		{
-			if payload.SortDesc {
-				qs = fmt.Sprintf(qs, "p.created_at DESC")
-				break
-			}
-
-			qs = fmt.Sprintf(qs, "p.created_at ASC")
-		}
-	case "price":
-		{
-			if payload.SortDesc {
-				qs = fmt.Sprintf(qs, "base_price DESC")
-				break
-			}
-
-			qs = fmt.Sprintf(qs, "base_price ASC")
-		}
-	case "most_purchased":
-		{
-			if payload.SortDesc {
-				qs = fmt.Sprintf(qs, "total_sold DESC")
-				break
-			}
-
-			qs = fmt.Sprintf(qs, "total_sold ASC")
-		}
+	if payload.MinRating > 0 {
+		conditions = append(conditions, "COALESCE(rt.rating, 0) >= :min_rating")
 	}
 
-	stmt, err := r.db.PrepareNamedContext(ctx, qs)
-	if err != nil {
-		return nil, err
+	if payload.InStock {
+		conditions = append(conditions, "COALESCE(st.total_stock, 0) > 0")
 	}
 
-	m := make([]dto.SearchProductResponseItem, 0)
-	if err := stmt.SelectContext(ctx, &m, args); err != nil {
-		return nil, err
-	}
-
-	return m, nil
+	return categoryCondition, strings.Join(conditions, "\n\t\tAND\n\t\t")
 }
 
 func (r *productRepository) FindRecommended(ctx context.Context) ([]dto.HomePageProductModel, error) {
@@ -330,6 +471,9 @@ func (r *productRepository) FindRecommended(ctx context.Context) ([]dto.HomePage
 	SELECT
 		p.product_code,
 		pm.media_url,
+		COALESCE(pm.thumbnail_url, '') AS image_thumbnail_url,
+		COALESCE(pm.medium_url, '') AS image_medium_url,
+		COALESCE(pm.large_url, '') AS image_large_url,
 		p.name, 
 		pv.price, 
 		(pv.price - (pv.price * pv.discount / 100)) as discounted_price,
@@ -368,10 +512,11 @@ func (r *productRepository) FindRecommended(ctx context.Context) ([]dto.HomePage
 		AND aa.is_shop
 	LEFT JOIN districts d
 		ON d.id = aa.district_id
+	WHERE p.status = $1
 	LIMIT 18;
 	`
 
-	err := r.db.SelectContext(ctx, &e, query)
+	err := r.db.SelectContext(ctx, &e, query, constant.ProductStatusActive)
 	if err != nil {
 		return nil, err
 	}
@@ -391,8 +536,8 @@ func (r *productRepository) FirstProductDetail(ctx context.Context, id int64) (*
 
 func (r *productRepository) CreateProduct(ctx context.Context, payload dto.AddProduct, accountId int, productCode string, mediaType []string) error {
 	qs1 := `
-	INSERT INTO products (name, product_code, description, thumbnail_url, seller_id, weight) VALUES 
-	($1,$2,$3,$4,$5,$6)
+	INSERT INTO products (name, product_code, description, thumbnail_url, seller_id, weight, status, publish_at, unpublish_at) VALUES 
+	($1,$2,$3,$4,$5,$6,$7,$8,$9)
 	RETURNING (id)
 	`
 
@@ -421,6 +566,7 @@ func (r *productRepository) CreateProduct(ctx context.Context, payload dto.AddPr
 	qs6 := `
 	INSERT INTO product_variants (price, stock, discount, product_id, variant_type1_id, variant_type2_id) VALUES
 	($1, $2, $3, $4, $5, $6)
+	RETURNING (id)
 	`
 
 	tx, err := r.db.BeginTxx(ctx, nil)
@@ -430,7 +576,7 @@ func (r *productRepository) CreateProduct(ctx context.Context, payload dto.AddPr
 	defer tx.Rollback()
 
 	var productID int64
-	err = tx.QueryRowx(qs1, payload.ProductName, productCode, payload.Description, payload.ImageURL[0], accountId, payload.Weight).Scan(&productID)
+	err = tx.QueryRowx(qs1, payload.ProductName, productCode, payload.Description, payload.ImageURL[0], accountId, payload.Weight, payload.Status, payload.PublishAt, payload.UnpublishAt).Scan(&productID)
 	if err != nil {
 		return err
 	}
@@ -494,10 +640,22 @@ func (r *productRepository) CreateProduct(ctx context.Context, payload dto.AddPr
 	}
 
 	for _, v := range payload.Variants {
-		_, err = tx.Exec(qs6, v.Price, v.Stock, 0, productID, listVariantType1ID[v.VariantType1], listVariantType2ID[v.VariantType2])
+		var variantID int64
+		err = tx.QueryRowx(qs6, v.Price, v.Stock, 0, productID, listVariantType1ID[v.VariantType1], listVariantType2ID[v.VariantType2]).Scan(&variantID)
 		if err != nil {
 			return err
 		}
+
+		movement := model.StockMovement{
+			ProductVariantID: variantID,
+			Quantity:         v.Stock,
+			StockAfter:       v.Stock,
+			Reason:           payload.StockReason,
+			ActorID:          sql.NullInt64{Int64: int64(accountId), Valid: true},
+		}
+		if err := CreateStockMovement(tx, movement); err != nil {
+			return err
+		}
 	}
 
 	if err = tx.Commit(); err != nil {
@@ -507,6 +665,155 @@ func (r *productRepository) CreateProduct(ctx context.Context, payload dto.AddPr
 	return nil
 }
 
+// FindExportRowsBySellerID implements ProductRepository. Every variant of the
+// seller's products is returned as one row, in the bulk import template order.
+func (r *productRepository) FindExportRowsBySellerID(ctx context.Context, sellerID int64) ([]dto.ProductExportRow, error) {
+	rows := make([]dto.ProductExportRow, 0)
+	qs := `
+	SELECT
+		p.id AS product_id,
+		p.name AS product_name,
+		p.description,
+		p.weight,
+		(
+			SELECT pc.category_id
+			FROM product_categories pc
+			JOIN categories c ON c.id = pc.category_id
+			WHERE pc.product_id = p.id AND c.level = 1
+			LIMIT 1
+		) AS category_level1,
+		(
+			SELECT pc.category_id
+			FROM product_categories pc
+			JOIN categories c ON c.id = pc.category_id
+			WHERE pc.product_id = p.id AND c.level = 2
+			LIMIT 1
+		) AS category_level2,
+		(
+			SELECT pc.category_id
+			FROM product_categories pc
+			JOIN categories c ON c.id = pc.category_id
+			WHERE pc.product_id = p.id AND c.level = 3
+			LIMIT 1
+		) AS category_level3,
+		COALESCE(
+			(
+				SELECT string_agg(pm.media_url, $2 ORDER BY pm.id)
+				FROM product_medias pm
+				WHERE pm.product_id = p.id
+			),
+			p.thumbnail_url
+		) AS image_urls,
+		COALESCE(vg1.name, '') AS variant_group1_name,
+		COALESCE(vt1.name, '') AS variant_type1_name,
+		COALESCE(vg2.name, '') AS variant_group2_name,
+		COALESCE(vt2.name, '') AS variant_type2_name,
+		pv.price,
+		pv.stock
+	FROM
+		products p
+		JOIN product_variants pv ON pv.product_id = p.id
+		LEFT JOIN variant_types vt1 ON pv.variant_type1_id = vt1.id
+		LEFT JOIN variant_types vt2 ON pv.variant_type2_id = vt2.id
+		LEFT JOIN variant_groups vg1 ON vg1.id = vt1.variant_group_id
+		LEFT JOIN variant_groups vg2 ON vg2.id = vt2.variant_group_id
+	WHERE
+		p.seller_id = $1 AND
+		p.deleted_at IS NULL AND
+		pv.deleted_at IS NULL
+	ORDER BY p.id, pv.id
+	`
+
+	err := r.db.SelectContext(ctx, &rows, qs, sellerID, constant.ProductImportListSeparator)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// UpdateProductStatus implements ProductRepository.
+func (r *productRepository) UpdateProductStatus(ctx context.Context, payload dto.UpdateProductStatusPayload) error {
+	qs := `
+	UPDATE products
+	SET status = $1, publish_at = $2, unpublish_at = $3, updated_at = NOW()
+	WHERE product_code = $4 AND seller_id = $5
+	RETURNING id
+	`
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	productIDs := make([]int64, 0)
+	err = tx.SelectContext(ctx, &productIDs, qs, payload.Status, payload.PublishAt, payload.UnpublishAt, payload.ProductCode, payload.SellerID)
+	if err != nil {
+		return err
+	}
+
+	for _, productID := range productIDs {
+		if err := createProductUpdatedEvent(tx, productID, payload.SellerID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ApplyProductSchedules implements ProductRepository. Draft and inactive
+// products past their publish time become active, active products past their
+// unpublish time become inactive.
+func (r *productRepository) ApplyProductSchedules(ctx context.Context) error {
+	qs1 := `
+	UPDATE products
+	SET status = $1, publish_at = NULL, updated_at = NOW()
+	WHERE
+		publish_at <= NOW() AND
+		status IN ($2, $3) AND
+		deleted_at IS NULL
+	RETURNING id, seller_id
+	`
+
+	qs2 := `
+	UPDATE products
+	SET status = $1, unpublish_at = NULL, updated_at = NOW()
+	WHERE
+		unpublish_at <= NOW() AND
+		publish_at IS NULL AND
+		status = $2 AND
+		deleted_at IS NULL
+	RETURNING id, seller_id
+	`
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	published := make([]dto.ProductUpdatedPayload, 0)
+	err = tx.SelectContext(ctx, &published, qs1, constant.ProductStatusActive, constant.ProductStatusDraft, constant.ProductStatusInactive)
+	if err != nil {
+		return err
+	}
+
+	unpublished := make([]dto.ProductUpdatedPayload, 0)
+	err = tx.SelectContext(ctx, &unpublished, qs2, constant.ProductStatusInactive, constant.ProductStatusActive)
+	if err != nil {
+		return err
+	}
+
+	for _, product := range append(published, unpublished...) {
+		if err := createProductUpdatedEvent(tx, product.ProductID, product.SellerID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
 func (r *productRepository) FindProductDetail(ctx context.Context, productCode string) ([]dto.GetProductDetail, error) {
 	detail := make([]dto.GetProductDetail, 0)
 	qs := `
@@ -614,7 +921,7 @@ func (r *productRepository) UpdateProduct(ctx context.Context, payload dto.Updat
 
 	qsb := `
 	UPDATE product_medias
-	SET media_url = $1
+	SET media_url = $1, thumbnail_url = NULL, medium_url = NULL, large_url = NULL
 	WHERE id = (
 		SELECT
 			id
@@ -685,11 +992,12 @@ func (r *productRepository) UpdateProduct(ctx context.Context, payload dto.Updat
 	`
 
 	qs6 := `
-	UPDATE product_variants
+	UPDATE product_variants pv
 	SET price = $1, stock = $2
-	WHERE id = (
+	FROM (
 		SELECT
-			pv.id
+			pv.id,
+			pv.stock
 		FROM
 			product_variants pv
 			LEFT JOIN products p ON pv.product_id = p.id
@@ -697,7 +1005,10 @@ func (r *productRepository) UpdateProduct(ctx context.Context, payload dto.Updat
 			LEFT JOIN variant_types vt2 ON pv.variant_type2_id = vt2.id
 		WHERE
 			pv.product_id = $3 AND vt1.name = $4 AND vt2.name = $5
-	)
+		FOR UPDATE OF pv
+	) old
+	WHERE pv.id = old.id
+	RETURNING pv.id, old.stock
 	`
 
 	tx, err := r.db.BeginTxx(ctx, nil)
@@ -809,27 +1120,51 @@ func (r *productRepository) UpdateProduct(ctx context.Context, payload dto.Updat
 		}
 	}
 
+	updateVariant := func(v dto.Variant, variantType1, variantType2 interface{}) error {
+		var variantID, stock int64
+		err := tx.QueryRowx(qs6, v.Price, v.Stock, payload.ProductID, variantType1, variantType2).Scan(&variantID, &stock)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil
+			}
+			return err
+		}
+
+		movement := model.StockMovement{
+			ProductVariantID: variantID,
+			Quantity:         v.Stock - stock,
+			StockAfter:       v.Stock,
+			Reason:           constant.StockReasonSellerAdjustment,
+			ActorID:          sql.NullInt64{Int64: accountId, Valid: true},
+		}
+		return CreateStockMovement(tx, movement)
+	}
+
 	for idx, v := range payload.Variants {
 		if payload.Variants[idx].VariantType1 == nil && payload.Variants[idx].VariantType2 == nil {
-			_, err := tx.Exec(qs6, v.Price, v.Stock, payload.ProductID, constant.ProductVariantDefault, constant.ProductVariantDefault)
+			err := updateVariant(v, constant.ProductVariantDefault, constant.ProductVariantDefault)
 			if err != nil {
 				return err
 			}
 		}
 
 		if payload.Variants[idx].VariantType2 == nil {
-			_, err := tx.Exec(qs6, v.Price, v.Stock, payload.ProductID, v.VariantType1, constant.ProductVariantDefault)
+			err := updateVariant(v, v.VariantType1, constant.ProductVariantDefault)
 			if err != nil {
 				return err
 			}
 		}
 
-		_, err := tx.Exec(qs6, v.Price, v.Stock, payload.ProductID, v.VariantType1, v.VariantType2)
+		err := updateVariant(v, v.VariantType1, v.VariantType2)
 		if err != nil {
 			return err
 		}
 	}
 
+	if err := createProductUpdatedEvent(tx, int64(payload.ProductID), accountId); err != nil {
+		return err
+	}
+
 	if err = tx.Commit(); err != nil {
 		return err
 	}
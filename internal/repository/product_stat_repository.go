@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type (
+	ProductStatRepository interface {
+		IncrementView(ctx context.Context, productID int64) error
+		IncrementAddToCart(ctx context.Context, productVariantID int64) error
+	}
+	productStatRepository struct {
+		db *sqlx.DB
+	}
+)
+
+// IncrementView implements ProductStatRepository. Views are counted per
+// product per day.
+func (r *productStatRepository) IncrementView(ctx context.Context, productID int64) error {
+	qs := `
+	INSERT INTO product_daily_stats (product_id, date, views) VALUES
+	($1, CURRENT_DATE, 1)
+	ON CONFLICT (product_id, date) DO UPDATE
+	SET views = product_daily_stats.views + 1
+	`
+
+	_, err := r.db.ExecContext(ctx, qs, productID)
+	return err
+}
+
+// IncrementAddToCart implements ProductStatRepository.
+func (r *productStatRepository) IncrementAddToCart(ctx context.Context, productVariantID int64) error {
+	qs := `
+	INSERT INTO product_daily_stats (product_id, date, add_to_carts)
+	SELECT
+		pv.product_id, CURRENT_DATE, 1
+	FROM product_variants pv
+	WHERE pv.id = $1
+	ON CONFLICT (product_id, date) DO UPDATE
+	SET add_to_carts = product_daily_stats.add_to_carts + 1
+	`
+
+	_, err := r.db.ExecContext(ctx, qs, productVariantID)
+	return err
+}
+
+func NewProductStatRepository(db *sqlx.DB) ProductStatRepository {
+	return &productStatRepository{
+		db: db,
+	}
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/lil-oren/rest/internal/dto"
@@ -35,7 +37,8 @@ func (r *productVariantRepository) FirstProductVariantByIDForCart(ctx context.Co
 		SELECT 
 			pv.id,
 			pv.stock, 
-			p.seller_id
+			p.seller_id,
+			p.status
 		FROM product_variants pv 
 		LEFT JOIN products p ON p.id  = pv.product_id 
 		WHERE pv.id = $1
@@ -57,7 +60,9 @@ func (r *productVariantRepository) FirstProductVariantByID(ctx context.Context,
 	return productVariant, nil
 }
 
-func DecreaseStock(tx *sqlx.Tx, code string, quantity int, vName1, vName2 string) error {
+// DecreaseStock takes quantity from the stock of a product variant and records
+// it as movement. Variants that no longer exist are skipped.
+func DecreaseStock(tx *sqlx.Tx, code string, quantity int, vName1, vName2 string, movement model.StockMovement) error {
 	qs := `
 	UPDATE product_variants
 	SET stock = stock-$1, updated_at = now()
@@ -71,13 +76,18 @@ func DecreaseStock(tx *sqlx.Tx, code string, quantity int, vName1, vName2 string
 			WHERE p.product_code = $2
 			AND (vt."name" = $3 AND vt2."name" = $4)
 	)
+	RETURNING id, stock
 	`
-	_, err := tx.Exec(qs, quantity, code, vName1, vName2)
+	err := tx.QueryRowx(qs, quantity, code, vName1, vName2).Scan(&movement.ProductVariantID, &movement.StockAfter)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
 		return err
 	}
 
-	return nil
+	movement.Quantity = -int64(quantity)
+	return CreateStockMovement(tx, movement)
 }
 
 func NewProductVariantRepository(db *sqlx.DB) ProductVariantRepository {
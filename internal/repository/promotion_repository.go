@@ -4,11 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/lil-oren/rest/internal/constant"
 	"github.com/lil-oren/rest/internal/dto"
 	"github.com/lil-oren/rest/internal/model"
+	"github.com/lil-oren/rest/internal/shared"
 )
 
 type (
@@ -21,6 +23,9 @@ type (
 		Update(ctx context.Context, promotion *model.Promotion) error
 		FirstPromotionByShopID(ctx context.Context, shopID, promotionID int64) (*dto.PromotionDetail, error)
 		SoftDelete(ctx context.Context, promotionID int64) error
+		FindAnalytics(ctx context.Context, promotion *model.Promotion, sellerID int64) (*dto.PromotionAnalyticsModel, error)
+		FindDailyAnalytics(ctx context.Context, promotion *model.Promotion, sellerID int64) ([]dto.PromotionDailyModel, error)
+		AverageNonPromoOrderValue(ctx context.Context, promotion *model.Promotion, sellerID int64) (float64, error)
 	}
 	promotionRepository struct {
 		db *sqlx.DB
@@ -115,14 +120,14 @@ func (r *promotionRepository) Create(ctx context.Context, promotion *model.Promo
 	INSERT INTO promotions (
 		name, exact_price, 
 		percentage, minimum_spend, 
-		quota, shop_id, 
-		started_at, expired_at
+		quota, usage_limit_per_user,
+		shop_id, started_at, expired_at
 	) VALUES
 	(
 		:name, :exact_price, 
 		:percentage, :minimum_spend,
-		:quota, :shop_id, 
-		:started_at, :expired_at
+		:quota, :usage_limit_per_user,
+		:shop_id, :started_at, :expired_at
 	)
 	`
 	_, err := r.db.NamedExecContext(ctx, query, promotion)
@@ -142,6 +147,7 @@ func (r *promotionRepository) Update(ctx context.Context, promotion *model.Promo
 		percentage = :percentage,
 		minimum_spend = :minimum_spend,
 		quota = :quota,
+		usage_limit_per_user = :usage_limit_per_user,
 		started_at = :started_at,
 		expired_at = :expired_at,
 		updated_at = now() 
@@ -210,6 +216,199 @@ func (r *promotionRepository) SoftDelete(ctx context.Context, promotionID int64)
 	return nil
 }
 
+// promotionOrdersCTE selects the orders that used a promotion with the value
+// of their products. Orders created before orders.promotion_id existed are
+// matched by the promotion name within the promotion period.
+const promotionOrdersCTE = `
+	WITH po AS (
+		SELECT
+			o.id,
+			o.buyer_id,
+			o.created_at,
+			COALESCE(o.promotion_amount, 0) AS discount,
+			COALESCE(SUM(od.sub_total_price), 0) AS gmv
+		FROM orders o
+		LEFT JOIN order_details od ON od.order_id = o.id
+		WHERE o.seller_id = $2
+		AND o.status <> $3
+		AND o.deleted_at IS NULL
+		AND (
+			o.promotion_id = $1
+			OR (o.promotion_id IS NULL AND o.promotion_name = $4 AND o.created_at BETWEEN $5 AND $6)
+		)
+		GROUP BY o.id
+	)
+`
+
+// FindAnalytics implements PromotionRepository. Cancelled orders are not
+// counted.
+func (r *promotionRepository) FindAnalytics(ctx context.Context, promotion *model.Promotion, sellerID int64) (*dto.PromotionAnalyticsModel, error) {
+	analytics := new(dto.PromotionAnalyticsModel)
+	qs := promotionOrdersCTE + `
+	SELECT
+		COUNT(po.id) AS total_orders,
+		COUNT(DISTINCT po.buyer_id) AS unique_buyers,
+		COALESCE(SUM(po.gmv), 0) AS gmv,
+		COALESCE(SUM(po.discount), 0) AS total_discount
+	FROM po
+	`
+
+	err := r.db.GetContext(ctx, analytics, qs,
+		promotion.ID,
+		sellerID,
+		constant.CancelOrderStatus,
+		promotion.Name,
+		promotion.StartedAt,
+		promotion.ExpiredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return analytics, nil
+}
+
+// FindDailyAnalytics implements PromotionRepository. Every day of the
+// promotion up to today is returned, including days without orders.
+func (r *promotionRepository) FindDailyAnalytics(ctx context.Context, promotion *model.Promotion, sellerID int64) ([]dto.PromotionDailyModel, error) {
+	daily := make([]dto.PromotionDailyModel, 0)
+	qs := promotionOrdersCTE + `
+	SELECT
+		to_char(d.day, 'YYYY-MM-DD') AS date,
+		COUNT(po.id) AS orders,
+		COALESCE(SUM(po.gmv), 0) AS gmv,
+		COALESCE(SUM(po.discount), 0) AS discount
+	FROM generate_series($7::date, $8::date, interval '1 day') AS d(day)
+	LEFT JOIN po ON po.created_at::date = d.day::date
+	GROUP BY d.day
+	ORDER BY d.day
+	`
+
+	until := promotion.ExpiredAt
+	if now := time.Now(); now.Before(until) {
+		until = now
+	}
+
+	err := r.db.SelectContext(ctx, &daily, qs,
+		promotion.ID,
+		sellerID,
+		constant.CancelOrderStatus,
+		promotion.Name,
+		promotion.StartedAt,
+		promotion.ExpiredAt,
+		promotion.StartedAt.Format(constant.DateLayoutISO),
+		until.Format(constant.DateLayoutISO),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return daily, nil
+}
+
+// AverageNonPromoOrderValue implements PromotionRepository. It averages the
+// seller's orders without a promotion placed during the promotion period.
+func (r *promotionRepository) AverageNonPromoOrderValue(ctx context.Context, promotion *model.Promotion, sellerID int64) (float64, error) {
+	var average float64
+	qs := `
+	SELECT
+		COALESCE(AVG(t.gmv), 0)
+	FROM (
+		SELECT
+			SUM(od.sub_total_price) AS gmv
+		FROM orders o
+		JOIN order_details od ON od.order_id = o.id
+		WHERE o.seller_id = $1
+		AND o.status <> $2
+		AND o.deleted_at IS NULL
+		AND o.promotion_id IS NULL
+		AND COALESCE(o.promotion_amount, 0) = 0
+		AND o.created_at BETWEEN $3 AND LEAST($4, now())
+		GROUP BY o.id
+	) t
+	`
+
+	err := r.db.GetContext(ctx, &average, qs, sellerID, constant.CancelOrderStatus, promotion.StartedAt, promotion.ExpiredAt)
+	if err != nil {
+		return 0, err
+	}
+
+	return average, nil
+}
+
+// ReservePromotion takes one use of a promotion's quota for an order within
+// tx. The promotion row is locked so concurrent checkouts cannot use more than
+// the quota or the buyer's usage limit.
+func ReservePromotion(tx *sqlx.Tx, promotionID, buyerID, orderID int64) error {
+	qs1 := `SELECT * FROM promotions WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+
+	qs2 := `
+	SELECT COUNT(id) FROM promotion_usages
+	WHERE promotion_id = $1 AND buyer_id = $2 AND released_at IS NULL
+	`
+
+	qs3 := `
+	INSERT INTO promotion_usages (promotion_id, buyer_id, order_id) VALUES
+	($1, $2, $3)
+	`
+
+	qs4 := `UPDATE promotions SET quota = quota - 1, updated_at = now() WHERE id = $1`
+
+	promotion := new(model.Promotion)
+	if err := tx.Get(promotion, qs1, promotionID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return shared.ErrPromotionNotFound
+		}
+		return err
+	}
+	if promotion.Quota <= 0 {
+		return shared.ErrPromotionQuotaEmpty
+	}
+
+	if promotion.UsageLimit.Valid {
+		var used int64
+		if err := tx.Get(&used, qs2, promotionID, buyerID); err != nil {
+			return err
+		}
+		if used >= promotion.UsageLimit.Int64 {
+			return shared.ErrPromotionUsageLimit
+		}
+	}
+
+	if _, err := tx.Exec(qs3, promotionID, buyerID, orderID); err != nil {
+		return err
+	}
+
+	_, err := tx.Exec(qs4, promotionID)
+	return err
+}
+
+// ReleasePromotion gives the promotion used by a cancelled order back to its
+// quota within tx.
+func ReleasePromotion(tx *sqlx.Tx, orderID int64) error {
+	qs1 := `
+	UPDATE promotion_usages
+	SET released_at = now()
+	WHERE order_id = $1 AND released_at IS NULL
+	RETURNING promotion_id
+	`
+
+	qs2 := `UPDATE promotions SET quota = quota + 1, updated_at = now() WHERE id = $1`
+
+	promotionIDs := make([]int64, 0)
+	if err := tx.Select(&promotionIDs, qs1, orderID); err != nil {
+		return err
+	}
+
+	for _, promotionID := range promotionIDs {
+		if _, err := tx.Exec(qs2, promotionID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func NewPromotionRepository(db *sqlx.DB) PromotionRepository {
 	return &promotionRepository{
 		db: db,
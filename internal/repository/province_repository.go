@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/lil-oren/rest/internal/model"
 )
 
@@ -11,6 +12,8 @@ type (
 	ProvinceRepository interface {
 		Find(ctx context.Context) ([]model.Province, error)
 		FirstByID(ctx context.Context, provinceID int64) (*model.Province, error)
+		FindAll(ctx context.Context) ([]model.Province, error)
+		Sync(ctx context.Context, provinces []model.Province, inactiveIDs []int64) error
 	}
 	provinceRepository struct {
 		db *sqlx.DB
@@ -46,7 +49,7 @@ func (r *provinceRepository) FirstByID(ctx context.Context, provinceID int64) (*
 func (r *provinceRepository) Find(ctx context.Context) ([]model.Province, error) {
 	province := make([]model.Province, 0)
 
-	qs := `SELECT * FROM provinces`
+	qs := `SELECT * FROM provinces WHERE is_active`
 
 	err := r.db.SelectContext(ctx, &province, qs)
 	if err != nil {
@@ -56,6 +59,79 @@ func (r *provinceRepository) Find(ctx context.Context) ([]model.Province, error)
 	return province, nil
 }
 
+// FindAll implements ProvinceRepository. Unlike Find it also returns the
+// provinces the provider no longer lists.
+func (r *provinceRepository) FindAll(ctx context.Context) ([]model.Province, error) {
+	provinces := make([]model.Province, 0)
+
+	qs := `SELECT * FROM provinces ORDER BY id`
+
+	if err := r.db.SelectContext(ctx, &provinces, qs); err != nil {
+		return nil, err
+	}
+
+	return provinces, nil
+}
+
+// Sync implements ProvinceRepository. Provinces without an id are added,
+// the others are updated and made active again, and inactiveIDs are hidden
+// from new addresses.
+func (r *provinceRepository) Sync(ctx context.Context, provinces []model.Province, inactiveIDs []int64) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	qs1 := `
+	INSERT INTO provinces (
+		name,
+		provider_id,
+		is_active
+	) VALUES (
+		$1,
+		$2,
+		TRUE
+	)
+	`
+
+	qs2 := `
+	UPDATE provinces
+	SET name = $1, provider_id = $2, is_active = TRUE
+	WHERE id = $3
+	`
+
+	qs3 := `
+	UPDATE provinces
+	SET is_active = FALSE
+	WHERE id = ANY($1)
+	`
+
+	for _, p := range provinces {
+		if p.ID == 0 {
+			if _, err := tx.ExecContext(ctx, qs1, p.Name, p.ProviderID); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, qs2, p.Name, p.ProviderID, p.ID); err != nil {
+			return err
+		}
+	}
+
+	if len(inactiveIDs) != 0 {
+		if _, err := tx.ExecContext(ctx, qs3, pq.Array(inactiveIDs)); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func NewProvinceRepository(db *sqlx.DB) ProvinceRepository {
 	return &provinceRepository{
 		db: db,
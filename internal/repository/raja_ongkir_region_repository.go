@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/shared"
+)
+
+type rajaOngkirRegionRepository struct {
+	client *resty.Client
+}
+
+// GetProvinces implements RegionProvider.
+func (r *rajaOngkirRegionRepository) GetProvinces(ctx context.Context) ([]dto.RegionProvince, error) {
+	result := new(dto.RajaOngkirProvinceHTTPResponse)
+	res, err := r.client.R().
+		SetContext(ctx).
+		SetResult(result).
+		SetError(result).
+		Get(constant.RajaOngkirProvincePath)
+	if err != nil || res.StatusCode() != http.StatusOK || result.RajaOngkir.Status.Code != http.StatusOK {
+		return nil, shared.ErrRegionProviderUnavailable
+	}
+
+	provinces := make([]dto.RegionProvince, 0, len(result.RajaOngkir.Results))
+	for _, p := range result.RajaOngkir.Results {
+		provinces = append(provinces, dto.RegionProvince{
+			ProviderID: p.ProvinceID,
+			Name:       strings.TrimSpace(p.Province),
+		})
+	}
+
+	return provinces, nil
+}
+
+// GetCities implements RegionProvider. A city is named after its type, so
+// "Kabupaten Bandung" and "Kota Bandung" stay apart.
+func (r *rajaOngkirRegionRepository) GetCities(ctx context.Context) ([]dto.RegionCity, error) {
+	result := new(dto.RajaOngkirCityHTTPResponse)
+	res, err := r.client.R().
+		SetContext(ctx).
+		SetResult(result).
+		SetError(result).
+		Get(constant.RajaOngkirCityPath)
+	if err != nil || res.StatusCode() != http.StatusOK || result.RajaOngkir.Status.Code != http.StatusOK {
+		return nil, shared.ErrRegionProviderUnavailable
+	}
+
+	cities := make([]dto.RegionCity, 0, len(result.RajaOngkir.Results))
+	for _, c := range result.RajaOngkir.Results {
+		cities = append(cities, dto.RegionCity{
+			ProviderID:         c.CityID,
+			ProvinceProviderID: c.ProvinceID,
+			Name:               strings.TrimSpace(c.Type + " " + c.CityName),
+			PostalCode:         c.PostalCode,
+		})
+	}
+
+	return cities, nil
+}
+
+func NewRajaOngkirRegionRepository(client *resty.Client) RegionProvider {
+	return &rajaOngkirRegionRepository{
+		client: client,
+	}
+}
@@ -2,69 +2,131 @@ package repository
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
+	"database/sql"
+	"errors"
 	"net/http"
-	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/go-resty/resty/v2"
 	"github.com/lil-oren/rest/internal/constant"
 	"github.com/lil-oren/rest/internal/dependency"
 	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/shared"
 )
 
 type (
 	RajaOngkirRepository interface {
-		GetCost(ctx context.Context, query dto.RajaOngkirGetCostHTTPQueries) (*float64, error)
+		ShippingProvider
 	}
 	rajaOngkirRepository struct {
+		client *resty.Client
+		cr     CacheRepository
+		dr     DistrictRepository
+		cb     *shared.CircuitBreaker
 		config dependency.Config
 	}
 )
 
-// GetCost implements RajaOngkirRepository.
-func (r *rajaOngkirRepository) GetCost(ctx context.Context, query dto.RajaOngkirGetCostHTTPQueries) (*float64, error) {
-	formData := url.Values{}
-	formData.Set("origin", strconv.Itoa(query.OriginCityID))
-	formData.Set("destination", strconv.Itoa(query.DestinationCityID))
-	formData.Set("weight", strconv.Itoa(query.Weight))
-	formData.Set("courier", query.CourierCode)
+// GetRates implements ShippingProvider. Districts are sent as the RajaOngkir
+// city they were synced from, and the weight is rounded up to
+// RO_WEIGHT_BUCKET grams so rates can be cached for every cart of about the
+// same weight. Once RajaOngkir keeps failing no request is made until the
+// circuit breaker lets one through again.
+func (r *rajaOngkirRepository) GetRates(ctx context.Context, query dto.ShippingRateQuery) ([]dto.ShippingRate, error) {
+	if bucket := int(r.config.ThirdParty.RajaOngkirWeightBucket); bucket > 0 {
+		query.Weight = (query.Weight + bucket - 1) / bucket * bucket
+	}
+
+	if rates, err := r.cr.GetShippingRates(ctx, query); err == nil && rates != nil {
+		return rates, nil
+	}
 
-	roURL := fmt.Sprintf("%s%s", r.config.ThirdParty.RajaOngkirBaseURL, "/cost")
-	req, err := http.NewRequest(http.MethodPost, roURL, strings.NewReader(formData.Encode()))
+	origin, err := r.cityID(ctx, query.OriginDistrictID)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Add(constant.RajaOngkirAPIKeyHeader, r.config.ThirdParty.RajaOngkirAPIKey)
-	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-
-	client := new(http.Client)
-	res, err := client.Do(req)
+	destination, err := r.cityID(ctx, query.DestinationDistrictID)
 	if err != nil {
 		return nil, err
 	}
 
+	if !r.cb.Allow() {
+		return nil, shared.ErrShippingCostUnavailable
+	}
+
 	result := new(dto.RajaOngkirGetCostHTTPResponse)
-	if err := json.NewDecoder(res.Body).Decode(result); err != nil {
-		return nil, err
+	res, err := r.client.R().
+		SetContext(ctx).
+		SetFormData(map[string]string{
+			"origin":      origin,
+			"destination": destination,
+			"weight":      strconv.Itoa(query.Weight),
+			"courier":     query.CourierCode,
+		}).
+		SetResult(result).
+		SetError(result).
+		Post(constant.RajaOngkirCostPath)
+	if err != nil || res.StatusCode() == http.StatusTooManyRequests || res.StatusCode() >= http.StatusInternalServerError {
+		r.cb.Failure()
+		return nil, shared.ErrShippingCostUnavailable
 	}
+	r.cb.Success()
 
-	firstResult := result.RajaOngkir.Results[0]
+	if res.StatusCode() != http.StatusOK || result.RajaOngkir.Status.Code != http.StatusOK {
+		return nil, shared.ErrInvalidShippingRoute
+	}
 
-	cost := float64(0)
-	for _, c := range firstResult.Costs {
-		if c.Service == query.CourierService {
-			cost = float64(c.Cost[0].Value)
+	rates := make([]dto.ShippingRate, 0)
+	for _, courier := range result.RajaOngkir.Results {
+		for _, c := range courier.Costs {
+			if len(c.Cost) == 0 {
+				continue
+			}
+			rates = append(rates, dto.ShippingRate{
+				CourierCode: query.CourierCode,
+				Service:     c.Service,
+				Description: c.Description,
+				Cost:        float64(c.Cost[0].Value),
+				ETD:         strings.TrimSpace(strings.TrimSuffix(strings.ToUpper(c.Cost[0].ETD), "HARI")),
+			})
 		}
 	}
 
-	return &cost, nil
+	_ = r.cr.SetShippingRates(ctx, query, rates)
+
+	return rates, nil
+}
+
+// cityID returns the RajaOngkir city of a district. Districts seeded before
+// the region sync have no provider id and were seeded with the city id.
+func (r *rajaOngkirRepository) cityID(ctx context.Context, districtID int64) (string, error) {
+	district, err := r.dr.FirstByID(ctx, districtID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", shared.ErrInvalidShippingRoute
+		}
+		return "", err
+	}
+
+	if district.ProviderID.Valid {
+		return district.ProviderID.String, nil
+	}
+
+	return strconv.FormatInt(district.ID, 10), nil
 }
 
-func NewRajaOngkirRepository(config dependency.Config) RajaOngkirRepository {
+func NewRajaOngkirRepository(client *resty.Client, cr CacheRepository, dr DistrictRepository, config dependency.Config) RajaOngkirRepository {
 	return &rajaOngkirRepository{
+		client: client,
+		cr:     cr,
+		dr:     dr,
+		cb: shared.NewCircuitBreaker(
+			int(config.ThirdParty.RajaOngkirBreakerThreshold),
+			time.Duration(config.ThirdParty.RajaOngkirBreakerCooldown)*time.Second,
+		),
 		config: config,
 	}
 }
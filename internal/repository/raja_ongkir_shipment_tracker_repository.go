@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/model"
+)
+
+type (
+	rajaOngkirShipmentTracker struct {
+		client *resty.Client
+	}
+)
+
+// Track implements ShipmentTracker using the RajaOngkir waybill API.
+func (t *rajaOngkirShipmentTracker) Track(ctx context.Context, shipment *model.OrderShipment) (*dto.ShipmentTrackingResult, error) {
+	result := new(dto.RajaOngkirWaybillHTTPResponse)
+	res, err := t.client.R().
+		SetContext(ctx).
+		SetFormData(map[string]string{
+			"waybill": shipment.WaybillNumber,
+			"courier": shipment.CourierCode,
+		}).
+		SetResult(result).
+		SetError(result).
+		Post(constant.RajaOngkirWaybillPath)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode() != http.StatusOK || result.RajaOngkir.Status.Code != http.StatusOK {
+		return nil, fmt.Errorf("rajaongkir waybill %s: %s", shipment.WaybillNumber, result.RajaOngkir.Status.Description)
+	}
+
+	loc, err := time.LoadLocation(constant.RajaOngkirManifestTimezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	tracking := &dto.ShipmentTrackingResult{
+		Delivered: result.RajaOngkir.Result.Delivered,
+		Events:    make([]dto.ShipmentTrackingEvent, 0, len(result.RajaOngkir.Result.Manifest)),
+	}
+	for _, m := range result.RajaOngkir.Result.Manifest {
+		occurredAt, err := time.ParseInLocation(constant.RajaOngkirManifestLayout, m.Date+" "+m.Time, loc)
+		if err != nil {
+			return nil, err
+		}
+		tracking.Events = append(tracking.Events, dto.ShipmentTrackingEvent{
+			Description: m.Description,
+			Location:    m.CityName,
+			OccurredAt:  occurredAt,
+		})
+	}
+
+	return tracking, nil
+}
+
+func NewRajaOngkirShipmentTracker(client *resty.Client) ShipmentTracker {
+	return &rajaOngkirShipmentTracker{
+		client: client,
+	}
+}
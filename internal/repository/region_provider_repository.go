@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/lil-oren/rest/internal/dto"
+)
+
+// RegionProvider lists the provinces and cities a shipping provider knows,
+// identified by the provider's own ids.
+type RegionProvider interface {
+	GetProvinces(ctx context.Context) ([]dto.RegionProvince, error)
+	GetCities(ctx context.Context) ([]dto.RegionCity, error)
+}
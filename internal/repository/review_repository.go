@@ -163,6 +163,11 @@ func (r *reviewRepository) Create(ctx context.Context, review *model.Review, med
 		}
 	}
 
+	review.ID = *reviewID
+	if err := createReviewPostedEvent(tx, review); err != nil {
+		return err
+	}
+
 	if err = tx.Commit(); err != nil {
 		return err
 	}
@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/shared"
+)
+
+const (
+	s3Algorithm      = "AWS4-HMAC-SHA256"
+	s3Service        = "s3"
+	s3UnsignedHash   = "UNSIGNED-PAYLOAD"
+	s3AmzDateLayout  = "20060102T150405Z"
+	s3ScopeDayLayout = "20060102"
+)
+
+type (
+	// s3MediaStorage talks to any S3 compatible server (AWS S3, MinIO) with
+	// path style addressing and signature version 4.
+	s3MediaStorage struct {
+		cfg    dependency.Config
+		client *http.Client
+	}
+)
+
+// Put implements MediaStorage.
+func (s *s3MediaStorage) Put(ctx context.Context, key string, contentType string, body io.Reader, size int64) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key), body)
+	if err != nil {
+		return err
+	}
+
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+	s.sign(req, time.Now().UTC())
+
+	return s.do(req, nil)
+}
+
+// Open implements MediaStorage.
+func (s *s3MediaStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.objectURL(key), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	s.sign(req, time.Now().UTC())
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == http.StatusNotFound {
+		res.Body.Close()
+		return nil, shared.ErrMediaNotFound
+	}
+
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		return nil, fmt.Errorf("s3 get object: unexpected status %d", res.StatusCode)
+	}
+
+	return res.Body, nil
+}
+
+// Delete implements MediaStorage.
+func (s *s3MediaStorage) Delete(ctx context.Context, key string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(key), nil)
+	if err != nil {
+		return err
+	}
+
+	s.sign(req, time.Now().UTC())
+
+	return s.do(req, []int{http.StatusNoContent, http.StatusNotFound})
+}
+
+// URL implements MediaStorage. The bucket is private, the url goes through
+// the media files route which only serves unpublished media with a
+// signature, so the plain url of a fresh upload does not expose it.
+func (s *s3MediaStorage) URL(key string) string {
+	return strings.TrimRight(s.cfg.MediaStorage.PublicBaseURL, "/") + constant.MediaFilesPath + s3EscapePath(key)
+}
+
+// SignedURL implements MediaStorage. The url is presigned for a direct
+// read from the bucket.
+func (s *s3MediaStorage) SignedURL(key string, expiration time.Duration) (string, error) {
+	u, err := url.Parse(s.objectURL(key))
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now().UTC()
+	amzDate := now.Format(s3AmzDateLayout)
+	scope := s.scope(now)
+
+	q := url.Values{}
+	q.Set("X-Amz-Algorithm", s3Algorithm)
+	q.Set("X-Amz-Credential", s.cfg.MediaStorage.S3AccessKey+"/"+scope)
+	q.Set("X-Amz-Date", amzDate)
+	q.Set("X-Amz-Expires", strconv.Itoa(int(expiration.Seconds())))
+	q.Set("X-Amz-SignedHeaders", "host")
+
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		u.EscapedPath(),
+		s3CanonicalQuery(q),
+		"host:" + u.Host + "\n",
+		"host",
+		s3UnsignedHash,
+	}, "\n")
+
+	q.Set("X-Amz-Signature", s.signature(now, canonicalRequest))
+	u.RawQuery = s3CanonicalQuery(q)
+
+	return u.String(), nil
+}
+
+func (s *s3MediaStorage) do(req *http.Request, okStatus []int) error {
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	for _, status := range okStatus {
+		if res.StatusCode == status {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("s3 %s object: unexpected status %d", strings.ToLower(req.Method), res.StatusCode)
+}
+
+func (s *s3MediaStorage) objectURL(key string) string {
+	return fmt.Sprintf("%s/%s/%s",
+		strings.TrimRight(s.cfg.MediaStorage.S3Endpoint, "/"),
+		s.cfg.MediaStorage.S3Bucket,
+		s3EscapePath(key),
+	)
+}
+
+func (s *s3MediaStorage) scope(t time.Time) string {
+	return fmt.Sprintf("%s/%s/%s/aws4_request", t.Format(s3ScopeDayLayout), s.cfg.MediaStorage.S3Region, s3Service)
+}
+
+func (s *s3MediaStorage) sign(req *http.Request, now time.Time) {
+	amzDate := now.Format(s3AmzDateLayout)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", s3UnsignedHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := fmt.Sprintf("host:%s\nx-amz-content-sha256:%s\nx-amz-date:%s\n", req.URL.Host, s3UnsignedHash, amzDate)
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		s3CanonicalQuery(req.URL.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		s3UnsignedHash,
+	}, "\n")
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm,
+		s.cfg.MediaStorage.S3AccessKey,
+		s.scope(now),
+		signedHeaders,
+		s.signature(now, canonicalRequest),
+	))
+}
+
+func (s *s3MediaStorage) signature(now time.Time, canonicalRequest string) string {
+	hashed := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		s3Algorithm,
+		now.Format(s3AmzDateLayout),
+		s.scope(now),
+		hex.EncodeToString(hashed[:]),
+	}, "\n")
+
+	key := s3HMAC([]byte("AWS4"+s.cfg.MediaStorage.S3SecretKey), now.Format(s3ScopeDayLayout))
+	key = s3HMAC(key, s.cfg.MediaStorage.S3Region)
+	key = s3HMAC(key, s3Service)
+	key = s3HMAC(key, "aws4_request")
+
+	return hex.EncodeToString(s3HMAC(key, stringToSign))
+}
+
+func s3HMAC(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func s3EscapePath(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = s3Escape(segment)
+	}
+
+	return strings.Join(segments, "/")
+}
+
+func s3Escape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+func s3CanonicalQuery(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		for _, v := range q[k] {
+			pairs = append(pairs, s3Escape(k)+"="+s3Escape(v))
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+func NewS3MediaStorage(cfg dependency.Config) MediaStorage {
+	return &s3MediaStorage{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 60 * time.Second},
+	}
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/model"
+	"github.com/lil-oren/rest/internal/shared"
+)
+
+type (
+	SalesReportJobRepository interface {
+		Create(ctx context.Context, job *model.SalesReportJob) (*int64, error)
+		FirstByIDAndSellerID(ctx context.Context, id, sellerID int64) (*model.SalesReportJob, error)
+		ClaimPending(ctx context.Context, lease time.Duration) (*model.SalesReportJob, error)
+		Finish(ctx context.Context, job *model.SalesReportJob) error
+	}
+	salesReportJobRepository struct {
+		db *sqlx.DB
+	}
+)
+
+// Create implements SalesReportJobRepository.
+func (r *salesReportJobRepository) Create(ctx context.Context, job *model.SalesReportJob) (*int64, error) {
+	qs := `
+	INSERT INTO sales_report_jobs (
+		seller_id,
+		start_date,
+		end_date,
+		order_status,
+		format,
+		status,
+		file_name
+	) VALUES
+	($1, $2, $3, $4, $5, $6, $7)
+	RETURNING (id)
+	`
+
+	id := new(int64)
+	err := r.db.QueryRowxContext(ctx, qs,
+		job.SellerID,
+		job.StartDate,
+		job.EndDate,
+		job.OrderStatus,
+		job.Format,
+		job.Status,
+		job.FileName,
+	).Scan(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return id, nil
+}
+
+// FirstByIDAndSellerID implements SalesReportJobRepository.
+func (r *salesReportJobRepository) FirstByIDAndSellerID(ctx context.Context, id, sellerID int64) (*model.SalesReportJob, error) {
+	job := new(model.SalesReportJob)
+	qs := `
+	SELECT
+		*
+	FROM
+		sales_report_jobs srj
+	WHERE
+		srj.id = $1 AND
+		srj.seller_id = $2 AND
+		srj.deleted_at IS NULL
+	`
+
+	if err := r.db.GetContext(ctx, job, qs, id, sellerID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, shared.ErrSalesReportJobNotFound
+		}
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// ClaimPending implements SalesReportJobRepository. It marks the oldest
+// pending job as processing and returns it, or returns nil when there is none.
+// A job still processing after lease was left by a worker that died and is
+// claimed again.
+func (r *salesReportJobRepository) ClaimPending(ctx context.Context, lease time.Duration) (*model.SalesReportJob, error) {
+	job := new(model.SalesReportJob)
+	qs := `
+	UPDATE sales_report_jobs
+	SET status = $1, updated_at = NOW()
+	WHERE id = (
+		SELECT
+			srj.id
+		FROM
+			sales_report_jobs srj
+		WHERE
+			(
+				srj.status = $2 OR
+				(srj.status = $1 AND srj.updated_at <= NOW() - $3 * INTERVAL '1 second')
+			) AND
+			srj.deleted_at IS NULL
+		ORDER BY srj.id
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING *
+	`
+
+	err := r.db.GetContext(ctx, job, qs, constant.SalesReportStatusProcessing, constant.SalesReportStatusPending, lease.Seconds())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Finish implements SalesReportJobRepository.
+func (r *salesReportJobRepository) Finish(ctx context.Context, job *model.SalesReportJob) error {
+	qs := `
+	UPDATE sales_report_jobs
+	SET
+		status = $1,
+		storage_key = $2,
+		updated_at = NOW(),
+		finished_at = NOW()
+	WHERE id = $3
+	`
+
+	_, err := r.db.ExecContext(ctx, qs,
+		job.Status,
+		job.StorageKey,
+		job.ID,
+	)
+
+	return err
+}
+
+func NewSalesReportJobRepository(db *sqlx.DB) SalesReportJobRepository {
+	return &salesReportJobRepository{
+		db: db,
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lil-oren/rest/internal/constant"
 	"github.com/lil-oren/rest/internal/dto"
 )
 
@@ -74,6 +75,7 @@ func (spr *sellerPageRepository) FindSellerProductBySearchTerm(ctx context.Conte
 				LEFT JOIN categories c ON pc.category_id = c.id 
 			WHERE 
 				p.name ILIKE :search_term
+				AND p.status = :product_status
 				%s
 		) p
 	LEFT JOIN
@@ -127,8 +129,9 @@ func (spr *sellerPageRepository) FindSellerProductBySearchTerm(ctx context.Conte
 	}
 
 	args := map[string]interface{}{
-		"offset":      start,
-		"search_term": searchTerm,
+		"offset":         start,
+		"search_term":    searchTerm,
+		"product_status": constant.ProductStatusActive,
 	}
 
 	condition := "WHERE p.seller_id = %d"
@@ -213,6 +216,7 @@ func (spr *sellerPageRepository) CountProductBySearchTerm(ctx context.Context, p
 				LEFT JOIN categories c ON pc.category_id = c.id 
 			WHERE 
 				p.name ILIKE :search_term
+				AND p.status = :product_status
 				%s
 		) p
 	LEFT JOIN
@@ -264,8 +268,9 @@ func (spr *sellerPageRepository) CountProductBySearchTerm(ctx context.Context, p
 	}
 
 	args := map[string]interface{}{
-		"offset":      start,
-		"search_term": searchTerm,
+		"offset":         start,
+		"search_term":    searchTerm,
+		"product_status": constant.ProductStatusActive,
 	}
 
 	condition := "WHERE p.seller_id = %d"
@@ -341,14 +346,14 @@ func (spr *sellerPageRepository) FindCategoryBySellerId(ctx context.Context, sel
 			product_categories pc 
 			LEFT JOIN products p ON p.id = pc.product_id
 			LEFT JOIN categories c ON c.id = pc.category_id 
-		WHERE p.seller_id = $1
+		WHERE p.seller_id = $1 AND p.status = $2
 		ORDER BY pc.id
 	) AS t
 	WHERE cn > 1 AND rn = 1
 	GROUP BY t.name
 	`
 
-	rows, err := spr.db.QueryxContext(ctx, qs, sellerId)
+	rows, err := spr.db.QueryxContext(ctx, qs, sellerId, constant.ProductStatusActive)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/model"
+)
+
+type (
+	ShipmentTracker interface {
+		Track(ctx context.Context, shipment *model.OrderShipment) (*dto.ShipmentTrackingResult, error)
+	}
+)
+
+// NewShipmentTracker picks the tracker from SHIPMENT_TRACKER, any unknown
+// tracker falls back to the local one.
+func NewShipmentTracker(cfg dependency.Config, client *resty.Client) ShipmentTracker {
+	if constant.ShipmentTrackerDriver(cfg.Shipment.Tracker) == constant.RajaOngkirShipmentTracker {
+		return NewRajaOngkirShipmentTracker(client)
+	}
+
+	return NewLocalShipmentTracker(cfg)
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/shared"
+)
+
+type (
+	ShippingProvider interface {
+		GetRates(ctx context.Context, query dto.ShippingRateQuery) ([]dto.ShippingRate, error)
+	}
+	ShippingProviderRegistry interface {
+		Register(name string, provider ShippingProvider)
+		ProviderFor(courierCode string) (ShippingProvider, error)
+	}
+	shippingProviderRegistry struct {
+		providers map[string]ShippingProvider
+		couriers  map[string]string
+		fallback  string
+	}
+)
+
+// Register implements ShippingProviderRegistry.
+func (r *shippingProviderRegistry) Register(name string, provider ShippingProvider) {
+	r.providers[name] = provider
+}
+
+// ProviderFor implements ShippingProviderRegistry. Couriers missing from
+// SHIPPING_COURIER_PROVIDERS are served by SHIPPING_DEFAULT_PROVIDER.
+func (r *shippingProviderRegistry) ProviderFor(courierCode string) (ShippingProvider, error) {
+	name, ok := r.couriers[strings.ToLower(courierCode)]
+	if !ok {
+		name = r.fallback
+	}
+
+	provider, ok := r.providers[name]
+	if !ok {
+		return nil, shared.ErrShippingServiceUnavailable
+	}
+
+	return provider, nil
+}
+
+func NewShippingProviderRegistry(cfg dependency.Config) ShippingProviderRegistry {
+	couriers := make(map[string]string)
+	for courier, provider := range cfg.ShippingRate.CourierProviders {
+		couriers[strings.ToLower(courier)] = provider
+	}
+
+	return &shippingProviderRegistry{
+		providers: make(map[string]ShippingProvider),
+		couriers:  couriers,
+		fallback:  cfg.ShippingRate.DefaultProvider,
+	}
+}
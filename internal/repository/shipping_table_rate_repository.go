@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/model"
+	"github.com/lil-oren/rest/internal/shared"
+)
+
+type (
+	ShippingTableRateRepository interface {
+		ShippingProvider
+		Create(ctx context.Context, rate *model.ShippingTableRate) (*int64, error)
+		Update(ctx context.Context, rate *model.ShippingTableRate) error
+		Delete(ctx context.Context, id int64) error
+		FirstByID(ctx context.Context, id int64) (*model.ShippingTableRate, error)
+		Find(ctx context.Context, params dto.ShippingTableRateParams) ([]model.ShippingTableRate, error)
+		Count(ctx context.Context, params dto.ShippingTableRateParams) (int, error)
+	}
+	shippingTableRateRepository struct {
+		db *sqlx.DB
+	}
+)
+
+// GetRates implements ShippingProvider. Every service of the courier whose
+// weight tier covers the weight between the two provinces is a rate.
+func (r *shippingTableRateRepository) GetRates(ctx context.Context, query dto.ShippingRateQuery) ([]dto.ShippingRate, error) {
+	tableRates := make([]model.ShippingTableRate, 0)
+	qs := `
+	SELECT
+		*
+	FROM
+		shipping_table_rates str
+	WHERE
+		str.courier_code = $1 AND
+		str.origin_province_id = $2 AND
+		str.destination_province_id = $3 AND
+		str.min_weight <= $4 AND
+		str.max_weight >= $4 AND
+		str.deleted_at IS NULL
+	ORDER BY str.price, str.id
+	`
+
+	err := r.db.SelectContext(ctx, &tableRates, qs,
+		query.CourierCode,
+		query.OriginProvinceID,
+		query.DestinationProvinceID,
+		query.Weight,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rates := make([]dto.ShippingRate, 0, len(tableRates))
+	for _, tr := range tableRates {
+		rates = append(rates, dto.ShippingRate{
+			CourierCode: tr.CourierCode,
+			Service:     tr.Service,
+			Description: tr.Description,
+			Cost:        tr.Price.InexactFloat64(),
+			ETD:         tr.ETD,
+		})
+	}
+
+	return rates, nil
+}
+
+// Create implements ShippingTableRateRepository.
+func (r *shippingTableRateRepository) Create(ctx context.Context, rate *model.ShippingTableRate) (*int64, error) {
+	qs := `
+	INSERT INTO shipping_table_rates (
+		courier_code,
+		service,
+		description,
+		origin_province_id,
+		destination_province_id,
+		min_weight,
+		max_weight,
+		price,
+		etd
+	) VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	RETURNING (id)
+	`
+
+	id := new(int64)
+	err := r.db.QueryRowxContext(ctx, qs,
+		rate.CourierCode,
+		rate.Service,
+		rate.Description,
+		rate.OriginProvinceID,
+		rate.DestinationProvinceID,
+		rate.MinWeight,
+		rate.MaxWeight,
+		rate.Price,
+		rate.ETD,
+	).Scan(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return id, nil
+}
+
+// Update implements ShippingTableRateRepository.
+func (r *shippingTableRateRepository) Update(ctx context.Context, rate *model.ShippingTableRate) error {
+	qs := `
+	UPDATE shipping_table_rates
+	SET
+		courier_code = $1,
+		service = $2,
+		description = $3,
+		origin_province_id = $4,
+		destination_province_id = $5,
+		min_weight = $6,
+		max_weight = $7,
+		price = $8,
+		etd = $9,
+		updated_at = NOW()
+	WHERE id = $10 AND deleted_at IS NULL
+	`
+
+	res, err := r.db.ExecContext(ctx, qs,
+		rate.CourierCode,
+		rate.Service,
+		rate.Description,
+		rate.OriginProvinceID,
+		rate.DestinationProvinceID,
+		rate.MinWeight,
+		rate.MaxWeight,
+		rate.Price,
+		rate.ETD,
+		rate.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return shippingTableRateAffected(res)
+}
+
+// Delete implements ShippingTableRateRepository.
+func (r *shippingTableRateRepository) Delete(ctx context.Context, id int64) error {
+	qs := `
+	UPDATE shipping_table_rates
+	SET deleted_at = NOW()
+	WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	res, err := r.db.ExecContext(ctx, qs, id)
+	if err != nil {
+		return err
+	}
+
+	return shippingTableRateAffected(res)
+}
+
+// FirstByID implements ShippingTableRateRepository.
+func (r *shippingTableRateRepository) FirstByID(ctx context.Context, id int64) (*model.ShippingTableRate, error) {
+	rate := new(model.ShippingTableRate)
+	qs := `
+	SELECT
+		*
+	FROM
+		shipping_table_rates str
+	WHERE
+		str.id = $1 AND
+		str.deleted_at IS NULL
+	`
+
+	if err := r.db.GetContext(ctx, rate, qs, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, shared.ErrShippingTableRateNotFound
+		}
+		return nil, err
+	}
+
+	return rate, nil
+}
+
+// Find implements ShippingTableRateRepository.
+func (r *shippingTableRateRepository) Find(ctx context.Context, params dto.ShippingTableRateParams) ([]model.ShippingTableRate, error) {
+	rates := make([]model.ShippingTableRate, 0)
+	qs := `
+	SELECT
+		*
+	FROM
+		shipping_table_rates str
+	WHERE
+		($1 = '' OR str.courier_code = $1) AND
+		str.deleted_at IS NULL
+	ORDER BY
+		str.courier_code,
+		str.origin_province_id,
+		str.destination_province_id,
+		str.min_weight,
+		str.id
+	LIMIT $2
+	OFFSET $3
+	`
+
+	offset := (params.Page - 1) * constant.ShippingTableRateDefaultItems
+	err := r.db.SelectContext(ctx, &rates, qs, params.CourierCode, constant.ShippingTableRateDefaultItems, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return rates, nil
+}
+
+// Count implements ShippingTableRateRepository.
+func (r *shippingTableRateRepository) Count(ctx context.Context, params dto.ShippingTableRateParams) (int, error) {
+	qs := `
+	SELECT
+		COUNT(1)
+	FROM
+		shipping_table_rates str
+	WHERE
+		($1 = '' OR str.courier_code = $1) AND
+		str.deleted_at IS NULL
+	`
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, qs, params.CourierCode); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func shippingTableRateAffected(res sql.Result) error {
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return shared.ErrShippingTableRateNotFound
+	}
+
+	return nil
+}
+
+func NewShippingTableRateRepository(db *sqlx.DB) ShippingTableRateRepository {
+	return &shippingTableRateRepository{
+		db: db,
+	}
+}
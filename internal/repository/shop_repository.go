@@ -5,8 +5,10 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lil-oren/rest/internal/constant"
 	"github.com/lil-oren/rest/internal/dto"
 	"github.com/lil-oren/rest/internal/model"
+	"github.com/lil-oren/rest/internal/shared"
 )
 
 type (
@@ -15,10 +17,11 @@ type (
 		FirstShopDetailByAccountID(ctx context.Context, id int64) (*dto.ProductPageShop, error)
 		CreateShop(ctx context.Context, payload dto.CreateShopPayload, accountId int) error
 		UpdateShopName(ctx context.Context, shopName string, accountId int) error
+		UpdateShopTier(ctx context.Context, shopID int64, tier string) error
 		UpdateShopAddress(ctx context.Context, addressId int, accountId int) error
 		UpdateShopCourier(ctx context.Context, payload []bool, shopId int) error
-		GetAllProductBySellerId(ctx context.Context, sellerId int, page int) ([]dto.GetAllProduct, error)
-		CountAllProductBySellerId(ctx context.Context, sellerId int) (*int, error)
+		GetAllProductBySellerId(ctx context.Context, payload dto.GetAllProductPayload) ([]dto.GetAllProduct, error)
+		CountAllProductBySellerId(ctx context.Context, sellerId int, status string) (*int, error)
 		FindAllProductDiscountByProductCode(ctx context.Context, sellerId int64, productCode string) ([]dto.GetProductDiscount, error)
 		UpdateProductDiscount(ctx context.Context, payload dto.UpdateProductDiscountPayload, sellerId int64, productCode string) error
 	}
@@ -63,7 +66,8 @@ func (sr *shopRepository) FirstShopById(ctx context.Context, accountId int) (*mo
 	SELECT 
 		id,
 		name,
-		account_id
+		account_id,
+		tier
 	FROM
 		shops
 	WHERE
@@ -152,6 +156,30 @@ func (sr *shopRepository) UpdateShopName(ctx context.Context, shopName string, a
 	return nil
 }
 
+// UpdateShopTier implements ShopRepository.
+func (sr *shopRepository) UpdateShopTier(ctx context.Context, shopID int64, tier string) error {
+	qs := `
+	UPDATE shops
+	SET tier = $1, updated_at = $2
+	WHERE id = $3
+	`
+
+	res, err := sr.db.ExecContext(ctx, qs, tier, time.Now(), shopID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return shared.ErrShopNotFound
+	}
+
+	return nil
+}
+
 func (sr *shopRepository) UpdateShopAddress(ctx context.Context, addressId int, accountId int) error {
 	qs1 := `
 	UPDATE account_addresses
@@ -215,24 +243,26 @@ func (sr *shopRepository) UpdateShopCourier(ctx context.Context, payload []bool,
 	return nil
 }
 
-func (r *shopRepository) GetAllProductBySellerId(ctx context.Context, sellerId int, page int) ([]dto.GetAllProduct, error) {
+func (r *shopRepository) GetAllProductBySellerId(ctx context.Context, payload dto.GetAllProductPayload) ([]dto.GetAllProduct, error) {
 	products := make([]dto.GetAllProduct, 0)
 	query := `
 		SELECT 
 			p.product_code, 
 			p.name AS product_name, 
-			p.thumbnail_url
+			p.thumbnail_url,
+			p.status
 		FROM
 			products p
 		WHERE
 			p.seller_id = $1
+			AND ($3::text = '' OR p.status = $3::text)
 		ORDER BY
 			p.updated_at DESC
 		LIMIT 10
 		OFFSET $2
 		`
 
-	rows, err := r.db.QueryxContext(ctx, query, sellerId, (page-1)*10)
+	rows, err := r.db.QueryxContext(ctx, query, payload.SellerID, (payload.Page-1)*10, payload.Status)
 	if err != nil {
 		return nil, err
 	}
@@ -248,22 +278,24 @@ func (r *shopRepository) GetAllProductBySellerId(ctx context.Context, sellerId i
 	return products, nil
 }
 
-func (r *shopRepository) CountAllProductBySellerId(ctx context.Context, sellerId int) (*int, error) {
+func (r *shopRepository) CountAllProductBySellerId(ctx context.Context, sellerId int, status string) (*int, error) {
 	products := make([]dto.GetAllProduct, 0)
 	query := `
 		SELECT 
 			p.product_code, 
 			p.name AS product_name, 
-			p.thumbnail_url
+			p.thumbnail_url,
+			p.status
 		FROM
 			products p
 		WHERE
 			p.seller_id = $1
+			AND ($2::text = '' OR p.status = $2::text)
 		ORDER BY
 			p.created_at DESC
 	`
 
-	rows, err := r.db.QueryxContext(ctx, query, sellerId)
+	rows, err := r.db.QueryxContext(ctx, query, sellerId, status)
 	if err != nil {
 		return nil, err
 	}
@@ -341,12 +373,33 @@ func (r *shopRepository) UpdateProductDiscount(ctx context.Context, payload dto.
 			p.product_code = $5
 	)
 	`
+	queryFlashSale := `
+	SELECT EXISTS (
+		SELECT
+			1
+		FROM
+			flash_sale_variants fsv
+			JOIN product_variants pv ON pv.id = fsv.product_variant_id
+			JOIN products p ON p.id = pv.product_id
+		WHERE
+			p.product_code = $1 AND fsv.status = $2
+	)
+	`
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
+	var inFlashSale bool
+	err = tx.QueryRowx(queryFlashSale, productCode, constant.FlashSaleStatusActive).Scan(&inFlashSale)
+	if err != nil {
+		return err
+	}
+	if inFlashSale {
+		return shared.ErrProductInFlashSale
+	}
+
 	for _, v := range payload.Variants {
 		_, err := tx.Exec(query, v.Discount, sellerId, v.VariantType1, v.VariantType2, productCode)
 		if err != nil {
@@ -80,6 +80,7 @@ func (r *transactionRepository) FindTransactionByAccountID(
 		t.amount,
 		t.to_wallet_id = w.id AS is_debit,
 		o.id AS order_id,
+		o.service_fee,
 		s.name AS shop_name,
 		t.created_at as date
 	FROM
@@ -143,6 +144,9 @@ func (*transactionRepository) CreateTransaction(tx *sqlx.Tx, transaction *model.
 	if err != nil {
 		return nil, err
 	}
+	if err := createWalletCreditedEvent(tx, id, transaction); err != nil {
+		return nil, err
+	}
 	return &id, nil
 }
 
@@ -180,6 +184,10 @@ func (*transactionRepository) CreateTopupTransaction(tx *sqlx.Tx, transaction *m
 		return nil, err
 	}
 
+	if err := createWalletCreditedEvent(tx, *id, transaction); err != nil {
+		return nil, err
+	}
+
 	return id, nil
 }
 
@@ -0,0 +1,514 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/model"
+	"github.com/lil-oren/rest/internal/shared"
+	"github.com/shopspring/decimal"
+)
+
+type (
+	VoucherRepository interface {
+		Create(ctx context.Context, voucher *model.Voucher, categoryIDs []int64) (*int64, error)
+		FirstByID(ctx context.Context, id int64) (*model.Voucher, error)
+		FirstByCode(ctx context.Context, code string) (*model.Voucher, error)
+		FindByShopID(ctx context.Context, shopID int64, page int) ([]model.Voucher, error)
+		CountByShopID(ctx context.Context, shopID int64) (int, error)
+		FindPlatform(ctx context.Context, page int) ([]model.Voucher, error)
+		CountPlatform(ctx context.Context) (int, error)
+		Update(ctx context.Context, voucher *model.Voucher, categoryIDs []int64) error
+		FindCategoriesByVoucherIDs(ctx context.Context, ids []int64) ([]dto.VoucherCategoryModel, error)
+		FindEligibleProductIDs(ctx context.Context, voucherID int64, productIDs []int64) ([]int64, error)
+		CountUsagesByBuyerID(ctx context.Context, voucherID, buyerID int64) (int, error)
+		SoftDelete(ctx context.Context, id int64) error
+	}
+	voucherRepository struct {
+		db *sqlx.DB
+	}
+)
+
+// Create implements VoucherRepository. Codes are unique among vouchers that
+// are not deleted.
+func (r *voucherRepository) Create(ctx context.Context, voucher *model.Voucher, categoryIDs []int64) (*int64, error) {
+	qs1 := `
+	SELECT EXISTS (
+		SELECT 1 FROM vouchers WHERE code = $1 AND deleted_at IS NULL
+	)
+	`
+
+	qs2 := `
+	INSERT INTO vouchers (
+		code,
+		name,
+		issuer,
+		shop_id,
+		type,
+		value,
+		max_discount,
+		minimum_spend,
+		quota,
+		usage_limit_per_user,
+		started_at,
+		expired_at
+	) VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	RETURNING (id)
+	`
+
+	qs3 := `
+	INSERT INTO voucher_categories (voucher_id, category_id) VALUES
+	($1, $2)
+	`
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var taken bool
+	if err := tx.QueryRowx(qs1, voucher.Code).Scan(&taken); err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, shared.ErrVoucherCodeTaken
+	}
+
+	var id int64
+	err = tx.QueryRowx(qs2,
+		voucher.Code,
+		voucher.Name,
+		voucher.Issuer,
+		voucher.ShopID,
+		voucher.Type,
+		voucher.Value,
+		voucher.MaxDiscount,
+		voucher.MinimumSpend,
+		voucher.Quota,
+		voucher.UsageLimitPerUser,
+		voucher.StartedAt,
+		voucher.ExpiredAt,
+	).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, categoryID := range categoryIDs {
+		if _, err := tx.Exec(qs3, id, categoryID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &id, nil
+}
+
+// FirstByID implements VoucherRepository.
+func (r *voucherRepository) FirstByID(ctx context.Context, id int64) (*model.Voucher, error) {
+	voucher := new(model.Voucher)
+	qs := `SELECT * FROM vouchers WHERE id = $1 AND deleted_at IS NULL`
+
+	err := r.db.GetContext(ctx, voucher, qs, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, shared.ErrVoucherNotFound
+		}
+		return nil, err
+	}
+
+	return voucher, nil
+}
+
+// FirstByCode implements VoucherRepository.
+func (r *voucherRepository) FirstByCode(ctx context.Context, code string) (*model.Voucher, error) {
+	voucher := new(model.Voucher)
+	qs := `SELECT * FROM vouchers WHERE code = $1 AND deleted_at IS NULL`
+
+	err := r.db.GetContext(ctx, voucher, qs, code)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, shared.ErrVoucherNotFound
+		}
+		return nil, err
+	}
+
+	return voucher, nil
+}
+
+// FindByShopID implements VoucherRepository.
+func (r *voucherRepository) FindByShopID(ctx context.Context, shopID int64, page int) ([]model.Voucher, error) {
+	vouchers := make([]model.Voucher, 0)
+	qs := `
+	SELECT * FROM vouchers
+	WHERE shop_id = $1 AND deleted_at IS NULL
+	ORDER BY started_at DESC, id DESC
+	LIMIT $2 OFFSET $3
+	`
+
+	offset := (page - 1) * constant.VoucherDefaultItems
+	err := r.db.SelectContext(ctx, &vouchers, qs, shopID, constant.VoucherDefaultItems, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return vouchers, nil
+}
+
+// CountByShopID implements VoucherRepository.
+func (r *voucherRepository) CountByShopID(ctx context.Context, shopID int64) (int, error) {
+	var count int
+	qs := `SELECT COUNT(id) FROM vouchers WHERE shop_id = $1 AND deleted_at IS NULL`
+
+	err := r.db.GetContext(ctx, &count, qs, shopID)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// FindPlatform implements VoucherRepository.
+func (r *voucherRepository) FindPlatform(ctx context.Context, page int) ([]model.Voucher, error) {
+	vouchers := make([]model.Voucher, 0)
+	qs := `
+	SELECT * FROM vouchers
+	WHERE issuer = $1 AND deleted_at IS NULL
+	ORDER BY started_at DESC, id DESC
+	LIMIT $2 OFFSET $3
+	`
+
+	offset := (page - 1) * constant.VoucherDefaultItems
+	err := r.db.SelectContext(ctx, &vouchers, qs, constant.VoucherIssuerPlatform, constant.VoucherDefaultItems, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return vouchers, nil
+}
+
+// CountPlatform implements VoucherRepository.
+func (r *voucherRepository) CountPlatform(ctx context.Context) (int, error) {
+	var count int
+	qs := `SELECT COUNT(id) FROM vouchers WHERE issuer = $1 AND deleted_at IS NULL`
+
+	err := r.db.GetContext(ctx, &count, qs, constant.VoucherIssuerPlatform)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Update implements VoucherRepository. The voucher row is locked so the
+// quota cannot drop below a usage taken while the update runs. The issuer,
+// shop and usage count are kept.
+func (r *voucherRepository) Update(ctx context.Context, voucher *model.Voucher, categoryIDs []int64) error {
+	qs1 := `SELECT * FROM vouchers WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+
+	qs2 := `
+	SELECT EXISTS (
+		SELECT 1 FROM vouchers WHERE code = $1 AND id != $2 AND deleted_at IS NULL
+	)
+	`
+
+	qs3 := `
+	UPDATE vouchers SET
+		code = $1,
+		name = $2,
+		type = $3,
+		value = $4,
+		max_discount = $5,
+		minimum_spend = $6,
+		quota = $7,
+		usage_limit_per_user = $8,
+		started_at = $9,
+		expired_at = $10,
+		updated_at = now()
+	WHERE id = $11
+	`
+
+	qs4 := `DELETE FROM voucher_categories WHERE voucher_id = $1`
+
+	qs5 := `
+	INSERT INTO voucher_categories (voucher_id, category_id) VALUES
+	($1, $2)
+	`
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	current := new(model.Voucher)
+	if err := tx.Get(current, qs1, voucher.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return shared.ErrVoucherNotFound
+		}
+		return err
+	}
+	if voucher.Quota < current.Used {
+		return shared.ErrVoucherQuotaBelowUsed
+	}
+
+	var taken bool
+	if err := tx.QueryRowx(qs2, voucher.Code, voucher.ID).Scan(&taken); err != nil {
+		return err
+	}
+	if taken {
+		return shared.ErrVoucherCodeTaken
+	}
+
+	_, err = tx.Exec(qs3,
+		voucher.Code,
+		voucher.Name,
+		voucher.Type,
+		voucher.Value,
+		voucher.MaxDiscount,
+		voucher.MinimumSpend,
+		voucher.Quota,
+		voucher.UsageLimitPerUser,
+		voucher.StartedAt,
+		voucher.ExpiredAt,
+		voucher.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(qs4, voucher.ID); err != nil {
+		return err
+	}
+	for _, categoryID := range categoryIDs {
+		if _, err := tx.Exec(qs5, voucher.ID, categoryID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// FindCategoriesByVoucherIDs implements VoucherRepository.
+func (r *voucherRepository) FindCategoriesByVoucherIDs(ctx context.Context, ids []int64) ([]dto.VoucherCategoryModel, error) {
+	categories := make([]dto.VoucherCategoryModel, 0)
+	if len(ids) == 0 {
+		return categories, nil
+	}
+
+	qs, args, err := sqlx.In(`SELECT voucher_id, category_id FROM voucher_categories WHERE voucher_id IN (?) ORDER BY voucher_id, category_id`, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.SelectContext(ctx, &categories, r.db.Rebind(qs), args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+// FindEligibleProductIDs implements VoucherRepository. A product is eligible
+// when one of its categories, or the parent of one, is whitelisted by the
+// voucher.
+func (r *voucherRepository) FindEligibleProductIDs(ctx context.Context, voucherID int64, productIDs []int64) ([]int64, error) {
+	ids := make([]int64, 0)
+	if len(productIDs) == 0 {
+		return ids, nil
+	}
+
+	qs, args, err := sqlx.In(`
+	SELECT
+		DISTINCT pc.product_id
+	FROM product_categories pc
+	JOIN categories c ON c.id = pc.category_id
+	JOIN voucher_categories vc ON vc.category_id IN (c.id, c.parent_category)
+	WHERE vc.voucher_id = ? AND pc.product_id IN (?)
+	`, voucherID, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.SelectContext(ctx, &ids, r.db.Rebind(qs), args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// CountUsagesByBuyerID implements VoucherRepository. Usages released by a
+// cancelled order are not counted.
+func (r *voucherRepository) CountUsagesByBuyerID(ctx context.Context, voucherID, buyerID int64) (int, error) {
+	var count int
+	qs := `
+	SELECT COUNT(id) FROM voucher_usages
+	WHERE voucher_id = $1 AND buyer_id = $2 AND released_at IS NULL
+	`
+
+	err := r.db.GetContext(ctx, &count, qs, voucherID, buyerID)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// SoftDelete implements VoucherRepository.
+func (r *voucherRepository) SoftDelete(ctx context.Context, id int64) error {
+	qs := `UPDATE vouchers SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`
+
+	_, err := r.db.ExecContext(ctx, qs, time.Now(), id)
+	return err
+}
+
+// RedeemVoucher takes one use of the voucher for the buyer within tx and
+// returns the id of the usage. The voucher row is locked so concurrent
+// checkouts cannot go over the quota or the per-user limit.
+func RedeemVoucher(tx *sqlx.Tx, voucherID, buyerID int64) (int64, error) {
+	qs1 := `SELECT * FROM vouchers WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+
+	qs2 := `
+	SELECT COUNT(id) FROM voucher_usages
+	WHERE voucher_id = $1 AND buyer_id = $2 AND released_at IS NULL
+	`
+
+	qs3 := `
+	INSERT INTO voucher_usages (voucher_id, buyer_id) VALUES
+	($1, $2)
+	RETURNING (id)
+	`
+
+	qs4 := `UPDATE vouchers SET used = used + 1, updated_at = now() WHERE id = $1`
+
+	voucher := new(model.Voucher)
+	if err := tx.Get(voucher, qs1, voucherID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, shared.ErrVoucherNotFound
+		}
+		return 0, err
+	}
+
+	now := time.Now()
+	if now.Before(voucher.StartedAt) {
+		return 0, shared.ErrVoucherNotStarted
+	}
+	if !now.Before(voucher.ExpiredAt) {
+		return 0, shared.ErrVoucherExpired
+	}
+	if voucher.Used >= voucher.Quota {
+		return 0, shared.ErrVoucherQuotaExceeded
+	}
+
+	var used int
+	if err := tx.Get(&used, qs2, voucherID, buyerID); err != nil {
+		return 0, err
+	}
+	if used >= voucher.UsageLimitPerUser {
+		return 0, shared.ErrVoucherUsageLimit
+	}
+
+	var usageID int64
+	if err := tx.QueryRowx(qs3, voucherID, buyerID).Scan(&usageID); err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.Exec(qs4, voucherID); err != nil {
+		return 0, err
+	}
+
+	return usageID, nil
+}
+
+// CreateOrderVoucher records the part of a voucher usage applied to an order.
+func CreateOrderVoucher(tx *sqlx.Tx, orderID, usageID int64, voucher dto.OrderVoucher) error {
+	qs := `
+	INSERT INTO order_vouchers (order_id, voucher_usage_id, voucher_id, issuer, amount) VALUES
+	($1, $2, $3, $4, $5)
+	`
+
+	_, err := tx.Exec(qs, orderID, usageID, voucher.VoucherID, voucher.Issuer, voucher.Amount)
+	return err
+}
+
+// ReleaseVoucher gives back the vouchers of a cancelled order within tx. A
+// usage shared by several orders, like a platform voucher, is only returned
+// to the quota once all of its orders are cancelled.
+func ReleaseVoucher(tx *sqlx.Tx, orderID int64) error {
+	qs1 := `
+	UPDATE order_vouchers
+	SET released_at = now()
+	WHERE order_id = $1 AND released_at IS NULL
+	RETURNING voucher_usage_id
+	`
+
+	qs2 := `
+	UPDATE voucher_usages vu
+	SET released_at = now()
+	WHERE vu.id = $1
+	AND vu.released_at IS NULL
+	AND NOT EXISTS (
+		SELECT 1 FROM order_vouchers ov
+		WHERE ov.voucher_usage_id = vu.id AND ov.released_at IS NULL
+	)
+	RETURNING vu.voucher_id
+	`
+
+	qs3 := `UPDATE vouchers SET used = used - 1, updated_at = now() WHERE id = $1 AND used > 0`
+
+	usageIDs := make([]int64, 0)
+	if err := tx.Select(&usageIDs, qs1, orderID); err != nil {
+		return err
+	}
+
+	for _, usageID := range usageIDs {
+		var voucherID int64
+		err := tx.QueryRowx(qs2, usageID).Scan(&voucherID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			return err
+		}
+
+		if _, err := tx.Exec(qs3, voucherID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PlatformVoucherSubsidy returns the amount of platform vouchers applied to an
+// order. The platform pays it to the seller when the order is received.
+func PlatformVoucherSubsidy(tx *sqlx.Tx, orderID int64) (decimal.Decimal, error) {
+	qs := `
+	SELECT
+		COALESCE(SUM(amount), 0)
+	FROM order_vouchers
+	WHERE order_id = $1 AND issuer = $2 AND released_at IS NULL
+	`
+
+	var amount decimal.Decimal
+	if err := tx.Get(&amount, qs, orderID, constant.VoucherIssuerPlatform); err != nil {
+		return decimal.Zero, err
+	}
+
+	return amount, nil
+}
+
+func NewVoucherRepository(db *sqlx.DB) VoucherRepository {
+	return &voucherRepository{
+		db: db,
+	}
+}
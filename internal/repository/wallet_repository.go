@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/lil-oren/rest/internal/constant"
@@ -21,6 +23,8 @@ type (
 		WithdrawShopUser(ctx context.Context, accountId int64, transaction *model.Transaction) error
 		Topup(ctx context.Context, accountId int64, transaction *model.Transaction) error
 		FirstShopWalletBalanceBySellerID(ctx context.Context, sellerID int64) (*dto.ShopWalletBalanceResponse, error)
+		CreatePlatformWallet(ctx context.Context, accountId int64) error
+		FirstPlatformWallet(ctx context.Context) (*model.Wallet, error)
 	}
 	walletRepository struct {
 		db *sqlx.DB
@@ -419,6 +423,90 @@ func (r *walletRepository) FirstShopWalletBalanceBySellerID(ctx context.Context,
 	return balance, nil
 }
 
+// CreatePlatformWallet implements WalletRepository. The platform has a
+// single wallet, so nothing is made when one already exists.
+func (r *walletRepository) CreatePlatformWallet(ctx context.Context, accountId int64) error {
+	qs := `
+	INSERT INTO wallets (balance, is_active, category, account_id)
+	SELECT $1, $2, $3, $4
+	WHERE NOT EXISTS (
+		SELECT 1 FROM wallets w WHERE w.category = $3
+	)
+	`
+
+	_, err := r.db.ExecContext(ctx, qs, 0, true, constant.PlatformWalletType, accountId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FirstPlatformWallet implements WalletRepository.
+func (r *walletRepository) FirstPlatformWallet(ctx context.Context) (*model.Wallet, error) {
+	wallet := new(model.Wallet)
+	qs := `SELECT * FROM wallets w WHERE w.category = $1 AND w.is_active`
+
+	if err := r.db.GetContext(ctx, wallet, qs, constant.PlatformWalletType); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, shared.ErrPlatformWalletNotFound
+		}
+		return nil, err
+	}
+
+	return wallet, nil
+}
+
+// PlatformWalletID locks the wallet the platform earns into within tx.
+func PlatformWalletID(tx *sqlx.Tx) (int64, error) {
+	var id int64
+	qs := `SELECT id FROM wallets WHERE category = $1 AND is_active FOR UPDATE`
+
+	if err := tx.Get(&id, qs, constant.PlatformWalletType); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, shared.ErrPlatformWalletNotFound
+		}
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// TransferWallets moves amount between two active wallets within tx.
+func TransferWallets(tx *sqlx.Tx, fromWalletID, toWalletID int64, amount decimal.Decimal) error {
+	query1 := `
+	UPDATE wallets
+	SET balance = balance-$1
+	WHERE id = $2 AND is_active
+	`
+
+	query2 := `
+	UPDATE wallets
+	SET balance = balance+$1
+	WHERE id = $2 AND is_active
+	`
+
+	row1, err := tx.Exec(query1, amount, fromWalletID)
+	if err != nil {
+		return err
+	}
+	aff1, _ := row1.RowsAffected()
+	if aff1 == 0 {
+		return shared.ErrUpdateInactiveWallet
+	}
+
+	row2, err := tx.Exec(query2, amount, toWalletID)
+	if err != nil {
+		return err
+	}
+	aff2, _ := row2.RowsAffected()
+	if aff2 == 0 {
+		return shared.ErrUpdateInactiveWallet
+	}
+
+	return nil
+}
+
 func NewWalletRepository(db *sqlx.DB, tr TransactionRepository) WalletRepository {
 	return &walletRepository{
 		db: db,
@@ -0,0 +1,421 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/model"
+	"github.com/lil-oren/rest/internal/shared"
+)
+
+type (
+	WebhookRepository interface {
+		CreateEndpoint(ctx context.Context, endpoint *model.WebhookEndpoint) error
+		CountEndpointsBySellerID(ctx context.Context, sellerID int64) (int, error)
+		FindEndpointsBySellerID(ctx context.Context, sellerID int64) ([]model.WebhookEndpoint, error)
+		FirstEndpoint(ctx context.Context, id, sellerID int64) (*model.WebhookEndpoint, error)
+		UpdateEndpoint(ctx context.Context, endpoint *model.WebhookEndpoint) error
+		DeleteEndpoint(ctx context.Context, id, sellerID int64) error
+		EnqueueDeliveries(ctx context.Context, sellerID, eventID int64, eventType constant.WebhookEventType, payload []byte) error
+		CreateDelivery(ctx context.Context, delivery *model.WebhookDelivery, lease time.Duration) error
+		ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]dto.WebhookDeliveryJob, error)
+		MarkDelivered(ctx context.Context, id int64, result dto.WebhookResult) error
+		MarkDeliveryFailed(ctx context.Context, id int64, status string, retryAt time.Time, result *dto.WebhookResult, reason string) error
+		FindDeliveries(ctx context.Context, sellerID int64, params dto.WebhookDeliveryParams) ([]model.WebhookDelivery, error)
+		CountDeliveries(ctx context.Context, sellerID int64, params dto.WebhookDeliveryParams) (int, error)
+		Redeliver(ctx context.Context, id, sellerID int64) error
+	}
+	webhookRepository struct {
+		db *sqlx.DB
+	}
+)
+
+// CreateEndpoint implements WebhookRepository. The id and timestamps of the
+// stored endpoint are set on endpoint.
+func (r *webhookRepository) CreateEndpoint(ctx context.Context, endpoint *model.WebhookEndpoint) error {
+	qs := `
+	INSERT INTO webhook_endpoints (
+		seller_id,
+		url,
+		secret,
+		event_types,
+		is_active
+	) VALUES
+	($1, $2, $3, $4, $5)
+	RETURNING id, created_at, updated_at
+	`
+
+	return r.db.QueryRowxContext(ctx, qs,
+		endpoint.SellerID,
+		endpoint.URL,
+		endpoint.Secret,
+		endpoint.EventTypes,
+		endpoint.IsActive,
+	).Scan(&endpoint.ID, &endpoint.CreatedAt, &endpoint.UpdatedAt)
+}
+
+// CountEndpointsBySellerID implements WebhookRepository.
+func (r *webhookRepository) CountEndpointsBySellerID(ctx context.Context, sellerID int64) (int, error) {
+	qs := `
+	SELECT
+		COUNT(1)
+	FROM
+		webhook_endpoints we
+	WHERE
+		we.seller_id = $1 AND
+		we.deleted_at IS NULL
+	`
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, qs, sellerID); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// FindEndpointsBySellerID implements WebhookRepository.
+func (r *webhookRepository) FindEndpointsBySellerID(ctx context.Context, sellerID int64) ([]model.WebhookEndpoint, error) {
+	endpoints := make([]model.WebhookEndpoint, 0)
+	qs := `
+	SELECT
+		*
+	FROM
+		webhook_endpoints we
+	WHERE
+		we.seller_id = $1 AND
+		we.deleted_at IS NULL
+	ORDER BY we.id
+	`
+
+	if err := r.db.SelectContext(ctx, &endpoints, qs, sellerID); err != nil {
+		return nil, err
+	}
+
+	return endpoints, nil
+}
+
+// FirstEndpoint implements WebhookRepository.
+func (r *webhookRepository) FirstEndpoint(ctx context.Context, id, sellerID int64) (*model.WebhookEndpoint, error) {
+	endpoint := new(model.WebhookEndpoint)
+	qs := `
+	SELECT
+		*
+	FROM
+		webhook_endpoints we
+	WHERE
+		we.id = $1 AND
+		we.seller_id = $2 AND
+		we.deleted_at IS NULL
+	`
+
+	err := r.db.GetContext(ctx, endpoint, qs, id, sellerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, shared.ErrWebhookNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return endpoint, nil
+}
+
+// UpdateEndpoint implements WebhookRepository.
+func (r *webhookRepository) UpdateEndpoint(ctx context.Context, endpoint *model.WebhookEndpoint) error {
+	qs := `
+	UPDATE webhook_endpoints
+	SET url = $1, event_types = $2, is_active = $3, updated_at = NOW()
+	WHERE id = $4 AND seller_id = $5 AND deleted_at IS NULL
+	RETURNING updated_at
+	`
+
+	err := r.db.QueryRowxContext(ctx, qs,
+		endpoint.URL,
+		endpoint.EventTypes,
+		endpoint.IsActive,
+		endpoint.ID,
+		endpoint.SellerID,
+	).Scan(&endpoint.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return shared.ErrWebhookNotFound
+	}
+
+	return err
+}
+
+// DeleteEndpoint implements WebhookRepository. Deliveries that are still
+// pending for the endpoint are not sent anymore.
+func (r *webhookRepository) DeleteEndpoint(ctx context.Context, id, sellerID int64) error {
+	qs := `
+	UPDATE webhook_endpoints
+	SET is_active = FALSE, deleted_at = NOW(), updated_at = NOW()
+	WHERE id = $1 AND seller_id = $2 AND deleted_at IS NULL
+	`
+
+	res, err := r.db.ExecContext(ctx, qs, id, sellerID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return shared.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// EnqueueDeliveries implements WebhookRepository. It queues a delivery of
+// payload to every active endpoint of the seller that subscribed to
+// eventType. An endpoint gets an event only once, so enqueueing the same
+// event again adds nothing.
+func (r *webhookRepository) EnqueueDeliveries(ctx context.Context, sellerID, eventID int64, eventType constant.WebhookEventType, payload []byte) error {
+	qs := `
+	INSERT INTO webhook_deliveries (
+		endpoint_id,
+		event_id,
+		event_type,
+		payload,
+		status
+	)
+	SELECT
+		we.id,
+		$2,
+		$3,
+		$4,
+		$5
+	FROM
+		webhook_endpoints we
+	WHERE
+		we.seller_id = $1 AND
+		$3 = ANY(we.event_types) AND
+		we.is_active AND
+		we.deleted_at IS NULL
+	ON CONFLICT (endpoint_id, event_id) DO NOTHING
+	`
+
+	_, err := r.db.ExecContext(ctx, qs, sellerID, eventID, eventType, payload, constant.WebhookDeliveryPending)
+	return err
+}
+
+// CreateDelivery implements WebhookRepository. The delivery counts as
+// being sent by the caller, so it is not claimed before lease has passed.
+func (r *webhookRepository) CreateDelivery(ctx context.Context, delivery *model.WebhookDelivery, lease time.Duration) error {
+	qs := `
+	INSERT INTO webhook_deliveries (
+		endpoint_id,
+		event_type,
+		payload,
+		status,
+		attempts,
+		available_at
+	) VALUES
+	($1, $2, $3, $4, 1, NOW() + $5 * INTERVAL '1 second')
+	RETURNING *
+	`
+
+	return r.db.GetContext(ctx, delivery, qs,
+		delivery.EndpointID,
+		delivery.EventType,
+		delivery.Payload,
+		constant.WebhookDeliveryPending,
+		lease.Seconds(),
+	)
+}
+
+// ClaimDeliveries implements WebhookRepository. It takes up to limit due
+// deliveries and counts an attempt for each of them. A claimed delivery is
+// not due again until lease has passed, so one whose sender died before
+// marking it is sent again later.
+func (r *webhookRepository) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]dto.WebhookDeliveryJob, error) {
+	jobs := make([]dto.WebhookDeliveryJob, 0)
+	qs := `
+	WITH claimed AS (
+		UPDATE webhook_deliveries
+		SET
+			attempts = attempts + 1,
+			available_at = NOW() + $1 * INTERVAL '1 second',
+			updated_at = NOW()
+		WHERE id IN (
+			SELECT
+				wd.id
+			FROM
+				webhook_deliveries wd
+				JOIN webhook_endpoints we ON we.id = wd.endpoint_id
+			WHERE
+				wd.status = $2 AND
+				wd.available_at <= NOW() AND
+				we.is_active AND
+				we.deleted_at IS NULL
+			ORDER BY wd.id
+			LIMIT $3
+			FOR UPDATE OF wd SKIP LOCKED
+		)
+		RETURNING *
+	)
+	SELECT
+		c.id,
+		c.endpoint_id,
+		c.event_type,
+		c.payload,
+		c.attempts,
+		c.created_at,
+		we.url,
+		we.secret
+	FROM
+		claimed c
+		JOIN webhook_endpoints we ON we.id = c.endpoint_id
+	ORDER BY c.id
+	`
+
+	err := r.db.SelectContext(ctx, &jobs, qs, lease.Seconds(), constant.WebhookDeliveryPending, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// MarkDelivered implements WebhookRepository.
+func (r *webhookRepository) MarkDelivered(ctx context.Context, id int64, result dto.WebhookResult) error {
+	qs := `
+	UPDATE webhook_deliveries
+	SET
+		status = $1,
+		response_status = $2,
+		response_body = $3,
+		last_error = NULL,
+		delivered_at = NOW(),
+		updated_at = NOW()
+	WHERE id = $4
+	`
+
+	_, err := r.db.ExecContext(ctx, qs, constant.WebhookDeliverySucceeded, result.StatusCode, result.Body, id)
+	return err
+}
+
+// MarkDeliveryFailed implements WebhookRepository. result is nil when the
+// endpoint could not be reached at all.
+func (r *webhookRepository) MarkDeliveryFailed(ctx context.Context, id int64, status string, retryAt time.Time, result *dto.WebhookResult, reason string) error {
+	qs := `
+	UPDATE webhook_deliveries
+	SET
+		status = $1,
+		available_at = $2,
+		response_status = $3,
+		response_body = $4,
+		last_error = $5,
+		updated_at = NOW()
+	WHERE id = $6
+	`
+
+	responseStatus := sql.NullInt64{}
+	responseBody := sql.NullString{}
+	if result != nil {
+		responseStatus = sql.NullInt64{Int64: int64(result.StatusCode), Valid: true}
+		responseBody = sql.NullString{String: result.Body, Valid: true}
+	}
+
+	_, err := r.db.ExecContext(ctx, qs, status, retryAt, responseStatus, responseBody, reason, id)
+	return err
+}
+
+// FindDeliveries implements WebhookRepository.
+func (r *webhookRepository) FindDeliveries(ctx context.Context, sellerID int64, params dto.WebhookDeliveryParams) ([]model.WebhookDelivery, error) {
+	deliveries := make([]model.WebhookDelivery, 0)
+	qs := `
+	SELECT
+		wd.*
+	FROM
+		webhook_deliveries wd
+		JOIN webhook_endpoints we ON we.id = wd.endpoint_id
+	WHERE
+		we.seller_id = $1 AND
+		($2 = 0 OR wd.endpoint_id = $2) AND
+		($3 = '' OR wd.status = $3) AND
+		we.deleted_at IS NULL
+	ORDER BY wd.id DESC
+	LIMIT $4
+	OFFSET $5
+	`
+
+	offset := (params.Page - 1) * constant.WebhookDefaultItems
+	err := r.db.SelectContext(ctx, &deliveries, qs, sellerID, params.EndpointID, params.Status, constant.WebhookDefaultItems, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// CountDeliveries implements WebhookRepository.
+func (r *webhookRepository) CountDeliveries(ctx context.Context, sellerID int64, params dto.WebhookDeliveryParams) (int, error) {
+	qs := `
+	SELECT
+		COUNT(1)
+	FROM
+		webhook_deliveries wd
+		JOIN webhook_endpoints we ON we.id = wd.endpoint_id
+	WHERE
+		we.seller_id = $1 AND
+		($2 = 0 OR wd.endpoint_id = $2) AND
+		($3 = '' OR wd.status = $3) AND
+		we.deleted_at IS NULL
+	`
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, qs, sellerID, params.EndpointID, params.Status); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Redeliver implements WebhookRepository. The delivery is queued again with
+// a fresh set of attempts, whatever became of it before.
+func (r *webhookRepository) Redeliver(ctx context.Context, id, sellerID int64) error {
+	qs := `
+	UPDATE webhook_deliveries wd
+	SET
+		status = $1,
+		attempts = 0,
+		available_at = NOW(),
+		updated_at = NOW()
+	FROM
+		webhook_endpoints we
+	WHERE
+		wd.id = $2 AND
+		we.id = wd.endpoint_id AND
+		we.seller_id = $3 AND
+		we.deleted_at IS NULL
+	`
+
+	res, err := r.db.ExecContext(ctx, qs, constant.WebhookDeliveryPending, id, sellerID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return shared.ErrWebhookDeliveryNotFound
+	}
+
+	return nil
+}
+
+func NewWebhookRepository(db *sqlx.DB) WebhookRepository {
+	return &webhookRepository{
+		db: db,
+	}
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/shared"
+)
+
+type (
+	// WebhookSender posts signed webhook bodies to the endpoints of sellers.
+	WebhookSender interface {
+		CheckURL(ctx context.Context, rawURL string) error
+		Send(ctx context.Context, url, secret string, deliveryID int64, eventType string, body []byte) (*dto.WebhookResult, error)
+	}
+	httpWebhookSender struct {
+		client    *http.Client
+		allowHTTP bool
+		resolver  *net.Resolver
+	}
+)
+
+// CheckURL implements WebhookSender. Endpoints are called from inside the
+// network of the platform, so a url is only allowed when every address its
+// host resolves to is public. Plain http is allowed in development only.
+func (s *httpWebhookSender) CheckURL(ctx context.Context, rawURL string) error {
+	u, err := s.parseURL(rawURL)
+	if err != nil {
+		return err
+	}
+
+	addrs, err := s.resolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return shared.ErrWebhookURLNotAllowed
+	}
+	for _, addr := range addrs {
+		if !dependency.IsPublicIP(addr.IP) {
+			return shared.ErrWebhookURLNotAllowed
+		}
+	}
+
+	return nil
+}
+
+// Send implements WebhookSender. It fails when the endpoint cannot be
+// reached or does not answer with a 2xx status, the result is returned
+// whenever the endpoint answered. Redirects are not followed.
+func (s *httpWebhookSender) Send(ctx context.Context, url, secret string, deliveryID int64, eventType string, body []byte) (*dto.WebhookResult, error) {
+	if _, err := s.parseURL(url); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", constant.WebhookUserAgent)
+	req.Header.Set(constant.WebhookEventHeader, eventType)
+	req.Header.Set(constant.WebhookDeliveryHeader, strconv.FormatInt(deliveryID, 10))
+	req.Header.Set(constant.WebhookSignatureHeader, SignWebhookBody(secret, time.Now().Unix(), body))
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	resBody, err := io.ReadAll(io.LimitReader(res.Body, constant.WebhookResponseLimit))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.WebhookResult{
+		StatusCode: res.StatusCode,
+		Body:       string(resBody),
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return result, fmt.Errorf("endpoint responded with status %d", res.StatusCode)
+	}
+
+	return result, nil
+}
+
+func (s *httpWebhookSender) parseURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" || u.User != nil {
+		return nil, shared.ErrWebhookURLNotAllowed
+	}
+	if u.Scheme != "https" && !(s.allowHTTP && u.Scheme == "http") {
+		return nil, shared.ErrWebhookURLNotAllowed
+	}
+
+	return u, nil
+}
+
+// SignWebhookBody returns the signature header of a webhook body sent at
+// timestamp. Endpoints check it the same way and reject stale timestamps
+// to keep old requests from being replayed.
+func SignWebhookBody(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(fmt.Sprintf("%d.", timestamp)))
+	mac.Write(body)
+	return fmt.Sprintf(constant.WebhookSignatureTemplate, timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+func NewWebhookSender(cfg dependency.Config) WebhookSender {
+	client := dependency.NewPublicHTTPClient(time.Duration(cfg.Webhook.Timeout) * time.Second)
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	return &httpWebhookSender{
+		client:    client,
+		allowHTTP: cfg.App.OriginDomain == "localhost",
+		resolver:  net.DefaultResolver,
+	}
+}
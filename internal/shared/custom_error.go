@@ -84,6 +84,8 @@ var (
 	ErrShopNameIsNull                = NewCustomError(BadRequest, "Seller not yet set shop name")
 	ErrProductNotFromSeller          = NewCustomError(BadRequest, "Seller do not own this product")
 	ErrDeleteProduct                 = NewCustomError(InternalServer, "Failed delete product")
+	ErrInvalidProductSchedule        = NewCustomError(BadRequest, "Invalid product publish schedule")
+	ErrProductNotActive              = NewCustomError(BadRequest, "Product is not available")
 
 	// cart
 	ErrDifferentSeller       = NewCustomError(BadRequest, "Product from the shop not found")
@@ -129,6 +131,126 @@ var (
 	ErrPromotionNotFound   = NewCustomError(NotFound, "Promotion did not exist")
 	ErrFindPromotion       = NewCustomError(InternalServer, "Failed find promotion")
 	ErrPromoNotFound       = NewCustomError(NotFound, "Shop promotion not found")
+	ErrPromotionQuotaEmpty = NewCustomError(BadRequest, "Promotion quota has run out")
+	ErrPromotionUsageLimit = NewCustomError(BadRequest, "Promotion usage limit reached")
+
+	// search
+	ErrInvalidMinRating = NewCustomError(BadRequest, "Minimum rating must be between 0 and 5")
+
+	// media
+	ErrMediaNotFound         = NewCustomError(NotFound, "Media not found")
+	ErrMediaTypeNotAllowed   = NewCustomError(BadRequest, "Media type not allowed")
+	ErrMediaTooLarge         = NewCustomError(BadRequest, "Media size exceeds the limit")
+	ErrImageTooLarge         = NewCustomError(BadRequest, "Image dimensions exceed the limit")
+	ErrMediaNotOwned         = NewCustomError(BadRequest, "Media does not belong to this user")
+	ErrFetchMedia            = NewCustomError(BadRequest, "Media could not be downloaded from its url")
+	ErrInvalidMediaSignature = NewCustomError(Forbidden, "Invalid or expired media signature")
+	ErrUploadMedia           = NewCustomError(InternalServer, "Failed upload media")
+
+	// product import
+	ErrInvalidXLSX                = NewCustomError(BadRequest, "Invalid xlsx file")
+	ErrInvalidImportFile          = NewCustomError(BadRequest, "Invalid import file")
+	ErrInvalidImportTemplate      = NewCustomError(BadRequest, "Import file does not follow the template")
+	ErrImportTooManyRows          = NewCustomError(BadRequest, "Import file has too many rows")
+	ErrProductImportJobNotFound   = NewCustomError(NotFound, "Product import job not found")
+	ErrInvalidExportFormat        = NewCustomError(BadRequest, "Invalid export format")
+	ErrImportValueRequired        = NewCustomError(BadRequest, "Value is required")
+	ErrImportValueNotNumber       = NewCustomError(BadRequest, "Value must be a number")
+	ErrImportSingleVariantRow     = NewCustomError(BadRequest, "Product without variant must have exactly one row")
+	ErrImportVariantGroupMismatch = NewCustomError(BadRequest, "Variant group differs from the first row of the product")
+	ErrImportProduct              = NewCustomError(InternalServer, "Failed import product")
+
+	// inventory
+	ErrProductVariantNotFound = NewCustomError(NotFound, "Product variant not found")
+	ErrDuplicateStockItem     = NewCustomError(BadRequest, "Product variant is listed more than once")
+	ErrFindStockHistory       = NewCustomError(InternalServer, "Failed find stock history")
+	ErrUpdateStock            = NewCustomError(InternalServer, "Failed update stock")
+
+	// flash sale
+	ErrFlashSaleNotFound         = NewCustomError(NotFound, "Flash sale not found")
+	ErrInvalidFlashSalePeriod    = NewCustomError(BadRequest, "Flash sale must end after it starts and in the future")
+	ErrInvalidFlashSaleDiscount  = NewCustomError(BadRequest, "Set either a discount or a discounted price lower than the price")
+	ErrDuplicateFlashSaleVariant = NewCustomError(BadRequest, "Product variant is listed more than once")
+	ErrFlashSaleOverlap          = NewCustomError(BadRequest, "Product variant already has a flash sale in this period")
+	ErrFlashSaleEnded            = NewCustomError(BadRequest, "Flash sale has already ended")
+	ErrFlashSalePurchaseLimit    = NewCustomError(BadRequest, "Quantity exceeds the flash sale purchase limit")
+	ErrFlashSaleSoldOut          = NewCustomError(BadRequest, "Flash sale stock is sold out")
+	ErrProductInFlashSale        = NewCustomError(BadRequest, "Cannot change discount of a product in a running flash sale")
+	ErrCreateFlashSale           = NewCustomError(InternalServer, "Failed create flash sale")
+
+	// voucher
+	ErrVoucherNotFound          = NewCustomError(NotFound, "Voucher not found")
+	ErrVoucherCodeTaken         = NewCustomError(BadRequest, "Voucher code is already used")
+	ErrInvalidVoucherValue      = NewCustomError(BadRequest, "Voucher value is invalid for its type")
+	ErrVoucherNotStarted        = NewCustomError(BadRequest, "Voucher is not active yet")
+	ErrVoucherExpired           = NewCustomError(BadRequest, "Voucher has expired")
+	ErrVoucherQuotaExceeded     = NewCustomError(BadRequest, "Voucher quota has run out")
+	ErrVoucherUsageLimit        = NewCustomError(BadRequest, "Voucher usage limit reached")
+	ErrVoucherMinimumSpend      = NewCustomError(BadRequest, "Minimum spend for voucher is not reached")
+	ErrVoucherNotApplicable     = NewCustomError(BadRequest, "Voucher cannot be used for this shop")
+	ErrVoucherNoEligibleProduct = NewCustomError(BadRequest, "No product in cart is eligible for voucher")
+	ErrInvalidVoucherDate       = NewCustomError(BadRequest, "Voucher dates must be formatted as YYYY-MM-DD hh:mm:ss")
+	ErrVoucherQuotaBelowUsed    = NewCustomError(BadRequest, "Voucher quota cannot be lower than its usage")
+	ErrFindVoucher              = NewCustomError(InternalServer, "Failed find voucher")
+
+	// dashboard
+	ErrInvalidDashboardPeriod = NewCustomError(BadRequest, "Dashboard period is invalid")
+
+	// sales report
+	ErrInvalidSalesReportPeriod = NewCustomError(BadRequest, "Sales report period is invalid")
+	ErrSalesReportJobNotFound   = NewCustomError(NotFound, "Sales report not found")
+	ErrSalesReportNotReady      = NewCustomError(BadRequest, "Sales report is not ready yet")
+
+	// order return
+	ErrOrderReturnNotFound = NewCustomError(NotFound, "Order return not found")
+	ErrOrderReturnExists   = NewCustomError(BadRequest, "Order already has a return request")
+	ErrOrderNotReturnable  = NewCustomError(BadRequest, "Only arrived or received orders can be returned")
+	ErrReturnWindowPassed  = NewCustomError(BadRequest, "Return period of the order has passed")
+	ErrInvalidReturnItem   = NewCustomError(BadRequest, "Return item is not part of the order or exceeds its quantity")
+	ErrInvalidReturnStatus = NewCustomError(BadRequest, "Order return cannot be updated in its current status")
+	ErrOrderReturnOpen     = NewCustomError(BadRequest, "Order has an open return request")
+
+	// shipment
+	ErrOrderShipmentNotFound      = NewCustomError(NotFound, "Order has not been shipped yet")
+	ErrInvalidWaybillNumber       = NewCustomError(BadRequest, "Waybill number may only contain letters, digits and dashes")
+	ErrShippingCostUnavailable    = NewCustomError(ServiceUnavailable, "Shipping cost is unavailable, please try again later")
+	ErrInvalidShippingRoute       = NewCustomError(BadRequest, "Shipping origin, destination or weight is invalid")
+	ErrShippingServiceUnavailable = NewCustomError(BadRequest, "Courier service is not available for this route")
+	ErrShippingTableRateNotFound  = NewCustomError(NotFound, "Shipping rate not found")
+	ErrRegionProviderUnavailable  = NewCustomError(ServiceUnavailable, "Region list of the shipping provider is unavailable")
+	ErrRegionProvinceNotFound     = NewCustomError(BadRequest, "City belongs to a province that is not synced")
+
+	// checkout session
+	ErrCheckoutSessionNotFound = NewCustomError(NotFound, "Checkout session not found or expired")
+	ErrCheckoutPricesChanged   = NewCustomError(Conflict, "Prices changed since checkout, please review your order again")
+	ErrCheckoutCourierRequired = NewCustomError(BadRequest, "Every order needs a courier")
+	ErrCheckoutSessionInUse    = NewCustomError(Conflict, "Checkout session is already being paid")
+
+	// fee
+	ErrFeeRuleNotFound        = NewCustomError(NotFound, "Fee rule not found")
+	ErrFeeRuleExists          = NewCustomError(Conflict, "A fee rule for this category and shop tier already exists")
+	ErrPlatformWalletNotFound = NewCustomError(InternalServer, "Platform wallet is not set up")
+
+	// order document
+	ErrPackingSlipCancelled = NewCustomError(BadRequest, "Cancelled orders have no packing slip")
+
+	// notification
+	ErrNotificationNotFound = NewCustomError(NotFound, "Notification not found")
+
+	// webhook
+	ErrWebhookNotFound         = NewCustomError(NotFound, "Webhook not found")
+	ErrWebhookDeliveryNotFound = NewCustomError(NotFound, "Webhook delivery not found")
+	ErrWebhookLimit            = NewCustomError(BadRequest, "Maximum number of webhooks reached")
+	ErrWebhookURLNotAllowed    = NewCustomError(BadRequest, "Webhook url must be a public https address")
+
+	// chat
+	ErrConversationNotFound = NewCustomError(NotFound, "Conversation not found")
+	ErrChatWithOwnShop      = NewCustomError(BadRequest, "Cannot start a conversation with your own shop")
+	ErrInvalidChatMessage   = NewCustomError(BadRequest, "Text messages need a text and image messages need an image url")
+	ErrChatProductNotFound  = NewCustomError(BadRequest, "Product does not belong to the shop of this conversation")
+	ErrChatOrderNotFound    = NewCustomError(BadRequest, "Order does not belong to this conversation")
+	ErrQuickReplyNotFound   = NewCustomError(NotFound, "Quick reply not found")
+	ErrQuickReplyLimit      = NewCustomError(BadRequest, "Maximum number of quick replies reached")
 
 	// internal server
 	ErrInternalServer = NewCustomError(InternalServer, "Internal server error")
@@ -25,6 +25,8 @@ const (
 	Forbidden
 	Unauthorized
 	InternalServer
+	ServiceUnavailable
+	Conflict
 )
 
 func (ce CustomError) Error() string {
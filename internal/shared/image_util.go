@@ -0,0 +1,309 @@
+package shared
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"strings"
+
+	"github.com/lil-oren/rest/internal/constant"
+)
+
+// DecodeImage decodes a jpeg or png image and applies its EXIF orientation.
+// The returned image is opaque, transparent pixels are flattened on white,
+// so it can be re-encoded as jpeg without carrying any of the source metadata.
+// Images with more than constant.MediaMaxImagePixels pixels are rejected from
+// their header, before any pixel is decoded.
+func DecodeImage(r io.Reader) (*image.RGBA, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || int64(cfg.Width)*int64(cfg.Height) > constant.MediaMaxImagePixels {
+		return nil, ErrImageTooLarge
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	b := src.Bounds()
+	img := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(img, img.Bounds(), src, b.Min, draw.Over)
+
+	return orientImage(img, jpegOrientation(raw)), nil
+}
+
+// ResizeImage scales img down with a box filter so that its longest side is
+// at most maxDimension. Images that already fit are returned as is.
+func ResizeImage(img *image.RGBA, maxDimension int) *image.RGBA {
+	sw, sh := img.Bounds().Dx(), img.Bounds().Dy()
+	if sw <= maxDimension && sh <= maxDimension {
+		return img
+	}
+
+	dw, dh := maxDimension, sh*maxDimension/sw
+	if sh > sw {
+		dw, dh = sw*maxDimension/sh, maxDimension
+	}
+	if dw < 1 {
+		dw = 1
+	}
+	if dh < 1 {
+		dh = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		y0, y1 := y*sh/dh, (y+1)*sh/dh
+		if y1 == y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < dw; x++ {
+			x0, x1 := x*sw/dw, (x+1)*sw/dw
+			if x1 == x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, b, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				i := img.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					r += uint32(img.Pix[i])
+					g += uint32(img.Pix[i+1])
+					b += uint32(img.Pix[i+2])
+					a += uint32(img.Pix[i+3])
+					n++
+					i += 4
+				}
+			}
+
+			j := dst.PixOffset(x, y)
+			dst.Pix[j] = uint8(r / n)
+			dst.Pix[j+1] = uint8(g / n)
+			dst.Pix[j+2] = uint8(b / n)
+			dst.Pix[j+3] = uint8(a / n)
+		}
+	}
+
+	return dst
+}
+
+func EncodeJPEG(img image.Image, quality int) (*bytes.Buffer, error) {
+	buf := new(bytes.Buffer)
+	if err := jpeg.Encode(buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
+
+	return buf, nil
+}
+
+// StripImageMetadata removes EXIF, XMP and text metadata, which may carry
+// the location a photo was taken at, from an uploaded image. Jpegs are
+// re-encoded with their orientation applied, png and webp chunks holding
+// metadata are dropped without touching the pixels.
+func StripImageMetadata(raw []byte, contentType string) ([]byte, error) {
+	switch contentType {
+	case "image/jpeg":
+		img, err := DecodeImage(bytes.NewReader(raw))
+		if err == ErrImageTooLarge {
+			return nil, err
+		}
+		if err != nil {
+			return nil, ErrMediaTypeNotAllowed
+		}
+
+		buf, err := EncodeJPEG(img, constant.MediaOriginalJPEGQuality)
+		if err != nil {
+			return nil, err
+		}
+
+		return buf.Bytes(), nil
+	case "image/png":
+		return stripPNGMetadata(raw)
+	case "image/webp":
+		return stripWEBPMetadata(raw)
+	}
+
+	return raw, nil
+}
+
+func stripPNGMetadata(raw []byte) ([]byte, error) {
+	const signature = "\x89PNG\r\n\x1a\n"
+	if len(raw) < len(signature) || string(raw[:len(signature)]) != signature {
+		return nil, ErrMediaTypeNotAllowed
+	}
+
+	out := bytes.NewBuffer(make([]byte, 0, len(raw)))
+	out.WriteString(signature)
+	for i := len(signature); i < len(raw); {
+		if i+8 > len(raw) {
+			return nil, ErrMediaTypeNotAllowed
+		}
+		size := int(binary.BigEndian.Uint32(raw[i : i+4]))
+		end := i + 12 + size
+		if size < 0 || end > len(raw) {
+			return nil, ErrMediaTypeNotAllowed
+		}
+
+		switch string(raw[i+4 : i+8]) {
+		case "eXIf", "tEXt", "zTXt", "iTXt", "tIME":
+		default:
+			out.Write(raw[i:end])
+		}
+
+		i = end
+	}
+
+	return out.Bytes(), nil
+}
+
+func stripWEBPMetadata(raw []byte) ([]byte, error) {
+	if len(raw) < 12 || string(raw[:4]) != "RIFF" || string(raw[8:12]) != "WEBP" {
+		return nil, ErrMediaTypeNotAllowed
+	}
+
+	out := bytes.NewBuffer(make([]byte, 0, len(raw)))
+	out.Write(raw[:12])
+	for i := 12; i < len(raw); {
+		if i+8 > len(raw) {
+			return nil, ErrMediaTypeNotAllowed
+		}
+		size := int(binary.LittleEndian.Uint32(raw[i+4 : i+8]))
+		end := i + 8 + size + size%2
+		if size < 0 || end > len(raw) {
+			return nil, ErrMediaTypeNotAllowed
+		}
+
+		switch strings.TrimSpace(string(raw[i : i+4])) {
+		case "EXIF", "XMP":
+		default:
+			out.Write(raw[i:end])
+		}
+
+		i = end
+	}
+
+	res := out.Bytes()
+	binary.LittleEndian.PutUint32(res[4:8], uint32(len(res)-8))
+
+	// the extended header flags which metadata chunks follow, clear the
+	// EXIF and XMP bits now that the chunks are gone
+	if len(res) >= 21 && string(res[12:16]) == "VP8X" {
+		res[20] &^= 0x08 | 0x04
+	}
+
+	return res, nil
+}
+
+// jpegOrientation reads the orientation tag from the EXIF block of a jpeg,
+// returning 1 (no transformation) when there is none.
+func jpegOrientation(raw []byte) int {
+	if len(raw) < 4 || raw[0] != 0xFF || raw[1] != 0xD8 {
+		return 1
+	}
+
+	for i := 2; i+4 <= len(raw); {
+		if raw[i] != 0xFF {
+			return 1
+		}
+		marker := raw[i+1]
+		size := int(binary.BigEndian.Uint16(raw[i+2 : i+4]))
+		if marker == 0xDA || size < 2 || i+2+size > len(raw) {
+			return 1
+		}
+
+		segment := raw[i+4 : i+2+size]
+		if marker == 0xE1 && len(segment) > 14 && string(segment[:6]) == "Exif\x00\x00" {
+			return exifOrientation(segment[6:])
+		}
+
+		i += 2 + size
+	}
+
+	return 1
+}
+
+func exifOrientation(tiff []byte) int {
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	offset := int(order.Uint32(tiff[4:8]))
+	if offset+2 > len(tiff) {
+		return 1
+	}
+
+	entries := int(order.Uint16(tiff[offset : offset+2]))
+	for i := 0; i < entries; i++ {
+		entry := offset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:entry+2]) == 0x0112 {
+			orientation := int(order.Uint16(tiff[entry+8 : entry+10]))
+			if orientation < 1 || orientation > 8 {
+				return 1
+			}
+			return orientation
+		}
+	}
+
+	return 1
+}
+
+func orientImage(img *image.RGBA, orientation int) *image.RGBA {
+	if orientation <= 1 {
+		return img
+	}
+
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			dx, dy := x, y
+			switch orientation {
+			case 2:
+				dx = w - 1 - x
+			case 3:
+				dx, dy = w-1-x, h-1-y
+			case 4:
+				dy = h - 1 - y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = h-1-y, x
+			case 7:
+				dx, dy = h-1-y, w-1-x
+			case 8:
+				dx, dy = y, w-1-x
+			}
+			dst.SetRGBA(dx, dy, img.RGBAAt(x, y))
+		}
+	}
+
+	return dst
+}
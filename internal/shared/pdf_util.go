@@ -0,0 +1,127 @@
+package shared
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	pdfPageWidth       = 595
+	pdfPageHeight      = 842
+	pdfMargin          = 40
+	pdfDefaultFontSize = 9
+	pdfLineSpacing     = 1.4
+)
+
+// PDFLine is a line of text on a PDF page. Text is set in a monospaced font,
+// so callers lay out columns by padding.
+type PDFLine struct {
+	Text string
+	Size float64
+	Bold bool
+}
+
+// WritePDF writes an A4 PDF with a page for every entry of pages. Lines that
+// do not fit on a page flow onto the next one. Only the standard Courier
+// fonts are used, so characters outside of Latin-1 are replaced.
+func WritePDF(w io.Writer, pages [][]PDFLine) error {
+	streams := make([]string, 0, len(pages))
+	for _, lines := range pages {
+		streams = append(streams, pdfContentStreams(lines)...)
+	}
+	if len(streams) == 0 {
+		streams = append(streams, "")
+	}
+
+	// objects 1 to 4 are the catalog, the page tree and the two fonts, each
+	// page then takes a page object followed by its content stream
+	objects := make([]string, 0, 4+2*len(streams))
+	kids := make([]string, 0, len(streams))
+	for i := range streams {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 5+2*i))
+	}
+	objects = append(objects,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>",
+	)
+	for i, stream := range streams {
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", pdfPageWidth, pdfPageHeight, 6+2*i),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(stream), stream),
+		)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, 0, len(objects))
+	for i, obj := range objects {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// pdfContentStreams lays lines out top to bottom and starts a new stream,
+// that is a new page, whenever the bottom margin is reached.
+func pdfContentStreams(lines []PDFLine) []string {
+	streams := make([]string, 0, 1)
+	var sb strings.Builder
+	y := float64(pdfPageHeight - pdfMargin)
+	for _, line := range lines {
+		size := line.Size
+		if size == 0 {
+			size = pdfDefaultFontSize
+		}
+
+		y -= size * pdfLineSpacing
+		if y < pdfMargin {
+			streams = append(streams, sb.String())
+			sb.Reset()
+			y = pdfPageHeight - pdfMargin - size*pdfLineSpacing
+		}
+		if line.Text == "" {
+			continue
+		}
+
+		font := "F1"
+		if line.Bold {
+			font = "F2"
+		}
+		fmt.Fprintf(&sb, "BT /%s %g Tf %d %.2f Td (%s) Tj ET\n", font, size, pdfMargin, y, pdfEscape(line.Text))
+	}
+
+	return append(streams, sb.String())
+}
+
+// pdfEscape encodes s as the content of a PDF string in WinAnsiEncoding.
+func pdfEscape(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case r >= 0x20 && r <= 0x7e:
+			sb.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&sb, "\\%03o", r)
+		default:
+			sb.WriteByte('?')
+		}
+	}
+
+	return sb.String()
+}
@@ -7,4 +7,5 @@ import (
 
 var (
 	WalletHistoryTransactionTypeQueryRegex = regexp2.MustCompile(constant.ListWalletHistoryTransactionTypeQueryRegexPattern, regexp2.None)
+	WaybillNumberRegex                     = regexp2.MustCompile(constant.WaybillNumberRegexPattern, regexp2.None)
 )
@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 
+	"github.com/lil-oren/rest/internal/constant"
 	"github.com/lil-oren/rest/internal/dto"
 	"github.com/lil-oren/rest/internal/model"
 	"github.com/lil-oren/rest/internal/repository"
@@ -22,6 +23,7 @@ type (
 	cartUsecase struct {
 		cr  repository.CartRepository
 		pvr repository.ProductVariantRepository
+		psr repository.ProductStatRepository
 	}
 )
 
@@ -93,6 +95,9 @@ func (cuc *cartUsecase) AddToCart(ctx context.Context, product *dto.AddToCartReq
 	if product.SellerID != productVariant.SellerID {
 		return shared.ErrDifferentSeller
 	}
+	if productVariant.Status != constant.ProductStatusActive {
+		return shared.ErrProductNotActive
+	}
 	if product.Quantity > productVariant.Stock {
 		return shared.ErrQuantityMoreThanStock
 	}
@@ -105,6 +110,7 @@ func (cuc *cartUsecase) AddToCart(ctx context.Context, product *dto.AddToCartReq
 		if err != nil {
 			return err
 		}
+		_ = cuc.psr.IncrementAddToCart(ctx, product.ProductVariantID)
 		return nil
 	}
 	item := &model.Cart{
@@ -117,6 +123,7 @@ func (cuc *cartUsecase) AddToCart(ctx context.Context, product *dto.AddToCartReq
 	if err != nil {
 		return err
 	}
+	_ = cuc.psr.IncrementAddToCart(ctx, product.ProductVariantID)
 	return nil
 }
 
@@ -189,9 +196,10 @@ func (cuc *cartUsecase) GetTotalPriceChecked(ctx context.Context, accountId int6
 	return res, nil
 }
 
-func NewCartUsecase(cr repository.CartRepository, pvr repository.ProductVariantRepository) CartUsecase {
+func NewCartUsecase(cr repository.CartRepository, pvr repository.ProductVariantRepository, psr repository.ProductStatRepository) CartUsecase {
 	return &cartUsecase{
 		cr:  cr,
 		pvr: pvr,
+		psr: psr,
 	}
 }
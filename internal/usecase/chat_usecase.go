@@ -0,0 +1,407 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"strings"
+
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/model"
+	"github.com/lil-oren/rest/internal/repository"
+	"github.com/lil-oren/rest/internal/shared"
+)
+
+type (
+	ChatUsecase interface {
+		StartConversation(ctx context.Context, buyerID int64, body dto.StartConversationRequestBody) (*dto.ConversationResponse, error)
+		GetConversations(ctx context.Context, accountID int64, params dto.ConversationParams) (*dto.ConversationListResponse, error)
+		GetMessages(ctx context.Context, conversationID, accountID int64, params dto.ChatMessageParams) (*dto.ChatMessageListResponse, error)
+		SendMessage(ctx context.Context, conversationID, senderID int64, body dto.SendChatMessageRequestBody) (*dto.ChatMessageResponse, error)
+		ReadConversation(ctx context.Context, conversationID, accountID int64) error
+		GetUnreadCount(ctx context.Context, accountID int64) (*dto.ChatUnreadResponse, error)
+		SubscribeChats(accountID int64) (<-chan dto.ChatFrame, func())
+		ListenChats(ctx context.Context) error
+		GetQuickReplies(ctx context.Context, sellerID int64) ([]dto.ChatQuickReplyResponse, error)
+		CreateQuickReply(ctx context.Context, sellerID int64, body dto.ChatQuickReplyRequestBody) (*dto.ChatQuickReplyResponse, error)
+		UpdateQuickReply(ctx context.Context, id, sellerID int64, body dto.ChatQuickReplyRequestBody) (*dto.ChatQuickReplyResponse, error)
+		DeleteQuickReply(ctx context.Context, id, sellerID int64) error
+	}
+	chatUsecase struct {
+		cr  repository.ChatRepository
+		cqr repository.ChatQuickReplyRepository
+		cb  repository.ChatBroker
+		mr  repository.MediaRepository
+	}
+)
+
+// StartConversation implements ChatUsecase. Starting a conversation with a
+// shop the buyer already talks to returns that conversation.
+func (uc *chatUsecase) StartConversation(ctx context.Context, buyerID int64, body dto.StartConversationRequestBody) (*dto.ConversationResponse, error) {
+	sellerID, err := uc.cr.FirstShopAccountID(ctx, body.ShopID)
+	if err != nil {
+		return nil, err
+	}
+	if sellerID == buyerID {
+		return nil, shared.ErrChatWithOwnShop
+	}
+
+	conversation, err := uc.cr.FirstOrCreateConversation(ctx, buyerID, body.ShopID, sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	detail, err := uc.cr.FirstConversationDetail(ctx, conversation.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := toConversationResponse(*detail, constant.BuyerChatRole)
+	return &res, nil
+}
+
+// GetConversations implements ChatUsecase.
+func (uc *chatUsecase) GetConversations(ctx context.Context, accountID int64, params dto.ConversationParams) (*dto.ConversationListResponse, error) {
+	if params.Page == 0 {
+		params.Page = constant.DefaultPage
+	}
+	if params.Role == "" {
+		params.Role = string(constant.BuyerChatRole)
+	}
+
+	conversations, err := uc.cr.FindConversations(ctx, accountID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := uc.cr.CountConversations(ctx, accountID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.ConversationListResponse{
+		Items:       make([]dto.ConversationResponse, 0, len(conversations)),
+		TotalData:   count,
+		TotalPage:   int(math.Ceil(float64(count) / constant.ChatDefaultConversations)),
+		CurrentPage: params.Page,
+	}
+	for _, conversation := range conversations {
+		res.Items = append(res.Items, toConversationResponse(conversation, constant.ChatRole(params.Role)))
+	}
+
+	return res, nil
+}
+
+// GetMessages implements ChatUsecase.
+func (uc *chatUsecase) GetMessages(ctx context.Context, conversationID, accountID int64, params dto.ChatMessageParams) (*dto.ChatMessageListResponse, error) {
+	if params.Limit == 0 {
+		params.Limit = constant.ChatDefaultMessages
+	}
+
+	conversation, err := uc.cr.FirstConversation(ctx, conversationID, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := uc.cr.FindMessages(ctx, conversation.ID, params.Cursor, params.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.ChatMessageListResponse{
+		Items: make([]dto.ChatMessageResponse, 0, len(messages)),
+	}
+	if len(messages) > params.Limit {
+		messages = messages[:params.Limit]
+		res.NextCursor = &messages[params.Limit-1].ID
+	}
+	for _, message := range messages {
+		res.Items = append(res.Items, toChatMessageResponse(message, conversation))
+	}
+
+	return res, nil
+}
+
+// SendMessage implements ChatUsecase. The message is pushed to both sides
+// of the conversation, including the other sockets of the sender.
+func (uc *chatUsecase) SendMessage(ctx context.Context, conversationID, senderID int64, body dto.SendChatMessageRequestBody) (*dto.ChatMessageResponse, error) {
+	conversation, err := uc.cr.FirstConversation(ctx, conversationID, senderID)
+	if err != nil {
+		return nil, err
+	}
+
+	message := &model.ChatMessage{
+		ConversationID: conversation.ID,
+		SenderID:       senderID,
+		Type:           body.Type,
+		Text:           strings.TrimSpace(body.Text),
+		ProductID:      sql.NullInt64{Int64: body.ProductID, Valid: body.ProductID != 0},
+		OrderID:        sql.NullInt64{Int64: body.OrderID, Valid: body.OrderID != 0},
+	}
+	if err := uc.validateMessage(ctx, conversation, message, body); err != nil {
+		return nil, err
+	}
+
+	if err := uc.cr.CreateMessage(ctx, message, chatRole(conversation, senderID)); err != nil {
+		return nil, err
+	}
+
+	detail, err := uc.cr.FirstMessage(ctx, message.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := toChatMessageResponse(*detail, conversation)
+	uc.publish(ctx, conversation, dto.ChatFrame{
+		Type:           constant.ChatMessageFrame,
+		ConversationID: conversation.ID,
+		Message:        &res,
+	})
+
+	return &res, nil
+}
+
+// ReadConversation implements ChatUsecase. The other side is told up to
+// which message it was read.
+func (uc *chatUsecase) ReadConversation(ctx context.Context, conversationID, accountID int64) error {
+	conversation, err := uc.cr.FirstConversation(ctx, conversationID, accountID)
+	if err != nil {
+		return err
+	}
+
+	lastReadID, err := uc.cr.MarkRead(ctx, conversation.ID, chatRole(conversation, accountID))
+	if err != nil {
+		return err
+	}
+
+	uc.publish(ctx, conversation, dto.ChatFrame{
+		Type:           constant.ChatReadFrame,
+		ConversationID: conversation.ID,
+		ReaderID:       accountID,
+		LastReadID:     lastReadID,
+	})
+
+	return nil
+}
+
+// GetUnreadCount implements ChatUsecase.
+func (uc *chatUsecase) GetUnreadCount(ctx context.Context, accountID int64) (*dto.ChatUnreadResponse, error) {
+	return uc.cr.CountUnread(ctx, accountID)
+}
+
+// SubscribeChats implements ChatUsecase.
+func (uc *chatUsecase) SubscribeChats(accountID int64) (<-chan dto.ChatFrame, func()) {
+	return uc.cb.Subscribe(accountID)
+}
+
+// ListenChats implements ChatUsecase.
+func (uc *chatUsecase) ListenChats(ctx context.Context) error {
+	return uc.cb.Listen(ctx)
+}
+
+// GetQuickReplies implements ChatUsecase.
+func (uc *chatUsecase) GetQuickReplies(ctx context.Context, sellerID int64) ([]dto.ChatQuickReplyResponse, error) {
+	replies, err := uc.cqr.FindBySellerID(ctx, sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]dto.ChatQuickReplyResponse, 0, len(replies))
+	for _, reply := range replies {
+		res = append(res, toChatQuickReplyResponse(reply))
+	}
+
+	return res, nil
+}
+
+// CreateQuickReply implements ChatUsecase.
+func (uc *chatUsecase) CreateQuickReply(ctx context.Context, sellerID int64, body dto.ChatQuickReplyRequestBody) (*dto.ChatQuickReplyResponse, error) {
+	count, err := uc.cqr.CountBySellerID(ctx, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= constant.ChatMaxQuickReplies {
+		return nil, shared.ErrQuickReplyLimit
+	}
+
+	reply := &model.ChatQuickReply{
+		SellerID: sellerID,
+		Title:    body.Title,
+		Body:     body.Body,
+	}
+	if err := uc.cqr.Create(ctx, reply); err != nil {
+		return nil, err
+	}
+
+	res := toChatQuickReplyResponse(*reply)
+	return &res, nil
+}
+
+// UpdateQuickReply implements ChatUsecase.
+func (uc *chatUsecase) UpdateQuickReply(ctx context.Context, id, sellerID int64, body dto.ChatQuickReplyRequestBody) (*dto.ChatQuickReplyResponse, error) {
+	reply := &model.ChatQuickReply{
+		ID:       id,
+		SellerID: sellerID,
+		Title:    body.Title,
+		Body:     body.Body,
+	}
+	if err := uc.cqr.Update(ctx, reply); err != nil {
+		return nil, err
+	}
+
+	res := toChatQuickReplyResponse(*reply)
+	return &res, nil
+}
+
+// DeleteQuickReply implements ChatUsecase.
+func (uc *chatUsecase) DeleteQuickReply(ctx context.Context, id, sellerID int64) error {
+	return uc.cqr.Delete(ctx, id, sellerID)
+}
+
+// validateMessage checks the content of message. An image has to be
+// uploaded by the sender through POST /media, a product card has to be of
+// the shop and an order card of the buyer with the shop.
+func (uc *chatUsecase) validateMessage(ctx context.Context, conversation *model.Conversation, message *model.ChatMessage, body dto.SendChatMessageRequestBody) error {
+	switch constant.ChatMessageType(body.Type) {
+	case constant.TextChatMessage:
+		if message.Text == "" || body.ImageURL != "" {
+			return shared.ErrInvalidChatMessage
+		}
+	case constant.ImageChatMessage:
+		if body.ImageURL == "" {
+			return shared.ErrInvalidChatMessage
+		}
+		if _, err := ownedMedias(ctx, uc.mr, message.SenderID, []string{body.ImageURL}); err != nil {
+			return err
+		}
+		message.ImageURL = sql.NullString{String: body.ImageURL, Valid: true}
+	}
+
+	if message.ProductID.Valid {
+		ok, err := uc.cr.IsShopProduct(ctx, message.ProductID.Int64, conversation.SellerID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return shared.ErrChatProductNotFound
+		}
+	}
+
+	if message.OrderID.Valid {
+		ok, err := uc.cr.IsConversationOrder(ctx, message.OrderID.Int64, conversation.BuyerID, conversation.SellerID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return shared.ErrChatOrderNotFound
+		}
+	}
+
+	return nil
+}
+
+// publish pushes frame to both sides of conversation. Pushing is best
+// effort, the frame is in the history either way.
+func (uc *chatUsecase) publish(ctx context.Context, conversation *model.Conversation, frame dto.ChatFrame) {
+	_ = uc.cb.Publish(ctx, dto.ChatEvent{
+		AccountIDs: []int64{conversation.BuyerID, conversation.SellerID},
+		Frame:      frame,
+	})
+}
+
+func chatRole(conversation *model.Conversation, accountID int64) constant.ChatRole {
+	if conversation.BuyerID == accountID {
+		return constant.BuyerChatRole
+	}
+
+	return constant.SellerChatRole
+}
+
+func toConversationResponse(conversation dto.ConversationModel, role constant.ChatRole) dto.ConversationResponse {
+	res := dto.ConversationResponse{
+		ID:   conversation.ID,
+		Role: string(role),
+		Buyer: dto.ChatParticipantResponse{
+			AccountID: conversation.BuyerID,
+			Name:      conversation.BuyerName,
+			ImageURL:  conversation.BuyerImageURL,
+		},
+		Shop: dto.ChatShopResponse{
+			ShopID: conversation.ShopID,
+			Name:   conversation.ShopName,
+		},
+		UnreadCount: conversation.BuyerUnreadCount,
+		CreatedAt:   conversation.CreatedAt,
+	}
+	if role == constant.SellerChatRole {
+		res.UnreadCount = conversation.SellerUnreadCount
+	}
+	if conversation.LastMessageID != nil {
+		res.LastMessage = &dto.ChatLastMessage{
+			ID:        *conversation.LastMessageID,
+			SenderID:  *conversation.LastMessageSenderID,
+			Type:      *conversation.LastMessageType,
+			Text:      *conversation.LastMessageText,
+			CreatedAt: *conversation.LastMessageCreatedAt,
+		}
+	}
+
+	return res
+}
+
+// toChatMessageResponse tells whether the other side of conversation has
+// read message.
+func toChatMessageResponse(message dto.ChatMessageModel, conversation *model.Conversation) dto.ChatMessageResponse {
+	lastReadID := conversation.SellerLastReadID
+	if message.SenderID == conversation.SellerID {
+		lastReadID = conversation.BuyerLastReadID
+	}
+
+	res := dto.ChatMessageResponse{
+		ID:             message.ID,
+		ConversationID: message.ConversationID,
+		SenderID:       message.SenderID,
+		Type:           message.Type,
+		Text:           message.Text,
+		ImageURL:       message.ImageURL,
+		IsRead:         message.ID <= lastReadID,
+		CreatedAt:      message.CreatedAt,
+	}
+	if message.ProductID != nil && message.ProductCode != nil {
+		res.Product = &dto.ChatProductCard{
+			ProductID:   *message.ProductID,
+			ProductCode: *message.ProductCode,
+			Name:        *message.ProductName,
+			ImageURL:    *message.ProductImageURL,
+		}
+	}
+	if message.OrderID != nil && message.OrderStatus != nil {
+		res.Order = &dto.ChatOrderCard{
+			OrderID:       *message.OrderID,
+			InvoiceNumber: message.OrderInvoiceNumber,
+			Status:        *message.OrderStatus,
+		}
+	}
+
+	return res
+}
+
+func toChatQuickReplyResponse(reply model.ChatQuickReply) dto.ChatQuickReplyResponse {
+	return dto.ChatQuickReplyResponse{
+		ID:        reply.ID,
+		Title:     reply.Title,
+		Body:      reply.Body,
+		CreatedAt: reply.CreatedAt,
+		UpdatedAt: reply.UpdatedAt,
+	}
+}
+
+func NewChatUsecase(cr repository.ChatRepository, cqr repository.ChatQuickReplyRepository, cb repository.ChatBroker, mr repository.MediaRepository) ChatUsecase {
+	return &chatUsecase{
+		cr:  cr,
+		cqr: cqr,
+		cb:  cb,
+		mr:  mr,
+	}
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dependency"
 	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/model"
 	"github.com/lil-oren/rest/internal/repository"
 	"github.com/lil-oren/rest/internal/shared"
 	"github.com/shopspring/decimal"
@@ -16,10 +19,12 @@ type (
 	CheckoutUsecase interface {
 		CalculateCheckoutSummary(ctx context.Context, payload dto.CalculateCheckoutSummaryPayload) (*dto.CalculateCheckoutSummaryResponse, error)
 		ListCheckoutItem(ctx context.Context, payload dto.ListCheckoutItemPayload) (*dto.ListCheckoutItemResponse, error)
+		CreateCheckoutSession(ctx context.Context, payload dto.CalculateCheckoutSummaryPayload) (*dto.CheckoutSessionResponse, error)
+		GetCheckoutSession(ctx context.Context, id string, buyerID int64) (*dto.CheckoutSessionResponse, error)
 	}
 	checkoutUsecase struct {
 		cr  repository.CartRepository
-		ror repository.RajaOngkirRepository
+		spr repository.ShippingProviderRegistry
 		aar repository.AccountAddressRepository
 		cor repository.CourierRepository
 		pr  repository.ProductRepository
@@ -27,6 +32,10 @@ type (
 		scr repository.ShopCourierRepository
 		wr  repository.WalletRepository
 		prr repository.PromotionRepository
+		vr  repository.VoucherRepository
+		chr repository.CacheRepository
+		frr repository.FeeRuleRepository
+		cfg dependency.Config
 	}
 )
 
@@ -148,17 +157,47 @@ func (uc *checkoutUsecase) ListCheckoutItem(ctx context.Context, payload dto.Lis
 		res.Checkouts[idx].PromotionDropdown = promotionDropdown
 	}
 
+	buyerAddress, err := uc.checkoutAddress(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
 	for idx, checkout := range res.Checkouts {
-		res.Checkouts[idx].CourierDropdown = make([]dto.DropdownValue, 0)
+		res.Checkouts[idx].CourierDropdown = make([]dto.CourierDropdownValue, 0)
 		couriers, err := uc.scr.FindAvailableCourierByShopID(ctx, checkout.ShopID)
 		if err != nil {
 			return nil, err
 		}
 
+		shopAddress, err := uc.aar.FirstShopAddressByShopID(ctx, checkout.ShopID)
+		if err != nil {
+			return nil, err
+		}
+
+		totalWeight := 0
+		for _, item := range checkout.Items {
+			totalWeight += item.TotalWeight
+		}
+
 		for _, courier := range couriers {
-			temp := dto.DropdownValue{
-				Label: courier.CourierName,
-				Value: courier.ShopCourierID,
+			temp := dto.CourierDropdownValue{
+				Label:    courier.CourierName,
+				Value:    courier.ShopCourierID,
+				Services: make([]dto.ShippingServiceResponse, 0),
+			}
+
+			// Services are only a hint here, a courier whose provider is
+			// down can still be picked and is reported by the summary.
+			if buyerAddress != nil {
+				c, err := uc.cor.FirstByShopCourierID(ctx, courier.ShopCourierID)
+				if err != nil {
+					return nil, err
+				}
+
+				rates, err := shippingRates(ctx, uc.spr, c, shopAddress, buyerAddress, totalWeight)
+				if err == nil {
+					temp.Services = toShippingServiceResponses(rates)
+				}
 			}
 			checkout.CourierDropdown = append(checkout.CourierDropdown, temp)
 		}
@@ -182,51 +221,180 @@ func (uc *checkoutUsecase) ListCheckoutItem(ctx context.Context, payload dto.Lis
 	return res, nil
 }
 
+// checkoutAddress returns the address the courier services of the checkout
+// are quoted to, which is the buyer's default address unless one is asked
+// for. A buyer without any default address gets no quotes.
+func (uc *checkoutUsecase) checkoutAddress(ctx context.Context, payload dto.ListCheckoutItemPayload) (*model.AccountAddresses, error) {
+	if payload.BuyerAddressID != 0 {
+		address, err := uc.aar.FirstByID(ctx, payload.BuyerAddressID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, shared.ErrAddressNotFound
+			}
+			return nil, err
+		}
+		if address.AccountId != payload.UserID {
+			return nil, shared.ErrAddressNotBelongToCurrentUser
+		}
+		return address, nil
+	}
+
+	addresses, err := uc.aar.FindAddressById(ctx, int(payload.UserID))
+	if err != nil {
+		return nil, err
+	}
+	for i := range addresses {
+		if addresses[i].IsDefault && !addresses[i].IsShop {
+			return &addresses[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// CreateCheckoutSession implements CheckoutUsecase. The session locks the
+// summary the buyer confirms, including the courier service that was
+// quoted, until CHECKOUT_SESSION_EXPIRATION minutes have passed.
+func (uc *checkoutUsecase) CreateCheckoutSession(ctx context.Context, payload dto.CalculateCheckoutSummaryPayload) (*dto.CheckoutSessionResponse, error) {
+	if len(payload.OrderDeliveries) == 0 {
+		return nil, shared.ErrNoCheckedCart
+	}
+	for _, od := range payload.OrderDeliveries {
+		if od.ShopCourierID == nil {
+			return nil, shared.ErrCheckoutCourierRequired
+		}
+	}
+
+	summary, orders, err := uc.summarize(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]dto.OrderDelivery, len(payload.OrderDeliveries))
+	copy(deliveries, payload.OrderDeliveries)
+	for idx := range deliveries {
+		deliveries[idx].CourierService = summary.Orders[idx].CourierService
+	}
+
+	session := dto.CheckoutSession{
+		ID:                  shared.GenerateUUID(),
+		BuyerID:             payload.BuyerID,
+		BuyerAddressID:      payload.BuyerAddressID,
+		PlatformVoucherCode: payload.PlatformVoucherCode,
+		OrderDeliveries:     deliveries,
+		Orders:              orders,
+		Summary:             *summary,
+		ExpiresAt:           time.Now().Add(time.Duration(uc.cfg.CheckoutSession.Expiration) * time.Minute),
+	}
+	if err := uc.chr.SetCheckoutSession(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return toCheckoutSessionResponse(session), nil
+}
+
+// GetCheckoutSession implements CheckoutUsecase.
+func (uc *checkoutUsecase) GetCheckoutSession(ctx context.Context, id string, buyerID int64) (*dto.CheckoutSessionResponse, error) {
+	session, err := uc.chr.GetCheckoutSession(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil || session.BuyerID != buyerID {
+		return nil, shared.ErrCheckoutSessionNotFound
+	}
+
+	return toCheckoutSessionResponse(*session), nil
+}
+
+// checkCartProductsActive fails when a product in the cart was taken off
+// sale after it was added.
+func checkCartProductsActive(carts []dto.CartOrderModel) error {
+	for _, cart := range carts {
+		if cart.ProductStatus != constant.ProductStatusActive {
+			return shared.ErrProductNotActive
+		}
+	}
+
+	return nil
+}
+
+func toCheckoutSessionResponse(session dto.CheckoutSession) *dto.CheckoutSessionResponse {
+	return &dto.CheckoutSessionResponse{
+		ID:        session.ID,
+		ExpiresAt: session.ExpiresAt,
+		Summary:   session.Summary,
+	}
+}
+
 // CalculateCheckoutSummary implements CheckoutUsecase.
 func (uc *checkoutUsecase) CalculateCheckoutSummary(ctx context.Context, payload dto.CalculateCheckoutSummaryPayload) (*dto.CalculateCheckoutSummaryResponse, error) {
+	res, _, err := uc.summarize(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// summarize prices the checkout and also returns what every order is made
+// of, so a checkout session can tell later whether anything changed.
+func (uc *checkoutUsecase) summarize(ctx context.Context, payload dto.CalculateCheckoutSummaryPayload) (*dto.CalculateCheckoutSummaryResponse, []dto.CheckoutSessionOrder, error) {
 	res := new(dto.CalculateCheckoutSummaryResponse)
 
 	// get buyer district
 	buyerAddress, err := uc.aar.FirstByID(ctx, payload.BuyerAddressID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	if buyerAddress.AccountId != payload.BuyerID {
+		return nil, nil, shared.ErrAddressNotBelongToCurrentUser
 	}
 
 	res.Orders = make([]dto.CalculateCheckoutSummaryOrder, 0)
+	voucherOrders := make([]*voucherOrder, 0, len(payload.OrderDeliveries))
+	sessionOrders := make([]dto.CheckoutSessionOrder, 0, len(payload.OrderDeliveries))
 	for _, od := range payload.OrderDeliveries {
 		cartModelOrders, err := uc.cr.FindCheckedCartByShopID(ctx, payload.BuyerID, od.ShopID)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
+		}
+		if err := checkCartProductsActive(cartModelOrders); err != nil {
+			return nil, nil, err
 		}
 
 		promotionDetail, err := uc.prr.FirstPromotionByShopID(ctx, od.ShopID, od.PromotionID)
 		if err != nil {
 			if err == sql.ErrNoRows {
-				return nil, shared.ErrPromotionNotFound
+				return nil, nil, shared.ErrPromotionNotFound
 			}
-			return nil, shared.ErrFindPromotion
+			return nil, nil, shared.ErrFindPromotion
 		}
 
 		temp := dto.CalculateCheckoutSummaryOrder{
-			ShopID: od.ShopID,
+			ShopID:           od.ShopID,
+			ShippingServices: make([]dto.ShippingServiceResponse, 0),
 		}
 
+		sessionOrder := dto.CheckoutSessionOrder{
+			ShopID: od.ShopID,
+			Items:  make([]dto.CheckoutSessionItem, 0, len(cartModelOrders)),
+		}
 		totalWeight := 0
+		subTotalProduct := decimal.Zero
 		for _, cmo := range cartModelOrders {
-			decimalQty := decimal.NewFromInt32(int32(cmo.Qty))
-			basePrice := cmo.BasePrice
-			decimalDiscount := decimal.NewFromFloat(cmo.Discount / 100)
-			total := basePrice.Mul(decimalQty)
-			discountedTotal := total.Mul(decimalDiscount)
-			total = total.Sub(discountedTotal)
-			subtotalProduct, _ := total.Float64()
+			total := cartItemPrice(cmo)
+			sessionOrder.Items = append(sessionOrder.Items, dto.CheckoutSessionItem{
+				ProductVariantID: cmo.ProductVariantID,
+				Quantity:         cmo.Qty,
+				Price:            total,
+			})
 
 			p, err := uc.pr.FirstProductDetail(ctx, cmo.ProductID)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 
-			temp.SubTotalProduct += subtotalProduct
+			subTotalProduct = subTotalProduct.Add(total)
 			res.TotalProduct += cmo.Qty
 			totalWeight += p.Weight * cmo.Qty
 		}
@@ -234,21 +402,22 @@ func (uc *checkoutUsecase) CalculateCheckoutSummary(ctx context.Context, payload
 		// get shop shopAddress
 		shopAddress, err := uc.aar.FirstShopAddressByShopID(ctx, od.ShopID)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
+		deliveryCost := decimal.Zero
 		if od.ShopCourierID != nil && totalWeight != 0 {
 
 			couriers, err := uc.scr.FindShopCourierByShopId(ctx, od.ShopID)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 
 			isCurrentShopCourier := false
 			for _, c := range couriers {
 				if *od.ShopCourierID == c.ShopCourierID {
 					if !c.IsAvailable {
-						return nil, shared.ErrCourierNotAvailable
+						return nil, nil, shared.ErrCourierNotAvailable
 					}
 
 					isCurrentShopCourier = true
@@ -257,60 +426,104 @@ func (uc *checkoutUsecase) CalculateCheckoutSummary(ctx context.Context, payload
 			}
 
 			if !isCurrentShopCourier {
-				return nil, shared.ErrCourierNotBelongToCurrentShop
+				return nil, nil, shared.ErrCourierNotBelongToCurrentShop
 			}
 
 			courier, err := uc.cor.FirstByShopCourierID(ctx, *od.ShopCourierID)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 
-			roQuery := dto.RajaOngkirGetCostHTTPQueries{
-				OriginCityID:      int(shopAddress.DistrictId),
-				DestinationCityID: int(buyerAddress.DistrictId),
-				Weight:            totalWeight,
-				CourierCode:       courier.Code,
-				CourierService:    courier.ServiceName,
-			}
-
-			if roQuery.OriginCityID == roQuery.DestinationCityID && roQuery.CourierCode == "jne" {
-				roQuery = shared.ServiceChanger(roQuery)
-			}
-
-			cost, err := uc.ror.GetCost(ctx, roQuery)
+			rate, rates, err := quoteShipping(ctx, uc.spr, courier, shopAddress, buyerAddress, totalWeight, od.CourierService)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 
-			temp.DeliveryCost = *cost
+			deliveryCost = decimal.NewFromFloat(rate.Cost)
+			temp.CourierService = rate.Service
+			temp.DeliveryETD = rate.ETD
+			temp.ShippingServices = toShippingServiceResponses(rates)
 		}
 
-		if promotionDetail.Percentage.Float64 == 0 && promotionDetail.ExactPrice.Float64 == 0 {
-			temp.SubTotalPromotion = temp.SubTotalProduct
+		subTotalPromotion := subTotalProduct
+		minimumSpend := decimal.NewFromFloat(promotionDetail.MinimumSpend)
+		if promotionDetail.Percentage.Float64 != 0 && subTotalProduct.GreaterThanOrEqual(minimumSpend) {
+			percentage := decimal.NewFromFloat(promotionDetail.Percentage.Float64)
+			subTotalPromotion = subTotalProduct.Sub(subTotalProduct.Mul(percentage).Div(decimal.NewFromInt(100)))
 		}
 
-		if promotionDetail.Percentage.Float64 != 0 && temp.SubTotalProduct >= promotionDetail.MinimumSpend {
-			temp.SubTotalPromotion = ((100 - promotionDetail.Percentage.Float64) / 100) * temp.SubTotalProduct
+		if promotionDetail.ExactPrice.Float64 != 0 && subTotalProduct.GreaterThanOrEqual(minimumSpend) {
+			subTotalPromotion = subTotalProduct.Sub(decimal.NewFromFloat(promotionDetail.ExactPrice.Float64))
 		}
 
-		if promotionDetail.ExactPrice.Float64 != 0 && temp.SubTotalProduct >= promotionDetail.MinimumSpend {
-			temp.SubTotalPromotion = temp.SubTotalProduct - promotionDetail.ExactPrice.Float64
+		fee, err := orderFees(ctx, uc.frr, cartModelOrders, subTotalPromotion)
+		if err != nil {
+			return nil, nil, err
 		}
+		sessionOrder.ServiceFee = fee.ServiceFee
+		sessionOrder.Subtotal = subTotalPromotion.Add(deliveryCost)
 
-		temp.Subtotal = temp.SubTotalPromotion + temp.DeliveryCost
+		temp.SubTotalProduct = subTotalProduct.InexactFloat64()
+		temp.SubTotalPromotion = subTotalPromotion.InexactFloat64()
+		temp.DeliveryCost = deliveryCost.InexactFloat64()
+		temp.ServiceFee = fee.ServiceFee.InexactFloat64()
 
 		res.Orders = append(res.Orders, temp)
+		sessionOrders = append(sessionOrders, sessionOrder)
+		voucherOrders = append(voucherOrders, &voucherOrder{
+			shopID:       od.ShopID,
+			voucherCode:  od.VoucherCode,
+			items:        cartModelOrders,
+			subtotal:     subTotalPromotion,
+			deliveryCost: deliveryCost,
+		})
 	}
 
-	res.ServicePrice = constant.ServicePrice
-	res.SummaryPrice += constant.ServicePrice
-	for _, o := range res.Orders {
-		res.TotalDeliveryCost += o.DeliveryCost
-		res.TotalShopPrice += o.SubTotalPromotion
-		res.SummaryPrice += o.Subtotal
+	if err := applyVouchers(ctx, uc.vr, payload.BuyerID, voucherOrders, payload.PlatformVoucherCode); err != nil {
+		return nil, nil, err
 	}
 
-	return res, nil
+	var servicePrice, totalDeliveryCost, totalShopPrice, totalVoucher, summaryPrice decimal.Decimal
+	for idx, vo := range voucherOrders {
+		shopVoucher, platformVoucher := decimal.Zero, decimal.Zero
+		for _, v := range vo.vouchers {
+			if v.Issuer == constant.VoucherIssuerPlatform {
+				platformVoucher = platformVoucher.Add(v.Amount)
+			} else {
+				shopVoucher = shopVoucher.Add(v.Amount)
+			}
+		}
+
+		so := &sessionOrders[idx]
+		so.Subtotal = so.Subtotal.Sub(vo.discount())
+
+		res.Orders[idx].ShopVoucher = shopVoucher.InexactFloat64()
+		res.Orders[idx].PlatformVoucher = platformVoucher.InexactFloat64()
+		res.Orders[idx].Subtotal = so.Subtotal.InexactFloat64()
+
+		servicePrice = servicePrice.Add(so.ServiceFee)
+		totalDeliveryCost = totalDeliveryCost.Add(vo.deliveryCost)
+		totalShopPrice = totalShopPrice.Add(vo.subtotal)
+		totalVoucher = totalVoucher.Add(shopVoucher).Add(platformVoucher)
+		summaryPrice = summaryPrice.Add(so.ServiceFee).Add(so.Subtotal)
+	}
+
+	res.ServicePrice = servicePrice.InexactFloat64()
+	res.TotalDeliveryCost = totalDeliveryCost.InexactFloat64()
+	res.TotalShopPrice = totalShopPrice.InexactFloat64()
+	res.TotalVoucher = totalVoucher.InexactFloat64()
+	res.SummaryPrice = summaryPrice.InexactFloat64()
+
+	return res, sessionOrders, nil
+}
+
+// cartItemPrice is the price of a cart item after its discount, both when
+// the buyer checks out and when the order is made.
+func cartItemPrice(cart dto.CartOrderModel) decimal.Decimal {
+	total := cart.BasePrice.Mul(decimal.NewFromInt(int64(cart.Qty)))
+	discount := total.Mul(decimal.NewFromFloat(cart.Discount / 100))
+
+	return total.Sub(discount)
 }
 
 func NewCheckoutUsecase(
@@ -318,21 +531,29 @@ func NewCheckoutUsecase(
 	aar repository.AccountAddressRepository,
 	cor repository.CourierRepository,
 	pr repository.ProductRepository,
-	ror repository.RajaOngkirRepository,
+	spr repository.ShippingProviderRegistry,
 	dr repository.DistrictRepository,
 	scr repository.ShopCourierRepository,
 	wr repository.WalletRepository,
 	prr repository.PromotionRepository,
+	vr repository.VoucherRepository,
+	chr repository.CacheRepository,
+	frr repository.FeeRuleRepository,
+	cfg dependency.Config,
 ) CheckoutUsecase {
 	return &checkoutUsecase{
 		cr:  cr,
 		aar: aar,
 		cor: cor,
 		pr:  pr,
-		ror: ror,
+		spr: spr,
 		dr:  dr,
 		scr: scr,
 		wr:  wr,
 		prr: prr,
+		vr:  vr,
+		chr: chr,
+		frr: frr,
+		cfg: cfg,
 	}
 }
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/repository"
+	"github.com/lil-oren/rest/internal/shared"
+)
+
+type (
+	DashboardUsecase interface {
+		GetSellerDashboard(ctx context.Context, sellerID int64, params dto.DashboardParams) (*dto.SellerDashboardResponse, error)
+	}
+	dashboardUsecase struct {
+		dr  repository.DashboardRepository
+		ccr repository.CacheRepository
+	}
+)
+
+// GetSellerDashboard implements DashboardUsecase. Without a period the last
+// 30 days are shown. Dashboards are cached for a few minutes and cache
+// failures only cost a recomputation.
+func (uc *dashboardUsecase) GetSellerDashboard(ctx context.Context, sellerID int64, params dto.DashboardParams) (*dto.SellerDashboardResponse, error) {
+	payload, err := dashboardPayload(sellerID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	cached, err := uc.ccr.GetSellerDashboard(ctx, payload)
+	if err == nil && cached != nil {
+		return cached, nil
+	}
+
+	summary, err := uc.dr.FindSummary(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	series, err := uc.dr.FindSeries(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	engagement, err := uc.dr.FindEngagement(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	rating, err := uc.dr.FindRating(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	topProducts, err := uc.dr.FindTopProducts(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	topCategories, err := uc.dr.FindTopCategories(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	res := dto.SellerDashboardResponse{
+		StartDate:     payload.StartDate,
+		EndDate:       payload.EndDate,
+		Bucket:        payload.Bucket,
+		Revenue:       summary.Revenue,
+		Orders:        summary.Orders,
+		UnitsSold:     summary.UnitsSold,
+		ProductViews:  engagement.ProductViews,
+		AddToCarts:    engagement.AddToCarts,
+		AverageRating: rating.AverageRating,
+		ReviewCount:   rating.ReviewCount,
+		Series:        make([]dto.DashboardBucketResponse, 0, len(series)),
+		TopProducts:   make([]dto.DashboardTopProductResponse, 0, len(topProducts)),
+		TopCategories: make([]dto.DashboardTopCategoryResponse, 0, len(topCategories)),
+	}
+	if engagement.ProductViews != 0 {
+		res.ConversionRate = float64(summary.Orders) / float64(engagement.ProductViews)
+	}
+	if placed := summary.Orders + summary.CancelledOrders; placed != 0 {
+		res.CancellationRate = float64(summary.CancelledOrders) / float64(placed)
+	}
+	for _, b := range series {
+		res.Series = append(res.Series, dto.DashboardBucketResponse(b))
+	}
+	for _, p := range topProducts {
+		res.TopProducts = append(res.TopProducts, dto.DashboardTopProductResponse(p))
+	}
+	for _, c := range topCategories {
+		res.TopCategories = append(res.TopCategories, dto.DashboardTopCategoryResponse(c))
+	}
+
+	_ = uc.ccr.SetSellerDashboard(ctx, payload, res)
+
+	return &res, nil
+}
+
+func dashboardPayload(sellerID int64, params dto.DashboardParams) (dto.DashboardPayload, error) {
+	payload := dto.DashboardPayload{
+		SellerID: sellerID,
+		Bucket:   params.Bucket,
+	}
+	if payload.Bucket == "" {
+		payload.Bucket = constant.DashboardBucketDaily
+	}
+
+	end, _ := time.Parse("2006-01-02", time.Now().Format("2006-01-02"))
+	if params.EndDate != "" {
+		end, _ = time.Parse("2006-01-02", params.EndDate)
+	}
+	start := end.AddDate(0, 0, 1-constant.DashboardDefaultDays)
+	if params.StartDate != "" {
+		start, _ = time.Parse("2006-01-02", params.StartDate)
+	}
+	if end.Before(start) || end.After(start.AddDate(0, 0, constant.DashboardMaxDays-1)) {
+		return payload, shared.ErrInvalidDashboardPeriod
+	}
+
+	payload.StartDate = start.Format("2006-01-02")
+	payload.EndDate = end.Format("2006-01-02")
+
+	return payload, nil
+}
+
+func NewDashboardUsecase(dr repository.DashboardRepository, ccr repository.CacheRepository) DashboardUsecase {
+	return &dashboardUsecase{
+		dr:  dr,
+		ccr: ccr,
+	}
+}
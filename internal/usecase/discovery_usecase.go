@@ -4,8 +4,10 @@ import (
 	"context"
 	"math"
 
+	"github.com/lil-oren/rest/internal/constant"
 	"github.com/lil-oren/rest/internal/dto"
 	"github.com/lil-oren/rest/internal/repository"
+	"github.com/lil-oren/rest/internal/shared"
 )
 
 type (
@@ -20,6 +22,10 @@ type (
 
 // SearchProduct implements DiscoveryUsecase.
 func (uc *discoveryUsecase) SearchProduct(ctx context.Context, payload dto.SearchProductPayload) (*dto.SearchProductResponse, error) {
+	if math.IsNaN(payload.MinRating) || payload.MinRating < 0 || payload.MinRating > constant.SearchMaxRating {
+		return nil, shared.ErrInvalidMinRating
+	}
+
 	res := &dto.SearchProductResponse{
 		Page:       payload.Page,
 		SearchTerm: payload.SearchTerm,
@@ -51,6 +57,8 @@ func (uc *discoveryUsecase) SearchProduct(ctx context.Context, payload dto.Searc
 		CategoryID:  payload.CategoryID,
 		MinPrice:    payload.MinPrice,
 		MaxPrice:    payload.MaxPrice,
+		MinRating:   payload.MinRating,
+		InStock:     payload.InStock,
 	}
 	count, err := uc.pr.CountProductBySearchTerm(ctx, countPayload)
 	if err != nil {
@@ -59,6 +67,13 @@ func (uc *discoveryUsecase) SearchProduct(ctx context.Context, payload dto.Searc
 
 	res.TotalPage = int(math.Ceil(float64(*count) / 30.0))
 
+	facets, err := uc.pr.FindSearchFacets(ctx, countPayload)
+	if err != nil {
+		return nil, err
+	}
+
+	res.Facets = facets
+
 	return res, nil
 }
 
@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/repository"
+)
+
+type (
+	// EventHandler reacts to a domain event. An event is delivered at least
+	// once, so a handler may see the same event again after it failed or
+	// after the dispatcher died, and has to be idempotent.
+	EventHandler func(ctx context.Context, event dto.DomainEvent) error
+
+	EventUsecase interface {
+		Subscribe(eventType constant.EventType, name string, handler EventHandler)
+		DispatchEvents(ctx context.Context) error
+	}
+	eventSubscriber struct {
+		name    string
+		handler EventHandler
+	}
+	eventUsecase struct {
+		or          repository.OutboxRepository
+		cfg         dependency.Config
+		mu          sync.RWMutex
+		subscribers map[constant.EventType][]eventSubscriber
+	}
+)
+
+// Subscribe implements EventUsecase. Subscribers are registered once at
+// start up, name is only used to tell which of them failed.
+func (uc *eventUsecase) Subscribe(eventType constant.EventType, name string, handler EventHandler) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	uc.subscribers[eventType] = append(uc.subscribers[eventType], eventSubscriber{
+		name:    name,
+		handler: handler,
+	})
+}
+
+// DispatchEvents implements EventUsecase. It hands every due event to all
+// of its subscribers until the outbox is drained. An event one of them
+// failed on is handed to all of them again later, with a wait that grows
+// with its attempts, until it runs out of attempts.
+func (uc *eventUsecase) DispatchEvents(ctx context.Context) error {
+	limit := int(uc.cfg.Outbox.BatchSize)
+	lease := time.Duration(uc.cfg.Outbox.LeaseTime) * time.Second
+	failed := make([]string, 0)
+
+	for ctx.Err() == nil {
+		events, err := uc.or.Claim(ctx, limit, int(uc.cfg.Outbox.MaxAttempts), lease)
+		if err != nil {
+			return err
+		}
+
+		for _, outboxEvent := range events {
+			event := dto.DomainEvent{
+				ID:          outboxEvent.ID,
+				Type:        constant.EventType(outboxEvent.EventType),
+				AggregateID: outboxEvent.AggregateID,
+				Attempt:     outboxEvent.Attempts,
+				Payload:     outboxEvent.Payload,
+			}
+
+			if reason := uc.dispatch(ctx, event); reason != "" {
+				failed = append(failed, fmt.Sprintf("event %d: %s", event.ID, reason))
+				if err := uc.or.MarkFailed(ctx, event.ID, time.Now().Add(outboxRetryBackoff(event.Attempt)), reason); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if err := uc.or.MarkProcessed(ctx, event.ID); err != nil {
+				return err
+			}
+		}
+
+		if len(events) < limit {
+			break
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%d events failed: %s", len(failed), strings.Join(failed, "; "))
+	}
+
+	return nil
+}
+
+// dispatch hands event to its subscribers and tells why it failed, if it
+// did.
+func (uc *eventUsecase) dispatch(ctx context.Context, event dto.DomainEvent) string {
+	uc.mu.RLock()
+	subscribers := uc.subscribers[event.Type]
+	uc.mu.RUnlock()
+
+	reasons := make([]string, 0)
+	for _, subscriber := range subscribers {
+		if err := subscriber.handler(ctx, event); err != nil {
+			reasons = append(reasons, fmt.Sprintf("%s: %s", subscriber.name, err.Error()))
+		}
+	}
+
+	return strings.Join(reasons, "; ")
+}
+
+func outboxRetryBackoff(attempt int) time.Duration {
+	backoff := constant.OutboxRetryBackoff
+	for i := 1; i < attempt && backoff < constant.OutboxMaxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > constant.OutboxMaxRetryBackoff {
+		return constant.OutboxMaxRetryBackoff
+	}
+
+	return backoff
+}
+
+func NewEventUsecase(or repository.OutboxRepository, cfg dependency.Config) EventUsecase {
+	return &eventUsecase{
+		or:          or,
+		cfg:         cfg,
+		subscribers: make(map[constant.EventType][]eventSubscriber),
+	}
+}
@@ -0,0 +1,262 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"math"
+
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/model"
+	"github.com/lil-oren/rest/internal/repository"
+	"github.com/shopspring/decimal"
+)
+
+type (
+	FeeUsecase interface {
+		GetFeeRules(ctx context.Context, params dto.FeeRuleParams) (*dto.FeeRuleListResponse, error)
+		CreateFeeRule(ctx context.Context, body dto.FeeRuleRequestBody) (*dto.FeeRuleResponse, error)
+		UpdateFeeRule(ctx context.Context, id int64, body dto.FeeRuleRequestBody) (*dto.FeeRuleResponse, error)
+		DeleteFeeRule(ctx context.Context, id int64) error
+		UpdateShopTier(ctx context.Context, shopID int64, tier string) error
+		GetPlatformWallet(ctx context.Context) (*dto.PlatformWalletResponse, error)
+	}
+	feeUsecase struct {
+		frr repository.FeeRuleRepository
+		sr  repository.ShopRepository
+		wr  repository.WalletRepository
+	}
+)
+
+// GetFeeRules implements FeeUsecase.
+func (uc *feeUsecase) GetFeeRules(ctx context.Context, params dto.FeeRuleParams) (*dto.FeeRuleListResponse, error) {
+	if params.Page == 0 {
+		params.Page = constant.DefaultPage
+	}
+
+	rules, err := uc.frr.Find(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := uc.frr.Count(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.FeeRuleListResponse{
+		Items:       make([]dto.FeeRuleResponse, 0, len(rules)),
+		TotalData:   count,
+		TotalPage:   int(math.Ceil(float64(count) / constant.FeeRuleDefaultItems)),
+		CurrentPage: params.Page,
+	}
+	for _, rule := range rules {
+		res.Items = append(res.Items, toFeeRuleResponse(rule))
+	}
+
+	return res, nil
+}
+
+// CreateFeeRule implements FeeUsecase.
+func (uc *feeUsecase) CreateFeeRule(ctx context.Context, body dto.FeeRuleRequestBody) (*dto.FeeRuleResponse, error) {
+	rule := toFeeRule(body)
+	id, err := uc.frr.Create(ctx, &rule)
+	if err != nil {
+		return nil, err
+	}
+
+	rule.ID = *id
+	res := toFeeRuleResponse(rule)
+
+	return &res, nil
+}
+
+// UpdateFeeRule implements FeeUsecase.
+func (uc *feeUsecase) UpdateFeeRule(ctx context.Context, id int64, body dto.FeeRuleRequestBody) (*dto.FeeRuleResponse, error) {
+	rule := toFeeRule(body)
+	rule.ID = id
+	if err := uc.frr.Update(ctx, &rule); err != nil {
+		return nil, err
+	}
+
+	res := toFeeRuleResponse(rule)
+
+	return &res, nil
+}
+
+// DeleteFeeRule implements FeeUsecase.
+func (uc *feeUsecase) DeleteFeeRule(ctx context.Context, id int64) error {
+	return uc.frr.Delete(ctx, id)
+}
+
+// UpdateShopTier implements FeeUsecase.
+func (uc *feeUsecase) UpdateShopTier(ctx context.Context, shopID int64, tier string) error {
+	return uc.sr.UpdateShopTier(ctx, shopID, tier)
+}
+
+// GetPlatformWallet implements FeeUsecase.
+func (uc *feeUsecase) GetPlatformWallet(ctx context.Context) (*dto.PlatformWalletResponse, error) {
+	wallet, err := uc.wr.FirstPlatformWallet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.PlatformWalletResponse{Balance: wallet.Balance.InexactFloat64()}, nil
+}
+
+func toFeeRule(body dto.FeeRuleRequestBody) model.FeeRule {
+	rule := model.FeeRule{
+		Kind:       body.Kind,
+		ShopTier:   sql.NullString{String: body.ShopTier, Valid: body.ShopTier != ""},
+		Rate:       decimal.NewFromFloat(body.Rate),
+		FlatAmount: decimal.NewFromFloat(body.FlatAmount),
+	}
+	if body.CategoryID != nil {
+		rule.CategoryID = sql.NullInt64{Int64: *body.CategoryID, Valid: true}
+	}
+
+	return rule
+}
+
+func toFeeRuleResponse(rule model.FeeRule) dto.FeeRuleResponse {
+	res := dto.FeeRuleResponse{
+		ID:         rule.ID,
+		Kind:       rule.Kind,
+		ShopTier:   rule.ShopTier.String,
+		Rate:       rule.Rate.InexactFloat64(),
+		FlatAmount: rule.FlatAmount.InexactFloat64(),
+	}
+	if rule.CategoryID.Valid {
+		res.CategoryID = &rule.CategoryID.Int64
+	}
+
+	return res
+}
+
+// feeItem is a cart item as far as fee rules are concerned: its share of
+// the amount fees are charged on and the levels of its categories.
+type feeItem struct {
+	base       decimal.Decimal
+	categories map[int64]uint8
+}
+
+// orderFees works out the service fee and the commission of an order made
+// of the checked cart items of a shop. Both are charged on base, the price
+// of the products after the shop promotion, which is split between items by
+// their price.
+func orderFees(ctx context.Context, frr repository.FeeRuleRepository, items []dto.CartOrderModel, base decimal.Decimal) (*dto.OrderFee, error) {
+	if len(items) == 0 {
+		return &dto.OrderFee{}, nil
+	}
+
+	rules, err := frr.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return &dto.OrderFee{}, nil
+	}
+
+	tier, err := frr.FirstShopTier(ctx, items[0].SellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	productIDs := make([]int64, 0, len(items))
+	gross := decimal.Zero
+	for _, item := range items {
+		productIDs = append(productIDs, item.ProductID)
+		gross = gross.Add(cartItemPrice(item))
+	}
+
+	categories, err := frr.FindProductCategories(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	productCategories := make(map[int64]map[int64]uint8)
+	for _, c := range categories {
+		if productCategories[c.ProductID] == nil {
+			productCategories[c.ProductID] = make(map[int64]uint8)
+		}
+		productCategories[c.ProductID][c.CategoryID] = c.Level
+	}
+
+	feeItems := make([]feeItem, 0, len(items))
+	for _, item := range items {
+		share := decimal.Zero
+		if gross.IsPositive() {
+			share = base.Mul(cartItemPrice(item)).Div(gross)
+		}
+		feeItems = append(feeItems, feeItem{
+			base:       share,
+			categories: productCategories[item.ProductID],
+		})
+	}
+
+	return &dto.OrderFee{
+		ServiceFee: feeAmount(rules, constant.ServiceFeeKind, tier, feeItems),
+		Commission: feeAmount(rules, constant.CommissionFeeKind, tier, feeItems),
+	}, nil
+}
+
+// feeAmount charges every item the rate of its rule of kind. The highest
+// flat amount of those rules is charged once for the whole order.
+func feeAmount(rules []model.FeeRule, kind constant.FeeKind, tier string, items []feeItem) decimal.Decimal {
+	amount := decimal.Zero
+	flat := decimal.Zero
+	for _, item := range items {
+		rule := matchFeeRule(rules, kind, tier, item.categories)
+		if rule == nil {
+			continue
+		}
+
+		amount = amount.Add(item.base.Mul(rule.Rate).Div(decimal.NewFromInt(100)))
+		flat = decimal.Max(flat, rule.FlatAmount)
+	}
+
+	return amount.Add(flat).Round(2)
+}
+
+// matchFeeRule picks the most specific rule of kind for an item of a shop
+// of tier. A category rule beats a shop tier rule, which beats the global
+// one; among category rules the deepest category wins and a rule for the
+// shop tier as well breaks ties.
+func matchFeeRule(rules []model.FeeRule, kind constant.FeeKind, tier string, categories map[int64]uint8) *model.FeeRule {
+	var match *model.FeeRule
+	matchRank := -1
+	for i, rule := range rules {
+		if rule.Kind != string(kind) {
+			continue
+		}
+		if rule.ShopTier.Valid && rule.ShopTier.String != tier {
+			continue
+		}
+
+		rank := 0
+		if rule.CategoryID.Valid {
+			level, ok := categories[rule.CategoryID.Int64]
+			if !ok {
+				continue
+			}
+			rank = 2 * (int(level) + 1)
+		}
+		if rule.ShopTier.Valid {
+			rank++
+		}
+
+		if rank > matchRank {
+			match = &rules[i]
+			matchRank = rank
+		}
+	}
+
+	return match
+}
+
+func NewFeeUsecase(frr repository.FeeRuleRepository, sr repository.ShopRepository, wr repository.WalletRepository) FeeUsecase {
+	return &feeUsecase{
+		frr: frr,
+		sr:  sr,
+		wr:  wr,
+	}
+}
@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/model"
+	"github.com/lil-oren/rest/internal/repository"
+	"github.com/lil-oren/rest/internal/shared"
+	"github.com/shopspring/decimal"
+)
+
+type (
+	FlashSaleUsecase interface {
+		CreateFlashSale(ctx context.Context, payload dto.CreateFlashSalePayload) (*dto.FlashSaleResponse, error)
+		GetFlashSales(ctx context.Context, sellerID int64, params dto.FlashSaleParams) (*dto.FlashSaleListResponse, error)
+		GetFlashSale(ctx context.Context, id, sellerID int64) (*dto.FlashSaleResponse, error)
+		CancelFlashSale(ctx context.Context, id, sellerID int64) error
+		ApplyFlashSales(ctx context.Context) error
+	}
+	flashSaleUsecase struct {
+		fsr repository.FlashSaleRepository
+	}
+)
+
+// CreateFlashSale implements FlashSaleUsecase. Each variant gets either a
+// percentage discount or a fixed discounted price for the whole period.
+func (uc *flashSaleUsecase) CreateFlashSale(ctx context.Context, payload dto.CreateFlashSalePayload) (*dto.FlashSaleResponse, error) {
+	if !payload.EndAt.After(payload.StartAt) || !payload.EndAt.After(time.Now()) {
+		return nil, shared.ErrInvalidFlashSalePeriod
+	}
+
+	seen := make(map[int64]bool)
+	variants := make([]model.FlashSaleVariant, 0, len(payload.Variants))
+	for _, v := range payload.Variants {
+		if seen[v.ProductVariantID] {
+			return nil, shared.ErrDuplicateFlashSaleVariant
+		}
+		seen[v.ProductVariantID] = true
+
+		if (v.Discount == 0) == (v.DiscountedPrice == 0) {
+			return nil, shared.ErrInvalidFlashSaleDiscount
+		}
+
+		variant := model.FlashSaleVariant{
+			ProductVariantID: v.ProductVariantID,
+			PurchaseLimit:    v.PurchaseLimit,
+			Quota:            v.Quota,
+		}
+		if v.Discount != 0 {
+			variant.Discount = sql.NullFloat64{Float64: v.Discount, Valid: true}
+		} else {
+			variant.DiscountedPrice = decimal.NullDecimal{Decimal: decimal.NewFromFloat(v.DiscountedPrice), Valid: true}
+		}
+		variants = append(variants, variant)
+	}
+
+	flashSale := &model.FlashSale{
+		SellerID: payload.SellerID,
+		Name:     payload.Name,
+		StartAt:  payload.StartAt,
+		EndAt:    payload.EndAt,
+	}
+
+	id, err := uc.fsr.Create(ctx, flashSale, variants)
+	if err != nil {
+		var ce *shared.CustomError
+		if errors.As(err, &ce) {
+			return nil, err
+		}
+		return nil, shared.ErrCreateFlashSale
+	}
+
+	if !payload.StartAt.After(time.Now()) {
+		if err := uc.fsr.ApplyFlashSales(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return uc.GetFlashSale(ctx, *id, payload.SellerID)
+}
+
+// GetFlashSales implements FlashSaleUsecase.
+func (uc *flashSaleUsecase) GetFlashSales(ctx context.Context, sellerID int64, params dto.FlashSaleParams) (*dto.FlashSaleListResponse, error) {
+	if params.Page == 0 {
+		params.Page = constant.DefaultPage
+	}
+
+	flashSales, err := uc.fsr.FindBySellerID(ctx, sellerID, params.Page)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := uc.fsr.CountBySellerID(ctx, sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := uc.toFlashSaleResponses(ctx, flashSales)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.FlashSaleListResponse{
+		Items:       items,
+		TotalData:   count,
+		TotalPage:   int(math.Ceil(float64(count) / constant.FlashSaleDefaultItems)),
+		CurrentPage: params.Page,
+	}, nil
+}
+
+// GetFlashSale implements FlashSaleUsecase.
+func (uc *flashSaleUsecase) GetFlashSale(ctx context.Context, id, sellerID int64) (*dto.FlashSaleResponse, error) {
+	flashSale, err := uc.fsr.FirstByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if flashSale.SellerID != sellerID {
+		return nil, shared.ErrFlashSaleNotFound
+	}
+
+	res, err := uc.toFlashSaleResponses(ctx, []model.FlashSale{*flashSale})
+	if err != nil {
+		return nil, err
+	}
+
+	return &res[0], nil
+}
+
+// CancelFlashSale implements FlashSaleUsecase.
+func (uc *flashSaleUsecase) CancelFlashSale(ctx context.Context, id, sellerID int64) error {
+	flashSale, err := uc.fsr.FirstByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if flashSale.SellerID != sellerID {
+		return shared.ErrFlashSaleNotFound
+	}
+	if !flashSale.EndAt.After(time.Now()) {
+		return shared.ErrFlashSaleEnded
+	}
+
+	return uc.fsr.Cancel(ctx, id)
+}
+
+// ApplyFlashSales implements FlashSaleUsecase.
+func (uc *flashSaleUsecase) ApplyFlashSales(ctx context.Context) error {
+	return uc.fsr.ApplyFlashSales(ctx)
+}
+
+func (uc *flashSaleUsecase) toFlashSaleResponses(ctx context.Context, flashSales []model.FlashSale) ([]dto.FlashSaleResponse, error) {
+	ids := make([]int64, 0, len(flashSales))
+	for _, fs := range flashSales {
+		ids = append(ids, fs.ID)
+	}
+
+	variants, err := uc.fsr.FindVariantsByFlashSaleIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byFlashSale := make(map[int64][]dto.FlashSaleVariantResponse)
+	for _, v := range variants {
+		byFlashSale[v.FlashSaleID] = append(byFlashSale[v.FlashSaleID], dto.FlashSaleVariantResponse{
+			ProductVariantID: v.ProductVariantID,
+			ProductCode:      v.ProductCode,
+			ProductName:      v.ProductName,
+			VariantType1:     v.VariantType1,
+			VariantType2:     v.VariantType2,
+			Price:            v.Price,
+			Discount:         v.Discount,
+			DiscountedPrice:  v.DiscountedPrice,
+			PurchaseLimit:    v.PurchaseLimit,
+			Quota:            v.Quota,
+			Sold:             v.Sold,
+			Status:           v.Status,
+		})
+	}
+
+	res := make([]dto.FlashSaleResponse, 0, len(flashSales))
+	for _, fs := range flashSales {
+		items := byFlashSale[fs.ID]
+		if items == nil {
+			items = make([]dto.FlashSaleVariantResponse, 0)
+		}
+		res = append(res, dto.FlashSaleResponse{
+			ID:       fs.ID,
+			Name:     fs.Name,
+			StartAt:  fs.StartAt,
+			EndAt:    fs.EndAt,
+			Variants: items,
+		})
+	}
+
+	return res, nil
+}
+
+func NewFlashSaleUsecase(fsr repository.FlashSaleRepository) FlashSaleUsecase {
+	return &flashSaleUsecase{
+		fsr: fsr,
+	}
+}
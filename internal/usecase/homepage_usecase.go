@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/model"
 	"github.com/lil-oren/rest/internal/repository"
 	"github.com/shopspring/decimal"
 )
@@ -20,6 +21,7 @@ type (
 		rr   repository.ReviewRepository
 		catr repository.CategoryRepository
 		ccr  repository.CacheRepository
+		mr   repository.MediaRepository
 	}
 )
 
@@ -50,6 +52,33 @@ func (uc *homepageUsecase) GetRecommendedProducts(ctx context.Context) ([]dto.Ho
 	if err != nil {
 		return nil, err
 	}
+
+	urls := make([]string, 0)
+	for _, product := range res {
+		if product.ImageThumbnailUrl == "" {
+			urls = append(urls, product.ImageUrl)
+		}
+	}
+	if len(urls) == 0 {
+		return res, nil
+	}
+
+	medias, err := uc.mr.FindByURLs(ctx, urls)
+	if err != nil {
+		return nil, err
+	}
+	variants := make(map[string]model.Media)
+	for _, media := range medias {
+		variants[media.MediaUrl] = media
+	}
+	for idx, product := range res {
+		if media, ok := variants[product.ImageUrl]; ok {
+			res[idx].ImageThumbnailUrl = media.ThumbnailUrl.String
+			res[idx].ImageMediumUrl = media.MediumUrl.String
+			res[idx].ImageLargeUrl = media.LargeUrl.String
+		}
+	}
+
 	return res, nil
 }
 
@@ -76,6 +105,7 @@ func NewHomepageUsecase(
 	rr repository.ReviewRepository,
 	catr repository.CategoryRepository,
 	ccr repository.CacheRepository,
+	mr repository.MediaRepository,
 ) HomepageUsecase {
 	return &homepageUsecase{
 		pr:   pr,
@@ -83,5 +113,6 @@ func NewHomepageUsecase(
 		rr:   rr,
 		catr: catr,
 		ccr:  ccr,
+		mr:   mr,
 	}
 }
@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"math"
+	"net/smtp"
+	"strings"
+
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/repository"
+	"github.com/lil-oren/rest/internal/shared"
+)
+
+type (
+	InventoryUsecase interface {
+		GetStockHistory(ctx context.Context, payload dto.StockHistoryPayload) (*dto.StockHistoryResponseBody, error)
+		BulkUpdateStock(ctx context.Context, payload dto.BulkUpdateStockPayload) (*dto.BulkUpdateStockResponse, error)
+		UpdateLowStockThreshold(ctx context.Context, payload dto.UpdateLowStockThresholdPayload) error
+		SendLowStockAlerts(ctx context.Context) error
+	}
+	inventoryUsecase struct {
+		ir  repository.InventoryRepository
+		pr  repository.ProductRepository
+		cfg dependency.Config
+	}
+)
+
+// GetStockHistory implements InventoryUsecase.
+func (uc *inventoryUsecase) GetStockHistory(ctx context.Context, payload dto.StockHistoryPayload) (*dto.StockHistoryResponseBody, error) {
+	if payload.Page < 1 {
+		return nil, shared.ErrInvalidPage
+	}
+
+	product, err := uc.pr.FirstProductByCode(ctx, payload.ProductCode)
+	if err != nil {
+		if err == shared.ErrProductNotFound {
+			return nil, shared.ErrProductNotFound
+		}
+		return nil, shared.ErrFindProduct
+	}
+	if product.SellerID != payload.SellerID {
+		return nil, shared.ErrProductNotFromSeller
+	}
+
+	movements, err := uc.ir.FindStockMovements(ctx, payload)
+	if err != nil {
+		return nil, shared.ErrFindStockHistory
+	}
+
+	count, err := uc.ir.CountStockMovements(ctx, payload)
+	if err != nil {
+		return nil, shared.ErrFindStockHistory
+	}
+
+	res := &dto.StockHistoryResponseBody{
+		Movements: make([]dto.StockMovementResponse, 0, len(movements)),
+		Pagination: dto.StockHistoryPagination{
+			Page:       payload.Page,
+			TotalItems: count,
+			TotalPage:  int(math.Ceil(float64(count) / float64(constant.StockHistoryDefaultItems))),
+		},
+	}
+	for _, m := range movements {
+		res.Movements = append(res.Movements, dto.StockMovementResponse(m))
+	}
+
+	return res, nil
+}
+
+// BulkUpdateStock implements InventoryUsecase.
+func (uc *inventoryUsecase) BulkUpdateStock(ctx context.Context, payload dto.BulkUpdateStockPayload) (*dto.BulkUpdateStockResponse, error) {
+	seen := make(map[int64]bool)
+	for _, item := range payload.Items {
+		if seen[item.ProductVariantID] {
+			return nil, shared.ErrDuplicateStockItem
+		}
+		seen[item.ProductVariantID] = true
+	}
+
+	updated, err := uc.ir.BulkUpdateStock(ctx, payload)
+	if err != nil {
+		if err == shared.ErrProductVariantNotFound {
+			return nil, err
+		}
+		return nil, shared.ErrUpdateStock
+	}
+
+	return &dto.BulkUpdateStockResponse{UpdatedItems: updated}, nil
+}
+
+// UpdateLowStockThreshold implements InventoryUsecase.
+func (uc *inventoryUsecase) UpdateLowStockThreshold(ctx context.Context, payload dto.UpdateLowStockThresholdPayload) error {
+	err := uc.ir.UpdateLowStockThreshold(ctx, payload)
+	if err != nil {
+		if err == shared.ErrProductNotFound {
+			return err
+		}
+		return shared.ErrUpdateStock
+	}
+
+	return nil
+}
+
+// SendLowStockAlerts implements InventoryUsecase. Every seller gets one email
+// listing their variants that dropped to the low-stock threshold. A variant is
+// reported once until it is restocked above the threshold again. A seller
+// whose email could not be sent is left unmarked and alerted on a later run.
+func (uc *inventoryUsecase) SendLowStockAlerts(ctx context.Context) error {
+	threshold := uc.cfg.Inventory.LowStockThreshold
+	if err := uc.ir.ResetLowStockNotified(ctx, threshold); err != nil {
+		return err
+	}
+
+	variants, err := uc.ir.FindLowStockVariants(ctx, threshold)
+	if err != nil {
+		return err
+	}
+
+	bySeller := make(map[int64][]dto.LowStockVariant)
+	sellerIDs := make([]int64, 0)
+	for _, v := range variants {
+		if _, ok := bySeller[v.SellerID]; !ok {
+			sellerIDs = append(sellerIDs, v.SellerID)
+		}
+		bySeller[v.SellerID] = append(bySeller[v.SellerID], v)
+	}
+
+	smtpAuth := smtp.PlainAuth("", uc.cfg.EmailSender.Address, uc.cfg.EmailSender.Password, constant.SmtpAuthAddress)
+	failed := make([]string, 0)
+	for _, sellerID := range sellerIDs {
+		sellerVariants := bySeller[sellerID]
+
+		var rows strings.Builder
+		variantIDs := make([]int64, 0, len(sellerVariants))
+		for _, v := range sellerVariants {
+			rows.WriteString(fmt.Sprintf(constant.LowStockRowTemplate,
+				html.EscapeString(v.ProductName),
+				html.EscapeString(lowStockVariantName(v)),
+				v.Stock,
+				v.Threshold,
+			))
+			variantIDs = append(variantIDs, v.ProductVariantID)
+		}
+
+		first := sellerVariants[0]
+		content := fmt.Sprintf(constant.LowStockContentTemplate, html.EscapeString(first.SellerName), rows.String())
+		mail := shared.MakeEmail(uc.cfg.EmailSender.Name, uc.cfg.EmailSender.Address, constant.LowStockSubject, content, first.SellerEmail)
+		if err := mail.Send(constant.SmtpServerAddress, smtpAuth); err != nil {
+			failed = append(failed, fmt.Sprintf("seller %d: %s", sellerID, err.Error()))
+			continue
+		}
+
+		if err := uc.ir.MarkLowStockNotified(ctx, variantIDs); err != nil {
+			return err
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%d low stock alerts failed: %s", len(failed), strings.Join(failed, "; "))
+	}
+
+	return nil
+}
+
+func lowStockVariantName(v dto.LowStockVariant) string {
+	names := make([]string, 0, 2)
+	for _, name := range []string{v.VariantType1, v.VariantType2} {
+		if name != "" && name != constant.ProductVariantDefault {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "-"
+	}
+	return strings.Join(names, " - ")
+}
+
+func NewInventoryUsecase(ir repository.InventoryRepository, pr repository.ProductRepository, cfg dependency.Config) InventoryUsecase {
+	return &inventoryUsecase{
+		ir:  ir,
+		pr:  pr,
+		cfg: cfg,
+	}
+}
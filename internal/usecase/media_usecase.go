@@ -0,0 +1,323 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"database/sql"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/model"
+	"github.com/lil-oren/rest/internal/repository"
+	"github.com/lil-oren/rest/internal/shared"
+)
+
+type (
+	MediaUsecase interface {
+		UploadMedia(ctx context.Context, payload dto.UploadMediaPayload) (*dto.UploadMediaResponse, error)
+		GetSignedURL(ctx context.Context, mediaID, accountID int64) (*dto.MediaSignedURLResponse, error)
+		OpenMedia(ctx context.Context, payload dto.OpenMediaPayload) (*dto.OpenMediaResponse, error)
+		ProcessPendingMedias(ctx context.Context) error
+	}
+	mediaUsecase struct {
+		mr  repository.MediaRepository
+		pmr repository.ProductMediaRepository
+		ms  repository.MediaStorage
+		cfg dependency.Config
+	}
+)
+
+// UploadMedia implements MediaUsecase. Images are stored without their
+// metadata, see shared.StripImageMetadata.
+func (uc *mediaUsecase) UploadMedia(ctx context.Context, payload dto.UploadMediaPayload) (*dto.UploadMediaResponse, error) {
+	head := make([]byte, constant.MediaSniffLength)
+	n, err := io.ReadFull(payload.File, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	head = head[:n]
+
+	contentType := http.DetectContentType(head)
+	allowed, ok := constant.AllowedMediaContentTypes[contentType]
+	if !ok {
+		return nil, shared.ErrMediaTypeNotAllowed
+	}
+
+	limit := uc.cfg.MediaStorage.MaxImageSize
+	if allowed.MediaType == constant.VideoTypeDefault {
+		limit = uc.cfg.MediaStorage.MaxVideoSize
+	}
+
+	if payload.Size > limit {
+		return nil, shared.ErrMediaTooLarge
+	}
+
+	var body io.Reader = io.MultiReader(bytes.NewReader(head), payload.File)
+	size := payload.Size
+	if allowed.MediaType == constant.ImageTypeDefault {
+		raw, err := io.ReadAll(io.LimitReader(body, limit+1))
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(raw)) > limit {
+			return nil, shared.ErrMediaTooLarge
+		}
+
+		raw, err = shared.StripImageMetadata(raw, contentType)
+		if err != nil {
+			return nil, err
+		}
+		body, size = bytes.NewReader(raw), int64(len(raw))
+	}
+
+	key := fmt.Sprintf(constant.MediaKeyTemplate, payload.AccountID, shared.GenerateUUID(), allowed.Extension)
+	if err := uc.ms.Put(ctx, key, contentType, body, size); err != nil {
+		return nil, shared.ErrUploadMedia
+	}
+
+	media := &model.Media{
+		AccountID:   payload.AccountID,
+		StorageKey:  key,
+		MediaUrl:    uc.ms.URL(key),
+		MediaType:   allowed.MediaType,
+		ContentType: contentType,
+		Size:        size,
+	}
+
+	id, err := uc.mr.Create(ctx, media)
+	if err != nil {
+		_ = uc.ms.Delete(ctx, key)
+		return nil, err
+	}
+
+	signedURL, err := uc.ms.SignedURL(key, uc.signedURLExpiration())
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.UploadMediaResponse{
+		ID:          *id,
+		MediaURL:    media.MediaUrl,
+		SignedURL:   signedURL,
+		MediaType:   media.MediaType,
+		ContentType: media.ContentType,
+		Size:        media.Size,
+	}
+
+	return res, nil
+}
+
+// GetSignedURL implements MediaUsecase.
+func (uc *mediaUsecase) GetSignedURL(ctx context.Context, mediaID, accountID int64) (*dto.MediaSignedURLResponse, error) {
+	media, err := uc.mr.FirstByID(ctx, mediaID)
+	if err != nil {
+		return nil, err
+	}
+
+	if media.AccountID != accountID {
+		return nil, shared.ErrMediaNotOwned
+	}
+
+	expiration := uc.signedURLExpiration()
+	signedURL, err := uc.ms.SignedURL(media.StorageKey, expiration)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.MediaSignedURLResponse{
+		SignedURL: signedURL,
+		ExpiredAt: time.Now().Add(expiration).Format("2006-01-02 15:04:05"),
+	}
+
+	return res, nil
+}
+
+// OpenMedia implements MediaUsecase. Media that is not published yet, and
+// the variants of it, are only served with a valid signature.
+func (uc *mediaUsecase) OpenMedia(ctx context.Context, payload dto.OpenMediaPayload) (*dto.OpenMediaResponse, error) {
+	media, err := uc.mr.FirstByStorageKey(ctx, payload.StorageKey)
+	if err != nil {
+		return nil, err
+	}
+
+	published := media.PublishedAt.Valid
+	if media.VariantOf.Valid {
+		parent, err := uc.mr.FirstByID(ctx, media.VariantOf.Int64)
+		if err != nil {
+			return nil, err
+		}
+		published = parent.PublishedAt.Valid
+	}
+
+	if !published {
+		if err := uc.checkSignature(payload); err != nil {
+			return nil, err
+		}
+	}
+
+	body, err := uc.ms.Open(ctx, media.StorageKey)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.OpenMediaResponse{
+		ContentType: media.ContentType,
+		Size:        media.Size,
+		Body:        body,
+	}
+
+	return res, nil
+}
+
+// ProcessPendingMedias implements MediaUsecase. An image that fails is
+// counted and tried again on a later run, the others are processed anyway.
+func (uc *mediaUsecase) ProcessPendingMedias(ctx context.Context) error {
+	medias, err := uc.mr.FindUnprocessedImages(ctx, constant.MediaProcessingBatchSize)
+	if err != nil {
+		return err
+	}
+
+	failed := make([]string, 0)
+	for _, media := range medias {
+		variants, err := uc.createVariants(ctx, media)
+		if err == nil {
+			err = uc.mr.SaveVariants(ctx, media.ID, variants)
+		}
+		if err == nil {
+			continue
+		}
+
+		failed = append(failed, fmt.Sprintf("media %d: %s", media.ID, err.Error()))
+		if err := uc.mr.MarkProcessingFailed(ctx, media.ID); err != nil {
+			return err
+		}
+	}
+
+	if err := uc.pmr.SyncVariants(ctx); err != nil {
+		return err
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%d medias failed processing: %s", len(failed), strings.Join(failed, "; "))
+	}
+
+	return nil
+}
+
+// createVariants re-encodes the image as jpeg in every size of
+// constant.MediaVariants. Images that cannot be decoded get no variants so
+// clients keep using the original upload.
+func (uc *mediaUsecase) createVariants(ctx context.Context, media model.Media) ([]model.Media, error) {
+	variants := make([]model.Media, 0)
+
+	body, err := uc.ms.Open(ctx, media.StorageKey)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	img, err := shared.DecodeImage(body)
+	if err != nil {
+		return variants, nil
+	}
+
+	base := strings.TrimSuffix(media.StorageKey, path.Ext(media.StorageKey))
+	for _, v := range constant.MediaVariants {
+		buf, err := shared.EncodeJPEG(shared.ResizeImage(img, v.MaxDimension), constant.MediaVariantJPEGQuality)
+		if err != nil {
+			return nil, err
+		}
+
+		key := fmt.Sprintf(constant.MediaVariantKeyTemplate, base, v.Name, constant.JPGImageType)
+		size := int64(buf.Len())
+		if err := uc.ms.Put(ctx, key, constant.MediaVariantContentType, buf, size); err != nil {
+			return nil, err
+		}
+
+		variants = append(variants, model.Media{
+			AccountID:   media.AccountID,
+			StorageKey:  key,
+			MediaUrl:    uc.ms.URL(key),
+			MediaType:   constant.ImageTypeDefault,
+			ContentType: constant.MediaVariantContentType,
+			Size:        size,
+			VariantName: sql.NullString{String: v.Name, Valid: true},
+		})
+	}
+
+	return variants, nil
+}
+
+func (uc *mediaUsecase) checkSignature(payload dto.OpenMediaPayload) error {
+	expires, err := strconv.ParseInt(payload.Expires, 10, 64)
+	if err != nil {
+		return shared.ErrInvalidMediaSignature
+	}
+
+	if time.Now().Unix() > expires {
+		return shared.ErrInvalidMediaSignature
+	}
+
+	expected := repository.SignMediaKey(uc.cfg.MediaStorage.SigningSecret, payload.StorageKey, expires)
+	if !hmac.Equal([]byte(expected), []byte(payload.Signature)) {
+		return shared.ErrInvalidMediaSignature
+	}
+
+	return nil
+}
+
+func (uc *mediaUsecase) signedURLExpiration() time.Duration {
+	return time.Duration(uc.cfg.MediaStorage.SignedURLExpiration) * time.Minute
+}
+
+// ownedMedias makes sure every url was uploaded by the account through
+// POST /media and returns the uploaded media keyed by url. The media is
+// published since it is about to be shown to others.
+func ownedMedias(ctx context.Context, mr repository.MediaRepository, accountID int64, urls []string) (map[string]model.Media, error) {
+	res := make(map[string]model.Media)
+	if len(urls) == 0 {
+		return res, nil
+	}
+
+	medias, err := mr.FindByAccountIDAndURLs(ctx, accountID, urls)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, media := range medias {
+		res[media.MediaUrl] = media
+	}
+
+	ids := make([]int64, 0, len(medias))
+	for _, url := range urls {
+		media, ok := res[url]
+		if !ok {
+			return nil, shared.ErrMediaNotOwned
+		}
+		ids = append(ids, media.ID)
+	}
+
+	if err := mr.Publish(ctx, ids); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func NewMediaUsecase(mr repository.MediaRepository, pmr repository.ProductMediaRepository, ms repository.MediaStorage, cfg dependency.Config) MediaUsecase {
+	return &mediaUsecase{
+		mr:  mr,
+		pmr: pmr,
+		ms:  ms,
+		cfg: cfg,
+	}
+}
@@ -0,0 +1,293 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/model"
+	"github.com/lil-oren/rest/internal/repository"
+	"github.com/shopspring/decimal"
+)
+
+type (
+	NotificationUsecase interface {
+		GetNotifications(ctx context.Context, accountID int64, params dto.NotificationParams) (*dto.NotificationListResponse, error)
+		GetUnreadCount(ctx context.Context, accountID int64) (*dto.UnreadNotificationResponse, error)
+		ReadNotification(ctx context.Context, accountID, id int64) error
+		ReadAllNotifications(ctx context.Context, accountID int64) error
+		SubscribeNotifications(accountID int64) (<-chan dto.NotificationResponse, func())
+		ListenNotifications(ctx context.Context) error
+		NotifyOrderEvent(ctx context.Context, event dto.DomainEvent) error
+		NotifyWalletCredited(ctx context.Context, event dto.DomainEvent) error
+		NotifyFlashSaleStarted(ctx context.Context, event dto.DomainEvent) error
+	}
+	notificationUsecase struct {
+		nr repository.NotificationRepository
+		nb repository.NotificationBroker
+	}
+)
+
+// GetNotifications implements NotificationUsecase.
+func (uc *notificationUsecase) GetNotifications(ctx context.Context, accountID int64, params dto.NotificationParams) (*dto.NotificationListResponse, error) {
+	if params.Page == 0 {
+		params.Page = constant.DefaultPage
+	}
+
+	notifications, err := uc.nr.Find(ctx, accountID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := uc.nr.Count(ctx, accountID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	unread, err := uc.nr.CountUnread(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.NotificationListResponse{
+		Items:       make([]dto.NotificationResponse, 0, len(notifications)),
+		UnreadCount: unread,
+		TotalData:   count,
+		TotalPage:   int(math.Ceil(float64(count) / constant.NotificationDefaultItems)),
+		CurrentPage: params.Page,
+	}
+	for _, notification := range notifications {
+		res.Items = append(res.Items, toNotificationResponse(notification))
+	}
+
+	return res, nil
+}
+
+// GetUnreadCount implements NotificationUsecase.
+func (uc *notificationUsecase) GetUnreadCount(ctx context.Context, accountID int64) (*dto.UnreadNotificationResponse, error) {
+	unread, err := uc.nr.CountUnread(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.UnreadNotificationResponse{UnreadCount: unread}, nil
+}
+
+// ReadNotification implements NotificationUsecase.
+func (uc *notificationUsecase) ReadNotification(ctx context.Context, accountID, id int64) error {
+	return uc.nr.MarkRead(ctx, accountID, id)
+}
+
+// ReadAllNotifications implements NotificationUsecase.
+func (uc *notificationUsecase) ReadAllNotifications(ctx context.Context, accountID int64) error {
+	return uc.nr.MarkAllRead(ctx, accountID)
+}
+
+// SubscribeNotifications implements NotificationUsecase.
+func (uc *notificationUsecase) SubscribeNotifications(accountID int64) (<-chan dto.NotificationResponse, func()) {
+	return uc.nb.Subscribe(accountID)
+}
+
+// ListenNotifications implements NotificationUsecase.
+func (uc *notificationUsecase) ListenNotifications(ctx context.Context) error {
+	return uc.nb.Listen(ctx)
+}
+
+// NotifyOrderEvent implements NotificationUsecase. It subscribes to
+// ORDER_CREATED and ORDER_STATUS_CHANGED events and tells the buyer or the
+// seller, whoever has to act on the order or wait for the other, what
+// happened to it.
+func (uc *notificationUsecase) NotifyOrderEvent(ctx context.Context, event dto.DomainEvent) error {
+	payload := dto.OrderEventPayload{}
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return err
+	}
+
+	orderID := payload.OrderID
+	payloads := make([]dto.NotifyPayload, 0, 2)
+	switch {
+	case event.Type == constant.OrderCreatedEvent:
+		payloads = append(payloads, orderNotification(event.ID, payload.SellerID, orderID, "New order", fmt.Sprintf("You have a new order #%d. Process it before it is cancelled.", orderID)))
+	case payload.Status == string(constant.ProcessOrderStatus):
+		payloads = append(payloads, orderNotification(event.ID, payload.BuyerID, orderID, "Order processed", fmt.Sprintf("The seller is preparing order #%d.", orderID)))
+	case payload.Status == string(constant.DeliverOrderStatus):
+		payloads = append(payloads, orderNotification(event.ID, payload.BuyerID, orderID, "Order shipped", fmt.Sprintf("Order #%d is on its way.", orderID)))
+	case payload.Status == string(constant.ArriveOrderStatus):
+		payloads = append(payloads, orderNotification(event.ID, payload.BuyerID, orderID, "Order arrived", fmt.Sprintf("Order #%d has arrived. Confirm that you received it.", orderID)))
+	case payload.Status == string(constant.ReceiveOrderStatus):
+		payloads = append(payloads, orderNotification(event.ID, payload.SellerID, orderID, "Order completed", fmt.Sprintf("The buyer has received order #%d.", orderID)))
+	case payload.Status == string(constant.CancelOrderStatus):
+		message := fmt.Sprintf("Order #%d was cancelled.", orderID)
+		payloads = append(payloads,
+			orderNotification(event.ID, payload.BuyerID, orderID, "Order cancelled", message),
+			orderNotification(event.ID, payload.SellerID, orderID, "Order cancelled", message),
+		)
+	}
+
+	for _, p := range payloads {
+		if err := uc.notify(ctx, p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// NotifyWalletCredited implements NotificationUsecase. It subscribes to
+// WALLET_CREDITED events. Only user and shop wallets are the business of
+// their owner, money moving through the other ones is not notified.
+func (uc *notificationUsecase) NotifyWalletCredited(ctx context.Context, event dto.DomainEvent) error {
+	payload := dto.WalletCreditedPayload{}
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return err
+	}
+
+	if payload.WalletType != string(constant.UserWalletType) && payload.WalletType != string(constant.ShopWalletType) {
+		return nil
+	}
+
+	source, ok := walletCreditSource(constant.TransactionTitle(payload.Title))
+	if !ok {
+		return nil
+	}
+
+	return uc.notify(ctx, walletCreditNotification(event.ID, payload.AccountID, payload.Amount, source))
+}
+
+// NotifyFlashSaleStarted implements NotificationUsecase. It subscribes to
+// FLASH_SALE_STARTED events and tells the seller and the buyers who wished
+// for one of its products that the flash sale is running.
+func (uc *notificationUsecase) NotifyFlashSaleStarted(ctx context.Context, event dto.DomainEvent) error {
+	payload := dto.FlashSaleStartedPayload{}
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return err
+	}
+
+	payloads := make([]dto.NotifyPayload, 0, len(payload.WisherIDs)+1)
+	payloads = append(payloads, promotionNotification(event.ID, payload.SellerID, payload.FlashSaleID, fmt.Sprintf("Your flash sale %s has started.", payload.Name)))
+	for _, accountID := range payload.WisherIDs {
+		payloads = append(payloads, promotionNotification(event.ID, accountID, payload.FlashSaleID, fmt.Sprintf("A product on your wishlist is on sale in %s.", payload.Name)))
+	}
+
+	for _, p := range payloads {
+		if err := uc.notify(ctx, p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// notify stores a notification before it is pushed, so a client that is
+// not connected finds it in its list. Pushing is best effort: the
+// notification is not pushed again when the event is retried.
+func (uc *notificationUsecase) notify(ctx context.Context, payload dto.NotifyPayload) error {
+	notification := &model.Notification{
+		AccountID: payload.AccountID,
+		Type:      string(payload.Type),
+		Title:     payload.Title,
+		Message:   payload.Message,
+		ReferenceID: sql.NullInt64{
+			Int64: payload.ReferenceID,
+			Valid: payload.ReferenceID != 0,
+		},
+		EventID: sql.NullInt64{
+			Int64: payload.EventID,
+			Valid: payload.EventID != 0,
+		},
+	}
+	if err := uc.nr.Create(ctx, notification); err != nil {
+		return err
+	}
+	if notification.ID == 0 {
+		return nil
+	}
+
+	_ = uc.nb.Publish(ctx, dto.NotificationEvent{
+		AccountID:    notification.AccountID,
+		Notification: toNotificationResponse(*notification),
+	})
+	return nil
+}
+
+func toNotificationResponse(notification model.Notification) dto.NotificationResponse {
+	res := dto.NotificationResponse{
+		ID:        notification.ID,
+		Type:      notification.Type,
+		Title:     notification.Title,
+		Message:   notification.Message,
+		IsRead:    notification.ReadAt.Valid,
+		CreatedAt: notification.CreatedAt.Time.Format("2006-01-02 15:04:05"),
+	}
+	if notification.ReferenceID.Valid {
+		res.ReferenceID = &notification.ReferenceID.Int64
+	}
+
+	return res
+}
+
+func orderNotification(eventID, accountID, orderID int64, title, message string) dto.NotifyPayload {
+	return dto.NotifyPayload{
+		EventID:     eventID,
+		AccountID:   accountID,
+		Type:        constant.OrderStatusNotification,
+		Title:       title,
+		Message:     message,
+		ReferenceID: orderID,
+	}
+}
+
+func walletCreditNotification(eventID, accountID int64, amount decimal.Decimal, source string) dto.NotifyPayload {
+	return dto.NotifyPayload{
+		EventID:   eventID,
+		AccountID: accountID,
+		Type:      constant.WalletCreditNotification,
+		Title:     "Wallet credited",
+		Message:   fmt.Sprintf("%s from %s has been added to your wallet.", formatRupiah(amount), source),
+	}
+}
+
+func promotionNotification(eventID, accountID, flashSaleID int64, message string) dto.NotifyPayload {
+	return dto.NotifyPayload{
+		EventID:     eventID,
+		AccountID:   accountID,
+		Type:        constant.PromotionNotification,
+		Title:       "Flash sale started",
+		Message:     message,
+		ReferenceID: flashSaleID,
+	}
+}
+
+// walletCreditSource tells where the money of a transaction with title came
+// from, if it is worth a notification.
+func walletCreditSource(title constant.TransactionTitle) (string, bool) {
+	switch title {
+	case constant.TopUpTitle:
+		return "your top up", true
+	case constant.WithdrawTitle:
+		return "your shop wallet", true
+	case constant.TransferTitle:
+		return "a completed order", true
+	case constant.RefundTitle, constant.ReturnRefundTitle:
+		return "a refund", true
+	case constant.ReturnReleaseTitle:
+		return "a closed return", true
+	case constant.VoucherSubsidyTitle:
+		return "a platform voucher subsidy", true
+	case constant.CommissionBackTitle:
+		return "a commission refund", true
+	default:
+		return "", false
+	}
+}
+
+func NewNotificationUsecase(nr repository.NotificationRepository, nb repository.NotificationBroker) NotificationUsecase {
+	return &notificationUsecase{
+		nr: nr,
+		nb: nb,
+	}
+}
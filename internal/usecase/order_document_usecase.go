@@ -0,0 +1,482 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"html/template"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/repository"
+	"github.com/lil-oren/rest/internal/shared"
+	"github.com/shopspring/decimal"
+)
+
+type (
+	OrderDocumentUsecase interface {
+		GetInvoice(ctx context.Context, orderID, accountID int64, format string) (*dto.OrderDocumentResponse, error)
+		GetReceipt(ctx context.Context, orderID, buyerID int64, format string) (*dto.OrderDocumentResponse, error)
+		GetPackingSlip(ctx context.Context, orderID, sellerID int64, format string) (*dto.OrderDocumentResponse, error)
+		GetPackingSlips(ctx context.Context, orderIDs []int64, sellerID int64, format string) (*dto.OrderDocumentResponse, error)
+	}
+	orderDocumentUsecase struct {
+		odr repository.OrderDocumentRepository
+	}
+)
+
+// GetInvoice implements OrderDocumentUsecase. Both the buyer and the seller
+// of an order can get its invoice.
+func (uc *orderDocumentUsecase) GetInvoice(ctx context.Context, orderID, accountID int64, format string) (*dto.OrderDocumentResponse, error) {
+	orders, items, err := uc.findOrders(ctx, []int64{orderID}, func(order dto.OrderDocumentModel) bool {
+		return order.BuyerID == accountID || order.SellerID == accountID
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	doc := invoiceDocument(orders[0], items[orderID])
+	return renderOrderDocuments([]dto.OrderDocument{doc}, format, fmt.Sprintf(constant.InvoiceFileTemplate, orderID, format))
+}
+
+// GetReceipt implements OrderDocumentUsecase.
+func (uc *orderDocumentUsecase) GetReceipt(ctx context.Context, orderID, buyerID int64, format string) (*dto.OrderDocumentResponse, error) {
+	orders, items, err := uc.findOrders(ctx, []int64{orderID}, func(order dto.OrderDocumentModel) bool {
+		return order.BuyerID == buyerID
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	doc := receiptDocument(orders[0], items[orderID])
+	return renderOrderDocuments([]dto.OrderDocument{doc}, format, fmt.Sprintf(constant.ReceiptFileTemplate, orderID, format))
+}
+
+// GetPackingSlip implements OrderDocumentUsecase.
+func (uc *orderDocumentUsecase) GetPackingSlip(ctx context.Context, orderID, sellerID int64, format string) (*dto.OrderDocumentResponse, error) {
+	res, err := uc.packingSlips(ctx, []int64{orderID}, sellerID, format)
+	if err != nil {
+		return nil, err
+	}
+
+	res.FileName = fmt.Sprintf(constant.PackingSlipFileTemplate, orderID, format)
+	return res, nil
+}
+
+// GetPackingSlips implements OrderDocumentUsecase. The slips of all orders
+// are put in a single document, one order per page.
+func (uc *orderDocumentUsecase) GetPackingSlips(ctx context.Context, orderIDs []int64, sellerID int64, format string) (*dto.OrderDocumentResponse, error) {
+	res, err := uc.packingSlips(ctx, orderIDs, sellerID, format)
+	if err != nil {
+		return nil, err
+	}
+
+	res.FileName = fmt.Sprintf(constant.PackingSlipsFileTemplate, time.Now().Format("20060102150405"), format)
+	return res, nil
+}
+
+func (uc *orderDocumentUsecase) packingSlips(ctx context.Context, orderIDs []int64, sellerID int64, format string) (*dto.OrderDocumentResponse, error) {
+	orders, items, err := uc.findOrders(ctx, orderIDs, func(order dto.OrderDocumentModel) bool {
+		return order.SellerID == sellerID
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make([]dto.OrderDocument, 0, len(orders))
+	for _, order := range orders {
+		if order.Status == string(constant.CancelOrderStatus) {
+			return nil, shared.ErrPackingSlipCancelled
+		}
+		docs = append(docs, packingSlipDocument(order, items[order.ID]))
+	}
+
+	return renderOrderDocuments(docs, format, "")
+}
+
+// findOrders loads the orders a document is made of along with their items
+// by order id. Every order has to be allowed for the account asking, and
+// orders placed before invoices were numbered get their number here.
+func (uc *orderDocumentUsecase) findOrders(ctx context.Context, orderIDs []int64, allowed func(dto.OrderDocumentModel) bool) ([]dto.OrderDocumentModel, map[int64][]dto.OrderDocumentItemModel, error) {
+	orders, err := uc.odr.FindByOrderIDs(ctx, orderIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(orders) != len(orderIDs) {
+		return nil, nil, shared.ErrOrderIDNotFount
+	}
+
+	for i, order := range orders {
+		if !allowed(order) {
+			return nil, nil, shared.ErrOrderIDNotFount
+		}
+		if order.InvoiceNumber.Valid {
+			continue
+		}
+
+		number, err := uc.odr.AssignInvoiceNumber(ctx, order.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		orders[i].InvoiceNumber = sql.NullString{String: number, Valid: true}
+	}
+
+	items, err := uc.odr.FindItemsByOrderIDs(ctx, orderIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	itemsByOrder := make(map[int64][]dto.OrderDocumentItemModel, len(orders))
+	for _, item := range items {
+		itemsByOrder[item.OrderID] = append(itemsByOrder[item.OrderID], item)
+	}
+
+	return orders, itemsByOrder, nil
+}
+
+func invoiceDocument(order dto.OrderDocumentModel, items []dto.OrderDocumentItemModel) dto.OrderDocument {
+	doc := dto.OrderDocument{
+		Title: "Invoice",
+		Fields: []dto.OrderDocumentField{
+			{Label: "Invoice number", Value: order.InvoiceNumber.String},
+			{Label: "Order ID", Value: strconv.FormatInt(order.ID, 10)},
+			{Label: "Order date", Value: documentDate(order.CreatedAt)},
+			{Label: "Status", Value: order.Status},
+		},
+		Parties: []dto.OrderDocumentParty{
+			{Heading: "Seller", Lines: []string{order.ShopName}},
+			{Heading: "Buyer", Lines: []string{order.BuyerUsername, order.BuyerEmail}},
+			{Heading: "Ship to", Lines: receiverLines(order)},
+		},
+		Items:     documentItems(items, true),
+		ShowPrice: true,
+	}
+
+	subtotal := decimal.Zero
+	for _, item := range items {
+		subtotal = subtotal.Add(item.SubTotalPrice)
+	}
+	doc.Totals = append(doc.Totals, dto.OrderDocumentField{Label: "Subtotal", Value: formatRupiah(subtotal)})
+	if order.PromotionAmount.Float64 > 0 {
+		label := "Promotion"
+		if order.PromotionName.String != "" {
+			label = fmt.Sprintf("Promotion (%s)", order.PromotionName.String)
+		}
+		doc.Totals = append(doc.Totals, dto.OrderDocumentField{Label: label, Value: formatRupiah(decimal.NewFromFloat(-order.PromotionAmount.Float64))})
+	}
+	if order.VoucherAmount.Float64 > 0 {
+		doc.Totals = append(doc.Totals, dto.OrderDocumentField{Label: "Voucher", Value: formatRupiah(decimal.NewFromFloat(-order.VoucherAmount.Float64))})
+	}
+	doc.Totals = append(doc.Totals, dto.OrderDocumentField{Label: "Delivery cost", Value: formatRupiah(order.DeliveryCost)})
+	if order.ServiceFee.IsPositive() {
+		doc.Totals = append(doc.Totals, dto.OrderDocumentField{Label: "Service fee", Value: formatRupiah(order.ServiceFee)})
+	}
+	doc.Totals = append(doc.Totals, dto.OrderDocumentField{Label: "Total", Value: formatRupiah(order.PaymentAmount)})
+
+	return doc
+}
+
+func receiptDocument(order dto.OrderDocumentModel, items []dto.OrderDocumentItemModel) dto.OrderDocument {
+	return dto.OrderDocument{
+		Title: "Payment Receipt",
+		Fields: []dto.OrderDocumentField{
+			{Label: "Invoice number", Value: order.InvoiceNumber.String},
+			{Label: "Order ID", Value: strconv.FormatInt(order.ID, 10)},
+			{Label: "Paid at", Value: documentDate(order.CreatedAt)},
+			{Label: "Paid by", Value: order.BuyerUsername},
+			{Label: "Payment method", Value: "Wallet"},
+		},
+		Parties: []dto.OrderDocumentParty{
+			{Heading: "Paid to", Lines: []string{order.ShopName}},
+		},
+		Items: documentItems(items, true),
+		Totals: []dto.OrderDocumentField{
+			{Label: "Amount paid", Value: formatRupiah(order.PaymentAmount)},
+		},
+		ShowPrice: true,
+	}
+}
+
+func packingSlipDocument(order dto.OrderDocumentModel, items []dto.OrderDocumentItemModel) dto.OrderDocument {
+	weight := int64(0)
+	for _, item := range items {
+		weight += item.Weight.Int64 * int64(item.Quantity)
+	}
+
+	courier := order.CourierName
+	if order.CourierService.String != "" {
+		courier = fmt.Sprintf("%s %s", courier, order.CourierService.String)
+	}
+	waybill := order.WaybillNumber.String
+	if waybill == "" {
+		waybill = "-"
+	}
+
+	sender := addressLines(order.SenderName, order.SenderPhoneNumber, order.SenderAddress, order.SenderDistrict, order.SenderProvince, order.SenderPostalCode)
+	if len(sender) == 0 {
+		sender = []string{order.ShopName}
+	}
+
+	return dto.OrderDocument{
+		Title: "Packing Slip",
+		Fields: []dto.OrderDocumentField{
+			{Label: "Invoice number", Value: order.InvoiceNumber.String},
+			{Label: "Order ID", Value: strconv.FormatInt(order.ID, 10)},
+			{Label: "Order date", Value: documentDate(order.CreatedAt)},
+			{Label: "Courier", Value: courier},
+			{Label: "Waybill number", Value: waybill},
+			{Label: "Weight", Value: fmt.Sprintf("%d g", weight)},
+		},
+		Parties: []dto.OrderDocumentParty{
+			{Heading: "From", Lines: sender},
+			{Heading: "To", Lines: receiverLines(order)},
+		},
+		Items: documentItems(items, false),
+	}
+}
+
+func documentItems(items []dto.OrderDocumentItemModel, showPrice bool) []dto.OrderDocumentItem {
+	res := make([]dto.OrderDocumentItem, 0, len(items))
+	for _, item := range items {
+		docItem := dto.OrderDocumentItem{
+			Name:     item.ProductName,
+			Variant:  item.VariantName,
+			Quantity: item.Quantity,
+		}
+		if showPrice {
+			docItem.Amount = formatRupiah(item.SubTotalPrice)
+		}
+		res = append(res, docItem)
+	}
+
+	return res
+}
+
+func receiverLines(order dto.OrderDocumentModel) []string {
+	lines := addressLines(order.ReceiverName, order.ReceiverPhoneNumber, order.ReceiverAddress, order.ReceiverDistrict, order.ReceiverProvince, order.ReceiverPostalCode)
+	if len(lines) == 0 {
+		return []string{"-"}
+	}
+
+	return lines
+}
+
+func addressLines(name, phone, detail, district, province, postalCode sql.NullString) []string {
+	lines := make([]string, 0, 4)
+	for _, s := range []sql.NullString{name, phone, detail} {
+		if s.String != "" {
+			lines = append(lines, s.String)
+		}
+	}
+
+	region := make([]string, 0, 3)
+	for _, s := range []sql.NullString{district, province, postalCode} {
+		if s.String != "" {
+			region = append(region, s.String)
+		}
+	}
+	if len(region) > 0 {
+		lines = append(lines, strings.Join(region, ", "))
+	}
+
+	return lines
+}
+
+func documentDate(t sql.NullTime) string {
+	if !t.Valid {
+		return "-"
+	}
+
+	return t.Time.Format(constant.OrderDocumentDateLayout)
+}
+
+// formatRupiah formats amount as whole rupiah with dots between thousands.
+func formatRupiah(amount decimal.Decimal) string {
+	sign := ""
+	if amount.IsNegative() {
+		sign = "-"
+		amount = amount.Neg()
+	}
+
+	digits := amount.Round(0).String()
+	var sb strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			sb.WriteByte('.')
+		}
+		sb.WriteRune(d)
+	}
+
+	return sign + "Rp" + sb.String()
+}
+
+func renderOrderDocuments(docs []dto.OrderDocument, format, fileName string) (*dto.OrderDocumentResponse, error) {
+	res := &dto.OrderDocumentResponse{
+		FileName: fileName,
+		Body:     new(bytes.Buffer),
+	}
+
+	if format == constant.OrderDocumentFormatHTML {
+		res.ContentType = constant.OrderDocumentHTMLContentType
+		if err := orderDocumentTemplate.Execute(res.Body, docs); err != nil {
+			return nil, err
+		}
+		return res, nil
+	}
+
+	pages := make([][]shared.PDFLine, 0, len(docs))
+	for _, doc := range docs {
+		pages = append(pages, orderDocumentPDFLines(doc))
+	}
+	res.ContentType = constant.OrderDocumentPDFContentType
+	if err := shared.WritePDF(res.Body, pages); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// orderDocumentPDFLines lays a document out in lines of
+// constant.OrderDocumentLineWidth monospaced characters.
+func orderDocumentPDFLines(doc dto.OrderDocument) []shared.PDFLine {
+	width := constant.OrderDocumentLineWidth
+	rule := shared.PDFLine{Text: strings.Repeat("-", width)}
+	lines := []shared.PDFLine{
+		{Text: doc.Title, Size: 14, Bold: true},
+		{},
+	}
+
+	for _, field := range doc.Fields {
+		lines = append(lines, shared.PDFLine{Text: fmt.Sprintf("%-16s: %s", field.Label, field.Value)})
+	}
+	lines = append(lines, shared.PDFLine{})
+
+	for _, party := range doc.Parties {
+		lines = append(lines, shared.PDFLine{Text: party.Heading, Bold: true})
+		for _, line := range party.Lines {
+			for _, text := range wrapText(line, width) {
+				lines = append(lines, shared.PDFLine{Text: text})
+			}
+		}
+		lines = append(lines, shared.PDFLine{})
+	}
+
+	// the item name takes whatever the quantity and amount columns leave
+	nameWidth := width - 7
+	if doc.ShowPrice {
+		nameWidth -= 19
+	}
+	row := func(name, qty, amount string) string {
+		if doc.ShowPrice {
+			return fmt.Sprintf("%-*s %6s %18s", nameWidth, name, qty, amount)
+		}
+		return fmt.Sprintf("%-*s %6s", nameWidth, name, qty)
+	}
+
+	lines = append(lines, shared.PDFLine{Text: row("Item", "Qty", "Amount"), Bold: true}, rule)
+	for _, item := range doc.Items {
+		names := wrapText(item.Name, nameWidth)
+		lines = append(lines, shared.PDFLine{Text: row(names[0], strconv.Itoa(item.Quantity), item.Amount)})
+		for _, name := range names[1:] {
+			lines = append(lines, shared.PDFLine{Text: name})
+		}
+		if item.Variant != "" {
+			lines = append(lines, shared.PDFLine{Text: "  " + item.Variant})
+		}
+	}
+	lines = append(lines, rule)
+
+	for i, total := range doc.Totals {
+		lines = append(lines, shared.PDFLine{
+			Text: fmt.Sprintf("%*s %18s", width-19, total.Label, total.Value),
+			Bold: i == len(doc.Totals)-1,
+		})
+	}
+
+	return lines
+}
+
+// wrapText breaks s into lines of at most width characters, between words
+// where it can.
+func wrapText(s string, width int) []string {
+	lines := make([]string, 0, 1)
+	line := ""
+	for _, word := range strings.Fields(s) {
+		for len([]rune(word)) > width {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			lines = append(lines, string([]rune(word)[:width]))
+			word = string([]rune(word)[width:])
+		}
+
+		switch {
+		case line == "":
+			line = word
+		case len([]rune(line))+1+len([]rune(word)) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+
+	return append(lines, line)
+}
+
+var orderDocumentTemplate = template.Must(template.New("order_document").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{with index . 0}}{{.Title}}{{end}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; font-size: 13px; color: #222; margin: 0; }
+.document { max-width: 760px; margin: 0 auto; padding: 32px; page-break-after: always; }
+.document:last-child { page-break-after: auto; }
+h1 { font-size: 22px; margin: 0 0 16px; }
+h2 { font-size: 13px; margin: 0 0 4px; text-transform: uppercase; color: #666; }
+.fields td { padding: 2px 16px 2px 0; }
+.parties { display: flex; gap: 32px; margin: 16px 0; }
+.parties p { margin: 0; }
+.items { width: 100%; border-collapse: collapse; margin: 16px 0; }
+.items th, .items td { padding: 6px 4px; border-bottom: 1px solid #ddd; text-align: left; }
+.items .number { text-align: right; }
+.variant { color: #666; font-size: 12px; }
+.totals { margin-left: auto; }
+.totals td { padding: 2px 0 2px 24px; text-align: right; }
+.totals tr:last-child td { font-weight: bold; }
+</style>
+</head>
+<body>
+{{range .}}<div class="document">
+<h1>{{.Title}}</h1>
+<table class="fields">
+{{range .Fields}}<tr><td>{{.Label}}</td><td>{{.Value}}</td></tr>
+{{end}}</table>
+<div class="parties">
+{{range .Parties}}<div>
+<h2>{{.Heading}}</h2>
+{{range .Lines}}<p>{{.}}</p>
+{{end}}</div>
+{{end}}</div>
+<table class="items">
+<tr><th>Item</th><th class="number">Qty</th>{{if .ShowPrice}}<th class="number">Amount</th>{{end}}</tr>
+{{$showPrice := .ShowPrice}}{{range .Items}}<tr><td>{{.Name}}{{if .Variant}}<div class="variant">{{.Variant}}</div>{{end}}</td><td class="number">{{.Quantity}}</td>{{if $showPrice}}<td class="number">{{.Amount}}</td>{{end}}</tr>
+{{end}}</table>
+{{if .Totals}}<table class="totals">
+{{range .Totals}}<tr><td>{{.Label}}</td><td>{{.Value}}</td></tr>
+{{end}}</table>
+{{end}}</div>
+{{end}}</body>
+</html>
+`))
+
+func NewOrderDocumentUsecase(odr repository.OrderDocumentRepository) OrderDocumentUsecase {
+	return &orderDocumentUsecase{
+		odr: odr,
+	}
+}
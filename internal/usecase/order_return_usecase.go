@@ -0,0 +1,390 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/model"
+	"github.com/lil-oren/rest/internal/repository"
+	"github.com/lil-oren/rest/internal/shared"
+	"github.com/shopspring/decimal"
+)
+
+type (
+	OrderReturnUsecase interface {
+		RequestReturn(ctx context.Context, payload dto.CreateOrderReturnPayload) (*dto.OrderReturnResponse, error)
+		GetReturn(ctx context.Context, id, accountID int64) (*dto.OrderReturnResponse, error)
+		GetReturns(ctx context.Context, filter dto.OrderReturnFilter) (*dto.OrderReturnListResponse, error)
+		AcceptReturn(ctx context.Context, id, sellerID int64, note string) error
+		RejectReturn(ctx context.Context, id, sellerID int64, note string) error
+		EscalateReturn(ctx context.Context, id, buyerID int64, reason string) error
+		ArbitrateRefund(ctx context.Context, id int64, note string) error
+		ArbitrateClose(ctx context.Context, id int64, note string) error
+	}
+	orderReturnUsecase struct {
+		orr repository.OrderReturnRepository
+		or  repository.OrderRepository
+		odr repository.OrderDetailRepository
+		tr  repository.TransactionRepository
+		mr  repository.MediaRepository
+		cfg dependency.Config
+	}
+)
+
+// RequestReturn implements OrderReturnUsecase. Without items the whole
+// payment, delivery included, is refunded except for the service fee. Items
+// are refunded at what the buyer paid for them after discounts.
+func (uc *orderReturnUsecase) RequestReturn(ctx context.Context, payload dto.CreateOrderReturnPayload) (*dto.OrderReturnResponse, error) {
+	order, err := uc.or.FirstOrderByOrderID(ctx, payload.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.BuyerId != payload.BuyerID {
+		return nil, shared.ErrUnauthorizedUser
+	}
+
+	switch order.Status {
+	case string(constant.ArriveOrderStatus):
+	case string(constant.ReceiveOrderStatus):
+		// orders received before received_at was recorded fall back to
+		// their last update
+		receivedAt := order.ReceivedAt
+		if !receivedAt.Valid {
+			receivedAt = order.UpdatedAt
+		}
+		window := time.Duration(uc.cfg.OrderReturn.RequestWindow) * 24 * time.Hour
+		if time.Since(receivedAt.Time) > window {
+			return nil, shared.ErrReturnWindowPassed
+		}
+	default:
+		return nil, shared.ErrOrderNotReturnable
+	}
+
+	transaction, err := uc.tr.FirstTransactionByID(ctx, order.TransactionId)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &model.OrderReturn{
+		OrderID:  order.ID,
+		BuyerID:  order.BuyerId,
+		SellerID: order.SellerId,
+		Reason:   payload.Reason,
+		Status:   string(constant.RequestedReturnStatus),
+		Amount:   orderAmount(order, transaction),
+	}
+
+	items := make([]model.OrderReturnItem, 0)
+	if len(payload.Items) != 0 {
+		details, err := uc.odr.FindByOrderID(ctx, order.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		items, err = returnItems(details, payload.Items, ret.Amount.Sub(order.DeliveryCost))
+		if err != nil {
+			return nil, err
+		}
+
+		ret.Amount = decimal.Zero
+		for _, item := range items {
+			ret.Amount = ret.Amount.Add(item.Amount)
+		}
+	}
+
+	if _, err := ownedMedias(ctx, uc.mr, payload.BuyerID, payload.ImageUrls); err != nil {
+		return nil, err
+	}
+
+	id, err := uc.orr.Create(ctx, ret, items, payload.ImageUrls)
+	if err != nil {
+		if errors.Is(err, shared.ErrUpdateInactiveWallet) {
+			return nil, shared.ErrWalletNotActivated
+		}
+		return nil, err
+	}
+
+	return uc.GetReturn(ctx, *id, payload.BuyerID)
+}
+
+// GetReturn implements OrderReturnUsecase. Both the buyer and the seller of
+// the order can see the return.
+func (uc *orderReturnUsecase) GetReturn(ctx context.Context, id, accountID int64) (*dto.OrderReturnResponse, error) {
+	ret, err := uc.orr.FirstByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if ret.BuyerID != accountID && ret.SellerID != accountID {
+		return nil, shared.ErrOrderReturnNotFound
+	}
+
+	res, err := uc.toOrderReturnResponses(ctx, []model.OrderReturn{*ret})
+	if err != nil {
+		return nil, err
+	}
+
+	return &res[0], nil
+}
+
+// GetReturns implements OrderReturnUsecase.
+func (uc *orderReturnUsecase) GetReturns(ctx context.Context, filter dto.OrderReturnFilter) (*dto.OrderReturnListResponse, error) {
+	if filter.Page == 0 {
+		filter.Page = constant.DefaultPage
+	}
+
+	rets, err := uc.orr.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := uc.orr.Count(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := uc.toOrderReturnResponses(ctx, rets)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.OrderReturnListResponse{
+		Items:       items,
+		TotalData:   count,
+		TotalPage:   int(math.Ceil(float64(count) / constant.OrderReturnDefaultItems)),
+		CurrentPage: filter.Page,
+	}, nil
+}
+
+// AcceptReturn implements OrderReturnUsecase. The buyer is refunded right
+// away.
+func (uc *orderReturnUsecase) AcceptReturn(ctx context.Context, id, sellerID int64, note string) error {
+	ret, err := uc.sellerReturn(ctx, id, sellerID)
+	if err != nil {
+		return err
+	}
+	if ret.Status != string(constant.RequestedReturnStatus) {
+		return shared.ErrInvalidReturnStatus
+	}
+
+	ret.SellerNote = sql.NullString{String: note, Valid: note != ""}
+	return uc.refund(ctx, ret)
+}
+
+// RejectReturn implements OrderReturnUsecase.
+func (uc *orderReturnUsecase) RejectReturn(ctx context.Context, id, sellerID int64, note string) error {
+	ret, err := uc.sellerReturn(ctx, id, sellerID)
+	if err != nil {
+		return err
+	}
+	if ret.Status != string(constant.RequestedReturnStatus) {
+		return shared.ErrInvalidReturnStatus
+	}
+
+	ret.SellerNote = sql.NullString{String: note, Valid: true}
+	return walletError(uc.orr.Reject(ctx, ret))
+}
+
+// EscalateReturn implements OrderReturnUsecase. The buyer can ask for
+// arbitration while the seller has not answered or after a rejection.
+func (uc *orderReturnUsecase) EscalateReturn(ctx context.Context, id, buyerID int64, reason string) error {
+	ret, err := uc.orr.FirstByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if ret.BuyerID != buyerID {
+		return shared.ErrOrderReturnNotFound
+	}
+	if ret.Status != string(constant.RequestedReturnStatus) && ret.Status != string(constant.RejectedReturnStatus) {
+		return shared.ErrInvalidReturnStatus
+	}
+
+	ret.EscalationReason = sql.NullString{String: reason, Valid: true}
+	return walletError(uc.orr.Escalate(ctx, ret))
+}
+
+// ArbitrateRefund implements OrderReturnUsecase.
+func (uc *orderReturnUsecase) ArbitrateRefund(ctx context.Context, id int64, note string) error {
+	ret, err := uc.orr.FirstByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if ret.Status != string(constant.EscalatedReturnStatus) {
+		return shared.ErrInvalidReturnStatus
+	}
+
+	ret.AdminNote = sql.NullString{String: note, Valid: note != ""}
+	return uc.refund(ctx, ret)
+}
+
+// ArbitrateClose implements OrderReturnUsecase. The held money goes back to
+// the seller.
+func (uc *orderReturnUsecase) ArbitrateClose(ctx context.Context, id int64, note string) error {
+	ret, err := uc.orr.FirstByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if ret.Status != string(constant.EscalatedReturnStatus) {
+		return shared.ErrInvalidReturnStatus
+	}
+
+	ret.AdminNote = sql.NullString{String: note, Valid: note != ""}
+	return walletError(uc.orr.Close(ctx, ret))
+}
+
+func (uc *orderReturnUsecase) sellerReturn(ctx context.Context, id, sellerID int64) (*model.OrderReturn, error) {
+	ret, err := uc.orr.FirstByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if ret.SellerID != sellerID {
+		return nil, shared.ErrOrderReturnNotFound
+	}
+
+	return ret, nil
+}
+
+func (uc *orderReturnUsecase) refund(ctx context.Context, ret *model.OrderReturn) error {
+	order, err := uc.or.FirstOrderByOrderID(ctx, ret.OrderID)
+	if err != nil {
+		return err
+	}
+
+	transaction, err := uc.tr.FirstTransactionByID(ctx, order.TransactionId)
+	if err != nil {
+		return err
+	}
+
+	return walletError(uc.orr.Refund(ctx, ret, orderAmount(order, transaction)))
+}
+
+func (uc *orderReturnUsecase) toOrderReturnResponses(ctx context.Context, rets []model.OrderReturn) ([]dto.OrderReturnResponse, error) {
+	ids := make([]int64, 0, len(rets))
+	for _, ret := range rets {
+		ids = append(ids, ret.ID)
+	}
+
+	items, err := uc.orr.FindItemsByReturnIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	images, err := uc.orr.FindImagesByReturnIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	itemsByReturn := make(map[int64][]dto.OrderReturnItemResponse)
+	for _, item := range items {
+		itemsByReturn[item.OrderReturnID] = append(itemsByReturn[item.OrderReturnID], dto.OrderReturnItemResponse{
+			OrderDetailID: item.OrderDetailID,
+			ProductName:   item.ProductName,
+			VariantName:   item.VariantName,
+			Quantity:      item.Quantity,
+			Amount:        item.Amount.InexactFloat64(),
+		})
+	}
+	imagesByReturn := make(map[int64][]string)
+	for _, image := range images {
+		imagesByReturn[image.OrderReturnID] = append(imagesByReturn[image.OrderReturnID], image.ImageUrl)
+	}
+
+	res := make([]dto.OrderReturnResponse, 0, len(rets))
+	for _, ret := range rets {
+		r := dto.OrderReturnResponse{
+			ID:               ret.ID,
+			OrderID:          ret.OrderID,
+			Reason:           ret.Reason,
+			Status:           ret.Status,
+			Amount:           ret.Amount.InexactFloat64(),
+			ImageUrls:        imagesByReturn[ret.ID],
+			Items:            itemsByReturn[ret.ID],
+			SellerNote:       ret.SellerNote.String,
+			EscalationReason: ret.EscalationReason.String,
+			AdminNote:        ret.AdminNote.String,
+			CreatedAt:        ret.CreatedAt.Time.Format("2006-01-02 15:04:05"),
+		}
+		if r.ImageUrls == nil {
+			r.ImageUrls = make([]string, 0)
+		}
+		if r.Items == nil {
+			r.Items = make([]dto.OrderReturnItemResponse, 0)
+		}
+		if ret.ResolvedAt.Valid {
+			r.ResolvedAt = ret.ResolvedAt.Time.Format("2006-01-02 15:04:05")
+		}
+		res = append(res, r)
+	}
+
+	return res, nil
+}
+
+// returnItems prices the returned items. goodsPaid, what the buyer paid for
+// the products, is spread over the order lines by their subtotal so
+// promotions and vouchers are taken off every item alike.
+func returnItems(details []model.OrderDetail, requested []dto.OrderReturnItemRequest, goodsPaid decimal.Decimal) ([]model.OrderReturnItem, error) {
+	detailByID := make(map[int64]model.OrderDetail)
+	subtotal := decimal.Zero
+	for _, detail := range details {
+		detailByID[detail.ID] = detail
+		subtotal = subtotal.Add(decimal.NewFromFloat(detail.SubTotalPrice))
+	}
+	if !subtotal.IsPositive() {
+		return nil, shared.ErrInvalidReturnItem
+	}
+
+	items := make([]model.OrderReturnItem, 0, len(requested))
+	seen := make(map[int64]bool)
+	for _, req := range requested {
+		detail, ok := detailByID[req.OrderDetailID]
+		if !ok || seen[req.OrderDetailID] || req.Quantity > detail.Quantity {
+			return nil, shared.ErrInvalidReturnItem
+		}
+		seen[req.OrderDetailID] = true
+
+		amount := decimal.NewFromFloat(detail.SubTotalPrice).
+			Mul(decimal.NewFromInt(int64(req.Quantity))).
+			Div(decimal.NewFromInt(int64(detail.Quantity))).
+			Mul(goodsPaid).
+			Div(subtotal).
+			Round(2)
+
+		items = append(items, model.OrderReturnItem{
+			OrderDetailID: detail.ID,
+			Quantity:      req.Quantity,
+			Amount:        amount,
+		})
+	}
+
+	return items, nil
+}
+
+func walletError(err error) error {
+	if errors.Is(err, shared.ErrUpdateInactiveWallet) {
+		return shared.ErrWalletNotActivated
+	}
+
+	return err
+}
+
+func NewOrderReturnUsecase(
+	orr repository.OrderReturnRepository,
+	or repository.OrderRepository,
+	odr repository.OrderDetailRepository,
+	tr repository.TransactionRepository,
+	mr repository.MediaRepository,
+	cfg dependency.Config,
+) OrderReturnUsecase {
+	return &orderReturnUsecase{
+		orr: orr,
+		or:  or,
+		odr: odr,
+		tr:  tr,
+		mr:  mr,
+		cfg: cfg,
+	}
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
 	"math"
 	"time"
 
@@ -50,10 +51,12 @@ func (ouc *orderSellerUsecase) GetAllOrderOfSeller(ctx context.Context, sellerId
 				ReceiverPhoneNumber:  order.ReceiverPhoneNumber,
 				Address:              order.Address,
 				CourierName:          order.CourierName,
+				IsLate:               isLateOrder(order.Status, order.QuotedETA),
 				ETA:                  order.ETA.Time.Format("2006-01-02"),
 				TotalBeforePromotion: order.SubTotalPrice.InexactFloat64(),
 				PromotionAmount:      order.PromotionAmount.Float64,
 				TotalPrice:           order.SubTotalPrice.InexactFloat64() - order.PromotionAmount.Float64,
+				Commission:           order.Commission.InexactFloat64(),
 			}
 			orderRes = append(orderRes, v)
 			currentId = order.ID
@@ -78,17 +81,38 @@ func (ouc *orderSellerUsecase) GetAllOrderOfSeller(ctx context.Context, sellerId
 				ReceiverPhoneNumber:  order.ReceiverPhoneNumber,
 				Address:              order.Address,
 				CourierName:          order.CourierName,
+				IsLate:               isLateOrder(order.Status, order.QuotedETA),
 				ETA:                  order.ETA.Time.Format("2006-01-02 15:04:05"),
 				TotalBeforePromotion: orderRes[lastIdx].TotalPrice,
 				PromotionAmount:      order.PromotionAmount.Float64,
 				TotalPrice:           orderRes[lastIdx].TotalPrice - order.PromotionAmount.Float64,
+				Commission:           order.Commission.InexactFloat64(),
 			}
 			orderRes = append(orderRes, v)
 		}
 	}
+	for i := range orderRes {
+		orderRes[i].Earnings = orderRes[i].TotalPrice - orderRes[i].Commission
+	}
 	return orderRes, nil
 }
 
+// isLateOrder reports whether an order that has not arrived yet is past
+// the arrival quoted at checkout. Estimates the seller sets later do not
+// move it, so a late order cannot be hidden by shipping it with a new one.
+func isLateOrder(status string, eta sql.NullTime) bool {
+	if !eta.Valid {
+		return false
+	}
+
+	switch constant.OrderStatusType(status) {
+	case constant.NewOrderStatus, constant.ProcessOrderStatus, constant.DeliverOrderStatus:
+		return time.Now().After(eta.Time)
+	}
+
+	return false
+}
+
 func (ouc *orderSellerUsecase) GetAllOrderOfSellerMetadata(ctx context.Context, sellerId int64, params *dto.OrderSellerParams) (*dto.OrderSellerMetadata, error) {
 	orders, err := ouc.or.FindOrderBySellerIDMetadata(ctx, sellerId, params)
 	if err != nil {
@@ -119,6 +143,10 @@ func (ouc *orderSellerUsecase) UpdateOrderStatus(ctx context.Context, orderId in
 	if statusMap[req.NewStatus] != order.Status {
 		return shared.ErrWrongInitialStatus
 	}
+	if req.NewStatus == constant.DeliverOrderStatus {
+		eat := time.Now().Add(time.Hour * (time.Duration(req.EstDays * 24)))
+		return ouc.or.UpdateDeliverOrder(ctx, orderId, eat, req.WaybillNumber)
+	}
 	if req.EstDays < 1 {
 		err = ouc.or.UpdateOrderStatus(ctx, orderId, req.NewStatus, nil)
 		if err != nil {
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"math"
+	"time"
 
 	"github.com/lil-oren/rest/internal/constant"
 	"github.com/lil-oren/rest/internal/dto"
@@ -18,7 +19,7 @@ type (
 	OrderUsecase interface {
 		GetAllOrder(ctx context.Context, accountId int64, params *dto.OrderParams) ([]dto.OrderList, error)
 		GetAllOrderMetadata(ctx context.Context, accountId int64, params *dto.OrderParams) (*dto.OrderPage, error)
-		CreateOrder(ctx context.Context, accountId int, payload dto.CreateOrderRequestPayload) error
+		CreateOrder(ctx context.Context, accountId int, sessionID string) error
 		CancelOrder(ctx context.Context, orderId int64, userId int64) error
 		ReceiveOrder(ctx context.Context, orderId int64, userId int64) error
 	}
@@ -26,12 +27,16 @@ type (
 		or  repository.OrderRepository
 		cr  repository.CartRepository
 		er  repository.WalletRepository
-		ror repository.RajaOngkirRepository
+		spr repository.ShippingProviderRegistry
 		aar repository.AccountAddressRepository
 		cor repository.CourierRepository
 		pr  repository.ProductRepository
 		tr  repository.TransactionRepository
 		prr repository.PromotionRepository
+		vr  repository.VoucherRepository
+		orr repository.OrderReturnRepository
+		chr repository.CacheRepository
+		frr repository.FeeRuleRepository
 	}
 )
 
@@ -65,7 +70,7 @@ func (ou *orderUsecase) GetAllOrder(ctx context.Context, accountId int64, params
 				CourierName:         order.CourierName,
 				DeliveryCost:        order.DeliveryCost.InexactFloat64(),
 				ETA:                 order.ETA.Time.Format("2006-01-02 15:04:05"),
-				TotalPrice:          order.SubTotalPrice.InexactFloat64() + order.DeliveryCost.InexactFloat64() - order.PromotionAmount.Float64,
+				TotalPrice:          order.SubTotalPrice.InexactFloat64() + order.DeliveryCost.InexactFloat64() - order.PromotionAmount.Float64 - order.VoucherAmount.Float64,
 			}
 			orderRes = append(orderRes, v)
 			currentId = order.ID
@@ -93,7 +98,7 @@ func (ou *orderUsecase) GetAllOrder(ctx context.Context, accountId int64, params
 				CourierName:         order.CourierName,
 				DeliveryCost:        order.DeliveryCost.InexactFloat64(),
 				ETA:                 order.ETA.Time.Format("2006-01-02 15:04:05"),
-				TotalPrice:          order.SubTotalPrice.InexactFloat64() + order.DeliveryCost.InexactFloat64() - order.PromotionAmount.Float64,
+				TotalPrice:          order.SubTotalPrice.InexactFloat64() + order.DeliveryCost.InexactFloat64() - order.PromotionAmount.Float64 - order.VoucherAmount.Float64,
 			}
 			orderRes = append(orderRes, v)
 		}
@@ -114,24 +119,57 @@ func (ou *orderUsecase) GetAllOrderMetadata(ctx context.Context, accountId int64
 	return res, nil
 }
 
-func (ou *orderUsecase) CreateOrder(ctx context.Context, accountId int, payload dto.CreateOrderRequestPayload) error {
+// CreateOrder makes the orders of a checkout session. Everything is priced
+// again and the orders are only made when they cost what the buyer saw in
+// the session, otherwise the session is dropped and ErrCheckoutPricesChanged
+// is returned. The session is claimed first so it cannot be paid twice by
+// requests racing each other.
+func (ou *orderUsecase) CreateOrder(ctx context.Context, accountId int, sessionID string) (err error) {
+	claimed, err := ou.chr.ClaimCheckoutSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return shared.ErrCheckoutSessionInUse
+	}
+	ordered := false
+	defer func() {
+		if !ordered {
+			if releaseErr := ou.chr.ReleaseCheckoutSession(ctx, sessionID); releaseErr != nil && err == nil {
+				err = releaseErr
+			}
+		}
+	}()
+
+	session, err := ou.chr.GetCheckoutSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if session == nil || session.BuyerID != int64(accountId) {
+		return shared.ErrCheckoutSessionNotFound
+	}
+	payload := toCreateOrderPayload(*session)
+
 	totalPrice := decimal.NewFromFloat32(0)
 	totalPricePerOrder := decimal.NewFromFloat32(0)
 	totalWeightPerOrder := 0
 	priceList := make([]decimal.Decimal, 0)
 	costList := make([]decimal.Decimal, 0)
+	etaList := make([]sql.NullTime, 0)
 	pricePerOrder := make([]decimal.Decimal, 0)
 	promotionAmount := make([]float64, 0)
 	promotionName := make([]string, 0)
-	servicePriceDec := decimal.NewFromFloat(constant.ServicePrice)
+	fees := make([]dto.OrderFee, 0, len(payload.Orders))
+	totalServiceFee := decimal.Zero
 	createTxList := make([]*model.Transaction, 0)
+	voucherOrders := make([]*voucherOrder, 0, len(payload.Orders))
 
 	buyerAddress, err := ou.aar.FirstByID(ctx, int64(payload.BuyerAddressId))
 	if err != nil {
 		return err
 	}
 
-	for _, order := range payload.Orders {
+	for idx, order := range payload.Orders {
 		delivCost := float64(0)
 		cart, err := ou.cr.FindCheckedCartByShopID(ctx, int64(accountId), int64(order.ShopId))
 		if err != nil {
@@ -141,6 +179,9 @@ func (ou *orderUsecase) CreateOrder(ctx context.Context, accountId int, payload
 		if len(cart) == 0 {
 			return shared.ErrNoCheckedCart
 		}
+		if err := checkCartProductsActive(cart); err != nil {
+			return err
+		}
 
 		shopAddress, err := ou.aar.FirstShopAddressByShopID(ctx, int64(order.ShopId))
 		if err != nil {
@@ -152,9 +193,11 @@ func (ou *orderUsecase) CreateOrder(ctx context.Context, accountId int, payload
 			return err
 		}
 
+		if checkoutItemsChanged(session.Orders[idx].Items, cart) {
+			return ou.dropCheckoutSession(ctx, sessionID)
+		}
+
 		for _, checkedCart := range cart {
-			disc := decimal.NewFromFloat32(float32((100 - checkedCart.Discount) / 100))
-			qty := decimal.NewFromInt(int64(checkedCart.Qty))
 			p, err := ou.pr.FirstProductDetail(ctx, checkedCart.ProductID)
 			if err != nil {
 				return err
@@ -162,7 +205,7 @@ func (ou *orderUsecase) CreateOrder(ctx context.Context, accountId int, payload
 
 			totalWeightPerOrder += p.Weight * checkedCart.Qty
 
-			price := (checkedCart.BasePrice.Mul(disc)).Mul(qty)
+			price := cartItemPrice(checkedCart)
 			totalPricePerOrder = totalPricePerOrder.Add(price)
 			priceList = append(priceList, price)
 		}
@@ -200,32 +243,54 @@ func (ou *orderUsecase) CreateOrder(ctx context.Context, accountId int, payload
 			promotionName = append(promotionName, name)
 		}
 
-		roQuery := dto.RajaOngkirGetCostHTTPQueries{
-			OriginCityID:      int(shopAddress.DistrictId),
-			DestinationCityID: int(buyerAddress.DistrictId),
-			Weight:            totalWeightPerOrder,
-			CourierCode:       courier.Code,
-			CourierService:    courier.ServiceName,
+		fee, err := orderFees(ctx, ou.frr, cart, totalPricePerOrder)
+		if err != nil {
+			return err
 		}
-
-		if roQuery.OriginCityID == roQuery.DestinationCityID && roQuery.CourierCode == "jne" {
-			roQuery = shared.ServiceChanger(roQuery)
+		if !fee.ServiceFee.Equal(session.Orders[idx].ServiceFee.Round(2)) {
+			return ou.dropCheckoutSession(ctx, sessionID)
 		}
+		fees = append(fees, *fee)
+		totalServiceFee = totalServiceFee.Add(fee.ServiceFee)
 
-		cost, err := ou.ror.GetCost(ctx, roQuery)
+		rate, _, err := quoteShipping(ctx, ou.spr, courier, shopAddress, buyerAddress, totalWeightPerOrder, order.CourierService)
 		if err != nil {
 			return err
 		}
 
-		delivCost = *cost
+		payload.Orders[idx].CourierService = rate.Service
+		delivCost = rate.Cost
+		etaList = append(etaList, estimatedArrival(rate.ETD, time.Now()))
 		costDec := decimal.NewFromFloat(delivCost)
 		costList = append(costList, costDec)
 		pricePerOrder = append(pricePerOrder, totalPricePerOrder.Add(costDec))
 		totalPrice = totalPrice.Add(totalPricePerOrder).Add(costDec)
+		voucherOrders = append(voucherOrders, &voucherOrder{
+			shopID:       int64(order.ShopId),
+			voucherCode:  order.VoucherCode,
+			items:        cart,
+			subtotal:     totalPricePerOrder,
+			deliveryCost: costDec,
+		})
 		totalPricePerOrder = decimal.NewFromFloat32(0)
 		totalWeightPerOrder = 0
 	}
 
+	if err := applyVouchers(ctx, ou.vr, int64(accountId), voucherOrders, payload.PlatformVoucherCode); err != nil {
+		return err
+	}
+
+	vouchers := make([][]dto.OrderVoucher, 0, len(voucherOrders))
+	for idx, vo := range voucherOrders {
+		discount := vo.discount()
+		pricePerOrder[idx] = pricePerOrder[idx].Sub(discount)
+		totalPrice = totalPrice.Sub(discount)
+		vouchers = append(vouchers, vo.vouchers)
+		if !pricePerOrder[idx].Round(2).Equal(session.Orders[idx].Subtotal.Round(2)) {
+			return ou.dropCheckoutSession(ctx, sessionID)
+		}
+	}
+
 	walletUser, err := ou.er.FirstActiveWalletByAccountID(ctx, int64(accountId), constant.UserWalletType)
 	if err != nil {
 		return shared.ErrFindWallet
@@ -236,9 +301,9 @@ func (ou *orderUsecase) CreateOrder(ctx context.Context, accountId int, payload
 		return shared.ErrFindWallet
 	}
 
-	for _, price := range pricePerOrder {
+	for idx, price := range pricePerOrder {
 		createTx := &model.Transaction{
-			Amount:       price,
+			Amount:       price.Add(fees[idx].ServiceFee),
 			Title:        constant.PaymentOrderTitle,
 			ToWalletID:   walletTemp.ID,
 			FromWalletID: sql.NullInt64{Int64: walletUser.ID},
@@ -246,16 +311,64 @@ func (ou *orderUsecase) CreateOrder(ctx context.Context, accountId int, payload
 		createTxList = append(createTxList, createTx)
 	}
 
-	if (totalPrice.Add(servicePriceDec)).GreaterThan(walletUser.Balance) {
+	if (totalPrice.Add(totalServiceFee)).GreaterThan(walletUser.Balance) {
 		return shared.ErrInsufficientBalance
 	}
 
-	err = ou.or.CreateOrder(ctx, int64(accountId), priceList, costList, promotionAmount, promotionName, payload, createTxList)
+	err = ou.or.CreateOrder(ctx, int64(accountId), priceList, costList, etaList, promotionAmount, promotionName, vouchers, fees, payload, createTxList)
 	if err != nil {
 		return err
 	}
+	ordered = true
 
-	return nil
+	return ou.chr.DeleteCheckoutSession(ctx, sessionID)
+}
+
+// dropCheckoutSession forgets a session whose prices are stale so the buyer
+// has to check out again.
+func (ou *orderUsecase) dropCheckoutSession(ctx context.Context, sessionID string) error {
+	if err := ou.chr.DeleteCheckoutSession(ctx, sessionID); err != nil {
+		return err
+	}
+
+	return shared.ErrCheckoutPricesChanged
+}
+
+func toCreateOrderPayload(session dto.CheckoutSession) dto.CreateOrderRequestPayload {
+	payload := dto.CreateOrderRequestPayload{
+		Orders:              make([]dto.OrderPayload, 0, len(session.OrderDeliveries)),
+		BuyerAddressId:      int(session.BuyerAddressID),
+		PlatformVoucherCode: session.PlatformVoucherCode,
+	}
+	for _, od := range session.OrderDeliveries {
+		payload.Orders = append(payload.Orders, dto.OrderPayload{
+			ShopId:         int(od.ShopID),
+			CourierId:      int(*od.ShopCourierID),
+			CourierService: od.CourierService,
+			PromotionId:    int(od.PromotionID),
+			VoucherCode:    od.VoucherCode,
+		})
+	}
+
+	return payload
+}
+
+// checkoutItemsChanged reports whether the checked cart of a shop is no
+// longer what was locked in the checkout session.
+func checkoutItemsChanged(locked []dto.CheckoutSessionItem, cart []dto.CartOrderModel) bool {
+	if len(locked) != len(cart) {
+		return true
+	}
+
+	for i, c := range cart {
+		if locked[i].ProductVariantID != c.ProductVariantID ||
+			locked[i].Quantity != c.Qty ||
+			!locked[i].Price.Equal(cartItemPrice(c)) {
+			return true
+		}
+	}
+
+	return false
 }
 
 func (ou *orderUsecase) CancelOrder(ctx context.Context, orderId int64, userId int64) error {
@@ -280,6 +393,13 @@ func (ou *orderUsecase) ReceiveOrder(ctx context.Context, orderId int64, userId
 	if string(constant.ArriveOrderStatus) != order.Status {
 		return shared.ErrWrongInitialStatus
 	}
+	hasOpenReturn, err := ou.orr.HasOpenReturn(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+	if hasOpenReturn {
+		return shared.ErrOrderReturnOpen
+	}
 	transaction, err := ou.tr.FirstTransactionByID(ctx, order.TransactionId)
 	if err != nil {
 		return err
@@ -289,7 +409,7 @@ func (ou *orderUsecase) ReceiveOrder(ctx context.Context, orderId int64, userId
 		return err
 	}
 	receiveTx := &model.Transaction{
-		Amount:       transaction.Amount,
+		Amount:       orderAmount(order, transaction),
 		Title:        constant.TransferTitle,
 		FromWalletID: sql.NullInt64{Int64: transaction.ToWalletID, Valid: true},
 		ToWalletID:   shopWallet.ID,
@@ -304,6 +424,12 @@ func (ou *orderUsecase) ReceiveOrder(ctx context.Context, orderId int64, userId
 	return nil
 }
 
+// orderAmount is what the buyer paid for order, without the service fee
+// that goes to the platform.
+func orderAmount(order *model.Order, payment *model.Transaction) decimal.Decimal {
+	return payment.Amount.Sub(order.ServiceFee)
+}
+
 func CancelAndRejectOrder(ctx context.Context, order *model.Order,
 	tr repository.TransactionRepository,
 	er repository.WalletRepository,
@@ -336,22 +462,30 @@ func NewOrderUsecase(
 	or repository.OrderRepository,
 	cr repository.CartRepository,
 	er repository.WalletRepository,
-	ror repository.RajaOngkirRepository,
+	spr repository.ShippingProviderRegistry,
 	aar repository.AccountAddressRepository,
 	cor repository.CourierRepository,
 	pr repository.ProductRepository,
 	tr repository.TransactionRepository,
 	prr repository.PromotionRepository,
+	vr repository.VoucherRepository,
+	orr repository.OrderReturnRepository,
+	chr repository.CacheRepository,
+	frr repository.FeeRuleRepository,
 ) OrderUsecase {
 	return &orderUsecase{
 		or:  or,
 		cr:  cr,
 		er:  er,
-		ror: ror,
+		spr: spr,
 		aar: aar,
 		cor: cor,
 		pr:  pr,
 		tr:  tr,
 		prr: prr,
+		vr:  vr,
+		orr: orr,
+		chr: chr,
+		frr: frr,
 	}
 }
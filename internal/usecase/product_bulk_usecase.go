@@ -0,0 +1,604 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/model"
+	"github.com/lil-oren/rest/internal/repository"
+	"github.com/lil-oren/rest/internal/shared"
+)
+
+type (
+	ProductBulkUsecase interface {
+		ImportProducts(ctx context.Context, payload dto.ImportProductPayload) (*dto.ProductImportJobResponse, error)
+		GetImportJob(ctx context.Context, jobID, accountID int64) (*dto.ProductImportJobResponse, error)
+		ProcessPendingImports(ctx context.Context) error
+		ExportProducts(ctx context.Context, payload dto.ExportProductPayload) (*dto.ExportProductResponse, error)
+	}
+	productBulkUsecase struct {
+		su   ShopUsecase
+		pr   repository.ProductRepository
+		pijr repository.ProductImportJobRepository
+		mr   repository.MediaRepository
+		mf   repository.MediaFetcher
+		mu   MediaUsecase
+		cfg  dependency.Config
+	}
+)
+
+// ImportProducts implements ProductBulkUsecase. Every row is validated up
+// front, products with valid rows are queued and created by
+// ProcessPendingImports.
+func (uc *productBulkUsecase) ImportProducts(ctx context.Context, payload dto.ImportProductPayload) (*dto.ProductImportJobResponse, error) {
+	raw, err := io.ReadAll(payload.File)
+	if err != nil {
+		return nil, shared.ErrInvalidImportFile
+	}
+
+	rows, err := readImportFile(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 || !isImportHeader(rows[0]) {
+		return nil, shared.ErrInvalidImportTemplate
+	}
+
+	totalRows := 0
+	for _, row := range rows[1:] {
+		if !isBlankImportRow(row) {
+			totalRows++
+		}
+	}
+	if totalRows == 0 {
+		return nil, shared.ErrInvalidImportTemplate
+	}
+	if totalRows > constant.ProductImportMaxRows {
+		return nil, shared.ErrImportTooManyRows
+	}
+
+	items, importErrs := parseImportRows(rows)
+
+	failedRows := make(map[int]bool)
+	for _, importErr := range importErrs {
+		failedRows[importErr.Row] = true
+	}
+
+	itemsJSON, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	errsJSON, err := json.Marshal(importErrs)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &model.ProductImportJob{
+		AccountID:  payload.AccountID,
+		FileName:   payload.FileName,
+		Status:     constant.ProductImportStatusPending,
+		TotalRows:  totalRows,
+		FailedRows: len(failedRows),
+		Items:      string(itemsJSON),
+		Errors:     string(errsJSON),
+	}
+	if len(items) == 0 {
+		job.Status = constant.ProductImportStatusDone
+	}
+
+	id, err := uc.pijr.Create(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.GetImportJob(ctx, *id, payload.AccountID)
+}
+
+// GetImportJob implements ProductBulkUsecase.
+func (uc *productBulkUsecase) GetImportJob(ctx context.Context, jobID, accountID int64) (*dto.ProductImportJobResponse, error) {
+	job, err := uc.pijr.FirstByIDAndAccountID(ctx, jobID, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	importErrs := make([]dto.ProductImportError, 0)
+	if err := json.Unmarshal([]byte(job.Errors), &importErrs); err != nil {
+		return nil, err
+	}
+
+	res := &dto.ProductImportJobResponse{
+		ID:          job.ID,
+		FileName:    job.FileName,
+		Status:      job.Status,
+		TotalRows:   job.TotalRows,
+		SuccessRows: job.SuccessRows,
+		FailedRows:  job.FailedRows,
+		Errors:      importErrs,
+		CreatedAt:   job.CreatedAt.Time.Format("2006-01-02 15:04:05"),
+	}
+	if job.FinishedAt.Valid {
+		res.FinishedAt = job.FinishedAt.Time.Format("2006-01-02 15:04:05")
+	}
+
+	return res, nil
+}
+
+// ProcessPendingImports implements ProductBulkUsecase. Progress is saved
+// after every product, so a job taken over from a worker that died goes on
+// where that worker stopped.
+func (uc *productBulkUsecase) ProcessPendingImports(ctx context.Context) error {
+	lease := time.Duration(uc.cfg.ProductImport.LeaseTime) * time.Second
+
+	for ctx.Err() == nil {
+		job, err := uc.pijr.ClaimPending(ctx, lease)
+		if err != nil {
+			return err
+		}
+		if job == nil {
+			return nil
+		}
+
+		if err := uc.processImport(ctx, job); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// processImport creates the products of job. A job whose items cannot be
+// read is marked as failed instead of being picked up again.
+func (uc *productBulkUsecase) processImport(ctx context.Context, job *model.ProductImportJob) error {
+	items := make([]dto.ProductImportItem, 0)
+	importErrs := make([]dto.ProductImportError, 0)
+	if err := json.Unmarshal([]byte(job.Items), &items); err != nil {
+		return uc.failImport(ctx, job)
+	}
+	if err := json.Unmarshal([]byte(job.Errors), &importErrs); err != nil {
+		return uc.failImport(ctx, job)
+	}
+
+	for idx := job.Processed; idx < len(items); idx++ {
+		item := items[idx]
+		item.Payload.StockReason = constant.StockReasonBulkImport
+		imageURLs, err := uc.importImages(ctx, job.AccountID, item.Payload.ImageURL)
+		if err == nil {
+			item.Payload.ImageURL = imageURLs
+			err = uc.su.AddProduct(ctx, item.Payload, int(job.AccountID))
+		}
+		if err == nil {
+			job.SuccessRows += len(item.Rows)
+		} else {
+			message := shared.ErrImportProduct.Error()
+			var ce *shared.CustomError
+			if errors.As(err, &ce) && ce.Code != shared.InternalServer {
+				message = ce.Error()
+			}
+			for _, row := range item.Rows {
+				importErrs = append(importErrs, dto.ProductImportError{Row: row, Message: message})
+			}
+			job.FailedRows += len(item.Rows)
+		}
+
+		errsJSON, err := json.Marshal(importErrs)
+		if err != nil {
+			return err
+		}
+		job.Errors = string(errsJSON)
+		job.Processed = idx + 1
+		if err := uc.pijr.Checkpoint(ctx, job); err != nil {
+			return err
+		}
+	}
+
+	sort.SliceStable(importErrs, func(i, j int) bool {
+		return importErrs[i].Row < importErrs[j].Row
+	})
+	errsJSON, err := json.Marshal(importErrs)
+	if err != nil {
+		return err
+	}
+	job.Errors = string(errsJSON)
+
+	return uc.pijr.Finish(ctx, job)
+}
+
+// importImages uploads the images of a product that are hosted elsewhere,
+// as sellers moving from another platform link the images they have there.
+// It returns the urls to add the product with.
+func (uc *productBulkUsecase) importImages(ctx context.Context, accountID int64, urls []string) ([]string, error) {
+	owned, err := uc.mr.FindByAccountIDAndURLs(ctx, accountID, urls)
+	if err != nil {
+		return nil, err
+	}
+
+	imported := make(map[string]string)
+	for _, media := range owned {
+		imported[media.MediaUrl] = media.MediaUrl
+	}
+
+	res := make([]string, 0, len(urls))
+	for _, url := range urls {
+		if _, ok := imported[url]; !ok {
+			body, err := uc.mf.Fetch(ctx, url, uc.cfg.MediaStorage.MaxVideoSize)
+			if err != nil {
+				return nil, err
+			}
+
+			media, err := uc.mu.UploadMedia(ctx, dto.UploadMediaPayload{
+				AccountID: accountID,
+				FileName:  path.Base(url),
+				Size:      int64(len(body)),
+				File:      bytes.NewReader(body),
+			})
+			if err != nil {
+				return nil, err
+			}
+			imported[url] = media.MediaURL
+		}
+		res = append(res, imported[url])
+	}
+
+	return res, nil
+}
+
+func (uc *productBulkUsecase) failImport(ctx context.Context, job *model.ProductImportJob) error {
+	errsJSON, err := json.Marshal([]dto.ProductImportError{{Message: shared.ErrImportProduct.Error()}})
+	if err != nil {
+		return err
+	}
+	job.Errors = string(errsJSON)
+
+	return uc.pijr.Fail(ctx, job)
+}
+
+// ExportProducts implements ProductBulkUsecase. The file follows the import
+// template so it can be edited and uploaded again.
+func (uc *productBulkUsecase) ExportProducts(ctx context.Context, payload dto.ExportProductPayload) (*dto.ExportProductResponse, error) {
+	if payload.Format != constant.ProductExportFormatCSV && payload.Format != constant.ProductExportFormatXLSX {
+		return nil, shared.ErrInvalidExportFormat
+	}
+
+	exportRows, err := uc.pr.FindExportRowsBySellerID(ctx, payload.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := [][]string{constant.ProductImportColumns}
+	for _, r := range exportRows {
+		row := make([]string, len(constant.ProductImportColumns))
+		row[constant.ProductColumnName] = r.ProductName
+		row[constant.ProductColumnDescription] = r.Description
+		row[constant.ProductColumnWeight] = strconv.Itoa(r.Weight)
+		row[constant.ProductColumnImageURLs] = r.ImageURLs
+		row[constant.ProductColumnPrice] = r.Price.String()
+		row[constant.ProductColumnStock] = strconv.FormatInt(r.Stock, 10)
+		if r.CategoryLevel1.Valid {
+			row[constant.ProductColumnCategoryLevel1] = strconv.FormatInt(r.CategoryLevel1.Int64, 10)
+		}
+		if r.CategoryLevel2.Valid {
+			row[constant.ProductColumnCategoryLevel2] = strconv.FormatInt(r.CategoryLevel2.Int64, 10)
+		}
+		if r.CategoryLevel3.Valid {
+			row[constant.ProductColumnCategoryLevel3] = strconv.FormatInt(r.CategoryLevel3.Int64, 10)
+		}
+		if r.VariantGroup1 != constant.ProductVariantDefault {
+			row[constant.ProductColumnVariantGroup1] = r.VariantGroup1
+			row[constant.ProductColumnVariantType1] = r.VariantType1
+			if r.VariantGroup2 != constant.ProductVariantDefault {
+				row[constant.ProductColumnVariantGroup2] = r.VariantGroup2
+				row[constant.ProductColumnVariantType2] = r.VariantType2
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	res := &dto.ExportProductResponse{
+		FileName: fmt.Sprintf(constant.ProductExportFileTemplate, time.Now().Format("20060102150405"), payload.Format),
+		Body:     new(bytes.Buffer),
+	}
+
+	switch payload.Format {
+	case constant.ProductExportFormatXLSX:
+		res.ContentType = constant.ProductExportXLSXType
+		if err := shared.WriteXLSX(res.Body, constant.ProductImportSheetName, rows); err != nil {
+			return nil, err
+		}
+	default:
+		res.ContentType = constant.ProductExportCSVContentType
+		w := csv.NewWriter(res.Body)
+		if err := w.WriteAll(rows); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+func readImportFile(raw []byte) ([][]string, error) {
+	if bytes.HasPrefix(raw, []byte("PK")) {
+		return shared.ReadXLSX(raw)
+	}
+
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(raw, []byte("\xef\xbb\xbf"))))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, shared.ErrInvalidImportFile
+	}
+
+	return rows, nil
+}
+
+func isImportHeader(row []string) bool {
+	if len(row) < len(constant.ProductImportColumns) {
+		return false
+	}
+
+	for idx, column := range constant.ProductImportColumns {
+		if !strings.EqualFold(strings.TrimSpace(row[idx]), column) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isBlankImportRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// importRow is a data row of the import file with its sheet row number.
+type importRow struct {
+	number int
+	cells  []string
+}
+
+func (r importRow) cell(column int) string {
+	if column >= len(r.cells) {
+		return ""
+	}
+
+	return strings.TrimSpace(r.cells[column])
+}
+
+// parseImportRows groups consecutive rows with the same product name into
+// one product and validates them. Products with any invalid row are not
+// imported; all of their errors are reported.
+func parseImportRows(rows [][]string) ([]dto.ProductImportItem, []dto.ProductImportError) {
+	items := make([]dto.ProductImportItem, 0)
+	importErrs := make([]dto.ProductImportError, 0)
+
+	groups := make([][]importRow, 0)
+	for idx, cells := range rows[1:] {
+		if isBlankImportRow(cells) {
+			continue
+		}
+
+		row := importRow{number: idx + 2, cells: cells}
+		last := len(groups) - 1
+		if last >= 0 && row.cell(constant.ProductColumnName) != "" &&
+			groups[last][0].cell(constant.ProductColumnName) == row.cell(constant.ProductColumnName) {
+			groups[last] = append(groups[last], row)
+			continue
+		}
+		groups = append(groups, []importRow{row})
+	}
+
+	for _, group := range groups {
+		item, errs := parseImportProduct(group)
+		if len(errs) > 0 {
+			importErrs = append(importErrs, errs...)
+			continue
+		}
+		items = append(items, *item)
+	}
+
+	return items, importErrs
+}
+
+func parseImportProduct(group []importRow) (*dto.ProductImportItem, []dto.ProductImportError) {
+	importErrs := make([]dto.ProductImportError, 0)
+	addErr := func(row importRow, column int, err error) {
+		importErrs = append(importErrs, dto.ProductImportError{
+			Row:     row.number,
+			Column:  constant.ProductImportColumns[column],
+			Message: err.Error(),
+		})
+	}
+
+	first := group[0]
+	payload := dto.AddProductPayload{
+		ProductName: first.cell(constant.ProductColumnName),
+		Description: first.cell(constant.ProductColumnDescription),
+	}
+
+	if payload.ProductName == "" {
+		addErr(first, constant.ProductColumnName, shared.ErrImportValueRequired)
+	}
+	if payload.Description == "" {
+		addErr(first, constant.ProductColumnDescription, shared.ErrImportValueRequired)
+	}
+
+	weight, err := strconv.Atoi(first.cell(constant.ProductColumnWeight))
+	switch {
+	case err != nil:
+		addErr(first, constant.ProductColumnWeight, shared.ErrImportValueNotNumber)
+	case weight <= 0:
+		addErr(first, constant.ProductColumnWeight, shared.ErrInvalidWeight)
+	}
+	payload.Weight = weight
+
+	level1, err := strconv.Atoi(first.cell(constant.ProductColumnCategoryLevel1))
+	if err != nil || level1 <= 0 {
+		addErr(first, constant.ProductColumnCategoryLevel1, shared.ErrNoCategory)
+	}
+	level2, err := strconv.Atoi(first.cell(constant.ProductColumnCategoryLevel2))
+	if err != nil || level2 <= 0 {
+		addErr(first, constant.ProductColumnCategoryLevel2, shared.ErrNoCategory)
+	}
+	payload.ProductCategoryID = dto.ProductCategory{Level1: level1, Level2: level2}
+	if value := first.cell(constant.ProductColumnCategoryLevel3); value != "" {
+		level3, err := strconv.Atoi(value)
+		if err != nil || level3 <= 0 {
+			addErr(first, constant.ProductColumnCategoryLevel3, shared.ErrNoCategory)
+		}
+		payload.ProductCategoryID.Level3 = &level3
+	}
+
+	payload.ImageURL = make([]string, 0)
+	for _, url := range strings.Split(first.cell(constant.ProductColumnImageURLs), constant.ProductImportListSeparator) {
+		if url = strings.TrimSpace(url); url != "" {
+			payload.ImageURL = append(payload.ImageURL, url)
+		}
+	}
+	if len(payload.ImageURL) == 0 {
+		addErr(first, constant.ProductColumnImageURLs, shared.ErrImportValueRequired)
+	}
+
+	group1 := first.cell(constant.ProductColumnVariantGroup1)
+	group2 := first.cell(constant.ProductColumnVariantGroup2)
+	payload.IsVariant = group1 != ""
+	if !payload.IsVariant && group2 != "" {
+		addErr(first, constant.ProductColumnVariantGroup1, shared.ErrImportValueRequired)
+	}
+
+	types1 := newImportVariantTypes()
+	types2 := newImportVariantTypes()
+	combinations := make(map[string]bool)
+	rows := make([]int, 0)
+	for idx, row := range group {
+		rows = append(rows, row.number)
+
+		price, err := strconv.ParseFloat(row.cell(constant.ProductColumnPrice), 64)
+		switch {
+		case err != nil:
+			addErr(row, constant.ProductColumnPrice, shared.ErrImportValueNotNumber)
+		case price < constant.PriceDefault:
+			addErr(row, constant.ProductColumnPrice, shared.ErrInvalidPrice)
+		}
+
+		stock, err := strconv.ParseInt(row.cell(constant.ProductColumnStock), 10, 64)
+		switch {
+		case err != nil:
+			addErr(row, constant.ProductColumnStock, shared.ErrImportValueNotNumber)
+		case stock < constant.StockDefault:
+			addErr(row, constant.ProductColumnStock, shared.ErrInvalidStock)
+		}
+
+		variant := dto.Variant{Price: price, Stock: stock}
+
+		if !payload.IsVariant {
+			if idx > 0 {
+				addErr(row, constant.ProductColumnVariantGroup1, shared.ErrImportSingleVariantRow)
+			}
+			payload.Variants = append(payload.Variants, variant)
+			continue
+		}
+
+		if value := row.cell(constant.ProductColumnVariantGroup1); value != "" && value != group1 {
+			addErr(row, constant.ProductColumnVariantGroup1, shared.ErrImportVariantGroupMismatch)
+		}
+		if value := row.cell(constant.ProductColumnVariantGroup2); value != "" && value != group2 {
+			addErr(row, constant.ProductColumnVariantGroup2, shared.ErrImportVariantGroupMismatch)
+		}
+
+		type1 := row.cell(constant.ProductColumnVariantType1)
+		if type1 == "" {
+			addErr(row, constant.ProductColumnVariantType1, shared.ErrImportValueRequired)
+		}
+		variant.VariantType1 = types1.add(type1)
+
+		type2 := row.cell(constant.ProductColumnVariantType2)
+		switch {
+		case group2 != "" && type2 == "":
+			addErr(row, constant.ProductColumnVariantType2, shared.ErrImportValueRequired)
+		case group2 == "" && type2 != "":
+			addErr(row, constant.ProductColumnVariantGroup2, shared.ErrImportValueRequired)
+		case group2 != "":
+			variant.VariantType2 = types2.add(type2)
+		}
+
+		combination := strings.ToLower(type1) + "\x00" + strings.ToLower(type2)
+		if combinations[combination] {
+			addErr(row, constant.ProductColumnVariantType1, shared.ErrDuplicateVariantType)
+		}
+		combinations[combination] = true
+
+		payload.Variants = append(payload.Variants, variant)
+	}
+
+	if payload.IsVariant {
+		payload.VariantDefinitions.VariantGroup1 = &dto.VariantGroup{Name: group1, VariantTypes: types1.names}
+		if group2 != "" {
+			payload.VariantDefinitions.VariantGroup2 = &dto.VariantGroup{Name: group2, VariantTypes: types2.names}
+		}
+	}
+
+	if len(importErrs) > 0 {
+		return nil, importErrs
+	}
+
+	return &dto.ProductImportItem{Rows: rows, Payload: payload}, nil
+}
+
+// importVariantTypes collects the distinct variant types of a group, keeping
+// the spelling of the first occurrence since types are unique regardless of
+// case.
+type importVariantTypes struct {
+	names []string
+	index map[string]int
+}
+
+func newImportVariantTypes() *importVariantTypes {
+	return &importVariantTypes{
+		names: make([]string, 0),
+		index: make(map[string]int),
+	}
+}
+
+func (t *importVariantTypes) add(name string) *string {
+	key := strings.ToLower(name)
+	idx, ok := t.index[key]
+	if !ok {
+		idx = len(t.names)
+		t.index[key] = idx
+		t.names = append(t.names, name)
+	}
+
+	v := t.names[idx]
+	return &v
+}
+
+func NewProductBulkUsecase(su ShopUsecase, pr repository.ProductRepository, pijr repository.ProductImportJobRepository, mr repository.MediaRepository, mf repository.MediaFetcher, mu MediaUsecase, cfg dependency.Config) ProductBulkUsecase {
+	return &productBulkUsecase{
+		su:   su,
+		pr:   pr,
+		pijr: pijr,
+		mr:   mr,
+		mf:   mf,
+		mu:   mu,
+		cfg:  cfg,
+	}
+}
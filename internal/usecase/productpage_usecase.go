@@ -27,6 +27,9 @@ type (
 		wr  repository.WishlistRepository
 		rr  repository.ReviewRepository
 		odr repository.OrderDetailRepository
+		mr  repository.MediaRepository
+		fsr repository.FlashSaleRepository
+		psr repository.ProductStatRepository
 	}
 )
 
@@ -40,6 +43,14 @@ func (uc *productPageUsecase) GetProductDetail(ctx context.Context, productCode
 		return nil, err
 	}
 
+	if prod.Status != constant.ProductStatusActive && prod.SellerID != userID {
+		return nil, shared.ErrProductNotFound
+	}
+
+	if prod.SellerID != userID {
+		_ = uc.psr.IncrementView(ctx, prod.ID)
+	}
+
 	product := &dto.ProductPageProductDetail{
 		Name:        prod.Name,
 		Description: prod.Description,
@@ -55,6 +66,23 @@ func (uc *productPageUsecase) GetProductDetail(ctx context.Context, productCode
 	if err != nil {
 		return nil, err
 	}
+	flashSales, err := uc.fsr.FindActiveByProductID(ctx, prod.ID)
+	if err != nil {
+		return nil, err
+	}
+	flashSaleByVariant := make(map[int64]*dto.ProductPageFlashSale)
+	for _, fs := range flashSales {
+		flashSale := &dto.ProductPageFlashSale{
+			EndAt:         fs.EndAt,
+			PurchaseLimit: fs.PurchaseLimit,
+		}
+		if fs.Quota > 0 {
+			remaining := fs.Quota - fs.Sold
+			flashSale.RemainingQuota = &remaining
+		}
+		flashSaleByVariant[fs.ProductVariantID] = flashSale
+	}
+
 	productVariant := make([]dto.ProductPageProductVariant, 0)
 	for _, pVar := range pVars {
 		disc := decimal.NewFromFloat32((100.0 - pVar.Discount) / 100.0)
@@ -67,6 +95,7 @@ func (uc *productPageUsecase) GetProductDetail(ctx context.Context, productCode
 			DiscountedPrice: discountedPrice.InexactFloat64(),
 			VariantType1ID:  pVar.VariantType1ID,
 			VariantType2ID:  pVar.VariantType2ID,
+			FlashSale:       flashSaleByVariant[pVar.ID],
 		}
 		productVariant = append(productVariant, v)
 	}
@@ -75,14 +104,27 @@ func (uc *productPageUsecase) GetProductDetail(ctx context.Context, productCode
 	if err != nil {
 		return nil, err
 	}
-	productMedias := []dto.ProductPageProductMedia{{
+	thumbnail := dto.ProductPageProductMedia{
 		MediaUrl:  prod.ThumbnailUrl,
 		MediaType: "image",
-	}}
+	}
+	tMeds, err := uc.mr.FindByURLs(ctx, []string{prod.ThumbnailUrl})
+	if err != nil {
+		return nil, err
+	}
+	for _, tMed := range tMeds {
+		thumbnail.ThumbnailUrl = tMed.ThumbnailUrl.String
+		thumbnail.MediumUrl = tMed.MediumUrl.String
+		thumbnail.LargeUrl = tMed.LargeUrl.String
+	}
+	productMedias := []dto.ProductPageProductMedia{thumbnail}
 	for _, pMed := range pMeds {
 		m := dto.ProductPageProductMedia{
-			MediaUrl:  pMed.MediaUrl,
-			MediaType: pMed.MediaType,
+			MediaUrl:     pMed.MediaUrl,
+			MediaType:    pMed.MediaType,
+			ThumbnailUrl: pMed.ThumbnailUrl.String,
+			MediumUrl:    pMed.MediumUrl.String,
+			LargeUrl:     pMed.LargeUrl.String,
 		}
 		productMedias = append(productMedias, m)
 	}
@@ -192,6 +234,9 @@ func NewProductPageUsecase(
 	wr repository.WishlistRepository,
 	rr repository.ReviewRepository,
 	odr repository.OrderDetailRepository,
+	mr repository.MediaRepository,
+	fsr repository.FlashSaleRepository,
+	psr repository.ProductStatRepository,
 ) ProductPageUsecase {
 	return &productPageUsecase{
 		pr:  pr,
@@ -203,5 +248,8 @@ func NewProductPageUsecase(
 		wr:  wr,
 		rr:  rr,
 		odr: odr,
+		mr:  mr,
+		fsr: fsr,
+		psr: psr,
 	}
 }
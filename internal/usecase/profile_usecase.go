@@ -27,6 +27,7 @@ type (
 		ar  repository.AccountRepository
 		dr  repository.DistrictRepository
 		pr  repository.ProvinceRepository
+		mr  repository.MediaRepository
 	}
 )
 
@@ -130,6 +131,10 @@ func (uc *profileUsecase) UpdateAddress(ctx context.Context, payload dto.UpdateA
 
 // UploadProfilePicture implements ProfileUsecase.
 func (uc *profileUsecase) UploadProfilePicture(ctx context.Context, accountID int64, photoURL string) error {
+	if _, err := ownedMedias(ctx, uc.mr, accountID, []string{photoURL}); err != nil {
+		return err
+	}
+
 	err := uc.ar.UpdateProfilePicture(ctx, accountID, photoURL)
 	if err != nil {
 		return err
@@ -224,11 +229,13 @@ func NewProfileUsecase(
 	ar repository.AccountRepository,
 	dr repository.DistrictRepository,
 	pr repository.ProvinceRepository,
+	mr repository.MediaRepository,
 ) ProfileUsecase {
 	return &profileUsecase{
 		aar: aar,
 		ar:  ar,
 		dr:  dr,
 		pr:  pr,
+		mr:  mr,
 	}
 }
@@ -0,0 +1,254 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/model"
+	"github.com/lil-oren/rest/internal/repository"
+	"github.com/lil-oren/rest/internal/shared"
+)
+
+type (
+	RegionSyncUsecase interface {
+		Sync(ctx context.Context, dryRun bool) (*dto.RegionSyncReport, error)
+	}
+	regionSyncUsecase struct {
+		rp repository.RegionProvider
+		pr repository.ProvinceRepository
+		dr repository.DistrictRepository
+	}
+)
+
+// Sync implements RegionSyncUsecase. Provinces are synced before districts
+// so new cities can be placed in new provinces. Nothing is written when
+// dryRun is set, the report then shows what a sync would change.
+func (uc *regionSyncUsecase) Sync(ctx context.Context, dryRun bool) (*dto.RegionSyncReport, error) {
+	remoteProvinces, err := uc.rp.GetProvinces(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	remoteCities, err := uc.rp.GetCities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// An empty list is a broken answer, not every region being removed.
+	if len(remoteProvinces) == 0 || len(remoteCities) == 0 {
+		return nil, shared.ErrRegionProviderUnavailable
+	}
+
+	res := &dto.RegionSyncReport{
+		DryRun:    dryRun,
+		Provinces: newRegionSyncDiff(),
+		Districts: newRegionSyncDiff(),
+	}
+	if err := uc.syncProvinces(ctx, remoteProvinces, &res.Provinces, dryRun); err != nil {
+		return nil, err
+	}
+
+	if err := uc.syncDistricts(ctx, remoteCities, &res.Districts, dryRun); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (uc *regionSyncUsecase) syncProvinces(ctx context.Context, remote []dto.RegionProvince, diff *dto.RegionSyncDiff, dryRun bool) error {
+	local, err := uc.pr.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	m := newRegionMatcher(len(local))
+	for i := range local {
+		m.add(local[i].ID, local[i].ProviderID)
+	}
+
+	index := make(map[int64]*model.Province, len(local))
+	for i := range local {
+		index[local[i].ID] = &local[i]
+	}
+
+	upserts := make([]model.Province, 0)
+	for _, rp := range remote {
+		providerID := sql.NullString{String: rp.ProviderID, Valid: true}
+		id, ok := m.match(rp.ProviderID)
+		if !ok {
+			upserts = append(upserts, model.Province{Name: rp.Name, ProviderID: providerID})
+			diff.Added = append(diff.Added, dto.RegionChange{ProviderID: rp.ProviderID, Name: rp.Name})
+			continue
+		}
+
+		cur := index[id]
+		switch {
+		case cur.Name != rp.Name || !cur.IsActive:
+			diff.Updated = append(diff.Updated, regionChange(id, rp.ProviderID, rp.Name, cur.Name))
+		case !cur.ProviderID.Valid:
+			diff.Mapped++
+		default:
+			diff.Unchanged++
+			continue
+		}
+		upserts = append(upserts, model.Province{ID: id, Name: rp.Name, ProviderID: providerID})
+	}
+
+	inactiveIDs := make([]int64, 0)
+	for _, p := range local {
+		if p.IsActive && !m.seen[p.ID] {
+			inactiveIDs = append(inactiveIDs, p.ID)
+			diff.Deactivated = append(diff.Deactivated, dto.RegionChange{ID: p.ID, ProviderID: p.ProviderID.String, Name: p.Name})
+		}
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	return uc.pr.Sync(ctx, upserts, inactiveIDs)
+}
+
+func (uc *regionSyncUsecase) syncDistricts(ctx context.Context, remote []dto.RegionCity, diff *dto.RegionSyncDiff, dryRun bool) error {
+	provinces, err := uc.pr.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	provinceIDs := make(map[string]int64, len(provinces))
+	for _, p := range provinces {
+		if p.ProviderID.Valid {
+			provinceIDs[p.ProviderID.String] = p.ID
+		}
+	}
+
+	local, err := uc.dr.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	m := newRegionMatcher(len(local))
+	for i := range local {
+		m.add(local[i].ID, local[i].ProviderID)
+	}
+
+	index := make(map[int64]*model.District, len(local))
+	for i := range local {
+		index[local[i].ID] = &local[i]
+	}
+
+	upserts := make([]model.District, 0)
+	for _, rc := range remote {
+		provinceID, ok := provinceIDs[rc.ProvinceProviderID]
+		if !ok && !dryRun {
+			return shared.ErrRegionProvinceNotFound
+		}
+
+		district := model.District{
+			Name:       rc.Name,
+			ProvinceID: provinceID,
+			PostalCode: rc.PostalCode,
+			ProviderID: sql.NullString{String: rc.ProviderID, Valid: true},
+		}
+		id, ok := m.match(rc.ProviderID)
+		if !ok {
+			upserts = append(upserts, district)
+			diff.Added = append(diff.Added, dto.RegionChange{ProviderID: rc.ProviderID, Name: rc.Name})
+			continue
+		}
+
+		cur := index[id]
+		district.ID = id
+		switch {
+		case cur.Name != rc.Name || cur.PostalCode != rc.PostalCode || cur.ProvinceID != provinceID || !cur.IsActive:
+			diff.Updated = append(diff.Updated, regionChange(id, rc.ProviderID, rc.Name, cur.Name))
+		case !cur.ProviderID.Valid:
+			diff.Mapped++
+		default:
+			diff.Unchanged++
+			continue
+		}
+		upserts = append(upserts, district)
+	}
+
+	inactiveIDs := make([]int64, 0)
+	for _, d := range local {
+		if d.IsActive && !m.seen[d.ID] {
+			inactiveIDs = append(inactiveIDs, d.ID)
+			diff.Deactivated = append(diff.Deactivated, dto.RegionChange{ID: d.ID, ProviderID: d.ProviderID.String, Name: d.Name})
+		}
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	return uc.dr.Sync(ctx, upserts, inactiveIDs)
+}
+
+// regionMatcher finds the local row of a provider id. Rows seeded before the
+// sync have no provider id but were seeded with the provider's id as their
+// own, so such a row is claimed when nothing is mapped to the id yet.
+type regionMatcher struct {
+	mapped   map[string]int64
+	unmapped map[int64]bool
+	seen     map[int64]bool
+}
+
+func newRegionMatcher(size int) *regionMatcher {
+	return &regionMatcher{
+		mapped:   make(map[string]int64, size),
+		unmapped: make(map[int64]bool, size),
+		seen:     make(map[int64]bool, size),
+	}
+}
+
+func (m *regionMatcher) add(id int64, providerID sql.NullString) {
+	if providerID.Valid {
+		m.mapped[providerID.String] = id
+		return
+	}
+	m.unmapped[id] = true
+}
+
+func (m *regionMatcher) match(providerID string) (int64, bool) {
+	if id, ok := m.mapped[providerID]; ok {
+		m.seen[id] = true
+		return id, true
+	}
+
+	id, err := strconv.ParseInt(providerID, 10, 64)
+	if err != nil || !m.unmapped[id] || m.seen[id] {
+		return 0, false
+	}
+	m.seen[id] = true
+
+	return id, true
+}
+
+func newRegionSyncDiff() dto.RegionSyncDiff {
+	return dto.RegionSyncDiff{
+		Added:       make([]dto.RegionChange, 0),
+		Updated:     make([]dto.RegionChange, 0),
+		Deactivated: make([]dto.RegionChange, 0),
+	}
+}
+
+func regionChange(id int64, providerID, name, previousName string) dto.RegionChange {
+	change := dto.RegionChange{ID: id, ProviderID: providerID, Name: name}
+	if previousName != name {
+		change.PreviousName = previousName
+	}
+
+	return change
+}
+
+func NewRegionSyncUsecase(rp repository.RegionProvider, pr repository.ProvinceRepository, dr repository.DistrictRepository) RegionSyncUsecase {
+	return &regionSyncUsecase{
+		rp: rp,
+		pr: pr,
+		dr: dr,
+	}
+}
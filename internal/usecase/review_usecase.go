@@ -20,6 +20,7 @@ type (
 	reviewUsecase struct {
 		pr repository.ProductRepository
 		rr repository.ReviewRepository
+		mr repository.MediaRepository
 	}
 )
 
@@ -62,6 +63,9 @@ func (ruc *reviewUsecase) AddReviewOfProduct(ctx context.Context, payload *dto.A
 		}
 		return err
 	}
+	if _, err := ownedMedias(ctx, ruc.mr, payload.AccountID, payload.ImageUrls); err != nil {
+		return err
+	}
 	review := &model.Review{
 		Rating:      payload.Rating,
 		Comment:     payload.Comment,
@@ -74,9 +78,10 @@ func (ruc *reviewUsecase) AddReviewOfProduct(ctx context.Context, payload *dto.A
 	return nil
 }
 
-func NewReviewUsecase(rr repository.ReviewRepository, pr repository.ProductRepository) ReviewUsecase {
+func NewReviewUsecase(rr repository.ReviewRepository, pr repository.ProductRepository, mr repository.MediaRepository) ReviewUsecase {
 	return &reviewUsecase{
 		rr: rr,
 		pr: pr,
+		mr: mr,
 	}
 }
@@ -0,0 +1,271 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/model"
+	"github.com/lil-oren/rest/internal/repository"
+	"github.com/lil-oren/rest/internal/shared"
+	"github.com/shopspring/decimal"
+)
+
+type (
+	SalesReportUsecase interface {
+		ExportSalesReport(ctx context.Context, payload dto.SalesReportPayload) (*dto.SalesReportExportResponse, error)
+		WriteSalesReport(ctx context.Context, payload dto.SalesReportPayload, w io.Writer) error
+		GetSalesReportJob(ctx context.Context, jobID, sellerID int64) (*dto.SalesReportJobResponse, error)
+		DownloadSalesReport(ctx context.Context, jobID, sellerID int64) (*dto.SalesReportFileResponse, error)
+		ProcessPendingSalesReports(ctx context.Context) error
+	}
+	salesReportUsecase struct {
+		or   repository.OrderRepository
+		srjr repository.SalesReportJobRepository
+		ms   repository.MediaStorage
+		cfg  dependency.Config
+	}
+)
+
+// ExportSalesReport implements SalesReportUsecase. Reports with at most
+// constant.SalesReportSyncMaxOrders orders are left to WriteSalesReport,
+// larger ones are queued and the job is returned.
+func (uc *salesReportUsecase) ExportSalesReport(ctx context.Context, payload dto.SalesReportPayload) (*dto.SalesReportExportResponse, error) {
+	start, _ := time.Parse("2006-01-02", payload.StartDate)
+	end, _ := time.Parse("2006-01-02", payload.EndDate)
+	if end.Before(start) || end.After(start.AddDate(0, 0, constant.SalesReportMaxDays-1)) {
+		return nil, shared.ErrInvalidSalesReportPeriod
+	}
+
+	res := &dto.SalesReportExportResponse{
+		FileName:    fmt.Sprintf(constant.SalesReportFileTemplate, payload.StartDate, payload.EndDate, payload.Format),
+		ContentType: salesReportContentType(payload.Format),
+	}
+
+	total, err := uc.or.CountSalesReportOrders(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	if total <= constant.SalesReportSyncMaxOrders {
+		return res, nil
+	}
+
+	job := &model.SalesReportJob{
+		SellerID:  payload.SellerID,
+		StartDate: start,
+		EndDate:   end,
+		Format:    payload.Format,
+		Status:    constant.SalesReportStatusPending,
+		FileName:  res.FileName,
+	}
+	if payload.Status != "" {
+		job.OrderStatus = sql.NullString{String: payload.Status, Valid: true}
+	}
+
+	id, err := uc.srjr.Create(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+
+	res.Job, err = uc.GetSalesReportJob(ctx, *id, payload.SellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// WriteSalesReport implements SalesReportUsecase. Every order gets an order
+// row with its totals followed by a row for each of its items.
+func (uc *salesReportUsecase) WriteSalesReport(ctx context.Context, payload dto.SalesReportPayload, w io.Writer) error {
+	reportRows, err := uc.or.FindSalesReportRows(ctx, payload)
+	if err != nil {
+		return err
+	}
+
+	rows := [][]string{constant.SalesReportColumns}
+	for start := 0; start < len(reportRows); {
+		end := start
+		for end < len(reportRows) && reportRows[end].OrderID == reportRows[start].OrderID {
+			end++
+		}
+		rows = append(rows, salesReportOrderRows(reportRows[start:end])...)
+		start = end
+	}
+
+	if payload.Format == constant.ProductExportFormatXLSX {
+		return shared.WriteXLSX(w, constant.SalesReportSheetName, rows)
+	}
+
+	cw := csv.NewWriter(w)
+	return cw.WriteAll(rows)
+}
+
+// GetSalesReportJob implements SalesReportUsecase.
+func (uc *salesReportUsecase) GetSalesReportJob(ctx context.Context, jobID, sellerID int64) (*dto.SalesReportJobResponse, error) {
+	job, err := uc.srjr.FirstByIDAndSellerID(ctx, jobID, sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.SalesReportJobResponse{
+		ID:          job.ID,
+		StartDate:   job.StartDate.Format("2006-01-02"),
+		EndDate:     job.EndDate.Format("2006-01-02"),
+		OrderStatus: job.OrderStatus.String,
+		Format:      job.Format,
+		Status:      job.Status,
+		FileName:    job.FileName,
+		CreatedAt:   job.CreatedAt.Time.Format("2006-01-02 15:04:05"),
+	}
+	if job.FinishedAt.Valid {
+		res.FinishedAt = job.FinishedAt.Time.Format("2006-01-02 15:04:05")
+	}
+
+	return res, nil
+}
+
+// DownloadSalesReport implements SalesReportUsecase.
+func (uc *salesReportUsecase) DownloadSalesReport(ctx context.Context, jobID, sellerID int64) (*dto.SalesReportFileResponse, error) {
+	job, err := uc.srjr.FirstByIDAndSellerID(ctx, jobID, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != constant.SalesReportStatusDone || !job.StorageKey.Valid {
+		return nil, shared.ErrSalesReportNotReady
+	}
+
+	body, err := uc.ms.Open(ctx, job.StorageKey.String)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.SalesReportFileResponse{
+		FileName:    job.FileName,
+		ContentType: salesReportContentType(job.Format),
+		Body:        body,
+	}
+
+	return res, nil
+}
+
+// ProcessPendingSalesReports implements SalesReportUsecase. A report that
+// cannot be written is marked as failed so it is not picked up again.
+func (uc *salesReportUsecase) ProcessPendingSalesReports(ctx context.Context) error {
+	lease := time.Duration(uc.cfg.SalesReport.LeaseTime) * time.Second
+
+	for ctx.Err() == nil {
+		job, err := uc.srjr.ClaimPending(ctx, lease)
+		if err != nil {
+			return err
+		}
+		if job == nil {
+			return nil
+		}
+
+		payload := dto.SalesReportPayload{
+			SellerID:  job.SellerID,
+			StartDate: job.StartDate.Format("2006-01-02"),
+			EndDate:   job.EndDate.Format("2006-01-02"),
+			Status:    job.OrderStatus.String,
+			Format:    job.Format,
+		}
+
+		buf := new(bytes.Buffer)
+		key := fmt.Sprintf(constant.SalesReportKeyTemplate, job.SellerID, shared.GenerateUUID(), job.Format)
+		err = uc.WriteSalesReport(ctx, payload, buf)
+		if err == nil {
+			err = uc.ms.Put(ctx, key, salesReportContentType(job.Format), buf, int64(buf.Len()))
+		}
+
+		job.Status = constant.SalesReportStatusDone
+		job.StorageKey = sql.NullString{String: key, Valid: true}
+		if err != nil {
+			job.Status = constant.SalesReportStatusFailed
+			job.StorageKey = sql.NullString{}
+		}
+
+		if finishErr := uc.srjr.Finish(ctx, job); finishErr != nil {
+			return finishErr
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// salesReportOrderRows returns the rows of one order. The net received is
+// what the seller is paid for the order: its items and delivery less the
+// promotion and shop vouchers.
+func salesReportOrderRows(lines []dto.SalesReportRowModel) [][]string {
+	first := lines[0]
+	orderID := strconv.FormatInt(first.OrderID, 10)
+	orderDate := first.OrderDate.Format("2006-01-02 15:04:05")
+	promotion := decimal.NewFromFloat(first.PromotionAmount.Float64)
+
+	subtotal := decimal.Zero
+	quantity := 0
+	items := make([][]string, 0, len(lines))
+	for _, line := range lines {
+		subtotal = subtotal.Add(line.SubTotalPrice)
+		quantity += line.Quantity
+
+		item := make([]string, len(constant.SalesReportColumns))
+		item[constant.SalesReportColumnRowType] = constant.SalesReportRowItem
+		item[constant.SalesReportColumnOrderID] = orderID
+		item[constant.SalesReportColumnOrderDate] = orderDate
+		item[constant.SalesReportColumnStatus] = line.Status
+		item[constant.SalesReportColumnBuyerName] = line.BuyerName
+		item[constant.SalesReportColumnProductName] = line.ProductName
+		item[constant.SalesReportColumnVariantName] = line.VariantName
+		item[constant.SalesReportColumnQuantity] = strconv.Itoa(line.Quantity)
+		item[constant.SalesReportColumnSubtotal] = line.SubTotalPrice.String()
+		items = append(items, item)
+	}
+
+	order := make([]string, len(constant.SalesReportColumns))
+	order[constant.SalesReportColumnRowType] = constant.SalesReportRowOrder
+	order[constant.SalesReportColumnOrderID] = orderID
+	order[constant.SalesReportColumnOrderDate] = orderDate
+	order[constant.SalesReportColumnStatus] = first.Status
+	order[constant.SalesReportColumnBuyerName] = first.BuyerName
+	order[constant.SalesReportColumnQuantity] = strconv.Itoa(quantity)
+	order[constant.SalesReportColumnSubtotal] = subtotal.String()
+	order[constant.SalesReportColumnPromotionAmount] = promotion.String()
+	order[constant.SalesReportColumnVoucherAmount] = first.VoucherAmount.String()
+	order[constant.SalesReportColumnDeliveryCost] = first.DeliveryCost.String()
+	order[constant.SalesReportColumnNetReceived] = subtotal.
+		Sub(promotion).
+		Sub(first.VoucherAmount).
+		Add(first.DeliveryCost).
+		String()
+
+	return append([][]string{order}, items...)
+}
+
+func salesReportContentType(format string) string {
+	if format == constant.ProductExportFormatXLSX {
+		return constant.ProductExportXLSXType
+	}
+
+	return constant.ProductExportCSVContentType
+}
+
+func NewSalesReportUsecase(or repository.OrderRepository, srjr repository.SalesReportJobRepository, ms repository.MediaStorage, cfg dependency.Config) SalesReportUsecase {
+	return &salesReportUsecase{
+		or:   or,
+		srjr: srjr,
+		ms:   ms,
+		cfg:  cfg,
+	}
+}
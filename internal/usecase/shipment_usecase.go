@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/model"
+	"github.com/lil-oren/rest/internal/repository"
+	"github.com/lil-oren/rest/internal/shared"
+)
+
+type (
+	ShipmentUsecase interface {
+		GetOrderTracking(ctx context.Context, orderID, accountID int64) (*dto.OrderTrackingResponse, error)
+		ProcessShipmentTracking(ctx context.Context) error
+	}
+	shipmentUsecase struct {
+		or  repository.OrderRepository
+		osr repository.OrderShipmentRepository
+		st  repository.ShipmentTracker
+		cfg dependency.Config
+	}
+)
+
+// GetOrderTracking implements ShipmentUsecase. Both the buyer and the seller
+// of the order can track it.
+func (uc *shipmentUsecase) GetOrderTracking(ctx context.Context, orderID, accountID int64) (*dto.OrderTrackingResponse, error) {
+	order, err := uc.or.FirstOrderByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.BuyerId != accountID && order.SellerId != accountID {
+		return nil, shared.ErrUnauthorizedUser
+	}
+
+	shipment, err := uc.osr.FirstByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := uc.osr.FindEventsByShipmentID(ctx, shipment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.OrderTrackingResponse{
+		OrderID:        order.ID,
+		OrderStatus:    order.Status,
+		CourierCode:    shipment.CourierCode,
+		WaybillNumber:  shipment.WaybillNumber,
+		ShipmentStatus: shipment.Status,
+		ShippedAt:      shipment.CreatedAt.Time.Format("2006-01-02 15:04:05"),
+		Events:         make([]dto.OrderTrackingEventResponse, 0, len(events)),
+	}
+	if shipment.DeliveredAt.Valid {
+		res.DeliveredAt = shipment.DeliveredAt.Time.Format("2006-01-02 15:04:05")
+	}
+	for _, event := range events {
+		res.Events = append(res.Events, dto.OrderTrackingEventResponse{
+			Description: event.Description,
+			Location:    event.Location,
+			OccurredAt:  event.OccurredAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+
+	return res, nil
+}
+
+// ProcessShipmentTracking implements ShipmentUsecase. Every undelivered
+// shipment not checked in the last SHIPMENT_CHECK_INTERVAL minutes is asked
+// to the tracker. A shipment the tracker fails on is skipped until its next
+// check and the first such error is returned. After
+// SHIPMENT_MAX_FAILED_CHECKS failures in a row it is not tracked anymore.
+func (uc *shipmentUsecase) ProcessShipmentTracking(ctx context.Context) error {
+	checkedBefore := time.Now().Add(-time.Duration(uc.cfg.Shipment.CheckInterval) * time.Minute)
+
+	var trackErr error
+	for ctx.Err() == nil {
+		shipment, err := uc.osr.ClaimDue(ctx, checkedBefore)
+		if err != nil {
+			return err
+		}
+		if shipment == nil {
+			break
+		}
+
+		tracking, err := uc.st.Track(ctx, shipment)
+		if err != nil {
+			if trackErr == nil {
+				trackErr = err
+			}
+			if err := uc.osr.MarkTrackingFailed(ctx, shipment.ID, int(uc.cfg.Shipment.MaxFailedChecks)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		deliveredAt := time.Time{}
+		events := make([]model.OrderTrackingEvent, 0, len(tracking.Events))
+		for _, e := range tracking.Events {
+			if e.OccurredAt.After(deliveredAt) {
+				deliveredAt = e.OccurredAt
+			}
+			events = append(events, model.OrderTrackingEvent{
+				OrderShipmentID: shipment.ID,
+				Description:     e.Description,
+				Location:        e.Location,
+				OccurredAt:      e.OccurredAt,
+			})
+		}
+		if tracking.Delivered {
+			if deliveredAt.IsZero() {
+				deliveredAt = time.Now()
+			}
+			shipment.Status = string(constant.DeliveredShipmentStatus)
+			shipment.DeliveredAt = sql.NullTime{Time: deliveredAt, Valid: true}
+		}
+
+		if err := uc.osr.SaveTracking(ctx, shipment, events); err != nil {
+			return err
+		}
+	}
+
+	return trackErr
+}
+
+func NewShipmentUsecase(
+	or repository.OrderRepository,
+	osr repository.OrderShipmentRepository,
+	st repository.ShipmentTracker,
+	cfg dependency.Config,
+) ShipmentUsecase {
+	return &shipmentUsecase{
+		or:  or,
+		osr: osr,
+		st:  st,
+		cfg: cfg,
+	}
+}
@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/model"
+	"github.com/lil-oren/rest/internal/repository"
+	"github.com/lil-oren/rest/internal/shared"
+	"github.com/shopspring/decimal"
+)
+
+type (
+	ShippingRateUsecase interface {
+		GetTableRates(ctx context.Context, params dto.ShippingTableRateParams) (*dto.ShippingTableRateListResponse, error)
+		CreateTableRate(ctx context.Context, body dto.ShippingTableRateRequestBody) (*dto.ShippingTableRateResponse, error)
+		UpdateTableRate(ctx context.Context, id int64, body dto.ShippingTableRateRequestBody) (*dto.ShippingTableRateResponse, error)
+		DeleteTableRate(ctx context.Context, id int64) error
+	}
+	shippingRateUsecase struct {
+		strr repository.ShippingTableRateRepository
+	}
+)
+
+// GetTableRates implements ShippingRateUsecase.
+func (uc *shippingRateUsecase) GetTableRates(ctx context.Context, params dto.ShippingTableRateParams) (*dto.ShippingTableRateListResponse, error) {
+	if params.Page == 0 {
+		params.Page = constant.DefaultPage
+	}
+	params.CourierCode = strings.ToLower(params.CourierCode)
+
+	rates, err := uc.strr.Find(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := uc.strr.Count(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.ShippingTableRateListResponse{
+		Items:       make([]dto.ShippingTableRateResponse, 0, len(rates)),
+		TotalData:   count,
+		TotalPage:   int(math.Ceil(float64(count) / constant.ShippingTableRateDefaultItems)),
+		CurrentPage: params.Page,
+	}
+	for _, rate := range rates {
+		res.Items = append(res.Items, toShippingTableRateResponse(rate))
+	}
+
+	return res, nil
+}
+
+// CreateTableRate implements ShippingRateUsecase.
+func (uc *shippingRateUsecase) CreateTableRate(ctx context.Context, body dto.ShippingTableRateRequestBody) (*dto.ShippingTableRateResponse, error) {
+	rate := toShippingTableRate(body)
+	id, err := uc.strr.Create(ctx, &rate)
+	if err != nil {
+		return nil, err
+	}
+
+	rate.ID = *id
+	res := toShippingTableRateResponse(rate)
+
+	return &res, nil
+}
+
+// UpdateTableRate implements ShippingRateUsecase.
+func (uc *shippingRateUsecase) UpdateTableRate(ctx context.Context, id int64, body dto.ShippingTableRateRequestBody) (*dto.ShippingTableRateResponse, error) {
+	rate := toShippingTableRate(body)
+	rate.ID = id
+	if err := uc.strr.Update(ctx, &rate); err != nil {
+		return nil, err
+	}
+
+	res := toShippingTableRateResponse(rate)
+
+	return &res, nil
+}
+
+// DeleteTableRate implements ShippingRateUsecase.
+func (uc *shippingRateUsecase) DeleteTableRate(ctx context.Context, id int64) error {
+	return uc.strr.Delete(ctx, id)
+}
+
+func toShippingTableRate(body dto.ShippingTableRateRequestBody) model.ShippingTableRate {
+	return model.ShippingTableRate{
+		CourierCode:           strings.ToLower(body.CourierCode),
+		Service:               body.Service,
+		Description:           body.Description,
+		OriginProvinceID:      body.OriginProvinceID,
+		DestinationProvinceID: body.DestinationProvinceID,
+		MinWeight:             body.MinWeight,
+		MaxWeight:             body.MaxWeight,
+		Price:                 decimal.NewFromFloat(body.Price),
+		ETD:                   body.ETD,
+	}
+}
+
+func toShippingTableRateResponse(rate model.ShippingTableRate) dto.ShippingTableRateResponse {
+	return dto.ShippingTableRateResponse{
+		ID:                    rate.ID,
+		CourierCode:           rate.CourierCode,
+		Service:               rate.Service,
+		Description:           rate.Description,
+		OriginProvinceID:      rate.OriginProvinceID,
+		DestinationProvinceID: rate.DestinationProvinceID,
+		MinWeight:             rate.MinWeight,
+		MaxWeight:             rate.MaxWeight,
+		Price:                 rate.Price.InexactFloat64(),
+		ETD:                   rate.ETD,
+	}
+}
+
+// shippingRates asks the provider of courier for every service it offers
+// between the two addresses.
+func shippingRates(ctx context.Context, spr repository.ShippingProviderRegistry, courier *model.Courier,
+	shopAddress, buyerAddress *model.AccountAddresses, weight int) ([]dto.ShippingRate, error) {
+	provider, err := spr.ProviderFor(courier.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	return provider.GetRates(ctx, dto.ShippingRateQuery{
+		CourierCode:           courier.Code,
+		OriginDistrictID:      shopAddress.DistrictId,
+		OriginProvinceID:      shopAddress.ProvinceId,
+		DestinationDistrictID: buyerAddress.DistrictId,
+		DestinationProvinceID: buyerAddress.ProvinceId,
+		Weight:                weight,
+	})
+}
+
+// quoteShipping picks service, or the service of the courier when it is
+// empty, from the rates of courier. Within one city a courier may only
+// deliver with its same-city service, which is picked instead when the
+// chosen one is not offered.
+func quoteShipping(ctx context.Context, spr repository.ShippingProviderRegistry, courier *model.Courier,
+	shopAddress, buyerAddress *model.AccountAddresses, weight int, service string) (*dto.ShippingRate, []dto.ShippingRate, error) {
+	rates, err := shippingRates(ctx, spr, courier, shopAddress, buyerAddress, weight)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if service == "" {
+		service = courier.ServiceName
+	}
+	candidates := []string{service}
+	if sameCity, ok := constant.SameCityCourierServices[courier.Code]; ok && shopAddress.DistrictId == buyerAddress.DistrictId {
+		candidates = append(candidates, sameCity)
+	}
+
+	for _, candidate := range candidates {
+		for i := range rates {
+			if rates[i].Service == candidate {
+				return &rates[i], rates, nil
+			}
+		}
+	}
+
+	return nil, rates, shared.ErrShippingServiceUnavailable
+}
+
+// estimatedArrival turns an ETD such as "2" or "1-3" into the latest day
+// the package should arrive when it is ordered at from.
+func estimatedArrival(etd string, from time.Time) sql.NullTime {
+	fields := strings.Fields(etd)
+	if len(fields) == 0 {
+		return sql.NullTime{}
+	}
+
+	bounds := strings.Split(fields[0], "-")
+	days, err := strconv.Atoi(bounds[len(bounds)-1])
+	if err != nil || days < 0 {
+		return sql.NullTime{}
+	}
+
+	return sql.NullTime{Time: from.AddDate(0, 0, days), Valid: true}
+}
+
+func toShippingServiceResponses(rates []dto.ShippingRate) []dto.ShippingServiceResponse {
+	res := make([]dto.ShippingServiceResponse, 0, len(rates))
+	for _, rate := range rates {
+		res = append(res, dto.ShippingServiceResponse{
+			Service:     rate.Service,
+			Description: rate.Description,
+			Cost:        rate.Cost,
+			ETD:         rate.ETD,
+		})
+	}
+
+	return res
+}
+
+func NewShippingRateUsecase(strr repository.ShippingTableRateRepository) ShippingRateUsecase {
+	return &shippingRateUsecase{
+		strr: strr,
+	}
+}
@@ -21,6 +21,7 @@ type (
 		UpdateShopPromotion(ctx context.Context, payload *dto.UpsertShopPromotionPayload, promoID int64) error
 		DuplicateShopPromotion(ctx context.Context, promoID int64, sellerID int64) error
 		DeleteShopPromotion(ctx context.Context, promoID int64, sellerID int64) error
+		GetPromotionAnalytics(ctx context.Context, promoID int64, sellerID int64) (*dto.PromotionAnalyticsResponse, error)
 	}
 	promotionUsecase struct {
 		pr repository.PromotionRepository
@@ -42,6 +43,7 @@ func (uc *promotionUsecase) GetAllPromoFromShop(ctx context.Context, shopID int6
 			Percentage:   promo.Percentage.Float64,
 			MinimumSpend: promo.MinimumSpend.InexactFloat64(),
 			Quota:        promo.Quota,
+			UsageLimit:   int(promo.UsageLimit.Int64),
 			StartedAt:    promo.StartedAt.Format("2006-01-02 15:04:05"),
 			ExpiredAt:    promo.ExpiredAt.Format("2006-01-02 15:04:05"),
 		})
@@ -134,6 +136,7 @@ func makePromotionModel(ctx context.Context, payload *dto.UpsertShopPromotionPay
 		Percentage:   percentage,
 		MinimumSpend: decimal.NewFromFloat(payload.MinimumSpend),
 		Quota:        payload.Quota,
+		UsageLimit:   sql.NullInt64{Int64: int64(payload.UsageLimit), Valid: payload.UsageLimit != 0},
 		ShopID:       shop.ID,
 		StartedAt:    startedAt,
 		ExpiredAt:    expiredAt,
@@ -178,6 +181,58 @@ func (uc *promotionUsecase) DeleteShopPromotion(ctx context.Context, promoID int
 	return uc.pr.SoftDelete(ctx, promoID)
 }
 
+// GetPromotionAnalytics implements PromotionUsecase.
+func (uc *promotionUsecase) GetPromotionAnalytics(ctx context.Context, promoID int64, sellerID int64) (*dto.PromotionAnalyticsResponse, error) {
+	shop, err := uc.sr.FirstShopById(ctx, int(sellerID))
+	if err != nil {
+		return nil, err
+	}
+	promo, err := uc.pr.FirstByID(ctx, promoID)
+	if err != nil {
+		return nil, err
+	}
+	if promo == nil {
+		return nil, shared.ErrPromoNotFound
+	}
+	if promo.ShopID != shop.ID {
+		return nil, shared.ErrUnauthorizedUser
+	}
+
+	analytics, err := uc.pr.FindAnalytics(ctx, promo, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	daily, err := uc.pr.FindDailyAnalytics(ctx, promo, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	nonPromoAverage, err := uc.pr.AverageNonPromoOrderValue(ctx, promo, sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.PromotionAnalyticsResponse{
+		PromotionID:               promo.ID,
+		Name:                      promo.Name,
+		StartedAt:                 promo.StartedAt.Format("2006-01-02 15:04:05"),
+		ExpiredAt:                 promo.ExpiredAt.Format("2006-01-02 15:04:05"),
+		TotalOrders:               analytics.TotalOrders,
+		UniqueBuyers:              analytics.UniqueBuyers,
+		GMV:                       analytics.GMV,
+		TotalDiscount:             analytics.TotalDiscount,
+		NonPromoAverageOrderValue: nonPromoAverage,
+		Daily:                     make([]dto.PromotionDailyResponse, 0, len(daily)),
+	}
+	if analytics.TotalOrders != 0 {
+		res.AverageOrderValue = analytics.GMV / float64(analytics.TotalOrders)
+	}
+	for _, d := range daily {
+		res.Daily = append(res.Daily, dto.PromotionDailyResponse(d))
+	}
+
+	return res, nil
+}
+
 func NewPromotionRepository(pr repository.PromotionRepository, sr repository.ShopRepository) PromotionUsecase {
 	return &promotionUsecase{
 		pr: pr,
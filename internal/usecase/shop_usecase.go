@@ -6,6 +6,7 @@ import (
 	"math"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/lil-oren/rest/internal/constant"
 	"github.com/lil-oren/rest/internal/dto"
@@ -23,10 +24,12 @@ type (
 		AddProduct(ctx context.Context, payload dto.AddProductPayload, accountId int) error
 		GetProductDetail(ctx context.Context, productCode string) (*dto.GetProductDetailResponseBody, error)
 		UpdateProduct(ctx context.Context, payload dto.UpdateProductPayload, accountId int) error
-		GetAllProduct(ctx context.Context, sellerId int, page int) (*dto.GetAllProductResponseBody, error)
+		GetAllProduct(ctx context.Context, payload dto.GetAllProductPayload) (*dto.GetAllProductResponseBody, error)
 		GetProductDiscount(ctx context.Context, sellerId int, productCode string) (*dto.GetProductDiscountResponseBody, error)
 		EditProductDiscount(ctx context.Context, payload dto.UpdateProductDiscountPayload, sellerId int, productCode string) error
 		DeleteProduct(ctx context.Context, productCode string, sellerId int64) error
+		UpdateProductStatus(ctx context.Context, payload dto.UpdateProductStatusPayload) error
+		ApplyProductSchedules(ctx context.Context) error
 	}
 	shopUsecase struct {
 		sr  repository.ShopRepository
@@ -34,6 +37,7 @@ type (
 		scr repository.ShopCourierRepository
 		er  repository.WalletRepository
 		pr  repository.ProductRepository
+		mr  repository.MediaRepository
 	}
 )
 
@@ -194,19 +198,12 @@ func (su *shopUsecase) UpdateProduct(ctx context.Context, payload dto.UpdateProd
 		categories = append(categories, val.Field(i).Interface())
 	}
 
-	mediaType := make([]string, 0)
-	for _, v := range payload.ImageURL {
-		switch {
-		case strings.Contains(v, constant.MP4VideoType) || strings.Contains(v, constant.MKVVideoType):
-			mediaType = append(mediaType, constant.VideoTypeDefault)
-		case strings.Contains(v, constant.JPGImageType) || strings.Contains(v, constant.JPEGImageType) || strings.Contains(v, constant.PNGImageType):
-			mediaType = append(mediaType, constant.ImageTypeDefault)
-		default:
-			mediaType = append(mediaType, constant.ImageTypeDefault)
-		}
+	mediaType, err := su.productMediaTypes(ctx, int64(accountId), payload.ImageURL)
+	if err != nil {
+		return err
 	}
 
-	err := su.pr.UpdateProduct(ctx, payload, categories, mediaType, int64(accountId))
+	err = su.pr.UpdateProduct(ctx, payload, categories, mediaType, int64(accountId))
 	if err != nil {
 		return shared.ErrUpdateProduct
 	}
@@ -265,6 +262,19 @@ func (su *shopUsecase) AddProduct(ctx context.Context, payload dto.AddProductPay
 		}
 	}
 
+	status, err := productScheduleStatus(payload.Status, payload.PublishAt, payload.UnpublishAt)
+	if err != nil {
+		return err
+	}
+
+	addProduct.StockReason = constant.StockReasonInitial
+	if payload.StockReason != "" {
+		addProduct.StockReason = payload.StockReason
+	}
+
+	addProduct.Status = status
+	addProduct.PublishAt = payload.PublishAt
+	addProduct.UnpublishAt = payload.UnpublishAt
 	addProduct.ProductName = payload.ProductName
 	addProduct.Description = payload.Description
 	addProduct.ImageURL = payload.ImageURL
@@ -324,17 +334,10 @@ func (su *shopUsecase) AddProduct(ctx context.Context, payload dto.AddProductPay
 	}
 
 	uuid := shared.GenerateUUID()
-	mediaType := make([]string, 0)
 	productCode := payload.ProductName + "-" + uuid
-	for _, v := range payload.ImageURL {
-		switch {
-		case strings.Contains(v, constant.MP4VideoType) || strings.Contains(v, constant.MKVVideoType):
-			mediaType = append(mediaType, constant.VideoTypeDefault)
-		case strings.Contains(v, constant.JPGImageType) || strings.Contains(v, constant.JPEGImageType) || strings.Contains(v, constant.PNGImageType):
-			mediaType = append(mediaType, constant.ImageTypeDefault)
-		default:
-			mediaType = append(mediaType, constant.ImageTypeDefault)
-		}
+	mediaType, err := su.productMediaTypes(ctx, int64(accountId), payload.ImageURL)
+	if err != nil {
+		return err
 	}
 
 	for idx, v := range addProduct.VariantDefinitions.VariantGroup1.VariantTypes {
@@ -355,7 +358,7 @@ func (su *shopUsecase) AddProduct(ctx context.Context, payload dto.AddProductPay
 		}
 	}
 
-	err := su.pr.CreateProduct(ctx, addProduct, accountId, productCode, mediaType)
+	err = su.pr.CreateProduct(ctx, addProduct, accountId, productCode, mediaType)
 	if err != nil {
 		return err
 	}
@@ -439,24 +442,24 @@ func (su *shopUsecase) GetProductDetail(ctx context.Context, productCode string)
 	return productDetail, nil
 }
 
-func (su *shopUsecase) GetAllProduct(ctx context.Context, sellerId int, page int) (*dto.GetAllProductResponseBody, error) {
+func (su *shopUsecase) GetAllProduct(ctx context.Context, payload dto.GetAllProductPayload) (*dto.GetAllProductResponseBody, error) {
 	res := dto.GetAllProductResponseBody{}
 
-	if page < 0 {
+	if payload.Page < 0 {
 		return nil, shared.ErrInvalidPage
 	}
-	products, err := su.sr.GetAllProductBySellerId(ctx, sellerId, page)
+	products, err := su.sr.GetAllProductBySellerId(ctx, payload)
 	if err != nil {
 		return nil, shared.ErrFindProduct
 	}
 
-	count, err := su.sr.CountAllProductBySellerId(ctx, sellerId)
+	count, err := su.sr.CountAllProductBySellerId(ctx, payload.SellerID, payload.Status)
 	if err != nil {
 		return nil, shared.ErrFindProduct
 	}
 
 	paginationDetail := dto.PaginationDetail{
-		Page: page,
+		Page: payload.Page,
 	}
 	paginationDetail.TotalProduct = *count
 	paginationDetail.TotalPage = int(math.Ceil(float64(paginationDetail.TotalProduct) / 10.0))
@@ -514,18 +517,92 @@ func (su *shopUsecase) GetProductDiscount(ctx context.Context, sellerId int, pro
 func (su *shopUsecase) EditProductDiscount(ctx context.Context, payload dto.UpdateProductDiscountPayload, sellerId int, productCode string) error {
 	err := su.sr.UpdateProductDiscount(ctx, payload, int64(sellerId), productCode)
 	if err != nil {
+		if err == shared.ErrProductInFlashSale {
+			return err
+		}
 		return shared.ErrUpdateProductDiscount
 	}
 
 	return nil
 }
 
-func NewShopUsecase(sr repository.ShopRepository, aar repository.AccountAddressRepository, scr repository.ShopCourierRepository, er repository.WalletRepository, pr repository.ProductRepository) ShopUsecase {
+func (su *shopUsecase) UpdateProductStatus(ctx context.Context, payload dto.UpdateProductStatusPayload) error {
+	product, err := su.pr.FirstProductByCode(ctx, payload.ProductCode)
+	if err != nil {
+		return err
+	}
+
+	if product.SellerID != payload.SellerID {
+		return shared.ErrProductNotFromSeller
+	}
+
+	status, err := productScheduleStatus(payload.Status, payload.PublishAt, payload.UnpublishAt)
+	if err != nil {
+		return err
+	}
+	payload.Status = status
+
+	return su.pr.UpdateProductStatus(ctx, payload)
+}
+
+func (su *shopUsecase) ApplyProductSchedules(ctx context.Context) error {
+	return su.pr.ApplyProductSchedules(ctx)
+}
+
+// productScheduleStatus validates a publish schedule and returns the status
+// the product is stored with. Products scheduled to be published start as
+// draft unless another status is given.
+func productScheduleStatus(status string, publishAt, unpublishAt *time.Time) (string, error) {
+	now := time.Now()
+	if status == "" {
+		status = constant.ProductStatusActive
+		if publishAt != nil {
+			status = constant.ProductStatusDraft
+		}
+	}
+
+	if publishAt != nil {
+		if status != constant.ProductStatusDraft && status != constant.ProductStatusInactive {
+			return "", shared.ErrInvalidProductSchedule
+		}
+		if !publishAt.After(now) {
+			return "", shared.ErrInvalidProductSchedule
+		}
+	}
+
+	if unpublishAt != nil {
+		if status == constant.ProductStatusArchived || !unpublishAt.After(now) {
+			return "", shared.ErrInvalidProductSchedule
+		}
+		if publishAt != nil && !unpublishAt.After(*publishAt) {
+			return "", shared.ErrInvalidProductSchedule
+		}
+	}
+
+	return status, nil
+}
+
+func (su *shopUsecase) productMediaTypes(ctx context.Context, accountID int64, urls []string) ([]string, error) {
+	medias, err := ownedMedias(ctx, su.mr, accountID, urls)
+	if err != nil {
+		return nil, err
+	}
+
+	mediaType := make([]string, 0)
+	for _, v := range urls {
+		mediaType = append(mediaType, medias[v].MediaType)
+	}
+
+	return mediaType, nil
+}
+
+func NewShopUsecase(sr repository.ShopRepository, aar repository.AccountAddressRepository, scr repository.ShopCourierRepository, er repository.WalletRepository, pr repository.ProductRepository, mr repository.MediaRepository) ShopUsecase {
 	return &shopUsecase{
 		sr:  sr,
 		aar: aar,
 		scr: scr,
 		er:  er,
 		pr:  pr,
+		mr:  mr,
 	}
 }
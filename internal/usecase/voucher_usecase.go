@@ -0,0 +1,592 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/model"
+	"github.com/lil-oren/rest/internal/repository"
+	"github.com/lil-oren/rest/internal/shared"
+	"github.com/shopspring/decimal"
+)
+
+type (
+	VoucherUsecase interface {
+		CreateShopVoucher(ctx context.Context, payload dto.VoucherPayload) (*dto.VoucherResponse, error)
+		GetShopVouchers(ctx context.Context, sellerID int64, params dto.VoucherParams) (*dto.VoucherListResponse, error)
+		DeleteShopVoucher(ctx context.Context, id, sellerID int64) error
+		ValidateVoucher(ctx context.Context, payload dto.ValidateVoucherPayload) (*dto.ValidateVoucherResponse, error)
+		CreatePlatformVoucher(ctx context.Context, payload dto.VoucherPayload) (*dto.VoucherResponse, error)
+		GetPlatformVouchers(ctx context.Context, params dto.VoucherParams) (*dto.VoucherListResponse, error)
+		UpdatePlatformVoucher(ctx context.Context, payload dto.VoucherPayload) (*dto.VoucherResponse, error)
+	}
+	voucherUsecase struct {
+		vr repository.VoucherRepository
+		sr repository.ShopRepository
+		cr repository.CartRepository
+	}
+)
+
+// CreateShopVoucher implements VoucherUsecase. Shop vouchers are funded by the
+// seller, platform vouchers by the platform.
+func (uc *voucherUsecase) CreateShopVoucher(ctx context.Context, payload dto.VoucherPayload) (*dto.VoucherResponse, error) {
+	shop, err := uc.sr.FirstShopById(ctx, int(payload.SellerID))
+	if err != nil {
+		return nil, err
+	}
+
+	voucher, err := makeVoucherModel(payload)
+	if err != nil {
+		return nil, err
+	}
+	voucher.Issuer = constant.VoucherIssuerShop
+	voucher.ShopID = sql.NullInt64{Int64: shop.ID, Valid: true}
+
+	return uc.createVoucher(ctx, voucher, payload.CategoryIDs)
+}
+
+// CreatePlatformVoucher implements VoucherUsecase. Platform vouchers apply
+// to the whole checkout and are paid for by the platform wallet.
+func (uc *voucherUsecase) CreatePlatformVoucher(ctx context.Context, payload dto.VoucherPayload) (*dto.VoucherResponse, error) {
+	voucher, err := makeVoucherModel(payload)
+	if err != nil {
+		return nil, err
+	}
+	voucher.Issuer = constant.VoucherIssuerPlatform
+
+	return uc.createVoucher(ctx, voucher, payload.CategoryIDs)
+}
+
+// GetPlatformVouchers implements VoucherUsecase.
+func (uc *voucherUsecase) GetPlatformVouchers(ctx context.Context, params dto.VoucherParams) (*dto.VoucherListResponse, error) {
+	if params.Page == 0 {
+		params.Page = constant.DefaultPage
+	}
+
+	vouchers, err := uc.vr.FindPlatform(ctx, params.Page)
+	if err != nil {
+		return nil, shared.ErrFindVoucher
+	}
+
+	count, err := uc.vr.CountPlatform(ctx)
+	if err != nil {
+		return nil, shared.ErrFindVoucher
+	}
+
+	items, err := uc.toVoucherResponses(ctx, vouchers)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.VoucherListResponse{
+		Items:       items,
+		TotalData:   count,
+		TotalPage:   int(math.Ceil(float64(count) / constant.VoucherDefaultItems)),
+		CurrentPage: params.Page,
+	}, nil
+}
+
+// UpdatePlatformVoucher implements VoucherUsecase. Orders that already used
+// the voucher keep their discount.
+func (uc *voucherUsecase) UpdatePlatformVoucher(ctx context.Context, payload dto.VoucherPayload) (*dto.VoucherResponse, error) {
+	current, err := uc.vr.FirstByID(ctx, payload.ID)
+	if err != nil {
+		return nil, err
+	}
+	if current.Issuer != constant.VoucherIssuerPlatform {
+		return nil, shared.ErrVoucherNotFound
+	}
+
+	voucher, err := makeVoucherModel(payload)
+	if err != nil {
+		return nil, err
+	}
+	voucher.ID = current.ID
+	voucher.Issuer = constant.VoucherIssuerPlatform
+
+	if err := uc.vr.Update(ctx, voucher, payload.CategoryIDs); err != nil {
+		return nil, err
+	}
+
+	return uc.voucherResponse(ctx, voucher.ID)
+}
+
+func (uc *voucherUsecase) createVoucher(ctx context.Context, voucher *model.Voucher, categoryIDs []int64) (*dto.VoucherResponse, error) {
+	id, err := uc.vr.Create(ctx, voucher, categoryIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.voucherResponse(ctx, *id)
+}
+
+func (uc *voucherUsecase) voucherResponse(ctx context.Context, id int64) (*dto.VoucherResponse, error) {
+	voucher, err := uc.vr.FirstByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := uc.toVoucherResponses(ctx, []model.Voucher{*voucher})
+	if err != nil {
+		return nil, err
+	}
+
+	return &res[0], nil
+}
+
+// makeVoucherModel checks the dates and the value of a voucher for its type.
+// The issuer is left to the caller.
+func makeVoucherModel(payload dto.VoucherPayload) (*model.Voucher, error) {
+	startedAt, err := time.ParseInLocation("2006-01-02 15:04:05", payload.StartedAt, time.UTC)
+	if err != nil {
+		return nil, shared.ErrInvalidVoucherDate
+	}
+	expiredAt, err := time.ParseInLocation("2006-01-02 15:04:05", payload.ExpiredAt, time.UTC)
+	if err != nil {
+		return nil, shared.ErrInvalidVoucherDate
+	}
+	if !expiredAt.After(startedAt) {
+		return nil, shared.ErrExpiredBeforeStart
+	}
+
+	switch payload.Type {
+	case constant.VoucherTypePercentage:
+		if payload.Value == 0 || payload.Value > 100 {
+			return nil, shared.ErrInvalidVoucherValue
+		}
+	case constant.VoucherTypeFixed:
+		if payload.Value == 0 {
+			return nil, shared.ErrInvalidVoucherValue
+		}
+	case constant.VoucherTypeFreeShipping:
+		if payload.Value != 0 {
+			return nil, shared.ErrInvalidVoucherValue
+		}
+	}
+
+	voucher := &model.Voucher{
+		Code:              normalizeVoucherCode(payload.Code),
+		Name:              payload.Name,
+		Type:              payload.Type,
+		Value:             decimal.NewFromFloat(payload.Value),
+		MinimumSpend:      decimal.NewFromFloat(payload.MinimumSpend),
+		Quota:             payload.Quota,
+		UsageLimitPerUser: payload.UsageLimitPerUser,
+		StartedAt:         startedAt,
+		ExpiredAt:         expiredAt,
+	}
+	if payload.MaxDiscount != 0 {
+		voucher.MaxDiscount = decimal.NullDecimal{Decimal: decimal.NewFromFloat(payload.MaxDiscount), Valid: true}
+	}
+
+	return voucher, nil
+}
+
+// GetShopVouchers implements VoucherUsecase.
+func (uc *voucherUsecase) GetShopVouchers(ctx context.Context, sellerID int64, params dto.VoucherParams) (*dto.VoucherListResponse, error) {
+	if params.Page == 0 {
+		params.Page = constant.DefaultPage
+	}
+
+	shop, err := uc.sr.FirstShopById(ctx, int(sellerID))
+	if err != nil {
+		return nil, err
+	}
+
+	vouchers, err := uc.vr.FindByShopID(ctx, shop.ID, params.Page)
+	if err != nil {
+		return nil, shared.ErrFindVoucher
+	}
+
+	count, err := uc.vr.CountByShopID(ctx, shop.ID)
+	if err != nil {
+		return nil, shared.ErrFindVoucher
+	}
+
+	items, err := uc.toVoucherResponses(ctx, vouchers)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.VoucherListResponse{
+		Items:       items,
+		TotalData:   count,
+		TotalPage:   int(math.Ceil(float64(count) / constant.VoucherDefaultItems)),
+		CurrentPage: params.Page,
+	}, nil
+}
+
+// DeleteShopVoucher implements VoucherUsecase. Orders that already used the
+// voucher keep their discount.
+func (uc *voucherUsecase) DeleteShopVoucher(ctx context.Context, id, sellerID int64) error {
+	shop, err := uc.sr.FirstShopById(ctx, int(sellerID))
+	if err != nil {
+		return err
+	}
+
+	voucher, err := uc.vr.FirstByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if voucher.ShopID.Int64 != shop.ID {
+		return shared.ErrVoucherNotFound
+	}
+
+	return uc.vr.SoftDelete(ctx, id)
+}
+
+// ValidateVoucher implements VoucherUsecase. The discount is calculated
+// against the buyer's checked cart. Free shipping depends on the chosen
+// courier, so its discount is only known in the checkout summary.
+func (uc *voucherUsecase) ValidateVoucher(ctx context.Context, payload dto.ValidateVoucherPayload) (*dto.ValidateVoucherResponse, error) {
+	voucher, err := usableVoucher(ctx, uc.vr, payload.VoucherCode, payload.BuyerID)
+	if err != nil {
+		return nil, err
+	}
+
+	var carts []dto.CartOrderModel
+	if voucher.Issuer == constant.VoucherIssuerShop {
+		if payload.ShopID != 0 && payload.ShopID != voucher.ShopID.Int64 {
+			return nil, shared.ErrVoucherNotApplicable
+		}
+		carts, err = uc.cr.FindCheckedCartByShopID(ctx, payload.BuyerID, voucher.ShopID.Int64)
+	} else {
+		carts, err = uc.cr.FindCheckedCartByAccountID(ctx, payload.BuyerID)
+	}
+	if err != nil {
+		return nil, shared.ErrFindCart
+	}
+
+	eligible, err := eligibleSubtotal(ctx, uc.vr, voucher, carts)
+	if err != nil {
+		return nil, err
+	}
+
+	discount, err := voucherDiscount(voucher, eligible, decimal.Zero)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.ValidateVoucherResponse{
+		Code:             voucher.Code,
+		Name:             voucher.Name,
+		Issuer:           voucher.Issuer,
+		Type:             voucher.Type,
+		MinimumSpend:     voucher.MinimumSpend.InexactFloat64(),
+		EligibleSubtotal: eligible.InexactFloat64(),
+		Discount:         discount.InexactFloat64(),
+		ExpiredAt:        voucher.ExpiredAt.Format("2006-01-02 15:04:05"),
+	}
+	if voucher.MaxDiscount.Valid {
+		maxDiscount := voucher.MaxDiscount.Decimal.InexactFloat64()
+		res.MaxDiscount = &maxDiscount
+	}
+
+	return res, nil
+}
+
+func (uc *voucherUsecase) toVoucherResponses(ctx context.Context, vouchers []model.Voucher) ([]dto.VoucherResponse, error) {
+	ids := make([]int64, 0, len(vouchers))
+	for _, v := range vouchers {
+		ids = append(ids, v.ID)
+	}
+
+	categories, err := uc.vr.FindCategoriesByVoucherIDs(ctx, ids)
+	if err != nil {
+		return nil, shared.ErrFindVoucher
+	}
+
+	byVoucher := make(map[int64][]int64)
+	for _, c := range categories {
+		byVoucher[c.VoucherID] = append(byVoucher[c.VoucherID], c.CategoryID)
+	}
+
+	res := make([]dto.VoucherResponse, 0, len(vouchers))
+	for _, v := range vouchers {
+		categoryIDs := byVoucher[v.ID]
+		if categoryIDs == nil {
+			categoryIDs = make([]int64, 0)
+		}
+
+		item := dto.VoucherResponse{
+			ID:                v.ID,
+			Code:              v.Code,
+			Name:              v.Name,
+			Issuer:            v.Issuer,
+			Type:              v.Type,
+			Value:             v.Value.InexactFloat64(),
+			MinimumSpend:      v.MinimumSpend.InexactFloat64(),
+			Quota:             v.Quota,
+			Used:              v.Used,
+			UsageLimitPerUser: v.UsageLimitPerUser,
+			CategoryIDs:       categoryIDs,
+			StartedAt:         v.StartedAt.Format("2006-01-02 15:04:05"),
+			ExpiredAt:         v.ExpiredAt.Format("2006-01-02 15:04:05"),
+		}
+		if v.MaxDiscount.Valid {
+			maxDiscount := v.MaxDiscount.Decimal.InexactFloat64()
+			item.MaxDiscount = &maxDiscount
+		}
+		res = append(res, item)
+	}
+
+	return res, nil
+}
+
+// voucherOrder is one shop's part of a checkout that vouchers are applied to.
+type voucherOrder struct {
+	shopID       int64
+	voucherCode  string
+	items        []dto.CartOrderModel
+	subtotal     decimal.Decimal
+	deliveryCost decimal.Decimal
+	vouchers     []dto.OrderVoucher
+}
+
+// payable returns what is left of the order's products, or of its delivery
+// cost for free shipping, after the vouchers applied so far.
+func (o *voucherOrder) payable(voucherType string) decimal.Decimal {
+	isShipping := voucherType == constant.VoucherTypeFreeShipping
+
+	amount := o.subtotal
+	if isShipping {
+		amount = o.deliveryCost
+	}
+	for _, v := range o.vouchers {
+		if (v.Type == constant.VoucherTypeFreeShipping) == isShipping {
+			amount = amount.Sub(v.Amount)
+		}
+	}
+
+	return amount
+}
+
+func (o *voucherOrder) discount() decimal.Decimal {
+	amount := decimal.Zero
+	for _, v := range o.vouchers {
+		amount = amount.Add(v.Amount)
+	}
+
+	return amount
+}
+
+// applyVouchers applies every order's shop voucher and then the platform
+// voucher of the checkout. The platform voucher is spread over the orders by
+// their eligible subtotal, or by their delivery cost for free shipping, so
+// each seller knows how much of their order the platform pays for.
+func applyVouchers(ctx context.Context, vr repository.VoucherRepository, buyerID int64, orders []*voucherOrder, platformCode string) error {
+	for _, o := range orders {
+		if o.voucherCode == "" {
+			continue
+		}
+
+		voucher, err := usableVoucher(ctx, vr, o.voucherCode, buyerID)
+		if err != nil {
+			return err
+		}
+		if voucher.Issuer != constant.VoucherIssuerShop || voucher.ShopID.Int64 != o.shopID {
+			return shared.ErrVoucherNotApplicable
+		}
+
+		eligible, err := eligibleSubtotal(ctx, vr, voucher, o.items)
+		if err != nil {
+			return err
+		}
+
+		amount, err := voucherDiscount(voucher, eligible, o.deliveryCost)
+		if err != nil {
+			return err
+		}
+
+		o.vouchers = append(o.vouchers, dto.OrderVoucher{
+			VoucherID: voucher.ID,
+			Issuer:    voucher.Issuer,
+			Type:      voucher.Type,
+			Amount:    decimal.Min(amount, o.payable(voucher.Type)),
+		})
+	}
+
+	if platformCode == "" {
+		return nil
+	}
+
+	voucher, err := usableVoucher(ctx, vr, platformCode, buyerID)
+	if err != nil {
+		return err
+	}
+	if voucher.Issuer != constant.VoucherIssuerPlatform {
+		return shared.ErrVoucherNotApplicable
+	}
+
+	weights := make([]decimal.Decimal, len(orders))
+	totalEligible := decimal.Zero
+	totalDelivery := decimal.Zero
+	for i, o := range orders {
+		eligible, err := eligibleSubtotal(ctx, vr, voucher, o.items)
+		if err != nil {
+			return err
+		}
+
+		weights[i] = eligible
+		if voucher.Type == constant.VoucherTypeFreeShipping && eligible.IsPositive() {
+			weights[i] = o.deliveryCost
+			totalDelivery = totalDelivery.Add(o.deliveryCost)
+		}
+		totalEligible = totalEligible.Add(eligible)
+	}
+
+	amount, err := voucherDiscount(voucher, totalEligible, totalDelivery)
+	if err != nil {
+		return err
+	}
+
+	totalWeight := decimal.Sum(decimal.Zero, weights...)
+	if !totalWeight.IsPositive() {
+		return nil
+	}
+
+	last := 0
+	for i, w := range weights {
+		if w.IsPositive() {
+			last = i
+		}
+	}
+
+	remaining := amount
+	for i, o := range orders {
+		if !weights[i].IsPositive() {
+			continue
+		}
+
+		share := remaining
+		if i != last {
+			share = decimal.Min(amount.Mul(weights[i]).Div(totalWeight).Round(2), remaining)
+		}
+		share = decimal.Min(share, o.payable(voucher.Type))
+		if !share.IsPositive() {
+			continue
+		}
+
+		o.vouchers = append(o.vouchers, dto.OrderVoucher{
+			VoucherID: voucher.ID,
+			Issuer:    voucher.Issuer,
+			Type:      voucher.Type,
+			Amount:    share,
+		})
+		remaining = remaining.Sub(share)
+	}
+
+	return nil
+}
+
+// usableVoucher finds a voucher by code and checks that the buyer can still
+// use it. The quota and limit are checked again when the order is created.
+func usableVoucher(ctx context.Context, vr repository.VoucherRepository, code string, buyerID int64) (*model.Voucher, error) {
+	voucher, err := vr.FirstByCode(ctx, normalizeVoucherCode(code))
+	if err != nil {
+		if err == shared.ErrVoucherNotFound {
+			return nil, err
+		}
+		return nil, shared.ErrFindVoucher
+	}
+
+	now := time.Now()
+	if now.Before(voucher.StartedAt) {
+		return nil, shared.ErrVoucherNotStarted
+	}
+	if !now.Before(voucher.ExpiredAt) {
+		return nil, shared.ErrVoucherExpired
+	}
+	if voucher.Used >= voucher.Quota {
+		return nil, shared.ErrVoucherQuotaExceeded
+	}
+
+	used, err := vr.CountUsagesByBuyerID(ctx, voucher.ID, buyerID)
+	if err != nil {
+		return nil, shared.ErrFindVoucher
+	}
+	if used >= voucher.UsageLimitPerUser {
+		return nil, shared.ErrVoucherUsageLimit
+	}
+
+	return voucher, nil
+}
+
+// eligibleSubtotal sums the items in the voucher's category whitelist. A
+// voucher without a whitelist applies to every item.
+func eligibleSubtotal(ctx context.Context, vr repository.VoucherRepository, voucher *model.Voucher, items []dto.CartOrderModel) (decimal.Decimal, error) {
+	categories, err := vr.FindCategoriesByVoucherIDs(ctx, []int64{voucher.ID})
+	if err != nil {
+		return decimal.Zero, shared.ErrFindVoucher
+	}
+
+	eligible := make(map[int64]bool)
+	if len(categories) != 0 {
+		productIDs := make([]int64, 0, len(items))
+		for _, item := range items {
+			productIDs = append(productIDs, item.ProductID)
+		}
+
+		ids, err := vr.FindEligibleProductIDs(ctx, voucher.ID, productIDs)
+		if err != nil {
+			return decimal.Zero, shared.ErrFindVoucher
+		}
+		for _, id := range ids {
+			eligible[id] = true
+		}
+	}
+
+	subtotal := decimal.Zero
+	for _, item := range items {
+		if len(categories) != 0 && !eligible[item.ProductID] {
+			continue
+		}
+		subtotal = subtotal.Add(cartItemPrice(item))
+	}
+
+	return subtotal, nil
+}
+
+// voucherDiscount returns the discount of a voucher for an eligible subtotal,
+// capped by the voucher's max discount.
+func voucherDiscount(voucher *model.Voucher, eligible, deliveryCost decimal.Decimal) (decimal.Decimal, error) {
+	if !eligible.IsPositive() {
+		return decimal.Zero, shared.ErrVoucherNoEligibleProduct
+	}
+	if eligible.LessThan(voucher.MinimumSpend) {
+		return decimal.Zero, shared.ErrVoucherMinimumSpend
+	}
+
+	var amount decimal.Decimal
+	switch voucher.Type {
+	case constant.VoucherTypePercentage:
+		amount = eligible.Mul(voucher.Value).Div(decimal.NewFromInt(100))
+	case constant.VoucherTypeFixed:
+		amount = decimal.Min(voucher.Value, eligible)
+	case constant.VoucherTypeFreeShipping:
+		amount = deliveryCost
+	}
+
+	if voucher.MaxDiscount.Valid && amount.GreaterThan(voucher.MaxDiscount.Decimal) {
+		amount = voucher.MaxDiscount.Decimal
+	}
+
+	return amount.Round(2), nil
+}
+
+func normalizeVoucherCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func NewVoucherUsecase(vr repository.VoucherRepository, sr repository.ShopRepository, cr repository.CartRepository) VoucherUsecase {
+	return &voucherUsecase{
+		vr: vr,
+		sr: sr,
+		cr: cr,
+	}
+}
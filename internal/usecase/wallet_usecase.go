@@ -111,6 +111,10 @@ func (uc *walletUsecase) ListWalletHistory(ctx context.Context, payload dto.List
 			temp.ShopName = transaction.ShopName.String
 		}
 
+		if transaction.ServiceFee.Valid {
+			temp.ServiceFee = transaction.ServiceFee.Float64
+		}
+
 		res.History = append(res.History, temp)
 	}
 
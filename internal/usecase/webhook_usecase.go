@@ -0,0 +1,445 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lil-oren/rest/internal/constant"
+	"github.com/lil-oren/rest/internal/dependency"
+	"github.com/lil-oren/rest/internal/dto"
+	"github.com/lil-oren/rest/internal/model"
+	"github.com/lil-oren/rest/internal/repository"
+	"github.com/lil-oren/rest/internal/shared"
+)
+
+type (
+	WebhookUsecase interface {
+		CreateWebhook(ctx context.Context, sellerID int64, body dto.WebhookRequestBody) (*dto.WebhookResponse, error)
+		GetWebhooks(ctx context.Context, sellerID int64) ([]dto.WebhookResponse, error)
+		UpdateWebhook(ctx context.Context, id, sellerID int64, body dto.WebhookRequestBody) (*dto.WebhookResponse, error)
+		DeleteWebhook(ctx context.Context, id, sellerID int64) error
+		PingWebhook(ctx context.Context, id, sellerID int64) (*dto.WebhookDeliveryResponse, error)
+		GetDeliveries(ctx context.Context, sellerID int64, params dto.WebhookDeliveryParams) (*dto.WebhookDeliveryListResponse, error)
+		RedeliverDelivery(ctx context.Context, id, sellerID int64) error
+		EnqueueOrderEvent(ctx context.Context, event dto.DomainEvent) error
+		EnqueueReviewPosted(ctx context.Context, event dto.DomainEvent) error
+		DeliverWebhooks(ctx context.Context) error
+	}
+	webhookUsecase struct {
+		wr  repository.WebhookRepository
+		ws  repository.WebhookSender
+		cfg dependency.Config
+	}
+)
+
+// CreateWebhook implements WebhookUsecase. Every endpoint gets its own
+// secret to check the signature of what it is sent.
+func (uc *webhookUsecase) CreateWebhook(ctx context.Context, sellerID int64, body dto.WebhookRequestBody) (*dto.WebhookResponse, error) {
+	count, err := uc.wr.CountEndpointsBySellerID(ctx, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= int(uc.cfg.Webhook.MaxEndpoints) {
+		return nil, shared.ErrWebhookLimit
+	}
+
+	if err := uc.ws.CheckURL(ctx, body.URL); err != nil {
+		return nil, err
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := &model.WebhookEndpoint{
+		SellerID:   sellerID,
+		URL:        body.URL,
+		Secret:     secret,
+		EventTypes: body.EventTypes,
+		IsActive:   body.IsActive == nil || *body.IsActive,
+	}
+	if err := uc.wr.CreateEndpoint(ctx, endpoint); err != nil {
+		return nil, err
+	}
+
+	res := toWebhookResponse(*endpoint)
+	return &res, nil
+}
+
+// GetWebhooks implements WebhookUsecase.
+func (uc *webhookUsecase) GetWebhooks(ctx context.Context, sellerID int64) ([]dto.WebhookResponse, error) {
+	endpoints, err := uc.wr.FindEndpointsBySellerID(ctx, sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]dto.WebhookResponse, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		res = append(res, toWebhookResponse(endpoint))
+	}
+
+	return res, nil
+}
+
+// UpdateWebhook implements WebhookUsecase. An endpoint keeps its active
+// state when the body leaves it out.
+func (uc *webhookUsecase) UpdateWebhook(ctx context.Context, id, sellerID int64, body dto.WebhookRequestBody) (*dto.WebhookResponse, error) {
+	endpoint, err := uc.wr.FirstEndpoint(ctx, id, sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.ws.CheckURL(ctx, body.URL); err != nil {
+		return nil, err
+	}
+
+	endpoint.URL = body.URL
+	endpoint.EventTypes = body.EventTypes
+	if body.IsActive != nil {
+		endpoint.IsActive = *body.IsActive
+	}
+	if err := uc.wr.UpdateEndpoint(ctx, endpoint); err != nil {
+		return nil, err
+	}
+
+	res := toWebhookResponse(*endpoint)
+	return &res, nil
+}
+
+// DeleteWebhook implements WebhookUsecase.
+func (uc *webhookUsecase) DeleteWebhook(ctx context.Context, id, sellerID int64) error {
+	return uc.wr.DeleteEndpoint(ctx, id, sellerID)
+}
+
+// PingWebhook implements WebhookUsecase. The ping is sent right away, even
+// to an inactive endpoint, and is logged like any other delivery. A ping
+// that fails is not retried.
+func (uc *webhookUsecase) PingWebhook(ctx context.Context, id, sellerID int64) (*dto.WebhookDeliveryResponse, error) {
+	endpoint, err := uc.wr.FirstEndpoint(ctx, id, sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(dto.WebhookPingPayload{WebhookID: endpoint.ID, SellerID: sellerID})
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := &model.WebhookDelivery{
+		EndpointID: endpoint.ID,
+		EventType:  string(constant.PingWebhook),
+		Payload:    payload,
+	}
+	if err := uc.wr.CreateDelivery(ctx, delivery, time.Duration(uc.cfg.Webhook.LeaseTime)*time.Second); err != nil {
+		return nil, err
+	}
+
+	job := dto.WebhookDeliveryJob{
+		ID:         delivery.ID,
+		EndpointID: delivery.EndpointID,
+		EventType:  delivery.EventType,
+		Payload:    delivery.Payload,
+		Attempts:   delivery.Attempts,
+		CreatedAt:  delivery.CreatedAt,
+		URL:        endpoint.URL,
+		Secret:     endpoint.Secret,
+	}
+	result, err := uc.send(ctx, job)
+	if result != nil {
+		delivery.ResponseStatus = sql.NullInt64{Int64: int64(result.StatusCode), Valid: true}
+		delivery.ResponseBody = sql.NullString{String: result.Body, Valid: true}
+	}
+	if err != nil {
+		delivery.Status = constant.WebhookDeliveryDead
+		delivery.LastError = sql.NullString{String: err.Error(), Valid: true}
+		if err := uc.wr.MarkDeliveryFailed(ctx, delivery.ID, delivery.Status, time.Now(), result, err.Error()); err != nil {
+			return nil, err
+		}
+	} else {
+		delivery.Status = constant.WebhookDeliverySucceeded
+		delivery.DeliveredAt = sql.NullTime{Time: time.Now(), Valid: true}
+		if err := uc.wr.MarkDelivered(ctx, delivery.ID, *result); err != nil {
+			return nil, err
+		}
+	}
+
+	res := toWebhookDeliveryResponse(*delivery)
+	return &res, nil
+}
+
+// GetDeliveries implements WebhookUsecase. Deliveries that ran out of
+// attempts make up the dead-letter list.
+func (uc *webhookUsecase) GetDeliveries(ctx context.Context, sellerID int64, params dto.WebhookDeliveryParams) (*dto.WebhookDeliveryListResponse, error) {
+	if params.Page == 0 {
+		params.Page = constant.DefaultPage
+	}
+
+	deliveries, err := uc.wr.FindDeliveries(ctx, sellerID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := uc.wr.CountDeliveries(ctx, sellerID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.WebhookDeliveryListResponse{
+		Items:       make([]dto.WebhookDeliveryResponse, 0, len(deliveries)),
+		TotalData:   count,
+		TotalPage:   int(math.Ceil(float64(count) / constant.WebhookDefaultItems)),
+		CurrentPage: params.Page,
+	}
+	for _, delivery := range deliveries {
+		res.Items = append(res.Items, toWebhookDeliveryResponse(delivery))
+	}
+
+	return res, nil
+}
+
+// RedeliverDelivery implements WebhookUsecase. The delivery is sent again
+// by the next run of the webhook job.
+func (uc *webhookUsecase) RedeliverDelivery(ctx context.Context, id, sellerID int64) error {
+	return uc.wr.Redeliver(ctx, id, sellerID)
+}
+
+// EnqueueOrderEvent implements WebhookUsecase. It subscribes to
+// ORDER_CREATED and ORDER_STATUS_CHANGED events, of the status changes only
+// cancelled and received orders are sent to sellers.
+func (uc *webhookUsecase) EnqueueOrderEvent(ctx context.Context, event dto.DomainEvent) error {
+	payload := dto.OrderEventPayload{}
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return err
+	}
+
+	var eventType constant.WebhookEventType
+	switch {
+	case event.Type == constant.OrderCreatedEvent:
+		eventType = constant.OrderCreatedWebhook
+	case payload.Status == string(constant.CancelOrderStatus):
+		eventType = constant.OrderCancelledWebhook
+	case payload.Status == string(constant.ReceiveOrderStatus):
+		eventType = constant.OrderReceivedWebhook
+	default:
+		return nil
+	}
+
+	return uc.wr.EnqueueDeliveries(ctx, payload.SellerID, event.ID, eventType, event.Payload)
+}
+
+// EnqueueReviewPosted implements WebhookUsecase. It subscribes to
+// REVIEW_POSTED events.
+func (uc *webhookUsecase) EnqueueReviewPosted(ctx context.Context, event dto.DomainEvent) error {
+	payload := dto.ReviewPostedPayload{}
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return err
+	}
+
+	return uc.wr.EnqueueDeliveries(ctx, payload.SellerID, event.ID, constant.ReviewPostedWebhook, event.Payload)
+}
+
+// DeliverWebhooks implements WebhookUsecase. It sends every due delivery
+// until none is left. A delivery that fails is sent again later, with a
+// wait that grows with its attempts, until it runs out of attempts and is
+// dead-lettered.
+//
+// Deliveries of a batch are sent by WEBHOOK_WORKERS at once, with at most
+// WEBHOOK_PER_ENDPOINT of them to the same endpoint so a slow endpoint
+// cannot hold up the others.
+func (uc *webhookUsecase) DeliverWebhooks(ctx context.Context) error {
+	limit := int(uc.cfg.Webhook.BatchSize)
+	lease := uc.deliveryLease()
+	failed := make([]string, 0)
+
+	for ctx.Err() == nil {
+		jobs, err := uc.wr.ClaimDeliveries(ctx, limit, lease)
+		if err != nil {
+			return err
+		}
+
+		var (
+			wg        sync.WaitGroup
+			mu        sync.Mutex
+			markErr   error
+			workers   = make(chan struct{}, maxUint(uc.cfg.Webhook.Workers, 1))
+			endpoints = make(map[int64]chan struct{})
+		)
+		for _, job := range jobs {
+			slots, ok := endpoints[job.EndpointID]
+			if !ok {
+				slots = make(chan struct{}, maxUint(uc.cfg.Webhook.PerEndpoint, 1))
+				endpoints[job.EndpointID] = slots
+			}
+
+			wg.Add(1)
+			go func(job dto.WebhookDeliveryJob) {
+				defer wg.Done()
+
+				slots <- struct{}{}
+				defer func() { <-slots }()
+				workers <- struct{}{}
+				defer func() { <-workers }()
+
+				reason, err := uc.deliver(ctx, job)
+				mu.Lock()
+				defer mu.Unlock()
+				if reason != "" {
+					failed = append(failed, fmt.Sprintf("delivery %d: %s", job.ID, reason))
+				}
+				if err != nil && markErr == nil {
+					markErr = err
+				}
+			}(job)
+		}
+		wg.Wait()
+
+		if markErr != nil {
+			return markErr
+		}
+		if len(jobs) < limit {
+			break
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%d webhook deliveries failed: %s", len(failed), strings.Join(failed, "; "))
+	}
+
+	return nil
+}
+
+// deliver sends job and records how it went. It tells why the delivery
+// failed, if it did, and fails itself only when recording does.
+func (uc *webhookUsecase) deliver(ctx context.Context, job dto.WebhookDeliveryJob) (string, error) {
+	result, err := uc.send(ctx, job)
+	if err == nil {
+		return "", uc.wr.MarkDelivered(ctx, job.ID, *result)
+	}
+
+	status := constant.WebhookDeliveryPending
+	if job.Attempts >= int(uc.cfg.Webhook.MaxAttempts) {
+		status = constant.WebhookDeliveryDead
+	}
+	retryAt := time.Now().Add(webhookRetryBackoff(job.Attempts))
+
+	return err.Error(), uc.wr.MarkDeliveryFailed(ctx, job.ID, status, retryAt, result, err.Error())
+}
+
+// deliveryLease is how long a claimed batch is kept from other senders. It
+// is never shorter than the batch can take when every delivery goes to the
+// same endpoint and runs into the timeout, so a delivery still being sent
+// is not claimed and sent twice.
+func (uc *webhookUsecase) deliveryLease() time.Duration {
+	batch := maxUint(uc.cfg.Webhook.BatchSize, 1)
+	perEndpoint := maxUint(uc.cfg.Webhook.PerEndpoint, 1)
+	rounds := (batch + perEndpoint - 1) / perEndpoint
+
+	worst := time.Duration(rounds+1) * time.Duration(uc.cfg.Webhook.Timeout) * time.Second
+	lease := time.Duration(uc.cfg.Webhook.LeaseTime) * time.Second
+	if lease < worst {
+		return worst
+	}
+
+	return lease
+}
+
+func (uc *webhookUsecase) send(ctx context.Context, job dto.WebhookDeliveryJob) (*dto.WebhookResult, error) {
+	body, err := json.Marshal(dto.WebhookBody{
+		ID:        job.ID,
+		Event:     job.EventType,
+		CreatedAt: job.CreatedAt,
+		Data:      job.Payload,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.ws.Send(ctx, job.URL, job.Secret, job.ID, job.EventType, body)
+}
+
+func toWebhookResponse(endpoint model.WebhookEndpoint) dto.WebhookResponse {
+	return dto.WebhookResponse{
+		ID:         endpoint.ID,
+		URL:        endpoint.URL,
+		Secret:     endpoint.Secret,
+		EventTypes: endpoint.EventTypes,
+		IsActive:   endpoint.IsActive,
+		CreatedAt:  endpoint.CreatedAt,
+		UpdatedAt:  endpoint.UpdatedAt,
+	}
+}
+
+func toWebhookDeliveryResponse(delivery model.WebhookDelivery) dto.WebhookDeliveryResponse {
+	res := dto.WebhookDeliveryResponse{
+		ID:        delivery.ID,
+		WebhookID: delivery.EndpointID,
+		EventType: delivery.EventType,
+		Payload:   delivery.Payload,
+		Status:    delivery.Status,
+		Attempts:  delivery.Attempts,
+		CreatedAt: delivery.CreatedAt,
+	}
+	if delivery.ResponseStatus.Valid {
+		res.ResponseStatus = &delivery.ResponseStatus.Int64
+	}
+	if delivery.ResponseBody.Valid {
+		res.ResponseBody = &delivery.ResponseBody.String
+	}
+	if delivery.LastError.Valid {
+		res.LastError = &delivery.LastError.String
+	}
+	if delivery.Status == constant.WebhookDeliveryPending {
+		res.NextAttemptAt = &delivery.AvailableAt
+	}
+	if delivery.DeliveredAt.Valid {
+		res.DeliveredAt = &delivery.DeliveredAt.Time
+	}
+
+	return res
+}
+
+func generateWebhookSecret() (string, error) {
+	secret := make([]byte, constant.WebhookSecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return constant.WebhookSecretPrefix + hex.EncodeToString(secret), nil
+}
+
+func maxUint(a, b uint) uint {
+	if a > b {
+		return a
+	}
+
+	return b
+}
+
+func webhookRetryBackoff(attempt int) time.Duration {
+	backoff := constant.WebhookRetryBackoff
+	for i := 1; i < attempt && backoff < constant.WebhookMaxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > constant.WebhookMaxRetryBackoff {
+		return constant.WebhookMaxRetryBackoff
+	}
+
+	return backoff
+}
+
+func NewWebhookUsecase(wr repository.WebhookRepository, ws repository.WebhookSender, cfg dependency.Config) WebhookUsecase {
+	return &webhookUsecase{
+		wr:  wr,
+		ws:  ws,
+		cfg: cfg,
+	}
+}